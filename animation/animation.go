@@ -3,11 +3,14 @@ package animation
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/types"
 )
 
@@ -79,19 +82,106 @@ var (
 	}
 )
 
+// CubicBezierEasing 根据CSS/SMIL风格的三次贝塞尔控制点创建缓动函数
+// CubicBezierEasing builds an easing from CSS/SMIL style cubic-bezier control points (x1, y1, x2, y2)
+func CubicBezierEasing(x1, y1, x2, y2 float64) Easing {
+	// 曲线的多项式系数，起点(0,0)终点(1,1) / Polynomial coefficients for a curve from (0,0) to (1,1)
+	cx := 3 * x1
+	bx := 3*(x2-x1) - cx
+	ax := 1 - cx - bx
+	cy := 3 * y1
+	by := 3*(y2-y1) - cy
+	ay := 1 - cy - by
+
+	sampleX := func(t float64) float64 { return ((ax*t+bx)*t + cx) * t }
+	sampleY := func(t float64) float64 { return ((ay*t+by)*t + cy) * t }
+	sampleDX := func(t float64) float64 { return (3*ax*t+2*bx)*t + cx }
+
+	// solveT 求解x(t)=x对应的参数t / Solve the parameter t for which x(t) = x
+	solveT := func(x float64) float64 {
+		// 先用牛顿迭代 / Try Newton-Raphson first
+		t := x
+		for i := 0; i < 8; i++ {
+			err := sampleX(t) - x
+			if math.Abs(err) < 1e-7 {
+				return t
+			}
+			d := sampleDX(t)
+			if math.Abs(d) < 1e-6 {
+				break
+			}
+			t -= err / d
+		}
+
+		// 回退到二分法 / Fall back to bisection
+		lo, hi := 0.0, 1.0
+		t = x
+		for i := 0; i < 50; i++ {
+			v := sampleX(t)
+			if math.Abs(v-x) < 1e-7 {
+				break
+			}
+			if v < x {
+				lo = t
+			} else {
+				hi = t
+			}
+			t = (lo + hi) / 2
+		}
+		return t
+	}
+
+	return func(t float64) float64 {
+		if t <= 0 {
+			return 0
+		}
+		if t >= 1 {
+			return 1
+		}
+		return sampleY(solveT(t))
+	}
+}
+
+// Reverse 返回e的反向缓动，即1-e(1-t)，如Reverse(EaseInQuad)等于EaseOutQuad
+// Reverse returns the reversed easing 1-e(1-t); for example Reverse(EaseInQuad) equals EaseOutQuad
+func Reverse(e Easing) Easing {
+	return func(t float64) float64 {
+		return 1 - e(1-t)
+	}
+}
+
+// Chain 连接两个缓动，前半段按a从0到0.5，后半段按b从0.5到1
+// Chain joins two easings: the first half follows a from 0 to 0.5 and the second half follows b from 0.5 to 1
+func Chain(a, b Easing) Easing {
+	return func(t float64) float64 {
+		if t < 0.5 {
+			return a(2*t) / 2
+		}
+		return 0.5 + b(2*t-1)/2
+	}
+}
+
+// Scale 将缓动的输出映射到from到to的取值范围 / Map the easing's output into the value range from to to
+func Scale(e Easing, from, to float64) func(t float64) float64 {
+	return func(t float64) float64 {
+		return from + (to-from)*e(t)
+	}
+}
+
 // BaseAnimation 是所有动画的基础结构
 type BaseAnimation struct {
-	duration      float64 // 持续时间（秒）
-	delay         float64 // 延迟时间（秒）
-	currentTime   float64 // 当前时间（秒）
-	isRunning     bool    // 是否正在运行
-	isCompleted   bool    // 是否已完成
-	easing        Easing  // 缓动函数
-	onComplete    func()  // 完成回调
-	repeatCount   int     // 重复次数（-1表示无限重复）
-	currentRepeat int     // 当前重复次数
-	autoReverse   bool    // 是否自动反向
-	isReversed    bool    // 是否反向播放
+	duration      float64                // 持续时间（秒）
+	delay         float64                // 延迟时间（秒）
+	currentTime   float64                // 当前时间（秒）
+	isRunning     bool                   // 是否正在运行
+	isCompleted   bool                   // 是否已完成
+	easing        Easing                 // 缓动函数
+	onComplete    func()                 // 完成回调
+	onUpdate      func(progress float64) // 每帧更新回调，参数为缓动后的进度 / Per-frame callback receiving the eased progress
+	repeatCount   int                    // 重复次数（-1表示无限重复）
+	currentRepeat int                    // 当前重复次数
+	autoReverse   bool                   // 是否自动反向
+	isReversed    bool                   // 是否反向播放
 }
 
 // NewBaseAnimation 创建一个新的基础动画
@@ -191,8 +281,22 @@ func (a *BaseAnimation) OnComplete(callback func()) {
 	a.onComplete = callback
 }
 
+// OnUpdate 设置每次Update应用动画效果后的回调，参数为缓动后的进度
+// OnUpdate sets a callback invoked after each Update applies the animation, receiving the eased progress
+func (a *BaseAnimation) OnUpdate(callback func(progress float64)) {
+	a.onUpdate = callback
+}
+
 // Update 更新动画状态
 func (a *BaseAnimation) Update(deltaTime float64) {
+	a.step(deltaTime, a.apply)
+}
+
+// step 推进动画时间并以缓动后的进度调用apply，随后触发更新回调
+// step advances the animation clock, calls apply with the eased progress and then fires the update callback
+//
+// 嵌入BaseAnimation的动画需在各自的Update中传入自己的apply / Animations embedding BaseAnimation pass their own apply from their Update
+func (a *BaseAnimation) step(deltaTime float64, apply func(progress float64)) {
 	if !a.isRunning || a.isCompleted {
 		return
 	}
@@ -214,7 +318,9 @@ func (a *BaseAnimation) Update(deltaTime float64) {
 		// 处理重复
 		if a.repeatCount == -1 || a.currentRepeat < a.repeatCount {
 			a.currentRepeat++
-			a.currentTime = a.delay + float64(int64(a.currentTime-a.delay)%int64(a.duration))
+			// 按浮点取余回绕，支持小于1秒的时长 / Wrap with a floating-point modulo so sub-second durations work
+			a.currentTime = a.delay + math.Mod(a.currentTime-a.delay, a.duration)
+			progress = (a.currentTime - a.delay) / a.duration
 
 			// 处理自动反向
 			if a.autoReverse {
@@ -242,7 +348,10 @@ func (a *BaseAnimation) Update(deltaTime float64) {
 	}
 
 	// 应用动画效果（由子类实现）
-	a.apply(easedProgress)
+	apply(easedProgress)
+	if a.onUpdate != nil {
+		a.onUpdate(easedProgress)
+	}
 }
 
 // apply 应用动画效果（由子类实现）
@@ -274,56 +383,7 @@ func NewPropertyAnimation(element types.Element, property, fromValue, toValue st
 
 // Update 更新属性动画
 func (a *PropertyAnimation) Update(deltaTime float64) {
-	if !a.isRunning || a.isCompleted {
-		return
-	}
-
-	// 处理延迟
-	if a.currentTime < a.delay {
-		a.currentTime += deltaTime
-		return
-	}
-
-	// 更新当前时间
-	a.currentTime += deltaTime
-
-	// 计算进度
-	progress := (a.currentTime - a.delay) / a.duration
-
-	// 检查是否完成一次循环
-	if progress >= 1.0 {
-		// 处理重复
-		if a.repeatCount == -1 || a.currentRepeat < a.repeatCount {
-			a.currentRepeat++
-			a.currentTime = a.delay + float64(int64(a.currentTime-a.delay)%int64(a.duration))
-
-			// 处理自动反向
-			if a.autoReverse {
-				a.isReversed = !a.isReversed
-			}
-		} else {
-			// 动画完成
-			a.isRunning = false
-			a.isCompleted = true
-			progress = 1.0
-
-			// 调用完成回调
-			if a.onComplete != nil {
-				a.onComplete()
-			}
-		}
-	}
-
-	// 应用缓动函数
-	easedProgress := a.easing(progress)
-
-	// 如果是反向播放，反转进度
-	if a.isReversed {
-		easedProgress = 1.0 - easedProgress
-	}
-
-	// 应用动画效果
-	a.apply(easedProgress)
+	a.step(deltaTime, a.apply)
 }
 
 // apply 应用属性动画
@@ -338,6 +398,16 @@ func (a *PropertyAnimation) apply(progress float64) {
 		value = interpolateLength(a.fromValue, a.toValue, progress)
 	case "color":
 		value = interpolateColor(a.fromValue, a.toValue, progress)
+	case "path":
+		var ok bool
+		if value, ok = interpolatePath(a.fromValue, a.toValue, progress); ok {
+			break
+		}
+		// 命令结构不同的路径无法插值，在中点切换 / Paths with different command structures cannot be interpolated and switch halfway
+		value = a.fromValue
+		if progress >= 0.5 {
+			value = a.toValue
+		}
 	default:
 		// 对于不支持插值的类型，在过程中间切换值
 		if progress < 0.5 {
@@ -369,6 +439,11 @@ func NewTransformAnimation(element types.Element, fromTransform, toTransform *at
 	}
 }
 
+// Update 更新变换动画 / Update the transform animation
+func (a *TransformAnimation) Update(deltaTime float64) {
+	a.step(deltaTime, a.apply)
+}
+
 // apply 应用变换动画
 func (a *TransformAnimation) apply(progress float64) {
 	// 插值变换矩阵的各个属性
@@ -400,13 +475,20 @@ func (a *TransformAnimation) apply(progress float64) {
 	a.element.SetAttribute("transform", transformStr)
 }
 
+// keyframe 单个关键帧 / A single keyframe
+type keyframe struct {
+	time   float64 // 时间点（0-1） / Time offset (0-1)
+	value  string  // 值 / Value
+	easing Easing  // 到下一关键帧的缓动函数，nil表示线性 / Easing to the next keyframe, nil means linear
+}
+
 // KeyframeAnimation 关键帧动画
 type KeyframeAnimation struct {
 	*BaseAnimation
-	element   types.Element      // 目标元素
-	property  string             // 属性名
-	keyframes map[float64]string // 关键帧（时间点 -> 值）
-	valueType string             // 值类型
+	element   types.Element // 目标元素
+	property  string        // 属性名
+	keyframes []keyframe    // 按时间排序的关键帧 / Keyframes sorted by time
+	valueType string        // 值类型
 }
 
 // NewKeyframeAnimation 创建一个新的关键帧动画
@@ -415,75 +497,90 @@ func NewKeyframeAnimation(element types.Element, property string, duration float
 		BaseAnimation: NewBaseAnimation(duration),
 		element:       element,
 		property:      property,
-		keyframes:     make(map[float64]string),
+		keyframes:     make([]keyframe, 0),
 		valueType:     "unknown",
 	}
 }
 
 // AddKeyframe 添加关键帧
 func (a *KeyframeAnimation) AddKeyframe(time float64, value string) {
-	a.keyframes[time] = value
+	a.insertKeyframe(keyframe{time: time, value: value})
+}
+
+// AddKeyframeWithSpline 添加带三次贝塞尔关键样条的关键帧
+// AddKeyframeWithSpline adds a keyframe whose segment to the next keyframe is timed by a cubic-bezier key spline
+func (a *KeyframeAnimation) AddKeyframeWithSpline(time float64, value string, x1, y1, x2, y2 float64) {
+	a.insertKeyframe(keyframe{time: time, value: value, easing: CubicBezierEasing(x1, y1, x2, y2)})
+}
+
+// insertKeyframe 按时间顺序插入关键帧，相同时间点的关键帧会被替换
+// insertKeyframe inserts a keyframe in time order, replacing any keyframe at the same time
+func (a *KeyframeAnimation) insertKeyframe(kf keyframe) {
+	i := sort.Search(len(a.keyframes), func(i int) bool {
+		return a.keyframes[i].time >= kf.time
+	})
+	if i < len(a.keyframes) && a.keyframes[i].time == kf.time {
+		a.keyframes[i] = kf
+	} else {
+		a.keyframes = append(a.keyframes, keyframe{})
+		copy(a.keyframes[i+1:], a.keyframes[i:])
+		a.keyframes[i] = kf
+	}
 
 	// 更新值类型
-	if a.valueType == "unknown" && len(a.keyframes) > 0 {
-		a.valueType = detectValueType(value, value)
+	if a.valueType == "unknown" {
+		a.valueType = detectValueType(kf.value, kf.value)
 	}
 }
 
+// Update 更新关键帧动画 / Update the keyframe animation
+func (a *KeyframeAnimation) Update(deltaTime float64) {
+	a.step(deltaTime, a.apply)
+}
+
 // apply 应用关键帧动画
 func (a *KeyframeAnimation) apply(progress float64) {
-	// 找到当前进度对应的关键帧
-	var prevTime float64 = 0
-	var nextTime float64 = 1
-	var prevValue, nextValue string
-
-	for time, value := range a.keyframes {
-		if time <= progress && time > prevTime {
-			prevTime = time
-			prevValue = value
-		}
-		if time > progress && time < nextTime {
-			nextTime = time
-			nextValue = value
-		}
-	}
-
-	// 如果没有找到合适的关键帧，使用第一个和最后一个
-	if prevValue == "" {
-		for time, value := range a.keyframes {
-			if time < prevTime || prevValue == "" {
-				prevTime = time
-				prevValue = value
-			}
-		}
+	if len(a.keyframes) == 0 {
+		return
 	}
 
-	if nextValue == "" {
-		for time, value := range a.keyframes {
-			if time > nextTime || nextValue == "" {
-				nextTime = time
-				nextValue = value
-			}
-		}
-	}
+	// 二分查找第一个晚于当前进度的关键帧 / Binary search for the first keyframe after the current progress
+	next := sort.Search(len(a.keyframes), func(i int) bool {
+		return a.keyframes[i].time > progress
+	})
 
 	// 计算关键帧之间的插值
 	var value string
 
-	if nextTime == prevTime {
-		value = prevValue
-	} else {
-		segmentProgress := (progress - prevTime) / (nextTime - prevTime)
+	switch {
+	case next == 0:
+		// 早于第一个关键帧 / Before the first keyframe
+		value = a.keyframes[0].value
+	case next == len(a.keyframes):
+		// 晚于或等于最后一个关键帧 / At or after the last keyframe
+		value = a.keyframes[len(a.keyframes)-1].value
+	default:
+		prev := a.keyframes[next-1]
+		nextFrame := a.keyframes[next]
+		segmentProgress := (progress - prev.time) / (nextFrame.time - prev.time)
+		if prev.easing != nil {
+			segmentProgress = prev.easing(segmentProgress)
+		}
 
 		switch a.valueType {
 		case "number":
-			value = interpolateNumber(prevValue, nextValue, segmentProgress)
+			value = interpolateNumber(prev.value, nextFrame.value, segmentProgress)
 		case "length":
-			value = interpolateLength(prevValue, nextValue, segmentProgress)
+			value = interpolateLength(prev.value, nextFrame.value, segmentProgress)
 		case "color":
-			value = interpolateColor(prevValue, nextValue, segmentProgress)
+			value = interpolateColor(prev.value, nextFrame.value, segmentProgress)
+		case "path":
+			var ok bool
+			if value, ok = interpolatePath(prev.value, nextFrame.value, segmentProgress); !ok {
+				value = prev.value
+			}
 		default:
-			value = prevValue
+			value = prev.value
 		}
 	}
 
@@ -648,8 +745,121 @@ func (g *SequentialAnimationGroup) Update(deltaTime float64) {
 	}
 }
 
+// StaggeredAnimationGroup 交错动画组，第N个子动画在组时间N*stagger之后才开始
+// StaggeredAnimationGroup starts its Nth child only after N*stagger seconds of group time, for cascade effects
+type StaggeredAnimationGroup struct {
+	*AnimationGroup
+	stagger float64 // 相邻子动画的开始间隔（秒）
+	started int     // 已开始的子动画数量
+}
+
+// NewStaggeredAnimationGroup 创建一个新的交错动画组
+func NewStaggeredAnimationGroup() *StaggeredAnimationGroup {
+	return &StaggeredAnimationGroup{
+		AnimationGroup: NewAnimationGroup(),
+	}
+}
+
+// SetStagger 设置相邻子动画的开始间隔，负值按0处理
+// SetStagger sets the begin offset between consecutive children; negative values are treated as 0
+func (g *StaggeredAnimationGroup) SetStagger(delay float64) {
+	g.stagger = math.Max(delay, 0)
+	g.updateDuration()
+}
+
+// AddAnimation 添加子动画
+func (g *StaggeredAnimationGroup) AddAnimation(animation Animation) {
+	g.animations = append(g.animations, animation)
+	g.updateDuration()
+}
+
+// updateDuration 按最晚结束的子动画计算组持续时间 / Compute the group duration from the child that ends last
+func (g *StaggeredAnimationGroup) updateDuration() {
+	g.duration = 0
+	for i, animation := range g.animations {
+		g.duration = math.Max(g.duration, g.offset(i)+animation.Duration())
+	}
+}
+
+// offset 返回第i个子动画的开始时间 / Return the begin time of the ith child
+func (g *StaggeredAnimationGroup) offset(i int) float64 {
+	return float64(i) * g.stagger
+}
+
+// Start 开始组动画，只启动开始时间为0的子动画
+func (g *StaggeredAnimationGroup) Start() {
+	g.BaseAnimation.Start()
+	g.started = 0
+	g.launch()
+	g.checkCompleted()
+}
+
+// Resume 恢复已开始的子动画，尚未到开始时间的子动画保持等待
+func (g *StaggeredAnimationGroup) Resume() {
+	g.BaseAnimation.Resume()
+
+	for _, animation := range g.animations[:g.started] {
+		animation.Resume()
+	}
+}
+
+// Reset 重置所有子动画
+func (g *StaggeredAnimationGroup) Reset() {
+	g.AnimationGroup.Reset()
+	g.started = 0
+}
+
+// Update 推进组时间，更新已开始的子动画并启动到达开始时间的子动画
+func (g *StaggeredAnimationGroup) Update(deltaTime float64) {
+	if !g.isRunning || g.isCompleted {
+		return
+	}
+
+	g.currentTime += deltaTime
+	for _, animation := range g.animations[:g.started] {
+		animation.Update(deltaTime)
+	}
+	g.launch()
+	g.checkCompleted()
+}
+
+// launch 启动组时间已到达开始时间的子动画，并补上超出开始时间的部分
+// launch starts every child whose begin time has been reached and advances it by the time elapsed since then
+func (g *StaggeredAnimationGroup) launch() {
+	for g.started < len(g.animations) && g.currentTime >= g.offset(g.started) {
+		animation := g.animations[g.started]
+		animation.Start()
+		if late := g.currentTime - g.offset(g.started); late > 0 {
+			animation.Update(late)
+		}
+		g.started++
+	}
+}
+
+// checkCompleted 所有子动画都已开始并结束时标记组动画为完成
+func (g *StaggeredAnimationGroup) checkCompleted() {
+	if g.started < len(g.animations) {
+		return
+	}
+	for _, animation := range g.animations {
+		if animation.IsRunning() {
+			return
+		}
+	}
+
+	g.isCompleted = true
+	g.isRunning = false
+	if g.onComplete != nil {
+		g.onComplete()
+	}
+}
+
 // AnimationManager 动画管理器
 type AnimationManager struct {
+	// FixedStep 禁用基于系统时钟的Update，只能通过UpdateFixed推进，用于可复现的逐帧导出
+	// FixedStep disables the wall-clock Update so animations only advance through UpdateFixed, for reproducible frame export
+	FixedStep bool
+
 	animations []Animation
 	lastTime   time.Time
 	isRunning  bool
@@ -707,7 +917,23 @@ func (m *AnimationManager) Update() {
 	now := time.Now()
 	deltaTime := now.Sub(m.lastTime).Seconds()
 	m.lastTime = now
+	if m.FixedStep {
+		return
+	}
+
+	m.advance(deltaTime)
+}
 
+// UpdateFixed 将所有动画精确推进deltaTime秒，与实际时间无关 / Advance all animations by exactly deltaTime seconds, independent of real time
+func (m *AnimationManager) UpdateFixed(deltaTime float64) {
+	if !m.isRunning {
+		return
+	}
+	m.advance(deltaTime)
+}
+
+// advance 推进所有动画并移除已结束的动画 / Advance every animation and drop the finished ones
+func (m *AnimationManager) advance(deltaTime float64) {
 	// 更新所有动画
 	for i := 0; i < len(m.animations); i++ {
 		animation := m.animations[i]
@@ -736,6 +962,11 @@ func detectValueType(fromValue, toValue string) string {
 		return "length"
 	}
 
+	// 检查是否是路径数据 / Check for path data
+	if isPathData(fromValue) && isPathData(toValue) {
+		return "path"
+	}
+
 	// 检查是否是数字
 	_, err1 := strconv.ParseFloat(fromValue, 64)
 	_, err2 := strconv.ParseFloat(toValue, 64)
@@ -746,6 +977,50 @@ func detectValueType(fromValue, toValue string) string {
 	return "string"
 }
 
+// isPathData 判断值是否为以moveto开头的路径数据 / Report whether a value is path data starting with a moveto
+func isPathData(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "M") || strings.HasPrefix(s, "m")
+}
+
+// interpolatePath 逐数值插值两条命令结构相同的路径，结构不同时返回false
+// interpolatePath interpolates two paths number by number; it returns false when their command structures differ
+//
+// 标记划分与path包的解析一致；弧标志不作连续插值，在进度过半时从起始值跳到结束值
+// Tokens are split the way the path package parses them; arc flags are not interpolated continuously but step from the start to the end value halfway through
+func interpolatePath(from, to string, progress float64) (string, bool) {
+	fromTokens, fromFlags, err := path.Tokenize(from)
+	if err != nil {
+		return "", false
+	}
+	toTokens, toFlags, err := path.Tokenize(to)
+	if err != nil || len(fromTokens) != len(toTokens) {
+		return "", false
+	}
+
+	result := make([]string, len(fromTokens))
+	for i, token := range fromTokens {
+		fromVal, err1 := strconv.ParseFloat(token, 64)
+		toVal, err2 := strconv.ParseFloat(toTokens[i], 64)
+		switch {
+		case fromFlags[i] != toFlags[i]:
+			return "", false
+		case fromFlags[i] && progress < 0.5:
+			result[i] = token
+		case fromFlags[i]:
+			result[i] = toTokens[i]
+		case err1 != nil && err2 != nil && token == toTokens[i]:
+			result[i] = token
+		case err1 == nil && err2 == nil:
+			value := math.Round((fromVal+(toVal-fromVal)*progress)*1e6) / 1e6
+			result[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			return "", false
+		}
+	}
+	return strings.Join(result, " "), true
+}
+
 // containsLengthUnit 检查字符串是否包含长度单位
 func containsLengthUnit(s string) bool {
 	units := []string{"px", "pt", "pc", "mm", "cm", "in", "%", "em", "ex", "rem"}
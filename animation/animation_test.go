@@ -0,0 +1,268 @@
+package animation
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/elements"
+)
+
+// TestCubicBezierEasing 测试三次贝塞尔缓动函数 / Test cubic-bezier easing
+func TestCubicBezierEasing(t *testing.T) {
+	linear := CubicBezierEasing(0, 0, 1, 1)
+	for _, x := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		if got := linear(x); math.Abs(got-x) > 1e-4 {
+			t.Errorf("linear cubic-bezier(%v) = %v, want %v", x, got, x)
+		}
+	}
+
+	easeIn := CubicBezierEasing(0.42, 0, 1, 1)
+	if got := easeIn(0.5); got >= 0.5 {
+		t.Errorf("ease-in at 0.5 should lag linear, got %v", got)
+	}
+}
+
+// TestEasingCombinators 测试缓动的反向、连接和范围映射 / Test reversing, chaining and range-mapping easings
+func TestEasingCombinators(t *testing.T) {
+	for _, x := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		if got, want := Reverse(EaseInQuad)(x), EaseOutQuad(x); math.Abs(got-want) > 1e-12 {
+			t.Errorf("Reverse(EaseInQuad)(%v) = %v, want %v", x, got, want)
+		}
+	}
+
+	chained := Chain(EaseInQuad, EaseOutQuad)
+	for _, x := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		if got, want := chained(x), EaseInOutQuad(x); math.Abs(got-want) > 1e-12 {
+			t.Errorf("Chain(EaseInQuad, EaseOutQuad)(%v) = %v, want %v", x, got, want)
+		}
+	}
+
+	scaled := Scale(EaseInQuad, 100, 50)
+	if got := scaled(0); got != 100 {
+		t.Errorf("Scale at 0 = %v, want 100", got)
+	}
+	if got := scaled(0.5); got != 87.5 {
+		t.Errorf("Scale at 0.5 = %v, want 87.5", got)
+	}
+	if got := scaled(1); got != 50 {
+		t.Errorf("Scale at 1 = %v, want 50", got)
+	}
+}
+
+// TestKeyframeSpline 测试关键样条使带缓动的片段落后于线性片段 / Test the key spline makes the eased segment lag the linear one
+func TestKeyframeSpline(t *testing.T) {
+	rect := elements.NewRect(0, 0, 10, 10)
+	anim := NewKeyframeAnimation(rect, "x", 1)
+	anim.AddKeyframe(0, "0")
+	anim.AddKeyframeWithSpline(0.5, "100", 0.42, 0, 1, 1)
+	anim.AddKeyframe(1, "200")
+
+	valueAt := func(progress float64) float64 {
+		anim.apply(progress)
+		v, _ := rect.GetAttribute("x")
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			t.Fatalf("invalid animated value %q: %v", v, err)
+		}
+		return f
+	}
+
+	// 第一段为线性 / The first segment is linear
+	linearMid := valueAt(0.25) - 0
+	// 第二段使用缓入样条 / The second segment uses an ease-in spline
+	easedMid := valueAt(0.75) - 100
+
+	if math.Abs(linearMid-50) > 1e-6 {
+		t.Errorf("expected linear midpoint 50, got %v", linearMid)
+	}
+	if easedMid >= linearMid {
+		t.Errorf("expected eased midpoint to lag the linear one, got %v >= %v", easedMid, linearMid)
+	}
+}
+
+// TestKeyframeOrdering 测试乱序添加的关键帧能被正确插值 / Test keyframes added out of order interpolate correctly
+func TestKeyframeOrdering(t *testing.T) {
+	rect := elements.NewRect(0, 0, 10, 10)
+	anim := NewKeyframeAnimation(rect, "x", 1)
+	anim.AddKeyframe(0.75, "30")
+	anim.AddKeyframe(0, "0")
+	anim.AddKeyframe(1, "40")
+	anim.AddKeyframe(0.25, "10")
+	anim.AddKeyframe(0.5, "20")
+
+	tests := []struct {
+		progress float64
+		want     float64
+	}{
+		{0, 0},
+		{0.125, 5},
+		{0.25, 10},
+		{0.4, 16},
+		{0.6, 24},
+		{0.875, 35},
+		{1, 40},
+	}
+
+	for _, tt := range tests {
+		for run := 0; run < 5; run++ {
+			anim.apply(tt.progress)
+			v, _ := rect.GetAttribute("x")
+			got, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				t.Fatalf("invalid animated value %q: %v", v, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("progress %v: got %v, want %v", tt.progress, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// TestOnUpdate 测试每次更新都以递增的进度调用回调 / Test the update callback fires with increasing progress
+func TestOnUpdate(t *testing.T) {
+	rect := elements.NewRect(0, 0, 10, 10)
+	keyframes := NewKeyframeAnimation(rect, "x", 1)
+	keyframes.AddKeyframe(0, "0")
+	keyframes.AddKeyframe(1, "100")
+
+	animations := map[string]interface {
+		Animation
+		OnUpdate(func(progress float64))
+	}{
+		"base":      NewBaseAnimation(1),
+		"property":  NewPropertyAnimation(rect, "width", "10", "20", 1),
+		"keyframe":  keyframes,
+		"transform": NewTransformAnimation(rect, attributes.NewTransform(), attributes.NewTransform().Translate(10, 0), 1),
+	}
+	for name, anim := range animations {
+		var progress []float64
+		anim.OnUpdate(func(p float64) { progress = append(progress, p) })
+		anim.Start()
+		for i := 0; i < 5; i++ {
+			anim.Update(0.25)
+		}
+
+		if len(progress) != 4 {
+			t.Errorf("%s: expected 4 callbacks before completion, got %v", name, progress)
+			continue
+		}
+		for i := 1; i < len(progress); i++ {
+			if progress[i] <= progress[i-1] {
+				t.Errorf("%s: expected increasing progress, got %v", name, progress)
+			}
+		}
+		if progress[len(progress)-1] != 1 {
+			t.Errorf("%s: expected the final progress to be 1, got %v", name, progress)
+		}
+	}
+
+	if x, _ := rect.GetAttribute("x"); x != "100" {
+		t.Errorf("expected the keyframe animation to apply through Update, got x=%q", x)
+	}
+}
+
+// TestUpdateFixed 测试固定步长推进结果可复现 / Test that fixed-timestep updates are reproducible
+func TestUpdateFixed(t *testing.T) {
+	run := func(steps int) (string, int) {
+		rect := elements.NewRect(0, 0, 10, 10)
+		m := NewAnimationManager()
+		m.FixedStep = true
+		m.AddAnimation(NewPropertyAnimation(rect, "width", "0", "300", 1))
+		m.Start()
+		for i := 0; i < steps; i++ {
+			m.Update() // 固定步长模式下系统时钟不推进动画 / The wall clock does not advance animations in fixed-step mode
+			m.UpdateFixed(1.0 / 30)
+		}
+		width, _ := rect.GetAttribute("width")
+		return width, len(m.animations)
+	}
+
+	half, _ := run(15)
+	if again, _ := run(15); again != half {
+		t.Errorf("expected identical results for identical steps, got %q and %q", half, again)
+	}
+	if v, err := strconv.ParseFloat(half, 64); err != nil || math.Abs(v-150) > 1e-9 {
+		t.Errorf("expected width 150 after 15 steps of 1/30s, got %q", half)
+	}
+
+	done, remaining := run(31)
+	if done != "300" || remaining != 0 {
+		t.Errorf("expected the finished width 300 and no remaining animations, got %q with %d remaining", done, remaining)
+	}
+}
+
+// TestSubSecondLoop 测试小于1秒的循环动画按剩余时间回绕 / Test a looping sub-second animation wraps by its remaining time
+func TestSubSecondLoop(t *testing.T) {
+	rect := elements.NewRect(0, 0, 10, 10)
+	anim := NewPropertyAnimation(rect, "x", "0", "100", 0.5)
+	anim.SetRepeatCount(-1)
+	anim.Start()
+
+	anim.Update(0.75)
+	if x, _ := rect.GetAttribute("x"); x != "50" {
+		t.Errorf("expected x=50 half way through the second loop, got %s", x)
+	}
+	if !anim.IsRunning() {
+		t.Error("expected the animation to keep looping")
+	}
+}
+
+// TestStaggeredAnimationGroup 测试交错动画组按间隔依次启动子动画 / Test a staggered group starts each child after its offset
+func TestStaggeredAnimationGroup(t *testing.T) {
+	group := NewStaggeredAnimationGroup()
+	group.SetStagger(0.1)
+	children := make([]*BaseAnimation, 3)
+	for i := range children {
+		children[i] = NewBaseAnimation(0.5)
+		group.AddAnimation(children[i])
+	}
+	if d := group.Duration(); math.Abs(d-0.7) > 1e-9 {
+		t.Errorf("expected duration 0.7, got %v", d)
+	}
+
+	completed := false
+	group.OnComplete(func() { completed = true })
+	group.Start()
+	elapsed := 0.0
+	for step := 0; step < 30; step++ {
+		for i, child := range children {
+			begin := float64(i) * 0.1
+			if math.Abs(elapsed-begin) < 1e-9 {
+				continue
+			}
+			if want := elapsed > begin && elapsed < begin+0.5; child.IsRunning() != want {
+				t.Errorf("at %.2fs: expected child %d running=%v", elapsed, i, want)
+			}
+		}
+		group.Update(0.03)
+		elapsed += 0.03
+	}
+
+	if !completed || group.IsRunning() {
+		t.Errorf("expected the group to complete after its last child, completed=%v", completed)
+	}
+}
+
+// TestInterpolatePathArcFlags 测试紧凑书写的弧标志按单字符划分且离散切换 / Test compactly written arc flags split into single characters and switch discretely
+func TestInterpolatePathArcFlags(t *testing.T) {
+	const from, to = "M0 0A5 5 0 0110 0", "M0 0A5 5 0 1030 0"
+	for _, tt := range []struct {
+		progress float64
+		want     string
+	}{
+		{0.25, "M 0 0 A 5 5 0 0 1 15 0"},
+		{0.75, "M 0 0 A 5 5 0 1 0 25 0"},
+	} {
+		got, ok := interpolatePath(from, to, tt.progress)
+		if !ok || got != tt.want {
+			t.Errorf("at %v expected %q, got %q (ok=%v)", tt.progress, tt.want, got, ok)
+		}
+	}
+
+	if _, ok := interpolatePath("M 0 0 L 10 0", "M 0 0 A 5 5 0 0 1 10 0", 0.5); ok {
+		t.Error("expected paths with different structures not to interpolate")
+	}
+}
@@ -0,0 +1,387 @@
+package animation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/types"
+)
+
+// ParseSMIL 从文档中的SMIL动画元素构建动画
+// ParseSMIL builds animations from the SMIL <animate> and <animateTransform> elements in a document
+//
+// 动画元素的目标为其父元素，或由href/xlink:href指定的元素
+// The target of an animation element is its parent, or the element referenced by href/xlink:href
+func ParseSMIL(doc *types.Document) ([]Animation, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("document is nil")
+	}
+
+	var animations []Animation
+	var walk func(parent, el types.Element) error
+	walk = func(parent, el types.Element) error {
+		switch el.Tag() {
+		case "animate", "animateTransform":
+			target := parent
+			if href := smilHref(el); href != "" {
+				target = doc.FindElementByID(href)
+			}
+			if target == nil {
+				return fmt.Errorf("animation target not found for <%s>", el.Tag())
+			}
+			anim, err := parseSMILElement(el, target)
+			if err != nil {
+				return err
+			}
+			animations = append(animations, anim)
+		}
+
+		for _, child := range el.Children() {
+			if err := walk(el, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, el := range doc.Elements {
+		if err := walk(nil, el); err != nil {
+			return nil, err
+		}
+	}
+
+	return animations, nil
+}
+
+// smilHref 获取动画元素引用的目标ID / Get the target ID referenced by an animation element
+func smilHref(el types.Element) string {
+	href := smilAttr(el, "href")
+	if href == "" {
+		href = smilAttr(el, "xlink:href")
+	}
+	return strings.TrimPrefix(strings.TrimSpace(href), "#")
+}
+
+// smilAttr 获取元素属性，缺失时返回默认值 / Get an element attribute, returning the default when missing
+func smilAttr(el types.Element, name string, defaultValue ...string) string {
+	value, _ := el.GetAttribute(name, defaultValue...)
+	return value
+}
+
+// parseSMILElement 将单个SMIL元素转换为动画 / Convert a single SMIL element into an animation
+func parseSMILElement(el, target types.Element) (Animation, error) {
+	dur, err := parseClockValue(smilAttr(el, "dur"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid dur on <%s>: %v", el.Tag(), err)
+	}
+	if dur <= 0 {
+		return nil, fmt.Errorf("invalid dur on <%s>: must be positive", el.Tag())
+	}
+
+	var anim Animation
+	var base *BaseAnimation
+
+	if el.Tag() == "animateTransform" {
+		transformType := smilAttr(el, "type", "translate")
+		from, err := smilTransform(transformType, smilAttr(el, "from"))
+		if err != nil {
+			return nil, err
+		}
+		to, err := smilTransform(transformType, smilAttr(el, "to"))
+		if err != nil {
+			return nil, err
+		}
+		transformAnim := NewTransformAnimation(target, from, to, dur)
+		anim, base = transformAnim, transformAnim.BaseAnimation
+		if err := smilFromToEasing(el, base); err != nil {
+			return nil, err
+		}
+	} else {
+		property := smilAttr(el, "attributeName")
+		if property == "" {
+			return nil, fmt.Errorf("missing attributeName on <animate>")
+		}
+		if values := smilAttr(el, "values"); values != "" {
+			// values优先于from/to / values takes precedence over from/to
+			keyframeAnim, err := smilKeyframes(el, target, property, values, dur)
+			if err != nil {
+				return nil, err
+			}
+			anim, base = keyframeAnim, keyframeAnim.BaseAnimation
+		} else {
+			// 缺少from时使用目标当前值 / Use the target's current value when from is missing
+			from := smilAttr(el, "from", smilAttr(target, property))
+			to := smilAttr(el, "to")
+			propertyAnim := NewPropertyAnimation(target, property, from, to, dur)
+			anim, base = propertyAnim, propertyAnim.BaseAnimation
+			if err := smilFromToEasing(el, base); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// begin延迟，仅支持时钟值 / begin delay, only clock values are supported
+	if begin := smilAttr(el, "begin"); begin != "" {
+		if delay, err := parseClockValue(begin); err == nil {
+			base.SetDelay(delay)
+		}
+	}
+
+	// repeatCount为总播放次数 / repeatCount is the total number of plays
+	switch repeat := strings.TrimSpace(smilAttr(el, "repeatCount")); repeat {
+	case "":
+	case "indefinite":
+		base.SetRepeatCount(-1)
+	default:
+		count, err := strconv.ParseFloat(repeat, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repeatCount: %s", repeat)
+		}
+		if count > 1 {
+			base.SetRepeatCount(int(math.Ceil(count)) - 1)
+		}
+	}
+
+	return anim, nil
+}
+
+// smilKeyframes 根据values和keyTimes构建关键帧动画，未给出keyTimes时均匀分布
+// smilKeyframes builds a keyframe animation from values and keyTimes, spacing the values evenly when keyTimes is absent
+//
+// calcMode="discrete"时各值保持到下一关键时间；"spline"时各片段按keySplines缓动；"paced"时按数值间距分配时间并忽略keyTimes
+// With calcMode="discrete" each value holds until the next key time; "spline" eases each interval by keySplines; "paced" spaces the times by the distance between numeric values and ignores keyTimes
+func smilKeyframes(el, target types.Element, property, values string, dur float64) (*KeyframeAnimation, error) {
+	var list []string
+	for _, value := range strings.Split(values, ";") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("empty values on <%s>", el.Tag())
+	}
+
+	calcMode := smilCalcMode(el)
+	times := make([]float64, len(list))
+	if paced, ok := pacedKeyTimes(list); calcMode == "paced" && ok {
+		times = paced
+	} else if keyTimes := smilAttr(el, "keyTimes"); keyTimes != "" {
+		parts := strings.Split(strings.TrimSuffix(strings.TrimSpace(keyTimes), ";"), ";")
+		if len(parts) != len(list) {
+			return nil, fmt.Errorf("keyTimes has %d entries but values has %d", len(parts), len(list))
+		}
+		for i, part := range parts {
+			t, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil || t < 0 || t > 1 || (i > 0 && t < times[i-1]) {
+				return nil, fmt.Errorf("invalid keyTimes: %s", keyTimes)
+			}
+			times[i] = t
+		}
+	} else if len(list) > 1 {
+		for i := range times {
+			times[i] = float64(i) / float64(len(list)-1)
+		}
+	}
+
+	var splines [][4]float64
+	if calcMode == "spline" {
+		var err error
+		if splines, err = parseKeySplines(smilAttr(el, "keySplines"), len(list)-1); err != nil {
+			return nil, err
+		}
+	}
+
+	anim := NewKeyframeAnimation(target, property, dur)
+	for i, value := range list {
+		if i < len(splines) {
+			c := splines[i]
+			anim.AddKeyframeWithSpline(times[i], value, c[0], c[1], c[2], c[3])
+		} else {
+			anim.AddKeyframe(times[i], value)
+		}
+	}
+	if calcMode == "discrete" {
+		anim.valueType = "string"
+	}
+	return anim, nil
+}
+
+// smilCalcMode 返回动画元素的插值模式，默认为linear / Return an animation element's interpolation mode, linear by default
+func smilCalcMode(el types.Element) string {
+	return strings.TrimSpace(smilAttr(el, "calcMode", "linear"))
+}
+
+// smilFromToEasing 按calcMode为from/to动画设置缓动：discrete在中点跳变，spline使用首个keySplines
+// smilFromToEasing sets the easing of a from/to animation by calcMode: discrete jumps at the midpoint and spline uses the first keySplines entry
+func smilFromToEasing(el types.Element, base *BaseAnimation) error {
+	switch smilCalcMode(el) {
+	case "discrete":
+		base.SetEasing(func(t float64) float64 {
+			if t < 0.5 {
+				return 0
+			}
+			return 1
+		})
+	case "spline":
+		splines, err := parseKeySplines(smilAttr(el, "keySplines"), 1)
+		if err != nil {
+			return err
+		}
+		c := splines[0]
+		base.SetEasing(CubicBezierEasing(c[0], c[1], c[2], c[3]))
+	}
+	return nil
+}
+
+// parseKeySplines 解析keySplines，要求恰好n组控制点且取值在[0,1]内
+// parseKeySplines parses keySplines, requiring exactly n sets of control points with values in [0, 1]
+func parseKeySplines(value string, n int) ([][4]float64, error) {
+	var splines [][4]float64
+	for _, part := range strings.Split(strings.TrimSuffix(strings.TrimSpace(value), ";"), ";") {
+		fields := strings.FieldsFunc(part, func(r rune) bool {
+			return r == ' ' || r == ',' || r == '\t' || r == '\n'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid keySplines: %s", value)
+		}
+		var c [4]float64
+		for i, f := range fields {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil || v < 0 || v > 1 {
+				return nil, fmt.Errorf("invalid keySplines: %s", value)
+			}
+			c[i] = v
+		}
+		splines = append(splines, c)
+	}
+	if len(splines) != n {
+		return nil, fmt.Errorf("keySplines has %d entries but %d are required", len(splines), n)
+	}
+	return splines, nil
+}
+
+// pacedKeyTimes 按相邻数值的距离分配关键时间，值不全为数值时返回false
+// pacedKeyTimes spaces key times by the distance between neighbouring numeric values, returning false when not every value is numeric
+func pacedKeyTimes(list []string) ([]float64, bool) {
+	if len(list) < 2 {
+		return nil, false
+	}
+	numbers := make([]float64, len(list))
+	for i, value := range list {
+		v, err := strconv.ParseFloat(strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyz%"), 64)
+		if err != nil {
+			return nil, false
+		}
+		numbers[i] = v
+	}
+
+	times := make([]float64, len(list))
+	total := 0.0
+	for i := 1; i < len(numbers); i++ {
+		total += math.Abs(numbers[i] - numbers[i-1])
+		times[i] = total
+	}
+	if total == 0 {
+		return nil, false
+	}
+	for i := range times {
+		times[i] /= total
+	}
+	return times, true
+}
+
+// smilTransform 根据animateTransform类型和值构建变换 / Build a transform from an animateTransform type and value
+func smilTransform(transformType, value string) (*attributes.Transform, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t' || r == '\n'
+	})
+	params := make([]float64, 0, len(fields))
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform value: %s", value)
+		}
+		params = append(params, v)
+	}
+	param := func(i int, def float64) float64 {
+		if i < len(params) {
+			return params[i]
+		}
+		return def
+	}
+
+	t := attributes.NewTransform()
+	switch transformType {
+	case "translate":
+		t.Translate(param(0, 0), param(1, 0))
+	case "scale":
+		sx := param(0, 1)
+		t.Scale(sx, param(1, sx))
+	case "rotate":
+		if len(params) >= 3 {
+			t.RotateAround(params[0], params[1], params[2])
+		} else {
+			t.Rotate(param(0, 0))
+		}
+	case "skewX":
+		t.SkewX(param(0, 0))
+	case "skewY":
+		t.SkewY(param(0, 0))
+	default:
+		return nil, fmt.Errorf("unsupported animateTransform type: %s", transformType)
+	}
+	return t, nil
+}
+
+// parseClockValue 解析SMIL时钟值，返回秒数 / Parse a SMIL clock value in seconds
+// 支持 "2s"、"500ms"、"1.5min"、"1h"、"00:02" 和纯数字 / Supports "2s", "500ms", "1.5min", "1h", "00:02" and plain numbers
+func parseClockValue(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty clock value")
+	}
+
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		total := 0.0
+		for _, part := range parts {
+			v, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid clock value: %s", s)
+			}
+			total = total*60 + v
+		}
+		return total, nil
+	}
+
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"ms", 0.001},
+		{"min", 60},
+		{"h", 3600},
+		{"s", 1},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid clock value: %s", s)
+			}
+			return v * u.scale, nil
+		}
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock value: %s", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,227 @@
+package animation
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hoonfeng/svg/parser"
+)
+
+// TestParseSMIL 测试从文档解析<animate>元素 / Test parsing <animate> elements from a document
+func TestParseSMIL(t *testing.T) {
+	const content = `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100">
+	<circle id="ball" cx="10" cy="50" r="5">
+		<animate attributeName="cx" from="10" to="190" dur="2s" begin="500ms" repeatCount="3"/>
+	</circle>
+</svg>`
+
+	doc, err := parser.NewXMLParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	animations, err := ParseSMIL(doc)
+	if err != nil {
+		t.Fatalf("ParseSMIL failed: %v", err)
+	}
+	if len(animations) != 1 {
+		t.Fatalf("expected 1 animation, got %d", len(animations))
+	}
+
+	anim, ok := animations[0].(*PropertyAnimation)
+	if !ok {
+		t.Fatalf("expected *PropertyAnimation, got %T", animations[0])
+	}
+	if anim.property != "cx" {
+		t.Errorf("expected property cx, got %s", anim.property)
+	}
+	if anim.fromValue != "10" || anim.toValue != "190" {
+		t.Errorf("expected endpoints 10 -> 190, got %s -> %s", anim.fromValue, anim.toValue)
+	}
+	if anim.Duration() != 2 {
+		t.Errorf("expected duration 2, got %v", anim.Duration())
+	}
+	if anim.delay != 0.5 {
+		t.Errorf("expected begin delay 0.5, got %v", anim.delay)
+	}
+	if anim.repeatCount != 2 {
+		t.Errorf("expected 2 extra repeats, got %d", anim.repeatCount)
+	}
+	if anim.element.ID() != "ball" {
+		t.Errorf("expected target ball, got %s", anim.element.ID())
+	}
+}
+
+// TestParseSMILAnimateTransform 测试通过href解析<animateTransform> / Test parsing <animateTransform> targeted by href
+func TestParseSMILAnimateTransform(t *testing.T) {
+	const content = `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100">
+	<rect id="box" x="0" y="0" width="10" height="10"/>
+	<animateTransform href="#box" attributeName="transform" type="translate" from="0 0" to="50 20" dur="1s" repeatCount="indefinite"/>
+</svg>`
+
+	doc, err := parser.NewXMLParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	animations, err := ParseSMIL(doc)
+	if err != nil {
+		t.Fatalf("ParseSMIL failed: %v", err)
+	}
+	if len(animations) != 1 {
+		t.Fatalf("expected 1 animation, got %d", len(animations))
+	}
+
+	anim, ok := animations[0].(*TransformAnimation)
+	if !ok {
+		t.Fatalf("expected *TransformAnimation, got %T", animations[0])
+	}
+	if m := anim.toTransform.GetMatrix(); m.E != 50 || m.F != 20 {
+		t.Errorf("expected translate(50, 20), got E=%v F=%v", m.E, m.F)
+	}
+	if anim.repeatCount != -1 {
+		t.Errorf("expected indefinite repeat, got %d", anim.repeatCount)
+	}
+}
+
+// TestParseSMILPathValues 测试values和keyTimes驱动的多阶段路径动画 / Test a multi-stage path animation driven by values and keyTimes
+func TestParseSMILPathValues(t *testing.T) {
+	const content = `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100">
+	<path id="shape" d="M 0 0 L 10 0">
+		<animate attributeName="d" values="M 0 0 L 10 0; M 0 0 L 10 10; M 0 0 L 0 20" keyTimes="0;0.5;1" dur="2s"/>
+	</path>
+</svg>`
+
+	doc, err := parser.NewXMLParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	animations, err := ParseSMIL(doc)
+	if err != nil {
+		t.Fatalf("ParseSMIL failed: %v", err)
+	}
+	anim, ok := animations[0].(*KeyframeAnimation)
+	if !ok {
+		t.Fatalf("expected *KeyframeAnimation, got %T", animations[0])
+	}
+	target := doc.FindElementByID("shape")
+
+	anim.Start()
+	for _, step := range []struct {
+		dt   float64
+		want string
+	}{
+		{0.5, "M 0 0 L 10 5"},  // t=0.25，第一段中点 / t=0.25, halfway through the first stage
+		{0.5, "M 0 0 L 10 10"}, // t=0.5，中间阶段 / t=0.5, the middle stage
+		{0.5, "M 0 0 L 5 15"},  // t=0.75，第二段中点 / t=0.75, halfway through the second stage
+	} {
+		anim.Update(step.dt)
+		if got := smilAttr(target, "d"); got != step.want {
+			t.Errorf("expected d %q, got %q", step.want, got)
+		}
+	}
+
+	// keyTimes与values数量不一致时报错 / Mismatched keyTimes and values are rejected
+	doc, _ = parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg">
+	<path d="M 0 0 L 1 1"><animate attributeName="d" values="M 0 0 L 1 1;M 0 0 L 2 2" keyTimes="0;0.5;1" dur="1s"/></path>
+</svg>`)
+	if _, err := ParseSMIL(doc); err == nil {
+		t.Error("expected an error for mismatched keyTimes")
+	}
+}
+
+// TestParseSMILCalcMode 测试calcMode：discrete跳变、spline缓动、paced按距离分配时间
+// TestParseSMILCalcMode tests calcMode: discrete snaps, spline eases and paced spaces times by distance
+func TestParseSMILCalcMode(t *testing.T) {
+	const content = `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100">
+	<circle id="discrete" cx="0" cy="0" r="5">
+		<animate attributeName="cx" values="10;50;90" calcMode="discrete" dur="3s"/>
+		<animate attributeName="cy" from="0" to="100" calcMode="discrete" dur="3s"/>
+	</circle>
+	<circle id="spline" cx="0" cy="0" r="5">
+		<animate attributeName="cx" values="0;100" calcMode="spline" keySplines="0.42 0 1 1" dur="3s"/>
+	</circle>
+	<circle id="paced" cx="0" cy="0" r="5">
+		<animate attributeName="cx" values="0;10;100" keyTimes="0;0.5;1" calcMode="paced" dur="3s"/>
+	</circle>
+</svg>`
+
+	doc, err := parser.NewXMLParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	animations, err := ParseSMIL(doc)
+	if err != nil {
+		t.Fatalf("ParseSMIL failed: %v", err)
+	}
+	for _, anim := range animations {
+		anim.Start()
+		anim.Update(1.4)
+	}
+
+	// t≈0.47时discrete仍保持第一个值而不混合 / At t≈0.47 discrete still holds the first value instead of blending
+	discrete := doc.FindElementByID("discrete")
+	if got := smilAttr(discrete, "cx"); got != "10" {
+		t.Errorf("expected discrete values to hold 10, got %q", got)
+	}
+	if got, _ := strconv.ParseFloat(smilAttr(discrete, "cy"), 64); got != 0 {
+		t.Errorf("expected discrete from/to to hold the from value before the midpoint, got %v", got)
+	}
+
+	// 缓入样条落后于线性进度 / The ease-in spline lags linear progress
+	if got, _ := strconv.ParseFloat(smilAttr(doc.FindElementByID("spline"), "cx"), 64); got <= 0 || got >= 40 {
+		t.Errorf("expected the spline to lag the linear 46.7, got %v", got)
+	}
+
+	// paced忽略keyTimes，10在总距离100中位于0.1处 / paced ignores keyTimes, placing 10 at 0.1 of the total distance of 100
+	if got, _ := strconv.ParseFloat(smilAttr(doc.FindElementByID("paced"), "cx"), 64); got < 45 || got > 49 {
+		t.Errorf("expected paced progress near 46.7, got %v", got)
+	}
+
+	for _, anim := range animations {
+		anim.Update(0.2)
+	}
+	if got := smilAttr(discrete, "cx"); got != "50" {
+		t.Errorf("expected discrete values to snap to 50, got %q", got)
+	}
+	if got, _ := strconv.ParseFloat(smilAttr(discrete, "cy"), 64); got != 100 {
+		t.Errorf("expected discrete from/to to snap to the to value, got %v", got)
+	}
+
+	// keySplines数量与区间数不符时报错 / keySplines must match the number of intervals
+	doc, _ = parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg">
+	<circle r="1"><animate attributeName="r" values="1;2;3" calcMode="spline" keySplines="0 0 1 1" dur="1s"/></circle>
+</svg>`)
+	if _, err := ParseSMIL(doc); err == nil {
+		t.Error("expected an error for too few keySplines")
+	}
+}
+
+// TestParseClockValue 测试SMIL时钟值解析 / Test SMIL clock value parsing
+func TestParseClockValue(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"2s", 2},
+		{"500ms", 0.5},
+		{"1.5", 1.5},
+		{"0.5min", 30},
+		{"01:30", 90},
+	}
+
+	for _, tt := range tests {
+		got, err := parseClockValue(tt.input)
+		if err != nil {
+			t.Errorf("parseClockValue(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseClockValue(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := parseClockValue("indefinite"); err == nil {
+		t.Error("expected error for indefinite clock value")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 
 	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/renderer"
 	"github.com/hoonfeng/svg/types"
 )
@@ -283,7 +284,7 @@ func (ab *AnimationBuilder) CreateWaveAnimation(config AnimationConfig) *Animati
 		pathData := fmt.Sprintf("M0,%d", ab.height/2)
 		for x := 0; x <= ab.width; x += 5 {
 			y := float64(ab.height)/2 + 50*math.Sin(float64(x)*0.02+phase)
-			pathData += fmt.Sprintf(" L%d,%.2f", x, y)
+			pathData += fmt.Sprintf(" L%d,%s", x, path.FormatNumber(y))
 		}
 		pathData += fmt.Sprintf(" L%d,%d L0,%d Z", ab.width, ab.height, ab.height)
 
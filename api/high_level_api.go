@@ -5,9 +5,14 @@ package api
 import (
 	"fmt"
 	"image/color"
+	"math"
 	"strconv"
+	"strings"
 
+	"github.com/hoonfeng/svg/animation"
+	"github.com/hoonfeng/svg/attributes"
 	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/types"
 )
 
@@ -16,6 +21,13 @@ type SVGBuilder struct {
 	doc          *types.Document
 	currentGroup *elements.Group
 	groupStack   []*elements.Group
+	gradientSeq  int
+}
+
+// GradientStop 渐变停止点，不透明度取自颜色的Alpha / A gradient stop; its opacity comes from the color's alpha
+type GradientStop struct {
+	Offset float64
+	Color  color.Color
 }
 
 // NewSVGBuilder 创建新的SVG构建器 / Create new SVG builder
@@ -97,6 +109,41 @@ func (b *SVGBuilder) AddText(x, y float64, text string) *TextBuilder {
 	return &TextBuilder{text: textElement, builder: b}
 }
 
+// AddRectLength 以带单位的长度添加矩形，单位原样写入属性 / Add a rectangle from lengths with units, written to the attributes as is
+func (b *SVGBuilder) AddRectLength(x, y, width, height types.Length) *RectBuilder {
+	rb := b.AddRect(0, 0, 0, 0)
+	setLengths(rb.rect, map[string]types.Length{"x": x, "y": y, "width": width, "height": height})
+	return rb
+}
+
+// AddCircleLength 以带单位的长度添加圆形 / Add a circle from lengths with units
+func (b *SVGBuilder) AddCircleLength(cx, cy, r types.Length) *CircleBuilder {
+	cb := b.AddCircle(0, 0, 0)
+	setLengths(cb.circle, map[string]types.Length{"cx": cx, "cy": cy, "r": r})
+	return cb
+}
+
+// AddLineLength 以带单位的长度添加直线 / Add a line from lengths with units
+func (b *SVGBuilder) AddLineLength(x1, y1, x2, y2 types.Length) *LineBuilder {
+	lb := b.AddLine(0, 0, 0, 0)
+	setLengths(lb.line, map[string]types.Length{"x1": x1, "y1": y1, "x2": x2, "y2": y2})
+	return lb
+}
+
+// AddTextLength 以带单位的长度添加文本 / Add text positioned by lengths with units
+func (b *SVGBuilder) AddTextLength(x, y types.Length, text string) *TextBuilder {
+	tb := b.AddText(0, 0, text)
+	setLengths(tb.text, map[string]types.Length{"x": x, "y": y})
+	return tb
+}
+
+// setLengths 将长度写入对应属性 / Write lengths to their attributes
+func setLengths(element types.Element, lengths map[string]types.Length) {
+	for name, length := range lengths {
+		element.SetAttribute(name, length.String())
+	}
+}
+
 // AddPath 添加路径 / Add path
 func (b *SVGBuilder) AddPath(pathData string) *PathBuilder {
 	path := elements.NewPath(pathData)
@@ -104,6 +151,39 @@ func (b *SVGBuilder) AddPath(pathData string) *PathBuilder {
 	return &PathBuilder{path: path, builder: b}
 }
 
+// AddConicGradient 添加绕(cx,cy)的锥形渐变定义并返回可用于FillPaint的url(#id)引用
+// AddConicGradient adds a conic gradient definition around (cx,cy) and returns its url(#id) reference for FillPaint
+//
+// 锥形渐变是本库的扩展：写入为带data-svg-extension="conic"的radialGradient，其他查看器将显示径向近似
+// Conic gradients are an extension of this library: they are written as a radialGradient marked data-svg-extension="conic", which other viewers show as a radial approximation
+func (b *SVGBuilder) AddConicGradient(cx, cy float64, stops ...GradientStop) string {
+	b.gradientSeq++
+	id := fmt.Sprintf("conicGradient%d", b.gradientSeq)
+
+	gradient := elements.NewBaseElement("radialGradient")
+	gradient.SetID(id)
+	gradient.SetAttribute("gradientUnits", "userSpaceOnUse")
+	gradient.SetAttribute("cx", path.FormatNumber(cx))
+	gradient.SetAttribute("cy", path.FormatNumber(cy))
+	width, height := b.getDocumentSize()
+	gradient.SetAttribute("r", path.FormatNumber(math.Hypot(width, height)/2))
+	gradient.SetAttribute("data-svg-extension", attributes.ConicExtension)
+
+	for _, stop := range stops {
+		c := color.NRGBAModel.Convert(stop.Color).(color.NRGBA)
+		element := elements.NewBaseElement("stop")
+		element.SetAttribute("offset", path.FormatNumber(stop.Offset))
+		element.SetAttribute("stop-color", fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+		if c.A != 255 {
+			element.SetAttribute("stop-opacity", strconv.FormatFloat(float64(c.A)/255, 'f', 3, 64))
+		}
+		gradient.AppendChild(element)
+	}
+
+	b.doc.AddDef(gradient)
+	return "url(#" + id + ")"
+}
+
 // BeginGroup 开始组 / Begin group
 func (b *SVGBuilder) BeginGroup() *GroupBuilder {
 	group := elements.NewGroup()
@@ -167,6 +247,29 @@ func colorToString(c color.Color) string {
 	}
 }
 
+// parseDashPattern 解析stroke-dasharray值，无法解析时返回nil / Parse a stroke-dasharray value, returning nil when it cannot be parsed
+func parseDashPattern(value string) []float64 {
+	fields := strings.FieldsFunc(value, func(c rune) bool { return c == ',' || c == ' ' })
+	pattern := make([]float64, 0, len(fields))
+	for _, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil || v < 0 {
+			return nil
+		}
+		pattern = append(pattern, v)
+	}
+	return pattern
+}
+
+// dashArrayString 将虚线模式转换为stroke-dasharray值 / Convert a dash pattern to a stroke-dasharray value
+func dashArrayString(pattern []float64) string {
+	parts := make([]string, len(pattern))
+	for i, v := range pattern {
+		parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
 // Element builders for fluent API
 // 元素构建器，用于流式API
 
@@ -182,6 +285,12 @@ func (rb *RectBuilder) Fill(color color.Color) *RectBuilder {
 	return rb
 }
 
+// FillPaint 使用绘制源引用（如AddConicGradient返回的url(#id)）填充 / Fill with a paint reference such as the url(#id) returned by AddConicGradient
+func (rb *RectBuilder) FillPaint(paint string) *RectBuilder {
+	rb.rect.SetAttribute("fill", paint)
+	return rb
+}
+
 // Stroke 设置描边颜色 / Set stroke color
 func (rb *RectBuilder) Stroke(color color.Color) *RectBuilder {
 	rb.rect.SetAttribute("stroke", colorToString(color))
@@ -190,19 +299,25 @@ func (rb *RectBuilder) Stroke(color color.Color) *RectBuilder {
 
 // StrokeWidth 设置描边宽度 / Set stroke width
 func (rb *RectBuilder) StrokeWidth(width float64) *RectBuilder {
-	rb.rect.SetAttribute("stroke-width", fmt.Sprintf("%.2f", width))
+	rb.rect.SetAttribute("stroke-width", path.FormatNumber(width))
+	return rb
+}
+
+// StrokeWidthLength 以带单位的长度设置描边宽度 / Set stroke width as a length with a unit
+func (rb *RectBuilder) StrokeWidthLength(width types.Length) *RectBuilder {
+	rb.rect.SetAttribute("stroke-width", width.String())
 	return rb
 }
 
 // Rx 设置圆角半径X / Set border radius X
 func (rb *RectBuilder) Rx(rx float64) *RectBuilder {
-	rb.rect.SetAttribute("rx", fmt.Sprintf("%.2f", rx))
+	rb.rect.SetAttribute("rx", path.FormatNumber(rx))
 	return rb
 }
 
 // Ry 设置圆角半径Y / Set border radius Y
 func (rb *RectBuilder) Ry(ry float64) *RectBuilder {
-	rb.rect.SetAttribute("ry", fmt.Sprintf("%.2f", ry))
+	rb.rect.SetAttribute("ry", path.FormatNumber(ry))
 	return rb
 }
 
@@ -223,6 +338,12 @@ func (cb *CircleBuilder) Fill(color color.Color) *CircleBuilder {
 	return cb
 }
 
+// FillPaint 使用绘制源引用（如AddConicGradient返回的url(#id)）填充 / Fill with a paint reference such as the url(#id) returned by AddConicGradient
+func (cb *CircleBuilder) FillPaint(paint string) *CircleBuilder {
+	cb.circle.SetAttribute("fill", paint)
+	return cb
+}
+
 // Stroke 设置描边颜色 / Set stroke color
 func (cb *CircleBuilder) Stroke(color color.Color) *CircleBuilder {
 	cb.circle.SetAttribute("stroke", colorToString(color))
@@ -231,7 +352,13 @@ func (cb *CircleBuilder) Stroke(color color.Color) *CircleBuilder {
 
 // StrokeWidth 设置描边宽度 / Set stroke width
 func (cb *CircleBuilder) StrokeWidth(width float64) *CircleBuilder {
-	cb.circle.SetAttribute("stroke-width", fmt.Sprintf("%.2f", width))
+	cb.circle.SetAttribute("stroke-width", path.FormatNumber(width))
+	return cb
+}
+
+// StrokeWidthLength 以带单位的长度设置描边宽度 / Set stroke width as a length with a unit
+func (cb *CircleBuilder) StrokeWidthLength(width types.Length) *CircleBuilder {
+	cb.circle.SetAttribute("stroke-width", width.String())
 	return cb
 }
 
@@ -252,6 +379,12 @@ func (eb *EllipseBuilder) Fill(color color.Color) *EllipseBuilder {
 	return eb
 }
 
+// FillPaint 使用绘制源引用（如AddConicGradient返回的url(#id)）填充 / Fill with a paint reference such as the url(#id) returned by AddConicGradient
+func (eb *EllipseBuilder) FillPaint(paint string) *EllipseBuilder {
+	eb.ellipse.SetAttribute("fill", paint)
+	return eb
+}
+
 // Stroke 设置描边颜色 / Set stroke color
 func (eb *EllipseBuilder) Stroke(color color.Color) *EllipseBuilder {
 	eb.ellipse.SetAttribute("stroke", colorToString(color))
@@ -260,7 +393,13 @@ func (eb *EllipseBuilder) Stroke(color color.Color) *EllipseBuilder {
 
 // StrokeWidth 设置描边宽度 / Set stroke width
 func (eb *EllipseBuilder) StrokeWidth(width float64) *EllipseBuilder {
-	eb.ellipse.SetAttribute("stroke-width", fmt.Sprintf("%.2f", width))
+	eb.ellipse.SetAttribute("stroke-width", path.FormatNumber(width))
+	return eb
+}
+
+// StrokeWidthLength 以带单位的长度设置描边宽度 / Set stroke width as a length with a unit
+func (eb *EllipseBuilder) StrokeWidthLength(width types.Length) *EllipseBuilder {
+	eb.ellipse.SetAttribute("stroke-width", width.String())
 	return eb
 }
 
@@ -283,7 +422,13 @@ func (lb *LineBuilder) Stroke(color color.Color) *LineBuilder {
 
 // StrokeWidth 设置描边宽度 / Set stroke width
 func (lb *LineBuilder) StrokeWidth(width float64) *LineBuilder {
-	lb.line.SetAttribute("stroke-width", fmt.Sprintf("%.2f", width))
+	lb.line.SetAttribute("stroke-width", path.FormatNumber(width))
+	return lb
+}
+
+// StrokeWidthLength 以带单位的长度设置描边宽度 / Set stroke width as a length with a unit
+func (lb *LineBuilder) StrokeWidthLength(width types.Length) *LineBuilder {
+	lb.line.SetAttribute("stroke-width", width.String())
 	return lb
 }
 
@@ -312,7 +457,13 @@ func (tb *TextBuilder) FontFamily(family string) *TextBuilder {
 
 // FontSize 设置字体大小 / Set font size
 func (tb *TextBuilder) FontSize(size float64) *TextBuilder {
-	tb.text.SetAttribute("font-size", fmt.Sprintf("%.2f", size))
+	tb.text.SetAttribute("font-size", path.FormatNumber(size))
+	return tb
+}
+
+// FontSizeLength 以带单位的长度设置字体大小 / Set font size as a length with a unit
+func (tb *TextBuilder) FontSizeLength(size types.Length) *TextBuilder {
+	tb.text.SetAttribute("font-size", size.String())
 	return tb
 }
 
@@ -328,6 +479,12 @@ func (tb *TextBuilder) TextAnchor(anchor string) *TextBuilder {
 	return tb
 }
 
+// DominantBaseline 设置主基线 / Set dominant baseline
+func (tb *TextBuilder) DominantBaseline(baseline string) *TextBuilder {
+	tb.text.SetAttribute("dominant-baseline", baseline)
+	return tb
+}
+
 // End 结束文本构建 / End text building
 func (tb *TextBuilder) End() *SVGBuilder {
 	return tb.builder
@@ -345,6 +502,12 @@ func (pb *PathBuilder) Fill(color color.Color) *PathBuilder {
 	return pb
 }
 
+// FillPaint 使用绘制源引用（如AddConicGradient返回的url(#id)）填充 / Fill with a paint reference such as the url(#id) returned by AddConicGradient
+func (pb *PathBuilder) FillPaint(paint string) *PathBuilder {
+	pb.path.SetAttribute("fill", paint)
+	return pb
+}
+
 // Stroke 设置描边颜色 / Set stroke color
 func (pb *PathBuilder) Stroke(color color.Color) *PathBuilder {
 	pb.path.SetAttribute("stroke", colorToString(color))
@@ -353,10 +516,70 @@ func (pb *PathBuilder) Stroke(color color.Color) *PathBuilder {
 
 // StrokeWidth 设置描边宽度 / Set stroke width
 func (pb *PathBuilder) StrokeWidth(width float64) *PathBuilder {
-	pb.path.SetAttribute("stroke-width", fmt.Sprintf("%.2f", width))
+	pb.path.SetAttribute("stroke-width", path.FormatNumber(width))
 	return pb
 }
 
+// StrokeWidthLength 以带单位的长度设置描边宽度 / Set stroke width as a length with a unit
+func (pb *PathBuilder) StrokeWidthLength(width types.Length) *PathBuilder {
+	pb.path.SetAttribute("stroke-width", width.String())
+	return pb
+}
+
+// FillRule 设置填充规则，"nonzero"或"evenodd" / Set the fill rule, "nonzero" or "evenodd"
+func (pb *PathBuilder) FillRule(rule string) *PathBuilder {
+	pb.path.SetAttribute("fill-rule", rule)
+	return pb
+}
+
+// Dash 设置描边虚线模式，不传参数时恢复实线 / Set the stroke dash pattern; no arguments restores a solid stroke
+func (pb *PathBuilder) Dash(pattern ...float64) *PathBuilder {
+	if len(pattern) == 0 {
+		pb.path.RemoveAttribute("stroke-dasharray")
+		return pb
+	}
+	pb.path.SetAttribute("stroke-dasharray", dashArrayString(pattern))
+	return pb
+}
+
+// DashOffset 设置虚线模式的起始偏移 / Set the offset into the dash pattern
+func (pb *PathBuilder) DashOffset(offset float64) *PathBuilder {
+	pb.path.SetAttribute("stroke-dashoffset", strconv.FormatFloat(offset, 'f', -1, 64))
+	return pb
+}
+
+// MarchingAnts 创建循环移动stroke-dashoffset的"行军蚁"动画，speed为每秒移动的用户单位，正值沿路径方向前进
+// MarchingAnts creates a looping stroke-dashoffset animation for a marching-ants border; speed is in user units per second, positive values march along the path direction
+//
+// 未设置虚线模式时使用4,4；每个循环移动一个完整的模式周期，返回的动画需调用Start
+// A 4,4 pattern is used when no dash pattern is set; each loop moves one full pattern period, and the returned animation must be started
+func (pb *PathBuilder) MarchingAnts(speed float64) *animation.PropertyAnimation {
+	dashArray, _ := pb.path.GetAttribute("stroke-dasharray")
+	pattern := parseDashPattern(dashArray)
+	if len(pattern) == 0 {
+		pattern = []float64{4, 4}
+		pb.Dash(pattern...)
+	}
+	period := 0.0
+	for _, v := range pattern {
+		period += v
+	}
+	// 奇数个值的模式重复一次 / A pattern with an odd number of values repeats once
+	if len(pattern)%2 == 1 {
+		period *= 2
+	}
+
+	to, duration := 0.0, 1.0
+	if speed != 0 && period > 0 {
+		to = -math.Copysign(period, speed)
+		duration = period / math.Abs(speed)
+	}
+	pb.DashOffset(0)
+	anim := animation.NewPropertyAnimation(pb.path, "stroke-dashoffset", "0", strconv.FormatFloat(to, 'f', -1, 64), duration)
+	anim.SetRepeatCount(-1)
+	return anim
+}
+
 // End 结束路径构建 / End path building
 func (pb *PathBuilder) End() *SVGBuilder {
 	return pb.builder
@@ -376,21 +599,21 @@ func (gb *GroupBuilder) Transform(transform string) *GroupBuilder {
 
 // Translate 设置平移变换 / Set translate transform
 func (gb *GroupBuilder) Translate(x, y float64) *GroupBuilder {
-	transform := fmt.Sprintf("translate(%.2f,%.2f)", x, y)
+	transform := fmt.Sprintf("translate(%s,%s)", path.FormatNumber(x), path.FormatNumber(y))
 	gb.group.SetAttribute("transform", transform)
 	return gb
 }
 
 // Scale 设置缩放变换 / Set scale transform
 func (gb *GroupBuilder) Scale(sx, sy float64) *GroupBuilder {
-	transform := fmt.Sprintf("scale(%.2f,%.2f)", sx, sy)
+	transform := fmt.Sprintf("scale(%s,%s)", path.FormatNumber(sx), path.FormatNumber(sy))
 	gb.group.SetAttribute("transform", transform)
 	return gb
 }
 
 // Rotate 设置旋转变换 / Set rotate transform
 func (gb *GroupBuilder) Rotate(angle, cx, cy float64) *GroupBuilder {
-	transform := fmt.Sprintf("rotate(%.2f,%.2f,%.2f)", angle, cx, cy)
+	transform := fmt.Sprintf("rotate(%s,%s,%s)", path.FormatNumber(angle), path.FormatNumber(cx), path.FormatNumber(cy))
 	gb.group.SetAttribute("transform", transform)
 	return gb
 }
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"strconv"
 
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/types"
 )
 
@@ -127,18 +129,50 @@ func (g *SVGGenerator) createBarChart(data []float64, options ChartOptions) {
 	barWidth := options.Width / float64(len(data)) * 0.8
 	barSpacing := options.Width / float64(len(data)) * 0.2
 
+	// 有标签时在图表区域内为数值和类别标签各留出一行 / With labels, reserve a line inside the chart area for the values and one for the categories
+	plotTop, plotBottom := 0.0, options.Height
+	if options.FontSize > 0 {
+		plotTop = options.FontSize * 1.25
+		if len(options.Labels) > 0 {
+			plotBottom -= options.FontSize * 1.25
+		}
+	}
+
 	// 绘制柱子 / Draw bars
 	for i, value := range data {
-		barHeight := (value / maxValue) * options.Height
+		barHeight := (value / maxValue) * (plotBottom - plotTop)
 		x := float64(i)*(barWidth+barSpacing) + barSpacing/2
-		y := options.Height - barHeight
+		y := plotBottom - barHeight
 
 		g.builder.AddRect(x, y, barWidth, barHeight).
 			Fill(options.FillColor).
 			Stroke(options.StrokeColor).
 			StrokeWidth(1).
 			End()
+
+		if options.FontSize > 0 {
+			// 数值在柱顶上方，类别在基线下方 / Value above the bar top, category below the baseline
+			cx := x + barWidth/2
+			g.addChartLabel(cx, y-options.FontSize/4, strconv.FormatFloat(value, 'f', -1, 64), "auto", options)
+			if i < len(options.Labels) {
+				g.addChartLabel(cx, plotBottom+options.FontSize/4, options.Labels[i], "hanging", options)
+			}
+		}
+	}
+}
+
+// addChartLabel 添加水平居中的图表标签 / Add a horizontally centred chart label
+func (g *SVGGenerator) addChartLabel(x, y float64, text, baseline string, options ChartOptions) {
+	labelColor := options.LabelColor
+	if labelColor == nil {
+		labelColor = color.Black
 	}
+	g.builder.AddText(x, y, text).
+		Fill(labelColor).
+		FontSize(options.FontSize).
+		TextAnchor("middle").
+		DominantBaseline(baseline).
+		End()
 }
 
 // createLineChart 创建折线图 / Create line chart
@@ -170,9 +204,9 @@ func (g *SVGGenerator) createLineChart(data []float64, options ChartOptions) {
 		y := options.Height - ((value-minValue)/valueRange)*options.Height
 
 		if i == 0 {
-			pathData += fmt.Sprintf("M %.2f %.2f", x, y)
+			pathData += fmt.Sprintf("M %s %s", path.FormatNumber(x), path.FormatNumber(y))
 		} else {
-			pathData += fmt.Sprintf(" L %.2f %.2f", x, y)
+			pathData += fmt.Sprintf(" L %s %s", path.FormatNumber(x), path.FormatNumber(y))
 		}
 	}
 
@@ -180,6 +214,7 @@ func (g *SVGGenerator) createLineChart(data []float64, options ChartOptions) {
 		Fill(color.RGBA{0, 0, 0, 0}). // 透明填充 / Transparent fill
 		Stroke(options.StrokeColor).
 		StrokeWidth(2).
+		Dash(options.DashPattern...).
 		End()
 }
 
@@ -223,6 +258,25 @@ func (g *SVGGenerator) createPieChart(data []float64, options ChartOptions) {
 
 		startAngle = endAngle
 	}
+
+	// 标签在扇形上方绘制，避免被后续扇形遮挡 / Labels are drawn over the sectors so later sectors do not cover them
+	if options.FontSize <= 0 {
+		return
+	}
+	startAngle = 0
+	for _, value := range data {
+		angle := (value / total) * 2 * math.Pi
+		// 扇形质心距圆心2r·sin(α)/(3α)，α为半角 / A sector's centroid lies 2r·sin(α)/(3α) from the centre, with α the half angle
+		half := angle / 2
+		distance := 2 * radius / 3
+		if half > 0 {
+			distance = 2 * radius * math.Sin(half) / (3 * half)
+		}
+		mid := startAngle + half
+		label := fmt.Sprintf("%.1f%%", value/total*100)
+		g.addChartLabel(cx+distance*math.Cos(mid), cy+distance*math.Sin(mid), label, "central", options)
+		startAngle += angle
+	}
 }
 
 // createDotPattern 创建点图案 / Create dot pattern
@@ -289,9 +343,9 @@ func (g *SVGGenerator) createStar(options ShapeOptions) {
 		y := cy + radius*math.Sin(angle-math.Pi/2)
 
 		if i == 0 {
-			pathData += fmt.Sprintf("M %.2f %.2f", x, y)
+			pathData += fmt.Sprintf("M %s %s", path.FormatNumber(x), path.FormatNumber(y))
 		} else {
-			pathData += fmt.Sprintf(" L %.2f %.2f", x, y)
+			pathData += fmt.Sprintf(" L %s %s", path.FormatNumber(x), path.FormatNumber(y))
 		}
 	}
 	pathData += " Z"
@@ -321,9 +375,9 @@ func (g *SVGGenerator) createPolygon(options ShapeOptions) {
 		y := cy + radius*math.Sin(angle-math.Pi/2)
 
 		if i == 0 {
-			pathData += fmt.Sprintf("M %.2f %.2f", x, y)
+			pathData += fmt.Sprintf("M %s %s", path.FormatNumber(x), path.FormatNumber(y))
 		} else {
-			pathData += fmt.Sprintf(" L %.2f %.2f", x, y)
+			pathData += fmt.Sprintf(" L %s %s", path.FormatNumber(x), path.FormatNumber(y))
 		}
 	}
 	pathData += " Z"
@@ -346,17 +400,17 @@ func (g *SVGGenerator) createArrow(options ShapeOptions) {
 	cy := options.CenterY
 
 	// 箭头主体 / Arrow body
-	body := fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f L %.2f %.2f Z",
-		cx-length/2, cy-width/2,
-		cx+length/2-headLength, cy-width/2,
-		cx+length/2-headLength, cy+width/2,
-		cx-length/2, cy+width/2)
+	body := fmt.Sprintf("M %s %s L %s %s L %s %s L %s %s Z",
+		path.FormatNumber(cx-length/2), path.FormatNumber(cy-width/2),
+		path.FormatNumber(cx+length/2-headLength), path.FormatNumber(cy-width/2),
+		path.FormatNumber(cx+length/2-headLength), path.FormatNumber(cy+width/2),
+		path.FormatNumber(cx-length/2), path.FormatNumber(cy+width/2))
 
 	// 箭头头部 / Arrow head
-	head := fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f Z",
-		cx+length/2-headLength, cy-headWidth/2,
-		cx+length/2, cy,
-		cx+length/2-headLength, cy+headWidth/2)
+	head := fmt.Sprintf("M %s %s L %s %s L %s %s Z",
+		path.FormatNumber(cx+length/2-headLength), path.FormatNumber(cy-headWidth/2),
+		path.FormatNumber(cx+length/2), path.FormatNumber(cy),
+		path.FormatNumber(cx+length/2-headLength), path.FormatNumber(cy+headWidth/2))
 
 	pathData := body + " " + head
 
@@ -374,10 +428,10 @@ func (g *SVGGenerator) createHeart(options ShapeOptions) {
 	cy := options.CenterY
 
 	// 心形路径 / Heart path
-	pathData := fmt.Sprintf("M %.2f %.2f C %.2f %.2f %.2f %.2f %.2f %.2f C %.2f %.2f %.2f %.2f %.2f %.2f Z",
-		cx, cy+size*0.3,
-		cx-size*0.7, cy-size*0.3, cx-size*0.3, cy-size*0.3, cx, cy,
-		cx+size*0.3, cy-size*0.3, cx+size*0.7, cy-size*0.3, cx, cy+size*0.3)
+	pathData := fmt.Sprintf("M %s %s C %s %s %s %s %s %s C %s %s %s %s %s %s Z",
+		path.FormatNumber(cx), path.FormatNumber(cy+size*0.3),
+		path.FormatNumber(cx-size*0.7), path.FormatNumber(cy-size*0.3), path.FormatNumber(cx-size*0.3), path.FormatNumber(cy-size*0.3), path.FormatNumber(cx), path.FormatNumber(cy),
+		path.FormatNumber(cx+size*0.3), path.FormatNumber(cy-size*0.3), path.FormatNumber(cx+size*0.7), path.FormatNumber(cy-size*0.3), path.FormatNumber(cx), path.FormatNumber(cy+size*0.3))
 
 	g.builder.AddPath(pathData).
 		Fill(options.FillColor).
@@ -398,8 +452,8 @@ func (g *SVGGenerator) createArcPath(cx, cy, radius, startAngle, endAngle float6
 		largeArc = 1
 	}
 
-	return fmt.Sprintf("M %.2f %.2f L %.2f %.2f A %.2f %.2f 0 %d 1 %.2f %.2f Z",
-		cx, cy, x1, y1, radius, radius, largeArc, x2, y2)
+	return fmt.Sprintf("M %s %s L %s %s A %s %s 0 %d 1 %s %s Z",
+		path.FormatNumber(cx), path.FormatNumber(cy), path.FormatNumber(x1), path.FormatNumber(y1), path.FormatNumber(radius), path.FormatNumber(radius), largeArc, path.FormatNumber(x2), path.FormatNumber(y2))
 }
 
 // generateColors 生成颜色数组 / Generate color array
@@ -473,6 +527,10 @@ type ChartOptions struct {
 	Height      float64
 	FillColor   color.Color
 	StrokeColor color.Color
+	DashPattern []float64   // 折线的虚线模式，为空时绘制实线 / Dash pattern for line charts; empty draws solid lines
+	Labels      []string    // 柱状图的类别标签 / Category labels for bar charts
+	LabelColor  color.Color // 标签颜色，为空时为黑色 / Label color; black when nil
+	FontSize    float64     // 标签字号，为0时不绘制标签；柱状图在区域内为标签留出空间 / Label font size; no labels are drawn when 0, and bar charts make room for them inside the area
 }
 
 // GridOptions 网格选项 / Grid options
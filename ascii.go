@@ -0,0 +1,89 @@
+package svg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/hoonfeng/svg/renderer"
+)
+
+// asciiRamp 从亮到暗的字符梯度 / Character ramp from light to dark
+const asciiRamp = " .:-=+*#%@"
+
+// cellSamples 每个字符单元在每个方向上的采样像素数 / Sample pixels per character cell in each direction
+const cellSamples = 4
+
+// RenderToASCII 将文档渲染为cols列rows行的文本预览，按亮度映射到字符梯度，透明区域视为白色
+// RenderToASCII renders the document as a text preview of cols columns and rows rows, mapping luminance onto a character ramp with transparency as white
+//
+// 每个字符为其单元内多个采样像素的平均；尺寸无效或渲染失败时返回空字符串
+// Each character averages several sample pixels of its cell; an empty string is returned for an invalid size or a failed render
+func (s *SVG) RenderToASCII(cols, rows int) string {
+	cells, ok := s.renderCells(cols, rows)
+	if !ok {
+		return ""
+	}
+	var sb strings.Builder
+	for _, row := range cells {
+		for _, c := range row {
+			// 亮度255对应梯度首个字符，0对应最后一个 / Luminance 255 maps to the first ramp character and 0 to the last
+			level := (255 - int(renderer.Luminance(c.R, c.G, c.B))) * len(asciiRamp) / 256
+			sb.WriteByte(asciiRamp[level])
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// RenderToANSI 与RenderToASCII相同地划分单元，以ANSI真彩色背景的空格输出每个单元的平均颜色
+// RenderToANSI divides the cells like RenderToASCII and prints each cell's average color as a space with an ANSI truecolor background
+func (s *SVG) RenderToANSI(cols, rows int) string {
+	cells, ok := s.renderCells(cols, rows)
+	if !ok {
+		return ""
+	}
+	var sb strings.Builder
+	for _, row := range cells {
+		for _, c := range row {
+			fmt.Fprintf(&sb, "\x1b[48;2;%d;%d;%dm ", c.R, c.G, c.B)
+		}
+		sb.WriteString("\x1b[0m\n")
+	}
+	return sb.String()
+}
+
+// renderCells 按cellSamples倍采样渲染到白色背景上，返回每个单元的平均颜色
+// renderCells renders at cellSamples times the cell grid onto white and returns each cell's average color
+func (s *SVG) renderCells(cols, rows int) ([][]color.RGBA, bool) {
+	if cols <= 0 || rows <= 0 {
+		return nil, false
+	}
+	img, err := s.RenderToSize(cols*cellSamples, rows*cellSamples)
+	if err != nil {
+		return nil, false
+	}
+	// 渲染结果为非预乘颜色，按NRGBA合成到白色上 / The render holds straight-alpha colors, so composite it onto white as NRGBA
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Rect, image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Rect, &image.NRGBA{Pix: img.Pix, Stride: img.Stride, Rect: img.Rect}, img.Rect.Min, draw.Over)
+
+	const n = cellSamples * cellSamples
+	cells := make([][]color.RGBA, rows)
+	for row := range cells {
+		cells[row] = make([]color.RGBA, cols)
+		for col := range cells[row] {
+			var r, g, b int
+			for y := row * cellSamples; y < (row+1)*cellSamples; y++ {
+				for x := col * cellSamples; x < (col+1)*cellSamples; x++ {
+					c := flat.RGBAAt(x, y)
+					r, g, b = r+int(c.R), g+int(c.G), b+int(c.B)
+				}
+			}
+			cells[row][col] = color.RGBA{R: uint8((r + n/2) / n), G: uint8((g + n/2) / n), B: uint8((b + n/2) / n), A: 255}
+		}
+	}
+	return cells, true
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -37,6 +38,12 @@ func ParseColor(s string) (color.Color, error) {
 			g = g * 17
 			b = b * 17
 			a = 255
+		case 4: // #RGBA
+			fmt.Sscanf(hex, "%1x%1x%1x%1x", &r, &g, &b, &a)
+			r = r * 17
+			g = g * 17
+			b = b * 17
+			a = a * 17
 		case 6: // #RRGGBB
 			fmt.Sscanf(hex, "%2x%2x%2x", &r, &g, &b)
 			a = 255
@@ -116,6 +123,7 @@ func ParseColor(s string) (color.Color, error) {
 // Style 表示SVG样式
 type Style struct {
 	properties map[string]string
+	onChange   func(*Style) // 样式变化时回调，用于同步到元素 / Called after each change, used to sync back to an element
 }
 
 // NewStyle 创建一个新的样式
@@ -125,9 +133,38 @@ func NewStyle() *Style {
 	}
 }
 
+// ParseStyle 解析style属性中的声明 / Parse the declarations of a style attribute
+func ParseStyle(css string) *Style {
+	s := NewStyle()
+	for _, decl := range strings.Split(css, ";") {
+		parts := strings.SplitN(decl, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		if name != "" {
+			s.properties[name] = strings.TrimSpace(parts[1])
+		}
+	}
+	return s
+}
+
+// OnChange 设置样式每次修改后的回调 / Set a callback invoked after every change to the style
+func (s *Style) OnChange(fn func(*Style)) {
+	s.onChange = fn
+}
+
+// changed 通知样式已修改 / Notify that the style changed
+func (s *Style) changed() {
+	if s.onChange != nil {
+		s.onChange(s)
+	}
+}
+
 // Set 设置样式属性
 func (s *Style) Set(name, value string) {
 	s.properties[name] = value
+	s.changed()
 }
 
 // Get 获取样式属性
@@ -139,6 +176,7 @@ func (s *Style) Get(name string) (string, bool) {
 // Remove 移除样式属性
 func (s *Style) Remove(name string) {
 	delete(s.properties, name)
+	s.changed()
 }
 
 // SetFill 设置填充颜色
@@ -181,11 +219,17 @@ func (s *Style) SetTextAnchor(anchor string) {
 	s.Set("text-anchor", anchor)
 }
 
-// ToString 将样式转换为字符串
+// ToString 将样式转换为字符串，属性按名称排序以保证输出稳定 / Convert the style to a string, sorted by name for stable output
 func (s *Style) ToString() string {
+	names := make([]string, 0, len(s.properties))
+	for name := range s.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	var parts []string
-	for name, value := range s.properties {
-		parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %s", name, s.properties[name]))
 	}
 	return strings.Join(parts, "; ")
 }
@@ -209,6 +253,51 @@ func NewTransform() *Transform {
 	}
 }
 
+// ParseTransform 将transform属性解析为变换操作列表，无法识别的操作被忽略
+// ParseTransform parses a transform attribute into a list of operations, ignoring unrecognized ones
+func ParseTransform(value string) *Transform {
+	t := NewTransform()
+	for {
+		open := strings.Index(value, "(")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(value[open:], ")")
+		if end < 0 {
+			break
+		}
+		end += open
+		name := strings.TrimSpace(strings.Trim(strings.TrimSpace(value[:open]), ","))
+		args := strings.Join(strings.Fields(strings.ReplaceAll(value[open+1:end], ",", " ")), ",")
+		operation := name + "(" + args + ")"
+		if parseTransformOperation(operation) != nil {
+			t.operations = append(t.operations, operation)
+		}
+		value = value[end+1:]
+	}
+	return t
+}
+
+// Operations 返回变换操作列表的副本 / Return a copy of the transform's operations
+func (t *Transform) Operations() []string {
+	return append([]string(nil), t.operations...)
+}
+
+// SetOperations 替换变换操作列表，用于重排或批量修改 / Replace the operation list, used to reorder or edit operations
+func (t *Transform) SetOperations(operations []string) *Transform {
+	t.operations = append([]string(nil), operations...)
+	return t
+}
+
+// RemoveOperation 移除指定下标的变换操作，下标越界时返回false / Remove the operation at index i, returning false if out of range
+func (t *Transform) RemoveOperation(i int) bool {
+	if i < 0 || i >= len(t.operations) {
+		return false
+	}
+	t.operations = append(t.operations[:i], t.operations[i+1:]...)
+	return true
+}
+
 // Translate 添加平移变换
 func (t *Transform) Translate(x, y float64) *Transform {
 	t.operations = append(t.operations, fmt.Sprintf("translate(%f,%f)", x, y))
@@ -269,7 +358,8 @@ func (t *Transform) GetMatrix() *Matrix {
 	for _, operation := range t.operations {
 		opMatrix := parseTransformOperation(operation)
 		if opMatrix != nil {
-			result = multiplyMatrices(result, opMatrix)
+			// 列表中靠后的操作先作用于坐标 / Later operations in the list apply to coordinates first
+			result = multiplyMatrices(opMatrix, result)
 		}
 	}
 	
@@ -293,6 +383,8 @@ func parseTransformOperation(operation string) *Matrix {
 		if len(params) >= 2 {
 			x, y := params[0], params[1]
 			return &Matrix{A: 1, B: 0, C: 0, D: 1, E: x, F: y}
+		} else if len(params) == 1 {
+			return &Matrix{A: 1, B: 0, C: 0, D: 1, E: params[0], F: 0}
 		}
 	}
 	
@@ -407,7 +499,7 @@ func multiplyMatrices(m1, m2 *Matrix) *Matrix {
 type Gradient struct {
 	ID       string
 	Stops    []GradientStop
-	GradType string // "linear" 或 "radial"
+	GradType string // "linear"、"radial" 或扩展的 "conic" / "linear", "radial" or the "conic" extension
 	Attrs    map[string]string
 }
 
@@ -453,6 +545,30 @@ func NewRadialGradient(id string, cx, cy, r, fx, fy float64) *Gradient {
 	return g
 }
 
+// ConicExtension 标记以radialGradient近似序列化的锥形渐变的data-svg-extension属性值
+// ConicExtension is the data-svg-extension value marking a conic gradient serialized as a radialGradient approximation
+const ConicExtension = "conic"
+
+// NewConicGradient 创建一个锥形（角度）渐变，颜色随绕(cx,cy)的角度变化
+// NewConicGradient creates a conic (angular) gradient whose color varies with the angle around (cx,cy)
+//
+// 锥形渐变不是SVG标准，序列化时输出为带data-svg-extension="conic"标记的radialGradient近似
+// Conic gradients are not standard SVG; they serialize as a radialGradient approximation marked with data-svg-extension="conic"
+func NewConicGradient(id string, cx, cy float64) *Gradient {
+	g := &Gradient{
+		ID:       id,
+		Stops:    make([]GradientStop, 0),
+		GradType: "conic",
+		Attrs:    make(map[string]string),
+	}
+
+	g.Attrs["cx"] = fmt.Sprintf("%f", cx)
+	g.Attrs["cy"] = fmt.Sprintf("%f", cy)
+	g.Attrs["data-svg-extension"] = ConicExtension
+
+	return g
+}
+
 // AddStop 添加一个渐变停止点
 func (g *Gradient) AddStop(offset float64, c color.Color, opacity float64) {
 	g.Stops = append(g.Stops, GradientStop{
@@ -462,6 +578,243 @@ func (g *Gradient) AddStop(offset float64, c color.Color, opacity float64) {
 	})
 }
 
+// ColorAt 采样渐变在指定偏移处的颜色，同时插值颜色和停止点不透明度
+// ColorAt samples the gradient color at an offset, interpolating both color and stop opacity
+//
+// color-interpolation为linearRGB时在线性光空间中插值，否则在sRGB空间中插值
+// With color-interpolation set to linearRGB the colors are interpolated in linear light, otherwise in sRGB
+//
+// 返回的颜色为非预乘Alpha，偏移超出停止点范围时取端点颜色
+// The returned color uses straight (non-premultiplied) alpha; offsets outside the stops take the end colors
+func (g *Gradient) ColorAt(offset float64) color.RGBA {
+	if len(g.Stops) == 0 {
+		return color.RGBA{0, 0, 0, 0}
+	}
+
+	// 停止点偏移按规范单调不减 / Stop offsets are monotonically non-decreasing per spec
+	stops := make([]GradientStop, len(g.Stops))
+	copy(stops, g.Stops)
+	for i := range stops {
+		stops[i].Offset = math.Max(0, math.Min(1, stops[i].Offset))
+		if i > 0 && stops[i].Offset < stops[i-1].Offset {
+			stops[i].Offset = stops[i-1].Offset
+		}
+	}
+
+	if offset <= stops[0].Offset {
+		return stopColor(stops[0])
+	}
+	last := stops[len(stops)-1]
+	if offset >= last.Offset {
+		return stopColor(last)
+	}
+
+	for i := 1; i < len(stops); i++ {
+		next := stops[i]
+		if offset > next.Offset {
+			continue
+		}
+		prev := stops[i-1]
+		span := next.Offset - prev.Offset
+		if span <= 0 {
+			return stopColor(next)
+		}
+		if g.Attrs["color-interpolation"] == "linearRGB" {
+			return lerpLinearRGB(stopColor(prev), stopColor(next), (offset-prev.Offset)/span)
+		}
+		return lerpPremultiplied(stopColor(prev), stopColor(next), (offset-prev.Offset)/span)
+	}
+
+	return stopColor(last)
+}
+
+// SetSpreadMethod 设置渐变的扩展方式（pad、reflect或repeat） / Set the gradient spread method (pad, reflect or repeat)
+func (g *Gradient) SetSpreadMethod(method string) {
+	g.Attrs["spreadMethod"] = method
+}
+
+// SpreadMethod 获取渐变的扩展方式，默认为pad / Get the gradient spread method, defaults to pad
+func (g *Gradient) SpreadMethod() string {
+	if method, ok := g.Attrs["spreadMethod"]; ok && method != "" {
+		return method
+	}
+	return "pad"
+}
+
+// OffsetAt 计算点在渐变上的投影偏移，结果可能超出[0,1]
+// OffsetAt computes the projected gradient offset of a point, which may fall outside [0,1]
+//
+// 线性渐变投影到(x1,y1)-(x2,y2)向量上，径向渐变使用到圆心的距离与半径之比，锥形渐变使用绕中心的角度
+// Linear gradients project onto the (x1,y1)-(x2,y2) vector; radial gradients use the distance to the center over the radius; conic gradients use the angle around the center
+func (g *Gradient) OffsetAt(x, y float64) float64 {
+	if g.GradType == "conic" {
+		// 角度从正X轴开始，在Y轴向下的坐标系中顺时针增加 / The angle starts at the positive X axis and grows clockwise with Y pointing down
+		angle := math.Atan2(y-g.attrFloat("cy", 0.5), x-g.attrFloat("cx", 0.5))
+		if angle < 0 {
+			angle += 2 * math.Pi
+		}
+		return angle / (2 * math.Pi)
+	}
+	if g.GradType == "radial" {
+		cx := g.attrFloat("cx", 0.5)
+		cy := g.attrFloat("cy", 0.5)
+		r := g.attrFloat("r", 0.5)
+		if r <= 0 {
+			return 1
+		}
+		// 焦点默认与圆心重合 / The focal point defaults to the center
+		fx := g.attrFloat("fx", cx)
+		fy := g.attrFloat("fy", cy)
+		return radialFocalOffset(x, y, cx, cy, r, fx, fy)
+	}
+
+	x1 := g.attrFloat("x1", 0)
+	y1 := g.attrFloat("y1", 0)
+	x2 := g.attrFloat("x2", 1)
+	y2 := g.attrFloat("y2", 0)
+	dx, dy := x2-x1, y2-y1
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return 1
+	}
+	return ((x-x1)*dx + (y-y1)*dy) / lengthSq
+}
+
+// radialFocalOffset 计算带焦点的径向渐变偏移 / Compute the offset of a radial gradient with a focal point
+//
+// 偏移为焦点到采样点的距离与焦点沿同一射线到圆周距离之比；焦点在圆外时按SVG 1.1移到圆内
+// The offset is the distance from the focus to the point divided by the distance from the focus to the circle along the same ray; a focus outside the circle is moved inside per SVG 1.1
+func radialFocalOffset(x, y, cx, cy, r, fx, fy float64) float64 {
+	fdx, fdy := fx-cx, fy-cy
+	if dist := math.Hypot(fdx, fdy); dist > r*0.999 {
+		scale := r * 0.999 / dist
+		fdx, fdy = fdx*scale, fdy*scale
+	}
+
+	dx, dy := x-(cx+fdx), y-(cy+fdy)
+	a := dx*dx + dy*dy
+	if a == 0 {
+		return 0
+	}
+	// 求射线 F + s·d 与圆的交点，s>0 / Intersect the ray F + s·d with the circle, s > 0
+	b := 2 * (fdx*dx + fdy*dy)
+	c := fdx*fdx + fdy*fdy - r*r
+	s := (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+	return 1 / s
+}
+
+// ColorAtPoint 采样点处的渐变颜色，并按spreadMethod处理[0,1]以外的偏移
+// ColorAtPoint samples the gradient color at a point, applying spreadMethod to offsets outside [0,1]
+func (g *Gradient) ColorAtPoint(x, y float64) color.RGBA {
+	return g.ColorAt(applySpreadMethod(g.OffsetAt(x, y), g.SpreadMethod()))
+}
+
+// applySpreadMethod 将偏移映射回[0,1] / Map an offset back into [0,1]
+func applySpreadMethod(offset float64, method string) float64 {
+	switch method {
+	case "repeat":
+		offset -= math.Floor(offset)
+	case "reflect":
+		offset = math.Mod(math.Abs(offset), 2)
+		if offset > 1 {
+			offset = 2 - offset
+		}
+	default: // pad
+		offset = math.Max(0, math.Min(1, offset))
+	}
+	return offset
+}
+
+// attrFloat 读取渐变的数值属性，支持百分比 / Read a numeric gradient attribute, supporting percentages
+func (g *Gradient) attrFloat(name string, defaultValue float64) float64 {
+	value, ok := g.Attrs[name]
+	if !ok {
+		return defaultValue
+	}
+	value = strings.TrimSpace(value)
+	scale := 1.0
+	if strings.HasSuffix(value, "%") {
+		value = strings.TrimSuffix(value, "%")
+		scale = 0.01
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return f * scale
+}
+
+// stopColor 计算停止点的颜色，将不透明度乘入Alpha / Compute a stop's color with its opacity multiplied into alpha
+func stopColor(stop GradientStop) color.RGBA {
+	if stop.Color == nil {
+		return color.RGBA{0, 0, 0, 0}
+	}
+	r, g, b, a := stop.Color.RGBA()
+	opacity := math.Max(0, math.Min(1, stop.Opacity))
+	return color.RGBA{
+		R: uint8(r >> 8),
+		G: uint8(g >> 8),
+		B: uint8(b >> 8),
+		A: uint8(math.Round(float64(a>>8) * opacity)),
+	}
+}
+
+// lerpPremultiplied 在预乘空间中插值两个非预乘颜色 / Interpolate two straight-alpha colors in premultiplied space
+func lerpPremultiplied(c1, c2 color.RGBA, t float64) color.RGBA {
+	a1, a2 := float64(c1.A)/255, float64(c2.A)/255
+	a := a1 + (a2-a1)*t
+	if a <= 0 {
+		return color.RGBA{0, 0, 0, 0}
+	}
+
+	channel := func(v1, v2 uint8) uint8 {
+		p := float64(v1)*a1 + (float64(v2)*a2-float64(v1)*a1)*t
+		return uint8(math.Max(0, math.Min(255, math.Round(p/a))))
+	}
+
+	return color.RGBA{
+		R: channel(c1.R, c2.R),
+		G: channel(c1.G, c2.G),
+		B: channel(c1.B, c2.B),
+		A: uint8(math.Round(a * 255)),
+	}
+}
+
+// lerpLinearRGB 在线性光空间中插值两个非预乘sRGB颜色，结果转换回sRGB / Interpolate two straight-alpha sRGB colors in linear light and convert the result back to sRGB
+func lerpLinearRGB(c1, c2 color.RGBA, t float64) color.RGBA {
+	toLinear := func(v uint8) float64 {
+		c := float64(v) / 255
+		if c <= 0.04045 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	toSRGB := func(c float64) uint8 {
+		if c <= 0.0031308 {
+			c *= 12.92
+		} else {
+			c = 1.055*math.Pow(c, 1/2.4) - 0.055
+		}
+		return uint8(math.Max(0, math.Min(255, math.Round(c*255))))
+	}
+
+	a1, a2 := float64(c1.A)/255, float64(c2.A)/255
+	a := a1 + (a2-a1)*t
+	if a <= 0 {
+		return color.RGBA{0, 0, 0, 0}
+	}
+	channel := func(v1, v2 uint8) uint8 {
+		return toSRGB((toLinear(v1)*a1 + (toLinear(v2)*a2-toLinear(v1)*a1)*t) / a)
+	}
+
+	return color.RGBA{
+		R: channel(c1.R, c2.R),
+		G: channel(c1.G, c2.G),
+		B: channel(c1.B, c2.B),
+		A: uint8(math.Round(a * 255)),
+	}
+}
+
 // ToXML 将渐变转换为XML字符串
 func (g *Gradient) ToXML() string {
 	var sb strings.Builder
@@ -469,6 +822,7 @@ func (g *Gradient) ToXML() string {
 	if g.GradType == "linear" {
 		sb.WriteString(fmt.Sprintf("<linearGradient id=\"%s\"", g.ID))
 	} else {
+		// 锥形渐变以带扩展标记的radialGradient近似输出 / Conic gradients are written as a marked radialGradient approximation
 		sb.WriteString(fmt.Sprintf("<radialGradient id=\"%s\"", g.ID))
 	}
 
@@ -0,0 +1,179 @@
+package attributes
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+// TestParseColorShorthandAlpha 测试带透明度的四位十六进制简写 / Test the four-digit hex shorthand with alpha
+func TestParseColorShorthandAlpha(t *testing.T) {
+	c, err := ParseColor("#f008")
+	if err != nil {
+		t.Fatalf("ParseColor failed: %v", err)
+	}
+	if want := (color.RGBA{255, 0, 0, 136}); c != want {
+		t.Errorf("expected %v, got %v", want, c)
+	}
+}
+
+// TestGradientStopOpacity 测试渐变采样插值停止点不透明度 / Test gradient sampling interpolates stop opacity
+func TestGradientStopOpacity(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	g := NewLinearGradient("fade", 0, 0, 1, 0)
+	g.AddStop(0, red, 1)
+	g.AddStop(1, red, 0)
+
+	prevAlpha := 256
+	for _, offset := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		c := g.ColorAt(offset)
+		if int(c.A) >= prevAlpha {
+			t.Errorf("expected alpha to decrease at offset %v, got %d after %d", offset, c.A, prevAlpha)
+		}
+		if c.A > 0 && c.R != 255 {
+			t.Errorf("expected red channel to stay 255 at offset %v, got %d", offset, c.R)
+		}
+		prevAlpha = int(c.A)
+	}
+
+	if c := g.ColorAt(0); c.A != 255 {
+		t.Errorf("expected opaque start, got alpha %d", c.A)
+	}
+	if c := g.ColorAt(1); c.A != 0 {
+		t.Errorf("expected transparent end, got alpha %d", c.A)
+	}
+	if c := g.ColorAt(0.5); c.A < 126 || c.A > 129 {
+		t.Errorf("expected half alpha at midpoint, got %d", c.A)
+	}
+}
+
+// TestGradientColorAtClamp 测试超出停止点范围时取端点颜色 / Test offsets outside the stops take the end colors
+func TestGradientColorAtClamp(t *testing.T) {
+	g := NewLinearGradient("g", 0, 0, 1, 0)
+	g.AddStop(0.2, color.RGBA{0, 0, 255, 255}, 1)
+	g.AddStop(0.8, color.RGBA{0, 255, 0, 255}, 1)
+
+	if c := g.ColorAt(0); c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected first stop color before range, got %v", c)
+	}
+	if c := g.ColorAt(1); c != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("expected last stop color after range, got %v", c)
+	}
+}
+
+// TestGradientSpreadMethod 测试扩展方式 / Test gradient spread methods
+func TestGradientSpreadMethod(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	// 只覆盖0到10的短渐变 / A short gradient covering only 0 to 10
+	g := NewLinearGradient("short", 0, 0, 10, 0)
+	g.AddStop(0, black, 1)
+	g.AddStop(1, white, 1)
+
+	tests := []struct {
+		method string
+		x      float64
+		want   uint8
+	}{
+		{"pad", 15, 255},
+		{"pad", -5, 0},
+		{"repeat", 12.5, 63},
+		{"repeat", 25, 127},
+		{"reflect", 12.5, 191},
+		{"reflect", -2.5, 63},
+	}
+
+	for _, tt := range tests {
+		g.SetSpreadMethod(tt.method)
+		got := g.ColorAtPoint(tt.x, 0).R
+		if diff := int(got) - int(tt.want); diff < -1 || diff > 1 {
+			t.Errorf("%s at x=%v: got %d, want %d", tt.method, tt.x, got, tt.want)
+		}
+	}
+
+	// repeat时图案应该平铺 / With repeat the pattern should tile
+	g.SetSpreadMethod("repeat")
+	for x := 0.5; x < 10; x += 1 {
+		if a, b := g.ColorAtPoint(x, 0), g.ColorAtPoint(x+30, 0); a != b {
+			t.Errorf("expected tiled color at x=%v and x=%v, got %v and %v", x, x+30, a, b)
+		}
+	}
+}
+
+// TestRadialGradientFocalPoint 测试径向渐变焦点偏移 / Test radial gradient focal point offset
+func TestRadialGradientFocalPoint(t *testing.T) {
+	g := NewRadialGradient("focal", 0.5, 0.5, 0.5, 0.25, 0.5)
+	g.AddStop(0, color.RGBA{255, 255, 255, 255}, 1)
+	g.AddStop(1, color.RGBA{0, 0, 0, 255}, 1)
+
+	brightest, bestX, bestY := -1, 0.0, 0.0
+	for iy := 0; iy <= 40; iy++ {
+		for ix := 0; ix <= 40; ix++ {
+			x, y := float64(ix)/40, float64(iy)/40
+			if c := g.ColorAtPoint(x, y); int(c.R) > brightest {
+				brightest, bestX, bestY = int(c.R), x, y
+			}
+		}
+	}
+	if math.Hypot(bestX-0.25, bestY-0.5) > 0.03 {
+		t.Errorf("expected the brightest sample near the focus (0.25, 0.5), got (%.3f, %.3f)", bestX, bestY)
+	}
+
+	// 圆周上任意点偏移均为1 / Every point on the circle has offset 1
+	for _, p := range [][2]float64{{0, 0.5}, {1, 0.5}, {0.5, 0}, {0.5, 1}} {
+		if offset := g.OffsetAt(p[0], p[1]); math.Abs(offset-1) > 1e-9 {
+			t.Errorf("expected offset 1 on the circle at %v, got %f", p, offset)
+		}
+	}
+	// 与焦点等距时靠近圆周的一侧偏移更大 / At equal distance from the focus, the side nearer the circle has the larger offset
+	if near, far := g.OffsetAt(0.125, 0.5), g.OffsetAt(0.375, 0.5); near <= far {
+		t.Errorf("expected an asymmetric gradient, got near %f far %f", near, far)
+	}
+}
+
+// TestParseTransform 测试从字符串解析变换操作及矩阵 / Test parsing transform operations and their matrix from a string
+func TestParseTransform(t *testing.T) {
+	tr := ParseTransform("translate(10,20) rotate(30)")
+	ops := tr.Operations()
+	if len(ops) != 2 || ops[0] != "translate(10,20)" || ops[1] != "rotate(30)" {
+		t.Fatalf("unexpected operations %q", ops)
+	}
+
+	// 先旋转再平移：平移分量保持不变 / Rotate first, then translate: the translation stays intact
+	cos, sin := math.Cos(math.Pi/6), math.Sin(math.Pi/6)
+	want := Matrix{A: cos, B: sin, C: -sin, D: cos, E: 10, F: 20}
+	m := tr.GetMatrix()
+	for _, pair := range [][2]float64{{m.A, want.A}, {m.B, want.B}, {m.C, want.C}, {m.D, want.D}, {m.E, want.E}, {m.F, want.F}} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Fatalf("expected matrix %+v, got %+v", want, *m)
+		}
+	}
+
+	if !tr.RemoveOperation(1) || tr.ToString() != "translate(10,20)" {
+		t.Errorf("expected only the translation after removal, got %q", tr.ToString())
+	}
+	if ops := ParseTransform(" scale( 2 ) , skewX(10)bogus(1)").Operations(); len(ops) != 2 || ops[0] != "scale(2)" {
+		t.Errorf("unexpected operations for loosely formatted input: %q", ops)
+	}
+}
+
+// TestGradientColorInterpolation 测试黑到白渐变的中点在sRGB和linearRGB中的取值 / Test the midpoint of a black-to-white gradient in sRGB and linearRGB
+func TestGradientColorInterpolation(t *testing.T) {
+	g := NewLinearGradient("fade", 0, 0, 1, 0)
+	g.AddStop(0, color.RGBA{0, 0, 0, 255}, 1)
+	g.AddStop(1, color.RGBA{255, 255, 255, 255}, 1)
+
+	if mid := g.ColorAt(0.5); mid.R < 127 || mid.R > 128 {
+		t.Errorf("expected an sRGB midpoint near 127, got %d", mid.R)
+	}
+
+	// 线性光的一半亮度在sRGB中约为188 / Half the linear light is about 188 in sRGB
+	g.Attrs["color-interpolation"] = "linearRGB"
+	if mid := g.ColorAt(0.5); mid.R < 187 || mid.R > 189 || mid.A != 255 {
+		t.Errorf("expected a linearRGB midpoint near 188, got %v", mid)
+	}
+	if end := g.ColorAt(1); end.R != 255 {
+		t.Errorf("expected the end stop to stay white, got %v", end)
+	}
+}
@@ -0,0 +1,174 @@
+package svg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	. "github.com/hoonfeng/svg/types"
+)
+
+// ChangeKind 结构差异的类型 / Kind of structural difference
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"    // 元素只存在于新文档 / The element only exists in the new document
+	ChangeRemoved  ChangeKind = "removed"  // 元素只存在于旧文档 / The element only exists in the old document
+	ChangeModified ChangeKind = "modified" // 元素属性或文本不同 / The element's attributes or text differ
+)
+
+// AttributeChange 单个属性的新旧值，缺失的属性值为空 / Old and new values of one attribute; a missing attribute has an empty value
+type AttributeChange struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// Change 描述一个元素的结构差异 / Describes the structural difference of one element
+type Change struct {
+	Kind       ChangeKind
+	Path       string            // 元素位置，如"g#layer/rect[2]"，根元素为"svg" / Element location such as "g#layer/rect[2]"; the root is "svg"
+	Tag        string            // 元素标签 / Element tag
+	ID         string            // 元素ID，可为空 / Element ID, possibly empty
+	Attributes []AttributeChange // 按名称排序的属性差异，仅用于修改 / Attribute differences sorted by name, for modifications only
+}
+
+// String 返回便于日志输出的描述 / Return a description suitable for logs
+func (c Change) String() string {
+	if c.Kind != ChangeModified {
+		return fmt.Sprintf("%s %s", c.Kind, c.Path)
+	}
+	parts := make([]string, len(c.Attributes))
+	for i, attr := range c.Attributes {
+		parts[i] = fmt.Sprintf("%s %q -> %q", attr.Name, attr.Old, attr.New)
+	}
+	return fmt.Sprintf("%s %s: %s", c.Kind, c.Path, strings.Join(parts, "; "))
+}
+
+// textAttribute 文本内容差异使用的伪属性名 / Pseudo attribute name used for text content differences
+const textAttribute = "#text"
+
+// Diff 比较两个文档的结构，返回新增、删除和属性改变的元素；未改变时返回空列表
+// Diff compares the structure of two documents and returns added, removed and attribute-changed elements; it returns an empty list when nothing changed
+//
+// 同级元素优先按ID匹配，其余按相同标签的出现顺序匹配；文本内容的差异以"#text"属性报告
+// Siblings match by ID first and the rest by order of appearance among the same tag; text content differences are reported as a "#text" attribute
+func (s *SVG) Diff(other *SVG) []Change {
+	changes := make([]Change, 0)
+	root := map[string][2]string{
+		"width":   {s.doc.Width, other.doc.Width},
+		"height":  {s.doc.Height, other.doc.Height},
+		"viewBox": {s.doc.ViewBox, other.doc.ViewBox},
+	}
+	for name, value := range s.doc.Attributes {
+		root[name] = [2]string{value, other.doc.Attributes[name]}
+	}
+	for name, value := range other.doc.Attributes {
+		root[name] = [2]string{s.doc.Attributes[name], value}
+	}
+	if attrs := changedValues(root); len(attrs) > 0 {
+		changes = append(changes, Change{Kind: ChangeModified, Path: "svg", Tag: "svg", Attributes: attrs})
+	}
+
+	changes = diffElements(changes, "defs/", s.doc.Defs, other.doc.Defs)
+	return diffElements(changes, "", s.doc.Elements, other.doc.Elements)
+}
+
+// diffElements 比较两个同级元素列表并递归比较匹配的元素 / Compare two sibling lists, recursing into matched elements
+func diffElements(changes []Change, prefix string, before, after []Element) []Change {
+	matched := make([]Element, len(before))
+	used := make([]bool, len(after))
+
+	// 先按ID匹配，重复的ID按出现顺序一一对应 / Match by ID first, pairing repeated IDs in order of appearance
+	byID := make(map[string][]int)
+	for j, el := range after {
+		if id := el.ID(); id != "" {
+			byID[id] = append(byID[id], j)
+		}
+	}
+	for i, el := range before {
+		if el.ID() == "" {
+			continue
+		}
+		for _, j := range byID[el.ID()] {
+			if !used[j] && after[j].Tag() == el.Tag() {
+				matched[i], used[j] = after[j], true
+				break
+			}
+		}
+	}
+	// 其余按相同标签的出现顺序匹配 / Match the rest by order of appearance among the same tag
+	for i, el := range before {
+		if matched[i] != nil || el.ID() != "" {
+			continue
+		}
+		for j, candidate := range after {
+			if !used[j] && candidate.ID() == "" && candidate.Tag() == el.Tag() {
+				matched[i], used[j] = candidate, true
+				break
+			}
+		}
+	}
+
+	for i, el := range before {
+		location := prefix + elementLocation(el, before, i)
+		if matched[i] == nil {
+			changes = append(changes, Change{Kind: ChangeRemoved, Path: location, Tag: el.Tag(), ID: el.ID()})
+			continue
+		}
+		if attrs := attributeChanges(el, matched[i]); len(attrs) > 0 {
+			changes = append(changes, Change{Kind: ChangeModified, Path: location, Tag: el.Tag(), ID: el.ID(), Attributes: attrs})
+		}
+		changes = diffElements(changes, location+"/", el.Children(), matched[i].Children())
+	}
+	for j, el := range after {
+		if !used[j] {
+			changes = append(changes, Change{Kind: ChangeAdded, Path: prefix + elementLocation(el, after, j), Tag: el.Tag(), ID: el.ID()})
+		}
+	}
+	return changes
+}
+
+// elementLocation 返回元素在同级中的位置，有ID时为"tag#id"，否则为"tag[n]"，n为同标签元素的序号
+// elementLocation returns an element's location among its siblings: "tag#id" with an ID, otherwise "tag[n]" with n its index among same-tag siblings
+func elementLocation(el Element, siblings []Element, index int) string {
+	if id := el.ID(); id != "" {
+		return el.Tag() + "#" + id
+	}
+	n := 0
+	for _, sibling := range siblings[:index] {
+		if sibling.Tag() == el.Tag() {
+			n++
+		}
+	}
+	return fmt.Sprintf("%s[%d]", el.Tag(), n)
+}
+
+// attributeChanges 比较两个元素的属性和文本内容 / Compare two elements' attributes and text content
+func attributeChanges(before, after Element) []AttributeChange {
+	values := make(map[string][2]string)
+	for name, value := range before.GetAttributes() {
+		values[name] = [2]string{value, after.GetAttributes()[name]}
+	}
+	for name, value := range after.GetAttributes() {
+		values[name] = [2]string{before.GetAttributes()[name], value}
+	}
+	oldText, oldOK := before.(interface{ GetContent() string })
+	newText, newOK := after.(interface{ GetContent() string })
+	if oldOK && newOK {
+		values[textAttribute] = [2]string{oldText.GetContent(), newText.GetContent()}
+	}
+	return changedValues(values)
+}
+
+// changedValues 返回新旧值不同的条目，按名称排序 / Return the entries whose before and after values differ, sorted by name
+func changedValues(values map[string][2]string) []AttributeChange {
+	changes := make([]AttributeChange, 0)
+	for name, value := range values {
+		if value[0] != value[1] {
+			changes = append(changes, AttributeChange{Name: name, Old: value[0], New: value[1]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
@@ -0,0 +1,141 @@
+package elements
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// Bounds 计算元素在用户空间的几何边界框，不含描边宽度和变换
+// Bounds computes an element's user-space geometry bounding box, excluding stroke width and transforms
+//
+// 无法确定边界的元素（如文本）返回false / Returns false for elements whose bounds cannot be determined, such as text
+func Bounds(el types.Element) (types.Rect, bool) {
+	attrs := el.GetAttributes()
+	// 带单位的长度按默认DPI换算为用户单位，百分比无法解析时取0 / Lengths with units resolve to user units at the default DPI; percentages cannot be resolved and count as 0
+	num := func(name string) float64 {
+		length, err := types.ParseLength(attrs[name])
+		if err != nil || length.Unit == types.UnitPercent {
+			return 0
+		}
+		return length.Resolve(0, types.DefaultDPI)
+	}
+
+	switch el.Tag() {
+	case "rect", "image", "use":
+		return types.Rect{X: num("x"), Y: num("y"), W: num("width"), H: num("height")}, true
+	case "circle":
+		r := num("r")
+		return types.Rect{X: num("cx") - r, Y: num("cy") - r, W: 2 * r, H: 2 * r}, true
+	case "ellipse":
+		rx, ry := num("rx"), num("ry")
+		return types.Rect{X: num("cx") - rx, Y: num("cy") - ry, W: 2 * rx, H: 2 * ry}, true
+	case "line":
+		return pointsRect([]types.Point{{X: num("x1"), Y: num("y1")}, {X: num("x2"), Y: num("y2")}})
+	case "polyline", "polygon":
+		return pointsRect(parsePointList(attrs["points"]))
+	case "path":
+		parsed, err := path.ParsePath(attrs["d"])
+		if err != nil {
+			return types.Rect{}, false
+		}
+		return pointsRect(parsed.FlattenPath(0.1))
+	case "g", "svg":
+		// 组的边界为子元素边界的并集 / A group's bounds are the union of its children's
+		var result types.Rect
+		found := false
+		for _, child := range el.Children() {
+			b, ok := Bounds(child)
+			if !ok {
+				continue
+			}
+			if !found {
+				result, found = b, true
+				continue
+			}
+			minX, minY := math.Min(result.X, b.X), math.Min(result.Y, b.Y)
+			maxX, maxY := math.Max(result.X+result.W, b.X+b.W), math.Max(result.Y+result.H, b.Y+b.H)
+			result = types.Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+		}
+		return result, found
+	}
+	return types.Rect{}, false
+}
+
+// TransformedBounds 计算元素在父坐标系中的边界框，计入元素自身及其子元素的transform属性
+// TransformedBounds computes an element's bounding box in its parent's coordinate system, honoring the transform attribute of the element and its descendants
+//
+// 旋转或斜切后的边界取变换后四角的外接矩形 / After a rotation or skew the bounds are the box enclosing the transformed corners
+func TransformedBounds(el types.Element) (types.Rect, bool) {
+	var local types.Rect
+	found := false
+	switch el.Tag() {
+	case "g", "svg":
+		// 直接累计最小最大值，零宽或零高的子元素也计入 / Accumulate min/max directly so zero-width or zero-height children count too
+		var minX, minY, maxX, maxY float64
+		for _, child := range el.Children() {
+			b, ok := TransformedBounds(child)
+			if !ok {
+				continue
+			}
+			if !found {
+				minX, minY, maxX, maxY, found = b.X, b.Y, b.MaxX(), b.MaxY(), true
+				continue
+			}
+			minX, minY = math.Min(minX, b.X), math.Min(minY, b.Y)
+			maxX, maxY = math.Max(maxX, b.MaxX()), math.Max(maxY, b.MaxY())
+		}
+		local = types.Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+	default:
+		local, found = Bounds(el)
+	}
+	if !found {
+		return types.Rect{}, false
+	}
+
+	value, ok := el.GetAttribute("transform")
+	if !ok || strings.TrimSpace(value) == "" {
+		return local, true
+	}
+	m := attributes.ParseTransform(value).GetMatrix()
+	corners := []types.Point{
+		{X: local.X, Y: local.Y}, {X: local.MaxX(), Y: local.Y},
+		{X: local.MaxX(), Y: local.MaxY()}, {X: local.X, Y: local.MaxY()},
+	}
+	for i, c := range corners {
+		corners[i] = types.Point{X: m.A*c.X + m.C*c.Y + m.E, Y: m.B*c.X + m.D*c.Y + m.F}
+	}
+	return types.RectFromPoints(corners), true
+}
+
+// TransformedBounds 返回元素含变换的边界框，见包函数TransformedBounds
+// TransformedBounds returns the element's transform-aware bounds; see the package function TransformedBounds
+func (e *BaseElement) TransformedBounds() (types.Rect, bool) {
+	return TransformedBounds(e)
+}
+
+// pointsRect 计算点集的边界框 / Compute the bounding box of a point set
+func pointsRect(points []types.Point) (types.Rect, bool) {
+	if len(points) == 0 {
+		return types.Rect{}, false
+	}
+	return types.RectFromPoints(points), true
+}
+
+// parsePointList 解析points属性 / Parse a points attribute
+func parsePointList(s string) []types.Point {
+	parts := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	points := make([]types.Point, 0, len(parts)/2)
+	for i := 0; i+1 < len(parts); i += 2 {
+		x, errX := strconv.ParseFloat(parts[i], 64)
+		y, errY := strconv.ParseFloat(parts[i+1], 64)
+		if errX == nil && errY == nil {
+			points = append(points, types.Point{X: x, Y: y})
+		}
+	}
+	return points
+}
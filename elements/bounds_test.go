@@ -0,0 +1,54 @@
+package elements
+
+import (
+	"testing"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// TestCircleBounds 测试圆形边界与区域的相交判断 / Test circle bounds against tile rectangles
+func TestCircleBounds(t *testing.T) {
+	bounds, ok := Bounds(NewCircle(50, 40, 10))
+	if !ok {
+		t.Fatal("expected circle bounds")
+	}
+	if bounds != (types.Rect{X: 40, Y: 30, W: 20, H: 20}) {
+		t.Errorf("unexpected circle bounds: %+v", bounds)
+	}
+
+	far := types.Rect{X: 500, Y: 500, W: 64, H: 64}
+	if bounds.Intersects(far) {
+		t.Error("expected a far-away tile not to intersect the circle")
+	}
+	near := types.Rect{X: 0, Y: 0, W: 64, H: 64}
+	if !bounds.Intersects(near) {
+		t.Error("expected the tile covering the circle to intersect it")
+	}
+	if !near.Contains(bounds) {
+		t.Error("expected the tile to contain the circle bounds")
+	}
+	// 仅接触边缘不算相交 / Touching edges do not intersect
+	if bounds.Intersects(types.Rect{X: 60, Y: 30, W: 10, H: 10}) {
+		t.Error("expected an edge-adjacent tile not to intersect")
+	}
+}
+
+// TestGroupBounds 测试组边界为子元素的并集 / Test group bounds are the union of its children
+func TestGroupBounds(t *testing.T) {
+	group := NewGroup()
+	group.AppendChild(NewRect(0, 0, 10, 10))
+	group.AppendChild(NewPath("M 20 5 L 30 25"))
+	group.AppendChild(NewText(0, 0, "ignored"))
+
+	bounds, ok := Bounds(group)
+	if !ok {
+		t.Fatal("expected group bounds")
+	}
+	if bounds != (types.Rect{X: 0, Y: 0, W: 30, H: 25}) {
+		t.Errorf("unexpected group bounds: %+v", bounds)
+	}
+
+	if _, ok := Bounds(NewText(0, 0, "text")); ok {
+		t.Error("expected text bounds to be unknown")
+	}
+}
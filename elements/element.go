@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/types"
 )
 
@@ -54,6 +56,10 @@ func (e *BaseElement) GetAttributes() map[string]string {
 // SetAttribute 设置元素的属性
 func (e *BaseElement) SetAttribute(name, value string) {
 	e.attributes[name] = value
+	// 保持ID与id属性同步 / Keep the ID in sync with the id attribute
+	if name == "id" {
+		e.id = value
+	}
 }
 
 // GetAttribute 获取元素的属性
@@ -70,6 +76,31 @@ func (e *BaseElement) RemoveAttribute(name string) {
 	delete(e.attributes, name)
 }
 
+// Style 返回与style属性绑定的样式，对其修改会立即写回元素 / Return a style bound to the style attribute; changes are written back to the element immediately
+func (e *BaseElement) Style() *attributes.Style {
+	style := e.GetStyle()
+	style.OnChange(e.SetStyle)
+	return style
+}
+
+// GetStyle 将style属性解析为样式，修改返回值不会影响元素 / Parse the style attribute into a Style; changing the result does not affect the element
+func (e *BaseElement) GetStyle() *attributes.Style {
+	return attributes.ParseStyle(e.attributes["style"])
+}
+
+// SetStyle 将样式序列化到style属性，空样式移除该属性 / Serialize a style into the style attribute, removing it for an empty style
+func (e *BaseElement) SetStyle(style *attributes.Style) {
+	css := ""
+	if style != nil {
+		css = style.ToString()
+	}
+	if css == "" {
+		e.RemoveAttribute("style")
+		return
+	}
+	e.SetAttribute("style", css)
+}
+
 // Children 返回元素的子元素
 func (e *BaseElement) Children() []types.Element {
 	return e.children
@@ -161,9 +192,9 @@ func NewCircle(cx, cy, r float64) *Circle {
 	circle := &Circle{
 		BaseElement: NewBaseElement("circle"),
 	}
-	circle.SetAttribute("cx", fmt.Sprintf("%f", cx))
-	circle.SetAttribute("cy", fmt.Sprintf("%f", cy))
-	circle.SetAttribute("r", fmt.Sprintf("%f", r))
+	circle.SetAttribute("cx", path.FormatNumber(cx))
+	circle.SetAttribute("cy", path.FormatNumber(cy))
+	circle.SetAttribute("r", path.FormatNumber(r))
 	return circle
 }
 
@@ -177,10 +208,10 @@ func NewRect(x, y, width, height float64) *Rect {
 	rect := &Rect{
 		BaseElement: NewBaseElement("rect"),
 	}
-	rect.SetAttribute("x", fmt.Sprintf("%f", x))
-	rect.SetAttribute("y", fmt.Sprintf("%f", y))
-	rect.SetAttribute("width", fmt.Sprintf("%f", width))
-	rect.SetAttribute("height", fmt.Sprintf("%f", height))
+	rect.SetAttribute("x", path.FormatNumber(x))
+	rect.SetAttribute("y", path.FormatNumber(y))
+	rect.SetAttribute("width", path.FormatNumber(width))
+	rect.SetAttribute("height", path.FormatNumber(height))
 	return rect
 }
 
@@ -194,10 +225,10 @@ func NewEllipse(cx, cy, rx, ry float64) *Ellipse {
 	ellipse := &Ellipse{
 		BaseElement: NewBaseElement("ellipse"),
 	}
-	ellipse.SetAttribute("cx", fmt.Sprintf("%f", cx))
-	ellipse.SetAttribute("cy", fmt.Sprintf("%f", cy))
-	ellipse.SetAttribute("rx", fmt.Sprintf("%f", rx))
-	ellipse.SetAttribute("ry", fmt.Sprintf("%f", ry))
+	ellipse.SetAttribute("cx", path.FormatNumber(cx))
+	ellipse.SetAttribute("cy", path.FormatNumber(cy))
+	ellipse.SetAttribute("rx", path.FormatNumber(rx))
+	ellipse.SetAttribute("ry", path.FormatNumber(ry))
 	return ellipse
 }
 
@@ -211,10 +242,10 @@ func NewLine(x1, y1, x2, y2 float64) *Line {
 	line := &Line{
 		BaseElement: NewBaseElement("line"),
 	}
-	line.SetAttribute("x1", fmt.Sprintf("%f", x1))
-	line.SetAttribute("y1", fmt.Sprintf("%f", y1))
-	line.SetAttribute("x2", fmt.Sprintf("%f", x2))
-	line.SetAttribute("y2", fmt.Sprintf("%f", y2))
+	line.SetAttribute("x1", path.FormatNumber(x1))
+	line.SetAttribute("y1", path.FormatNumber(y1))
+	line.SetAttribute("x2", path.FormatNumber(x2))
+	line.SetAttribute("y2", path.FormatNumber(y2))
 	return line
 }
 
@@ -234,7 +265,7 @@ func NewPolyline(points []types.Point) *Polyline {
 		if i > 0 {
 			pointsStr += " "
 		}
-		pointsStr += fmt.Sprintf("%f,%f", point.X, point.Y)
+		pointsStr += path.FormatNumber(point.X) + "," + path.FormatNumber(point.Y)
 	}
 
 	polyline.SetAttribute("points", pointsStr)
@@ -257,7 +288,7 @@ func NewPolygon(points []types.Point) *Polygon {
 		if i > 0 {
 			pointsStr += " "
 		}
-		pointsStr += fmt.Sprintf("%f,%f", point.X, point.Y)
+		pointsStr += path.FormatNumber(point.X) + "," + path.FormatNumber(point.Y)
 	}
 
 	polygon.SetAttribute("points", pointsStr)
@@ -290,8 +321,8 @@ func NewText(x, y float64, content string) *Text {
 		BaseElement: NewBaseElement("text"),
 		content:     content,
 	}
-	text.SetAttribute("x", fmt.Sprintf("%f", x))
-	text.SetAttribute("y", fmt.Sprintf("%f", y))
+	text.SetAttribute("x", path.FormatNumber(x))
+	text.SetAttribute("y", path.FormatNumber(y))
 	// 设置默认字体属性
 	text.SetAttribute("font-family", "sans-serif")
 	text.SetAttribute("font-size", "16")
@@ -300,6 +331,12 @@ func NewText(x, y float64, content string) *Text {
 	return text
 }
 
+// Clone 克隆文本元素，保留文本内容 / Clone the text element, keeping its content
+func (t *Text) Clone() types.Element {
+	base := t.BaseElement.Clone().(*BaseElement)
+	return &Text{BaseElement: base, content: t.content}
+}
+
 // SetContent 设置文本内容
 func (t *Text) SetContent(content string) {
 	t.content = content
@@ -317,7 +354,7 @@ func (t *Text) SetFontFamily(fontFamily string) {
 
 // SetFontSize 设置字体大小
 func (t *Text) SetFontSize(fontSize float64) {
-	t.SetAttribute("font-size", fmt.Sprintf("%f", fontSize))
+	t.SetAttribute("font-size", path.FormatNumber(fontSize))
 }
 
 // SetFontWeight 设置字体粗细
@@ -352,7 +389,7 @@ func (t *Text) SetStroke(stroke string) {
 
 // SetStrokeWidth 设置描边宽度
 func (t *Text) SetStrokeWidth(strokeWidth float64) {
-	t.SetAttribute("stroke-width", fmt.Sprintf("%f", strokeWidth))
+	t.SetAttribute("stroke-width", path.FormatNumber(strokeWidth))
 }
 
 // ToXML 重写ToXML方法以包含文本内容
@@ -382,6 +419,66 @@ func (t *Text) ToXML() string {
 	return sb.String()
 }
 
+// TextPath 表示沿路径排列的文本，通常作为text元素的子元素 / A run of text laid out along a path, usually a child of a text element
+type TextPath struct {
+	*Text
+}
+
+// NewTextPath 创建引用指定路径的textPath元素，href形如"#id" / Create a textPath element referencing a path, with href like "#id"
+func NewTextPath(href, content string) *TextPath {
+	textPath := &TextPath{
+		Text: &Text{BaseElement: NewBaseElement("textPath"), content: content},
+	}
+	textPath.SetAttribute("href", href)
+	return textPath
+}
+
+// Clone 克隆textPath元素，保留文本内容 / Clone the textPath element, keeping its content
+func (t *TextPath) Clone() types.Element {
+	return &TextPath{Text: t.Text.Clone().(*Text)}
+}
+
+// SetStartOffset 设置文本在路径上的起始偏移，可为长度或百分比 / Set the start offset along the path, as a length or percentage
+func (t *TextPath) SetStartOffset(offset string) {
+	t.SetAttribute("startOffset", offset)
+}
+
+// Style 表示包含CSS样式表的style元素 / A style element holding a CSS style sheet
+type Style struct {
+	*BaseElement
+	css string
+}
+
+// NewStyle 创建包含指定CSS的style元素 / Create a style element with the given CSS
+func NewStyle(css string) *Style {
+	style := &Style{BaseElement: NewBaseElement("style"), css: css}
+	style.SetAttribute("type", "text/css")
+	return style
+}
+
+// Clone 克隆style元素，保留样式表 / Clone the style element, keeping its style sheet
+func (s *Style) Clone() types.Element {
+	return &Style{BaseElement: s.BaseElement.Clone().(*BaseElement), css: s.css}
+}
+
+// GetCSS 获取样式表 / Get the style sheet
+func (s *Style) GetCSS() string {
+	return s.css
+}
+
+// ToXML 将样式表写入CDATA段，CSS中无需转义 / Write the style sheet as a CDATA section so the CSS needs no escaping
+func (s *Style) ToXML() string {
+	var sb strings.Builder
+	sb.WriteString("<style")
+	for name, value := range s.Attributes() {
+		sb.WriteString(fmt.Sprintf(` %s="%s"`, name, value))
+	}
+	sb.WriteString("><![CDATA[")
+	sb.WriteString(strings.ReplaceAll(s.css, "]]>", "]]]]><![CDATA[>"))
+	sb.WriteString("]]></style>")
+	return sb.String()
+}
+
 // Group 表示SVG组元素
 type Group struct {
 	*BaseElement
@@ -404,10 +501,10 @@ func NewSVG(x, y, width, height float64) *SVG {
 	svg := &SVG{
 		BaseElement: NewBaseElement("svg"),
 	}
-	svg.SetAttribute("x", fmt.Sprintf("%f", x))
-	svg.SetAttribute("y", fmt.Sprintf("%f", y))
-	svg.SetAttribute("width", fmt.Sprintf("%f", width))
-	svg.SetAttribute("height", fmt.Sprintf("%f", height))
+	svg.SetAttribute("x", path.FormatNumber(x))
+	svg.SetAttribute("y", path.FormatNumber(y))
+	svg.SetAttribute("width", path.FormatNumber(width))
+	svg.SetAttribute("height", path.FormatNumber(height))
 	return svg
 }
 
@@ -421,10 +518,10 @@ func NewImage(x, y, width, height float64, href string) *Image {
 	image := &Image{
 		BaseElement: NewBaseElement("image"),
 	}
-	image.SetAttribute("x", fmt.Sprintf("%f", x))
-	image.SetAttribute("y", fmt.Sprintf("%f", y))
-	image.SetAttribute("width", fmt.Sprintf("%f", width))
-	image.SetAttribute("height", fmt.Sprintf("%f", height))
+	image.SetAttribute("x", path.FormatNumber(x))
+	image.SetAttribute("y", path.FormatNumber(y))
+	image.SetAttribute("width", path.FormatNumber(width))
+	image.SetAttribute("height", path.FormatNumber(height))
 	image.SetAttribute("href", href)
 	return image
 }
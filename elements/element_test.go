@@ -0,0 +1,85 @@
+package elements
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// TestGroupChildren 测试组按添加顺序返回子元素，供渲染器递归 / Test a group returns its children in insertion order for the renderer to recurse into
+func TestGroupChildren(t *testing.T) {
+	group := NewGroup()
+	first := NewRect(0, 0, 10, 10)
+	second := NewCircle(5, 5, 3)
+	group.AppendChild(first)
+	group.AppendChild(second)
+
+	var container types.Element = group
+	children := container.Children()
+	if len(children) != 2 || children[0] != first || children[1] != second {
+		t.Fatalf("expected [rect circle] in order, got %v", children)
+	}
+	if first.Parent() == nil || second.Parent() == nil {
+		t.Error("expected appended children to have a parent")
+	}
+
+	group.RemoveChild(first)
+	if children := group.Children(); len(children) != 1 || children[0] != second {
+		t.Errorf("expected only the circle after removing the rect, got %v", children)
+	}
+	if first.Parent() != nil {
+		t.Error("expected the removed child to lose its parent")
+	}
+}
+
+// TestTransformedBounds 测试边界计入元素及祖先组的变换 / Test bounds account for the transforms of an element and its enclosing groups
+func TestTransformedBounds(t *testing.T) {
+	rect := NewRect(0, 0, 10, 20)
+	rect.SetAttribute("transform", "scale(2)")
+	group := NewGroup()
+	group.SetAttribute("transform", "translate(5, 5)")
+	group.AppendChild(rect)
+
+	b, ok := TransformedBounds(group)
+	if !ok || b != (types.Rect{X: 5, Y: 5, W: 20, H: 40}) {
+		t.Errorf("expected {5 5 20 40}, got %v (ok=%v)", b, ok)
+	}
+
+	// 旋转90度后宽高互换 / A 90 degree rotation swaps width and height
+	rect.SetAttribute("transform", "rotate(90)")
+	b, _ = rect.TransformedBounds()
+	if math.Abs(b.X+20) > 1e-9 || math.Abs(b.Y) > 1e-9 || math.Abs(b.W-20) > 1e-9 || math.Abs(b.H-10) > 1e-9 {
+		t.Errorf("expected {-20 0 20 10} after rotation, got %v", b)
+	}
+}
+
+// TestTransformedBoundsZeroHeight 测试组的边界计入水平线等零高子元素 / Test a group's bounds include zero-height children such as a horizontal line
+func TestTransformedBoundsZeroHeight(t *testing.T) {
+	group := NewGroup()
+	group.AppendChild(NewRect(40, 40, 10, 10))
+	group.AppendChild(NewLine(0, 45, 20, 45))
+
+	b, ok := TransformedBounds(group)
+	if !ok || b != (types.Rect{X: 0, Y: 40, W: 50, H: 10}) {
+		t.Errorf("expected {0 40 50 10}, got %v (ok=%v)", b, ok)
+	}
+}
+
+// TestConstructorPrecision 测试构造函数按path.Precision格式化数值 / Test constructors format numbers per path.Precision
+func TestConstructorPrecision(t *testing.T) {
+	defer func(old int) { path.Precision = old }(path.Precision)
+
+	path.Precision = 1
+	attrs := NewCircle(10.25, 5, 2.04).GetAttributes()
+	if got := attrs["cx"] + " " + attrs["cy"] + " " + attrs["r"]; got != "10.2 5 2" {
+		t.Errorf("expected \"10.2 5 2\", got %q", got)
+	}
+
+	path.Precision = -1
+	polygon := NewPolygon([]types.Point{{X: 0.5, Y: 1}, {X: 3, Y: 4.125}})
+	if got := polygon.GetAttributes()["points"]; got != "0.5,1 3,4.125" {
+		t.Errorf("expected \"0.5,1 3,4.125\", got %q", got)
+	}
+}
@@ -0,0 +1,159 @@
+package font
+
+import (
+	"image"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// fallbackFace 按字符回退的字体面：每个字符使用列表中第一个含有该字形的字体面，都没有时使用主字体面
+// fallbackFace falls back per rune: each rune uses the first face in the list that has its glyph, or the primary face when none does
+//
+// 度量取自主字体面；彩色位图字形（sbix/CBDT）没有轮廓，按字体面能提供的单色字形绘制
+// Metrics come from the primary face; color bitmap glyphs (sbix/CBDT) have no outlines and draw as whatever monochrome glyph the face provides
+type fallbackFace struct {
+	faces []font.Face   // 按优先级排列，首个为主字体面 / In priority order, the first being the primary face
+	locks []*lockedFace // 按创建顺序排列以固定加锁顺序 / In creation order so locks are always taken in the same order
+}
+
+// newFallbackFace 创建按字符回退的字体面，faces不得重复 / Create a per-rune fallback face; faces must not repeat
+func newFallbackFace(faces []font.Face) *fallbackFace {
+	f := &fallbackFace{faces: faces}
+	for _, face := range faces {
+		if locked, ok := face.(*lockedFace); ok {
+			f.locks = append(f.locks, locked)
+		}
+	}
+	sort.Slice(f.locks, func(i, j int) bool { return f.locks[i].order < f.locks[j].order })
+	return f
+}
+
+// hasGlyph 判断字体面是否含有字符的字形，字形索引0视为缺失 / Report whether a face has a glyph for the rune, treating glyph index 0 as missing
+func hasGlyph(face font.Face, r rune) bool {
+	if locked, ok := face.(*lockedFace); ok && locked.outlines != nil {
+		index, err := locked.outlines.GlyphIndex(&sfnt.Buffer{}, r)
+		return err == nil && index != 0
+	}
+	_, ok := face.GlyphAdvance(r)
+	return ok
+}
+
+// pick 返回绘制字符使用的字体面 / Return the face used to draw the rune
+func (f *fallbackFace) pick(r rune) font.Face {
+	for _, face := range f.faces {
+		if hasGlyph(face, r) {
+			return face
+		}
+	}
+	return f.faces[0]
+}
+
+// Close 字体面由渲染器缓存共享，不在此关闭 / The faces are shared through the renderer's cache and are not closed here
+func (f *fallbackFace) Close() error {
+	return nil
+}
+
+// Glyph 使用含有该字形的字体面绘制字符 / Draw the rune with the face that has its glyph
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.pick(r).Glyph(dot, r)
+}
+
+// GlyphBounds 返回所选字体面中字形的边界 / Return the glyph's bounds in the chosen face
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.pick(r).GlyphBounds(r)
+}
+
+// GlyphAdvance 返回所选字体面中字形的步进 / Return the glyph's advance in the chosen face
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.pick(r).GlyphAdvance(r)
+}
+
+// Kern 两个字符来自同一字体面时使用其字偶距，否则为0 / Use the kerning of the shared face when both runes come from it, otherwise 0
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := f.pick(r0)
+	if face != f.pick(r1) {
+		return 0
+	}
+	return face.Kern(r0, r1)
+}
+
+// Metrics 返回主字体面的度量 / Return the primary face's metrics
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
+
+// fontFamilies 将font-family拆分为去掉引号的字体族列表 / Split font-family into a list of unquoted family names
+func fontFamilies(value string) []string {
+	var families []string
+	for _, family := range strings.Split(value, ",") {
+		family = strings.Trim(strings.TrimSpace(family), `'"`)
+		if family != "" {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+// SetEmojiFont 设置表情字体族，作为font-family列表之后每个字符的最后回退，空字符串取消
+// SetEmojiFont sets the emoji font family used as the last per-rune fallback after the font-family list; an empty string removes it
+func (r *SVGTextRenderer) SetEmojiFont(family string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emojiFamily = family
+	// 回退字体改变已测量文本的步进 / The fallback changes the advance of text already measured
+	r.measureCache = make(map[string]FontMetrics)
+}
+
+// loadFaces 加载文本样式的字体面，font-family列表中的其余字体族和表情字体作为按字符的回退
+// loadFaces loads the face for a text style, with the remaining families of the font-family list and the emoji font as per-rune fallbacks
+//
+// 找不到的字体族被跳过，都找不到时主字体面为基础位图字体 / Families that cannot be found are skipped; when none is found the primary face is the basic bitmap font
+func (r *SVGTextRenderer) loadFaces(style *TextStyle) (font.Face, error) {
+	families := fontFamilies(style.FontFamily)
+	r.mu.RLock()
+	emoji := r.emojiFamily
+	r.mu.RUnlock()
+	if emoji == "" && len(families) <= 1 {
+		return r.loadFont(style.FontFamily, style.FontSize, style.FontWeight, style.FontStyle)
+	}
+
+	var faces []font.Face
+	add := func(family string) error {
+		face, err := r.loadFont(family, style.FontSize, style.FontWeight, style.FontStyle)
+		if err != nil {
+			return err
+		}
+		if face == font.Face(basicfont.Face7x13) {
+			return nil
+		}
+		for _, existing := range faces {
+			if existing == face {
+				return nil
+			}
+		}
+		faces = append(faces, face)
+		return nil
+	}
+	for _, family := range families {
+		if err := add(family); err != nil {
+			return nil, err
+		}
+	}
+	if len(faces) == 0 {
+		faces = append(faces, basicfont.Face7x13)
+	}
+	if emoji != "" {
+		if err := add(emoji); err != nil {
+			return nil, err
+		}
+	}
+	if len(faces) == 1 {
+		return faces[0], nil
+	}
+	return newFallbackFace(faces), nil
+}
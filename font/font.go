@@ -10,10 +10,17 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/golang/freetype/truetype"
+	"github.com/hoonfeng/svg/path"
+	xdraw "golang.org/x/image/draw"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/f64"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -37,12 +44,61 @@ const (
 	AlignmentBaselineBottom     AlignmentBaseline = "bottom"
 )
 
+// DominantBaseline 定义主基线类型 / Dominant baseline type definition
+type DominantBaseline string
+
+const (
+	DominantBaselineAuto       DominantBaseline = "auto"        // 字母基线 / Alphabetic baseline
+	DominantBaselineCentral    DominantBaseline = "central"     // 居中 / Centered on the em box
+	DominantBaselineMiddle     DominantBaseline = "middle"      // 居中 / Middle
+	DominantBaselineHanging    DominantBaseline = "hanging"     // 悬挂基线 / Hanging baseline
+	DominantBaselineTextTop    DominantBaseline = "text-top"    // 文本顶部 / Top of the text
+	DominantBaselineTextBottom DominantBaseline = "text-bottom" // 文本底部 / Bottom of the text
+)
+
+// LengthAdjust 定义textLength的调整方式 / How text is adjusted to textLength
+type LengthAdjust string
+
+const (
+	LengthAdjustSpacing          LengthAdjust = "spacing"          // 仅调整字符间距 / Adjust inter-glyph spacing only
+	LengthAdjustSpacingAndGlyphs LengthAdjust = "spacingAndGlyphs" // 同时缩放字形宽度 / Also scale glyph widths
+)
+
+// FontKerning 定义字偶距的应用方式 / Whether kerning pairs are applied
+type FontKerning string
+
+const (
+	FontKerningAuto   FontKerning = "auto"   // 使用字体的字偶距表 / Use the font's kerning table
+	FontKerningNormal FontKerning = "normal" // 使用字体的字偶距表 / Use the font's kerning table
+	FontKerningNone   FontKerning = "none"   // 不应用字偶距 / Do not apply kerning
+)
+
 // FontMetrics 字体度量信息
 type FontMetrics struct {
 	Ascent  float64 // 上升高度
 	Descent float64 // 下降高度
 	Height  float64 // 总高度
 	Advance float64 // 字符前进宽度
+	LineGap float64 // 行间距，取自hhea或OS/2表 / Line gap from the hhea or OS/2 table
+}
+
+// BaselineOffset 返回指定基线相对字母基线的Y偏移（Y轴向下，上方为负）
+// BaselineOffset returns the Y offset of a baseline relative to the alphabetic baseline (Y points down, so baselines above it are negative)
+//
+// top和bottom为上升和下降边界，middle为两者中点，hanging按常见浏览器取上升高度的80%
+// top and bottom are the ascent and descent edges, middle lies halfway between them, and hanging is 80% of the ascent as in common browsers
+func (m *FontMetrics) BaselineOffset(baseline AlignmentBaseline) float64 {
+	switch baseline {
+	case AlignmentBaselineTop:
+		return -m.Ascent
+	case AlignmentBaselineHanging:
+		return -0.8 * m.Ascent
+	case AlignmentBaselineMiddle:
+		return -(m.Ascent - m.Descent) / 2
+	case AlignmentBaselineBottom:
+		return m.Descent
+	}
+	return 0
 }
 
 // FontWeight 定义字体粗细类型 / Font weight type definition
@@ -85,12 +141,17 @@ type TextStyle struct {
 	FontStyle         FontStyle         // 字体样式 / Font style
 	TextAnchor        TextAnchor        // 文本锚点 / Text anchor
 	AlignmentBaseline AlignmentBaseline // 基线对齐 / Alignment baseline
+	DominantBaseline  DominantBaseline  // 主基线 / Dominant baseline
 	Fill              image.Image       // 填充颜色 / Fill color
 	Stroke            image.Image       // 描边颜色 / Stroke color
 	StrokeWidth       float64           // 描边宽度 / Stroke width
+	StrokeFirst       bool              // 先描边后填充（paint-order）/ Paint the stroke before the fill (paint-order)
 	LetterSpacing     float64           // 字符间距 / Letter spacing
 	WordSpacing       float64           // 单词间距 / Word spacing
 	TextDecoration    string            // 文本装饰 / Text decoration (underline, overline, line-through)
+	TextLength        float64           // 目标文本宽度，0表示自然宽度 / Target text width, 0 for the natural width
+	LengthAdjust      LengthAdjust      // 文本宽度调整方式 / Text length adjustment
+	FontKerning       FontKerning       // 字偶距，空值等同auto / Kerning, empty means auto
 }
 
 // TextRenderer 是文本渲染器接口
@@ -107,15 +168,117 @@ type TextRenderer interface {
 
 // SVGTextRenderer 是符合SVG标准的文本渲染器实现
 type SVGTextRenderer struct {
-	fontCache map[string]font.Face // 字体缓存
-	fontPaths []string             // 字体搜索路径
+	fontCache    map[string]font.Face   // 字体缓存
+	fontPaths    []string               // 字体搜索路径
+	measureCache map[string]FontMetrics // 文本测量缓存，键为文本与规范化样式 / Measurement cache keyed by text and normalized style
+	emojiFamily  string                 // 按字符回退的表情字体族 / Emoji font family used as a per-rune fallback
+	mu           sync.RWMutex           // 保护上述缓存、路径和表情字体 / Guards the caches, paths and emoji font above
+}
+
+// lockedFace 带互斥锁的可缩放字体面，truetype和opentype字体面均不可并发使用 / A scalable face with its own lock, as neither truetype nor opentype faces are safe for concurrent use
+type lockedFace struct {
+	font.Face
+	mu sync.Mutex
+	// vertical 从hhea/OS/2表读取的纵向度量，不可用时为nil / Vertical metrics read from the hhea/OS/2 tables, nil when unavailable
+	vertical *font.Metrics
+	// outlines 用于提取字形轮廓的字体，ppem为对应的每em像素数 / The font used to extract glyph outlines, at ppem pixels per em
+	outlines *sfnt.Font
+	ppem     fixed.Int26_6
+	// order 创建序号，回退字体面按此顺序加锁以避免死锁 / Creation sequence; fallback faces lock in this order to avoid deadlock
+	order uint64
+}
+
+// faceOrder 字体面创建计数 / Counter of created faces
+var faceOrder uint64
+
+// isCFFFont 判断字体数据是否为CFF轮廓的OpenType字体（.otf） / Report whether the font data is a CFF-flavored OpenType font (.otf)
+func isCFFFont(fontBytes []byte) bool {
+	return len(fontBytes) >= 4 && string(fontBytes[:4]) == "OTTO"
+}
+
+// newLockedFace 按字体格式创建字体面：CFF字体使用opentype，其余使用truetype，并从字体数据读取真实的上升、下降和行间距
+// newLockedFace creates a face for the font's flavor, opentype for CFF fonts and truetype otherwise, and reads the true ascent, descent and line gap from the font data
+func newLockedFace(fontBytes []byte, options *truetype.Options) (*lockedFace, error) {
+	face := &lockedFace{order: atomic.AddUint64(&faceOrder, 1)}
+	if isCFFFont(fontBytes) {
+		parsed, err := opentype.Parse(fontBytes)
+		if err != nil {
+			return nil, err
+		}
+		otFace, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: options.Size, DPI: options.DPI, Hinting: options.Hinting})
+		if err != nil {
+			return nil, err
+		}
+		face.Face = otFace
+	} else {
+		tt, err := truetype.Parse(fontBytes)
+		if err != nil {
+			return nil, err
+		}
+		face.Face = truetype.NewFace(tt, options)
+	}
+	if parsed, err := sfnt.Parse(fontBytes); err == nil {
+		face.outlines = parsed
+		face.ppem = fixed.Int26_6(math.Round(options.Size * options.DPI / 72 * 64))
+		if metrics, err := parsed.Metrics(&sfnt.Buffer{}, face.ppem, font.HintingNone); err == nil {
+			face.vertical = &metrics
+		}
+	}
+	return face, nil
+}
+
+// faceMetrics 返回字体面的度量，优先使用表中读取的纵向度量 / Return a face's metrics, preferring the vertical metrics read from its tables
+func faceMetrics(face font.Face) *FontMetrics {
+	if plain, ok := face.(noKerningFace); ok {
+		face = plain.Face
+	}
+	if fallback, ok := face.(*fallbackFace); ok {
+		face = fallback.faces[0]
+	}
+	fontMetrics := face.Metrics()
+	metrics := &FontMetrics{
+		Ascent:  float64(fontMetrics.Ascent) / 64.0,
+		Descent: float64(fontMetrics.Descent) / 64.0,
+		Height:  float64(fontMetrics.Height) / 64.0,
+	}
+	if locked, ok := face.(*lockedFace); ok && locked.vertical != nil {
+		metrics.Ascent = float64(locked.vertical.Ascent) / 64.0
+		metrics.Descent = float64(locked.vertical.Descent) / 64.0
+		metrics.LineGap = math.Max(0, float64(locked.vertical.Height)/64.0-metrics.Ascent-metrics.Descent)
+	} else {
+		metrics.LineGap = math.Max(0, metrics.Height-metrics.Ascent-metrics.Descent)
+	}
+	return metrics
+}
+
+// lockFace 在使用字体面期间加锁并返回解锁函数，不可变的位图字体无需加锁
+// lockFace locks a face for the duration of its use and returns the unlock function; immutable bitmap faces need no lock
+//
+// 回退字体面按创建顺序锁住其中的每个字体面 / A fallback face locks each of its faces in creation order
+func lockFace(face font.Face) func() {
+	switch face := face.(type) {
+	case *lockedFace:
+		face.mu.Lock()
+		return face.mu.Unlock
+	case *fallbackFace:
+		for _, locked := range face.locks {
+			locked.mu.Lock()
+		}
+		return func() {
+			for i := len(face.locks) - 1; i >= 0; i-- {
+				face.locks[i].mu.Unlock()
+			}
+		}
+	}
+	return func() {}
 }
 
 // NewSVGTextRenderer 创建新的SVG文本渲染器 / Create a new SVG text renderer
 func NewSVGTextRenderer() *SVGTextRenderer {
 	return &SVGTextRenderer{
-		fontCache: make(map[string]font.Face),
-		fontPaths: getSystemFontPaths(),
+		fontCache:    make(map[string]font.Face),
+		fontPaths:    getSystemFontPaths(),
+		measureCache: make(map[string]FontMetrics),
 	}
 }
 
@@ -123,8 +286,9 @@ func NewSVGTextRenderer() *SVGTextRenderer {
 func NewSVGTextRendererWithFonts(customFontPaths []string) *SVGTextRenderer {
 	allPaths := append(customFontPaths, getSystemFontPaths()...)
 	return &SVGTextRenderer{
-		fontCache: make(map[string]font.Face),
-		fontPaths: allPaths,
+		fontCache:    make(map[string]font.Face),
+		fontPaths:    allPaths,
+		measureCache: make(map[string]FontMetrics),
 	}
 }
 
@@ -208,7 +372,10 @@ func (r *SVGTextRenderer) loadFont(fontFamily string, fontSize float64, fontWeig
 	cacheKey := fmt.Sprintf("%s-%.1f-%s-%s", fontFamily, fontSize, normalizedWeight, fontStyle)
 
 	// 检查缓存 / Check cache
-	if face, exists := r.fontCache[cacheKey]; exists {
+	r.mu.RLock()
+	face, exists := r.fontCache[cacheKey]
+	r.mu.RUnlock()
+	if exists {
 		return face, nil
 	}
 
@@ -235,12 +402,6 @@ func (r *SVGTextRenderer) loadFont(fontFamily string, fontSize float64, fontWeig
 		return basicfont.Face7x13, nil // 回退到基础字体 / Fallback to basic font
 	}
 
-	// 解析TrueType字体 / Parse TrueType font
-	tt, err := truetype.Parse(fontBytes)
-	if err != nil {
-		return basicfont.Face7x13, nil // 回退到基础字体 / Fallback to basic font
-	}
-
 	// 创建字体选项 / Create font options
 	options := &truetype.Options{
 		Size:    fontSize,
@@ -267,10 +428,19 @@ func (r *SVGTextRenderer) loadFont(fontFamily string, fontSize float64, fontWeig
 		}
 	}
 
-	// 创建字体面 / Create font face
-	face := truetype.NewFace(tt, options)
+	// 按字体格式创建字体面 / Create a font face for the font's flavor
+	loaded, err := newLockedFace(fontBytes, options)
+	if err != nil {
+		return basicfont.Face7x13, nil // 回退到基础字体 / Fallback to basic font
+	}
+	face = loaded
 
-	// 缓存字体面 / Cache font face
+	// 缓存字体面，并发加载时保留先写入的 / Cache font face, keeping the first one stored by concurrent loads
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, exists := r.fontCache[cacheKey]; exists {
+		return cached, nil
+	}
 	r.fontCache[cacheKey] = face
 	return face, nil
 }
@@ -502,7 +672,10 @@ func (r *SVGTextRenderer) findFontFile(fontFamily, fontWeight, fontStyle string)
 	}
 
 	// 在字体路径中搜索 / Search in font paths
-	for _, fontPath := range r.fontPaths {
+	r.mu.RLock()
+	fontPaths := r.fontPaths
+	r.mu.RUnlock()
+	for _, fontPath := range fontPaths {
 		for _, candidate := range candidates {
 			fullPath := filepath.Join(fontPath, candidate)
 			if _, err := os.Stat(fullPath); err == nil {
@@ -516,6 +689,9 @@ func (r *SVGTextRenderer) findFontFile(fontFamily, fontWeight, fontStyle string)
 
 // AddFontPath 添加自定义字体路径 / Add custom font path
 func (r *SVGTextRenderer) AddFontPath(fontPath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	// 检查路径是否已存在 / Check if path already exists
 	for _, existingPath := range r.fontPaths {
 		if existingPath == fontPath {
@@ -538,22 +714,23 @@ func (r *SVGTextRenderer) LoadFontFromFile(fontPath, fontFamily string, fontSize
 		return fmt.Errorf("读取字体文件失败 / Failed to read font file: %v", err)
 	}
 
-	// 解析字体 / Parse font
-	tt, err := truetype.Parse(fontBytes)
-	if err != nil {
-		return fmt.Errorf("解析字体文件失败 / Failed to parse font file: %v", err)
-	}
-
-	// 创建字体面 / Create font face
-	face := truetype.NewFace(tt, &truetype.Options{
+	// 解析TrueType或OpenType/CFF字体并创建字体面 / Parse a TrueType or OpenType/CFF font and create its face
+	face, err := newLockedFace(fontBytes, &truetype.Options{
 		Size:    fontSize,
 		DPI:     72,
 		Hinting: font.HintingFull,
 	})
+	if err != nil {
+		return fmt.Errorf("解析字体文件失败 / Failed to parse font file: %v", err)
+	}
 
 	// 生成缓存键并存储 / Generate cache key and store
 	cacheKey := fmt.Sprintf("%s-%.1f-normal-normal", fontFamily, fontSize)
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.fontCache[cacheKey] = face
+	// 新字体可能替换已测量样式的字体面 / The new face may replace one that was already measured
+	r.measureCache = make(map[string]FontMetrics)
 
 	return nil
 }
@@ -567,13 +744,18 @@ func (r *SVGTextRenderer) RegisterFontFamily(familyName string, fontFiles []stri
 	// Actual implementation would require refactoring findFontFile to support dynamic mappings
 }
 
-// ClearFontCache 清空字体缓存 / Clear font cache
+// ClearFontCache 清空字体缓存及文本测量缓存 / Clear the font cache and the text measurement cache
 func (r *SVGTextRenderer) ClearFontCache() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.fontCache = make(map[string]font.Face)
+	r.measureCache = make(map[string]FontMetrics)
 }
 
 // GetLoadedFonts 获取已加载的字体列表 / Get list of loaded fonts
 func (r *SVGTextRenderer) GetLoadedFonts() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	fonts := make([]string, 0, len(r.fontCache))
 	for key := range r.fontCache {
 		fonts = append(fonts, key)
@@ -583,8 +765,8 @@ func (r *SVGTextRenderer) GetLoadedFonts() []string {
 
 // RenderText 在图像上渲染文本 / Render text on image
 func (r *SVGTextRenderer) RenderText(img draw.Image, text string, x, y float64, style *TextStyle) error {
-	// 加载字体 / Load font
-	face, err := r.loadFont(style.FontFamily, style.FontSize, style.FontWeight, style.FontStyle)
+	// 加载字体及按字符回退的字体 / Load the font and its per-rune fallbacks
+	face, err := r.loadFaces(style)
 	if err != nil {
 		return err
 	}
@@ -592,25 +774,27 @@ func (r *SVGTextRenderer) RenderText(img draw.Image, text string, x, y float64,
 	// 测量文本尺寸用于锚点计算 / Measure text for anchor calculation
 	metrics, _ := r.MeasureText(text, style)
 
+	// 测量完成后独占字体面直到绘制结束 / Hold the face exclusively from after measuring until drawing finishes
+	defer lockFace(face)()
+	face = applyKerning(face, style)
+
+	// 设置textLength时以目标宽度排版 / Lay out with the target width when textLength is set
+	advance := metrics.Advance
+	fitLength := style.TextLength > 0 && metrics.Advance > 0
+	if fitLength {
+		advance = style.TextLength
+	}
+
 	// 根据文本锚点调整X坐标 / Adjust X coordinate based on text anchor
 	switch style.TextAnchor {
 	case TextAnchorMiddle:
-		x -= metrics.Advance / 2
+		x -= advance / 2
 	case TextAnchorEnd:
-		x -= metrics.Advance
+		x -= advance
 	}
 
-	// 根据基线对齐调整Y坐标 / Adjust Y coordinate based on alignment baseline
-	switch style.AlignmentBaseline {
-	case AlignmentBaselineMiddle:
-		y += metrics.Height / 2
-	case AlignmentBaselineHanging:
-		y += metrics.Ascent
-	case AlignmentBaselineTop:
-		y += metrics.Ascent
-	case AlignmentBaselineBottom:
-		y -= metrics.Descent
-	}
+	// 根据基线对齐调整Y坐标，使所选基线落在y处 / Adjust the Y coordinate so that the selected baseline lands on y
+	y -= metrics.BaselineOffset(style.effectiveBaseline())
 
 	// 检查是否需要软件字体效果 / Check if software font effects are needed
 	needsBoldEffect := false
@@ -629,35 +813,176 @@ func (r *SVGTextRenderer) RenderText(img draw.Image, text string, x, y float64,
 		needsItalicEffect = true
 	}
 
-	// 使用标准字体绘制器 / Use standard font drawer
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  style.Fill,
-		Face: face,
+	// 应用字体效果 / Apply font effects
+	drawText := func(d *font.Drawer, text string, x, y float64) {
+		if needsBoldEffect && needsItalicEffect {
+			// 粗斜体：先应用粗体效果，再应用斜体变换 / Bold italic: apply bold effect first, then italic transformation
+			r.renderBoldItalicText(d, text, x, y, style.FontStyle)
+		} else if needsBoldEffect {
+			// 粗体：多次绘制实现粗体效果 / Bold: multiple draws for bold effect
+			r.renderBoldText(d, text, x, y)
+		} else if needsItalicEffect {
+			// 斜体：使用变换矩阵实现斜体效果 / Italic: use transformation matrix for italic effect
+			r.renderItalicText(d, text, x, y, style.FontStyle)
+		} else {
+			// 普通绘制 / Normal drawing
+			d.Dot = fixed.Point26_6{
+				X: fixed.Int26_6(x * 64),
+				Y: fixed.Int26_6(y * 64),
+			}
+			d.DrawString(text)
+		}
 	}
 
-	// 应用字体效果 / Apply font effects
-	if needsBoldEffect && needsItalicEffect {
-		// 粗斜体：先应用粗体效果，再应用斜体变换 / Bold italic: apply bold effect first, then italic transformation
-		r.renderBoldItalicText(d, text, x, y, style.FontStyle)
-	} else if needsBoldEffect {
-		// 粗体：多次绘制实现粗体效果 / Bold: multiple draws for bold effect
-		r.renderBoldText(d, text, x, y)
-	} else if needsItalicEffect {
-		// 斜体：使用变换矩阵实现斜体效果 / Italic: use transformation matrix for italic effect
-		r.renderItalicText(d, text, x, y, style.FontStyle)
-	} else {
-		// 普通绘制 / Normal drawing
-		d.Dot = fixed.Point26_6{
-			X: fixed.Int26_6(x * 64),
-			Y: fixed.Int26_6(y * 64),
+	// 按排版将文本以src绘制到dst / Draw the laid-out text onto dst using src
+	paintText := func(dst draw.Image, src image.Image) {
+		// 使用标准字体绘制器 / Use standard font drawer
+		d := &font.Drawer{
+			Dst:  dst,
+			Src:  src,
+			Face: face,
 		}
-		d.DrawString(text)
+
+		if !fitLength {
+			drawText(d, text, x, y)
+		} else if style.LengthAdjust == LengthAdjustSpacingAndGlyphs {
+			r.renderScaledText(d, text, x, y, style.TextLength/metrics.Advance, drawText)
+		} else {
+			r.renderSpacedText(d, text, x, y, style.TextLength-metrics.Advance, drawText)
+		}
+	}
+
+	fill := func() {
+		if style.Fill != nil {
+			paintText(img, style.Fill)
+		}
+	}
+	stroke := func() {
+		if style.Stroke != nil && style.StrokeWidth > 0 {
+			textBox := image.Rect(
+				int(math.Floor(x)), int(math.Floor(y-metrics.Ascent)),
+				int(math.Ceil(x+advance)), int(math.Ceil(y+metrics.Descent)),
+			)
+			r.renderTextStroke(img, textBox, metrics.Height, style, paintText)
+		}
+	}
+
+	// 默认先填充后描边，StrokeFirst时相反 / Fill then stroke by default, reversed when StrokeFirst is set
+	if style.StrokeFirst {
+		stroke()
+		fill()
+	} else {
+		fill()
+		stroke()
 	}
 
 	return nil
 }
 
+// renderTextStroke 沿字形轮廓绘制描边，描边宽度一半在字形外、一半在字形内
+// renderTextStroke strokes the glyph outlines, with half the stroke width outside and half inside the glyphs
+//
+// 先将文本绘制为覆盖率遮罩，描边区域为膨胀遮罩减去腐蚀遮罩
+// The text is first drawn as a coverage mask; the stroke region is the dilated mask minus the eroded mask
+func (r *SVGTextRenderer) renderTextStroke(img draw.Image, textBox image.Rectangle, lineHeight float64, style *TextStyle, paintText func(dst draw.Image, src image.Image)) {
+	radius := style.StrokeWidth / 2
+	reach := int(math.Ceil(radius + 0.5))
+
+	// 为粗体、斜体效果和描边预留空间 / Reserve room for bold and italic effects and the stroke
+	pad := int(math.Ceil(lineHeight)) + reach + 1
+	box := textBox.Inset(-pad).Intersect(img.Bounds().Inset(-reach))
+	if box.Empty() {
+		return
+	}
+
+	coverage := image.NewRGBA(box)
+	paintText(coverage, image.White)
+
+	// 圆盘邻域内的偏移 / Offsets within the disk neighbourhood
+	limit := (radius + 0.5) * (radius + 0.5)
+	var offsets []image.Point
+	for dy := -reach; dy <= reach; dy++ {
+		for dx := -reach; dx <= reach; dx++ {
+			if float64(dx*dx+dy*dy) <= limit {
+				offsets = append(offsets, image.Point{X: dx, Y: dy})
+			}
+		}
+	}
+
+	alphaAt := func(x, y int) uint8 {
+		if !(image.Point{X: x, Y: y}).In(box) {
+			return 0
+		}
+		return coverage.Pix[coverage.PixOffset(x, y)+3]
+	}
+
+	ring := image.NewAlpha(box)
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			dilated, eroded := uint8(0), uint8(255)
+			for _, o := range offsets {
+				a := alphaAt(x+o.X, y+o.Y)
+				if a > dilated {
+					dilated = a
+				}
+				if a < eroded {
+					eroded = a
+				}
+			}
+			if dilated > eroded {
+				ring.SetAlpha(x, y, color.Alpha{A: dilated - eroded})
+			}
+		}
+	}
+
+	draw.DrawMask(img, box, style.Stroke, box.Min, ring, box.Min, draw.Over)
+}
+
+// renderSpacedText 在字形之间平均分配额外间距 / Distribute extra space evenly between glyphs
+func (r *SVGTextRenderer) renderSpacedText(d *font.Drawer, text string, x, y, extra float64, drawText func(d *font.Drawer, text string, x, y float64)) {
+	runes := []rune(text)
+	if len(runes) < 2 {
+		drawText(d, text, x, y)
+		return
+	}
+
+	gap := extra / float64(len(runes)-1)
+	penX := x
+	for i, ch := range runes {
+		if i > 0 {
+			penX += float64(d.Face.Kern(runes[i-1], ch)) / 64.0
+		}
+		glyph := string(ch)
+		drawText(d, glyph, penX, y)
+		penX += float64(font.MeasureString(d.Face, glyph))/64.0 + gap
+	}
+}
+
+// renderScaledText 水平缩放字形绘制文本 / Draw text with glyphs scaled horizontally
+func (r *SVGTextRenderer) renderScaledText(d *font.Drawer, text string, x, y, scale float64, drawText func(d *font.Drawer, text string, x, y float64)) {
+	metrics := d.Face.Metrics()
+	advance := int(font.MeasureString(d.Face, text)>>6) + 1
+	ascent := metrics.Ascent.Ceil()
+	height := ascent + metrics.Descent.Ceil()
+
+	// 预留空间给粗体和斜体效果 / Reserve space for bold and italic effects
+	padding := height
+	tempImg := image.NewRGBA(image.Rect(0, 0, advance+padding*2, height+padding*2))
+	tempDrawer := &font.Drawer{
+		Dst:  tempImg,
+		Src:  d.Src,
+		Face: d.Face,
+	}
+	drawText(tempDrawer, text, float64(padding), float64(padding+ascent))
+
+	// 临时图像坐标映射到目标图像 / Map temporary image coordinates onto the destination
+	transform := f64.Aff3{
+		scale, 0, x - float64(padding)*scale,
+		0, 1, y - float64(padding+ascent),
+	}
+	xdraw.BiLinear.Transform(d.Dst, transform, tempImg, tempImg.Bounds(), xdraw.Over, nil)
+}
+
 // renderBoldText 渲染粗体文本 / Render bold text
 func (r *SVGTextRenderer) renderBoldText(d *font.Drawer, text string, x, y float64) {
 	// 根据字体大小动态调整粗体效果强度 / Dynamically adjust bold effect intensity based on font size
@@ -749,26 +1074,156 @@ func (r *SVGTextRenderer) renderBoldItalicText(d *font.Drawer, text string, x, y
 	r.applyAdvancedItalicTransform(d.Dst, tempImg, x-float64(padding), y-float64(ascent), skewAngle)
 }
 
-// MeasureText 测量文本尺寸
+// MeasureText 测量文本尺寸，相同文本和字体样式的结果会被缓存 / Measure text, caching results per text and font style
 func (r *SVGTextRenderer) MeasureText(text string, style *TextStyle) (*FontMetrics, error) {
+	key := fmt.Sprintf("%s-%.1f-%s-%s-%t|%s", style.FontFamily, style.FontSize, normalizeFontWeight(style.FontWeight), style.FontStyle, style.FontKerning == FontKerningNone, text)
+	r.mu.RLock()
+	cached, ok := r.measureCache[key]
+	r.mu.RUnlock()
+	if ok {
+		return &cached, nil
+	}
+	metrics, err := r.measureText(text, style)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.measureCache == nil {
+		r.measureCache = make(map[string]FontMetrics)
+	}
+	r.measureCache[key] = *metrics
+	return metrics, nil
+}
+
+// measureText 不经缓存测量文本尺寸 / Measure text without consulting the cache
+func (r *SVGTextRenderer) measureText(text string, style *TextStyle) (*FontMetrics, error) {
 	// 加载字体
-	face, err := r.loadFont(style.FontFamily, style.FontSize, style.FontWeight, style.FontStyle)
+	face, err := r.loadFaces(style)
 	if err != nil {
 		return nil, err
 	}
+	defer lockFace(face)()
+	face = applyKerning(face, style)
 
 	// 获取字体度量
-	fontMetrics := face.Metrics()
+	metrics := faceMetrics(face)
 
 	// 测量文本宽度
-	advance := font.MeasureString(face, text)
+	metrics.Advance = float64(font.MeasureString(face, text)) / 64.0
 
-	return &FontMetrics{
-		Ascent:  float64(fontMetrics.Ascent) / 64.0,
-		Descent: float64(fontMetrics.Descent) / 64.0,
-		Height:  float64(fontMetrics.Height) / 64.0,
-		Advance: float64(advance) / 64.0,
-	}, nil
+	return metrics, nil
+}
+
+// TextToPath 将文本转换为由字形轮廓组成的路径数据，起点和锚点、基线的处理与RenderText一致
+// TextToPath converts text into path data built from the glyph outlines, positioned with the same anchor and baseline handling as RenderText
+//
+// 位图回退字体没有轮廓，此时返回错误 / The bitmap fallback font has no outlines, in which case an error is returned
+func (r *SVGTextRenderer) TextToPath(text string, x, y float64, style *TextStyle) (string, error) {
+	face, err := r.loadFaces(style)
+	if err != nil {
+		return "", err
+	}
+	// 每个字符使用按字符回退选中的字体面，与MeasureText的步进一致 / Each rune uses the face chosen by per-rune fallback, matching MeasureText's advances
+	outlineFace := func(ch rune) (*lockedFace, error) {
+		chosen := face
+		if fallback, ok := face.(*fallbackFace); ok {
+			chosen = fallback.pick(ch)
+		}
+		locked, ok := chosen.(*lockedFace)
+		if !ok || locked.outlines == nil {
+			return nil, fmt.Errorf("字体%q没有可用的字形轮廓 / Font %q has no glyph outlines", style.FontFamily, style.FontFamily)
+		}
+		return locked, nil
+	}
+	if _, ok := face.(*fallbackFace); !ok {
+		if _, err := outlineFace(0); err != nil {
+			return "", err
+		}
+	}
+
+	metrics, _ := r.MeasureText(text, style)
+	switch style.TextAnchor {
+	case TextAnchorMiddle:
+		x -= metrics.Advance / 2
+	case TextAnchorEnd:
+		x -= metrics.Advance
+	}
+	y -= metrics.BaselineOffset(style.effectiveBaseline())
+
+	defer lockFace(face)()
+	kerned := applyKerning(face, style)
+
+	var d strings.Builder
+	var buf sfnt.Buffer
+	pen := x
+	prev := rune(-1)
+	for _, ch := range text {
+		if prev >= 0 {
+			pen += float64(kerned.Kern(prev, ch)) / 64
+		}
+		prev = ch
+
+		locked, err := outlineFace(ch)
+		if err != nil {
+			return "", err
+		}
+		index, err := locked.outlines.GlyphIndex(&buf, ch)
+		if err != nil {
+			return "", fmt.Errorf("查找字形失败 / Failed to look up glyph %q: %v", ch, err)
+		}
+		segments, err := locked.outlines.LoadGlyph(&buf, index, locked.ppem, nil)
+		if err != nil {
+			return "", fmt.Errorf("加载字形失败 / Failed to load glyph %q: %v", ch, err)
+		}
+
+		// 字形坐标以基线原点为准且y轴向下 / Glyph coordinates are relative to the baseline origin with y pointing down
+		pt := func(p fixed.Point26_6) string {
+			return path.FormatNumber(pen+float64(p.X)/64) + " " + path.FormatNumber(y+float64(p.Y)/64)
+		}
+		open := false
+		for _, seg := range segments {
+			switch seg.Op {
+			case sfnt.SegmentOpMoveTo:
+				if open {
+					d.WriteString("Z ")
+				}
+				open = true
+				d.WriteString("M " + pt(seg.Args[0]) + " ")
+			case sfnt.SegmentOpLineTo:
+				d.WriteString("L " + pt(seg.Args[0]) + " ")
+			case sfnt.SegmentOpQuadTo:
+				d.WriteString("Q " + pt(seg.Args[0]) + " " + pt(seg.Args[1]) + " ")
+			case sfnt.SegmentOpCubeTo:
+				d.WriteString("C " + pt(seg.Args[0]) + " " + pt(seg.Args[1]) + " " + pt(seg.Args[2]) + " ")
+			}
+		}
+		if open {
+			d.WriteString("Z ")
+		}
+
+		advance, _ := kerned.GlyphAdvance(ch)
+		pen += float64(advance)/64 + style.LetterSpacing
+	}
+	return strings.TrimSpace(d.String()), nil
+}
+
+// applyKerning 按文本样式返回应用或禁用字偶距的字体面 / Return the face with kerning applied or disabled according to the style
+func applyKerning(face font.Face, style *TextStyle) font.Face {
+	if style.FontKerning == FontKerningNone {
+		return noKerningFace{face}
+	}
+	return face
+}
+
+// noKerningFace 忽略字偶距表的字体面 / A face that ignores the kerning table
+type noKerningFace struct {
+	font.Face
+}
+
+// Kern 始终返回0 / Kern always returns 0
+func (noKerningFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return 0
 }
 
 // applyShearTransform 应用斜切变换实现斜体效果 / Apply shear transformation for italic effect
@@ -1246,20 +1701,15 @@ func (r *SVGTextRenderer) bilinearInterpolation(src *image.RGBA, x, y float64) c
 // GetFontMetrics 获取字体度量信息
 func (r *SVGTextRenderer) GetFontMetrics(style *TextStyle) (*FontMetrics, error) {
 	// 加载字体
-	face, err := r.loadFont(style.FontFamily, style.FontSize, style.FontWeight, style.FontStyle)
+	face, err := r.loadFaces(style)
 	if err != nil {
 		return nil, err
 	}
 
-	// 获取字体度量
-	fontMetrics := face.Metrics()
-
-	return &FontMetrics{
-		Ascent:  float64(fontMetrics.Ascent) / 64.0,
-		Descent: float64(fontMetrics.Descent) / 64.0,
-		Height:  float64(fontMetrics.Height) / 64.0,
-		Advance: 0, // 对于字体度量，Advance通常为0
-	}, nil
+	// 获取字体度量，对于字体度量Advance为0 / Get the font metrics; Advance is 0 for font-wide metrics
+	unlock := lockFace(face)
+	defer unlock()
+	return faceMetrics(face), nil
 }
 
 // 辅助函数：创建纯色图像
@@ -1267,6 +1717,26 @@ func CreateSolidColor(c color.Color) *image.Uniform {
 	return image.NewUniform(c)
 }
 
+// effectiveBaseline 返回生效的基线对齐，alignment-baseline优先于dominant-baseline
+// effectiveBaseline returns the baseline in effect; alignment-baseline takes precedence over dominant-baseline
+func (s *TextStyle) effectiveBaseline() AlignmentBaseline {
+	if s.AlignmentBaseline != "" && s.AlignmentBaseline != AlignmentBaselineAlphabetic {
+		return s.AlignmentBaseline
+	}
+
+	switch s.DominantBaseline {
+	case DominantBaselineCentral, DominantBaselineMiddle:
+		return AlignmentBaselineMiddle
+	case DominantBaselineHanging:
+		return AlignmentBaselineHanging
+	case DominantBaselineTextTop:
+		return AlignmentBaselineTop
+	case DominantBaselineTextBottom:
+		return AlignmentBaselineBottom
+	}
+	return AlignmentBaselineAlphabetic
+}
+
 // DefaultTextRenderer 是默认的文本渲染器
 var DefaultTextRenderer TextRenderer = NewSVGTextRenderer()
 
@@ -4,8 +4,19 @@ import (
 	"image"
 	"image/color"
 	"image/png"
+	"math"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/golang/freetype/truetype"
+	xfont "golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
 )
 
 // TestFontStyles 测试各种字体样式的渲染效果
@@ -195,4 +206,447 @@ func BenchmarkItalicRendering(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		renderer.RenderText(img, "Italic Benchmark", 10, 50, style)
 	}
-}
\ No newline at end of file
+}
+// TestTextLength 测试textLength将文本拉伸到目标宽度 / Test textLength stretches text to the target width
+func TestTextLength(t *testing.T) {
+	renderer := NewSVGTextRenderer()
+	base := &TextStyle{
+		FontFamily: "sans-serif",
+		FontSize:   20,
+		FontWeight: FontWeightNormal,
+		FontStyle:  FontStyleNormal,
+		Fill:       &image.Uniform{color.RGBA{0, 0, 0, 255}},
+	}
+
+	metrics, err := renderer.MeasureText("Word", base)
+	if err != nil {
+		t.Fatalf("MeasureText failed: %v", err)
+	}
+	const startX = 10.0
+	target := metrics.Advance * 2
+
+	// rightmostInk 返回最右侧有像素的列 / Return the rightmost column containing ink
+	rightmostInk := func(img *image.RGBA) int {
+		b := img.Bounds()
+		for x := b.Max.X - 1; x >= b.Min.X; x-- {
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				if img.RGBAAt(x, y).A > 0 {
+					return x
+				}
+			}
+		}
+		return -1
+	}
+
+	for _, adjust := range []LengthAdjust{LengthAdjustSpacing, LengthAdjustSpacingAndGlyphs} {
+		t.Run(string(adjust), func(t *testing.T) {
+			style := *base
+			style.TextLength = target
+			style.LengthAdjust = adjust
+
+			img := image.NewRGBA(image.Rect(0, 0, 400, 60))
+			if err := renderer.RenderText(img, "Word", startX, 40, &style); err != nil {
+				t.Fatalf("RenderText failed: %v", err)
+			}
+
+			end := float64(rightmostInk(img))
+			want := startX + target
+			if end < want-metrics.Advance/4 || end > want+2 {
+				t.Errorf("expected last glyph to end near %.1f, got %.1f", want, end)
+			}
+		})
+	}
+}
+
+// TestMeasureTextCache 测试缓存的测量结果与重新测量一致 / Test cached measurements match fresh ones
+func TestMeasureTextCache(t *testing.T) {
+	renderer := NewSVGTextRenderer()
+	style := &TextStyle{FontFamily: "sans-serif", FontSize: 16, FontWeight: FontWeightNormal, FontStyle: FontStyleNormal}
+
+	first, err := renderer.MeasureText("Revenue", style)
+	if err != nil {
+		t.Fatalf("MeasureText failed: %v", err)
+	}
+	first.Advance = -1 // 修改返回值不应影响缓存 / Mutating the result must not affect the cache
+
+	cached, err := renderer.MeasureText("Revenue", style)
+	if err != nil {
+		t.Fatalf("MeasureText failed: %v", err)
+	}
+	fresh, err := renderer.measureText("Revenue", style)
+	if err != nil {
+		t.Fatalf("measureText failed: %v", err)
+	}
+	if *cached != *fresh {
+		t.Errorf("expected cached metrics %+v to equal fresh %+v", *cached, *fresh)
+	}
+	if len(renderer.measureCache) != 1 {
+		t.Errorf("expected one cached measurement, got %d", len(renderer.measureCache))
+	}
+
+	renderer.ClearFontCache()
+	if len(renderer.measureCache) != 0 {
+		t.Error("expected ClearFontCache to drop cached measurements")
+	}
+	if again, _ := renderer.MeasureText("Revenue", style); *again != *fresh {
+		t.Errorf("expected remeasured metrics %+v to equal %+v", *again, *fresh)
+	}
+}
+
+// BenchmarkMeasureText 比较重复测量同一标签时缓存前后的开销 / Compare repeated measurement of one label with and without the cache
+func BenchmarkMeasureText(b *testing.B) {
+	renderer := NewSVGTextRenderer()
+	style := &TextStyle{FontFamily: "sans-serif", FontSize: 12, FontWeight: FontWeightNormal, FontStyle: FontStyleNormal}
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 1000; j++ {
+				renderer.MeasureText("Quarterly revenue", style)
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 1000; j++ {
+				renderer.measureText("Quarterly revenue", style)
+			}
+		}
+	})
+}
+
+// TestKerning 测试字偶距缩小"AV"的宽度且可被禁用 / Test kerning tightens "AV" and can be disabled
+func TestKerning(t *testing.T) {
+	// 需要带字偶距表的字体 / A font with a kerning table is required
+	var fontPath string
+	for _, candidate := range []string{
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		"/Library/Fonts/Arial.ttf",
+		"C:/Windows/Fonts/arial.ttf",
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			fontPath = candidate
+			break
+		}
+	}
+	if fontPath == "" {
+		t.Skip("no font with a kerning table available")
+	}
+
+	renderer := NewSVGTextRenderer()
+	if err := renderer.LoadFontFromFile(fontPath, "kerned", 40); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+	style := &TextStyle{FontFamily: "kerned", FontSize: 40, FontWeight: FontWeightNormal, FontStyle: FontStyleNormal}
+
+	kerned, err := renderer.MeasureText("AV", style)
+	if err != nil {
+		t.Fatalf("MeasureText failed: %v", err)
+	}
+	unkernedStyle := *style
+	unkernedStyle.FontKerning = FontKerningNone
+	unkerned, err := renderer.MeasureText("AV", &unkernedStyle)
+	if err != nil {
+		t.Fatalf("MeasureText failed: %v", err)
+	}
+	if kerned.Advance >= unkerned.Advance {
+		t.Errorf("expected kerned width %.2f to be smaller than unkerned %.2f", kerned.Advance, unkerned.Advance)
+	}
+}
+
+// TestConcurrentRenderText 测试多个goroutine同时渲染和测量文本，需配合-race运行 / Test rendering and measuring text from many goroutines at once; run with -race
+func TestConcurrentRenderText(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	renderer := NewSVGTextRenderer()
+	if err := renderer.LoadFontFromFile(fontPath, "go", 16); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			family := []string{"go", "sans-serif"}[i%2]
+			style := &TextStyle{
+				FontFamily: family,
+				FontSize:   16,
+				FontWeight: FontWeightNormal,
+				FontStyle:  FontStyleNormal,
+				Fill:       &image.Uniform{color.RGBA{0, 0, 0, 255}},
+			}
+			img := image.NewRGBA(image.Rect(0, 0, 120, 30))
+			for j := 0; j < 10; j++ {
+				if err := renderer.RenderText(img, "Tile", 5, 20, style); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := renderer.MeasureText("Tile", style); err != nil {
+					errs <- err
+					return
+				}
+			}
+			renderer.AddFontPath(t.TempDir())
+			renderer.GetLoadedFonts()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent render failed: %v", err)
+	}
+}
+
+// TestBaselineOffsets 测试各基线按字体表的上升和下降定位，并在渲染中按序排列
+// TestBaselineOffsets tests that each baseline is placed from the font tables' ascent and descent and keeps its order when rendered
+func TestBaselineOffsets(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	renderer := NewSVGTextRenderer()
+	if err := renderer.LoadFontFromFile(fontPath, "go", 40); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+	style := &TextStyle{FontFamily: "go", FontSize: 40, FontWeight: FontWeightNormal, FontStyle: FontStyleNormal,
+		Fill: &image.Uniform{color.RGBA{0, 0, 0, 255}}}
+
+	metrics, err := renderer.GetFontMetrics(style)
+	if err != nil {
+		t.Fatalf("GetFontMetrics failed: %v", err)
+	}
+
+	// 参考值直接取自hhea/OS/2表 / Reference values come straight from the hhea/OS/2 tables
+	parsed, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatalf("sfnt.Parse failed: %v", err)
+	}
+	reference, err := parsed.Metrics(&sfnt.Buffer{}, fixed.I(40), xfont.HintingNone)
+	if err != nil {
+		t.Fatalf("Metrics failed: %v", err)
+	}
+	refAscent := float64(reference.Ascent) / 64
+	refDescent := float64(reference.Descent) / 64
+	if math.Abs(metrics.Ascent-refAscent) > 1e-6 || math.Abs(metrics.Descent-refDescent) > 1e-6 {
+		t.Errorf("expected ascent/descent %.2f/%.2f, got %.2f/%.2f", refAscent, refDescent, metrics.Ascent, metrics.Descent)
+	}
+	if want := float64(reference.Height)/64 - refAscent - refDescent; math.Abs(metrics.LineGap-want) > 1e-6 {
+		t.Errorf("expected line gap %.2f, got %.2f", want, metrics.LineGap)
+	}
+
+	// inkTop 返回最上方有像素的行 / Return the topmost row containing ink
+	inkTop := func(img *image.RGBA) int {
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if img.RGBAAt(x, y).A > 0 {
+					return y
+				}
+			}
+		}
+		return -1
+	}
+
+	baselines := []AlignmentBaseline{
+		AlignmentBaselineTop, AlignmentBaselineHanging, AlignmentBaselineMiddle,
+		AlignmentBaselineAlphabetic, AlignmentBaselineBottom,
+	}
+	tops := make([]int, len(baselines))
+	for i, baseline := range baselines {
+		s := *style
+		s.AlignmentBaseline = baseline
+		img := image.NewRGBA(image.Rect(0, 0, 80, 160))
+		if err := renderer.RenderText(img, "H", 10, 80, &s); err != nil {
+			t.Fatalf("RenderText failed: %v", err)
+		}
+		tops[i] = inkTop(img)
+	}
+
+	alphabetic := tops[3]
+	for i, baseline := range baselines {
+		offset := metrics.BaselineOffset(baseline)
+		if i > 0 && offset <= metrics.BaselineOffset(baselines[i-1]) {
+			t.Errorf("expected %s offset %.2f below %s", baseline, offset, baselines[i-1])
+		}
+		// 将所选基线对齐到y等价于将字形下移-offset / Aligning the baseline to y shifts the glyphs down by -offset
+		if shift := float64(tops[i] - alphabetic); math.Abs(shift+offset) > 1 {
+			t.Errorf("%s: expected glyphs shifted by %.2f, got %.0f", baseline, -offset, shift)
+		}
+	}
+}
+
+// TestLoadCFFFont 测试加载CFF轮廓的OpenType字体并渲染字形 / Test loading a CFF-flavored OpenType font and rendering a glyph
+func TestLoadCFFFont(t *testing.T) {
+	renderer := NewSVGTextRenderer()
+	if err := renderer.LoadFontFromFile(filepath.Join("testdata", "CFFTest.otf"), "cff", 40); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+	style := &TextStyle{FontFamily: "cff", FontSize: 40, FontWeight: FontWeightNormal, FontStyle: FontStyleNormal,
+		Fill: &image.Uniform{color.RGBA{0, 0, 0, 255}}}
+
+	img := image.NewRGBA(image.Rect(0, 0, 120, 80))
+	if err := renderer.RenderText(img, "Q", 10, 60, style); err != nil {
+		t.Fatalf("RenderText failed: %v", err)
+	}
+	ink := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] > 0 {
+			ink++
+		}
+	}
+	if ink == 0 {
+		t.Fatal("expected the CFF glyph to draw ink")
+	}
+
+	// 40px字形远大于7x13的回退位图字体 / A 40px glyph is far larger than the 7x13 fallback bitmap font
+	metrics, err := renderer.MeasureText("Q", style)
+	if err != nil {
+		t.Fatalf("MeasureText failed: %v", err)
+	}
+	if metrics.Ascent < 20 {
+		t.Errorf("expected the CFF face's metrics, got ascent %.2f", metrics.Ascent)
+	}
+}
+
+// TestTextToPath 测试将"I"转换为由直线组成的矩形轮廓 / Test converting "I" into a rectangular outline of straight lines
+func TestTextToPath(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	renderer := NewSVGTextRenderer()
+	if err := renderer.LoadFontFromFile(fontPath, "go", 100); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+	style := &TextStyle{FontFamily: "go", FontSize: 100, FontWeight: FontWeightNormal, FontStyle: FontStyleNormal}
+
+	d, err := renderer.TextToPath("I", 10, 120, style)
+	if err != nil {
+		t.Fatalf("TextToPath failed: %v", err)
+	}
+	fields := strings.Fields(d)
+	if len(fields) == 0 || fields[0] != "M" || fields[len(fields)-1] != "Z" || strings.ContainsAny(d, "QC") {
+		t.Fatalf("expected a closed move/line outline, got %q", d)
+	}
+
+	// "I"的轮廓只含水平和竖直边 / The outline of "I" has only horizontal and vertical edges
+	var points [][2]string
+	var minY, maxY float64 = math.Inf(1), math.Inf(-1)
+	for i := 0; i+2 < len(fields); i++ {
+		if fields[i] != "M" && fields[i] != "L" {
+			continue
+		}
+		points = append(points, [2]string{fields[i+1], fields[i+2]})
+		v, _ := strconv.ParseFloat(fields[i+2], 64)
+		minY, maxY = math.Min(minY, v), math.Max(maxY, v)
+	}
+	if len(points) < 4 {
+		t.Fatalf("expected at least 4 vertices, got %q", d)
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i][0] != points[i-1][0] && points[i][1] != points[i-1][1] {
+			t.Errorf("expected axis-aligned edges, got %v -> %v", points[i-1], points[i])
+		}
+	}
+	if math.Abs(maxY-120) > 0.5 || maxY-minY < 50 || maxY-minY > 100 {
+		t.Errorf("expected the stem to stand on the baseline at 120 with cap height, got y range [%.2f, %.2f]", minY, maxY)
+	}
+}
+
+// TestFontFallback 测试主字体缺少的中文字符由font-family列表和表情字体中的回退字体绘制
+// TestFontFallback tests that a CJK rune missing from the primary font is drawn by a fallback from the font-family list or the emoji font
+func TestFontFallback(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	renderer := NewSVGTextRenderer()
+	if err := renderer.LoadFontFromFile(fontPath, "latin", 40); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+	if err := renderer.LoadFontFromFile(filepath.Join("testdata", "CFFTest.otf"), "cff", 40); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+	style := func(family string) *TextStyle {
+		return &TextStyle{FontFamily: family, FontSize: 40, FontWeight: FontWeightNormal, FontStyle: FontStyleNormal,
+			Fill: &image.Uniform{color.RGBA{0, 0, 0, 255}}}
+	}
+	render := func(text string, x float64, style *TextStyle) *image.RGBA {
+		img := image.NewRGBA(image.Rect(0, 0, 160, 60))
+		if err := renderer.RenderText(img, text, x, 45, style); err != nil {
+			t.Fatalf("RenderText failed: %v", err)
+		}
+		return img
+	}
+
+	latin, err := renderer.MeasureText("A", style("latin"))
+	if err != nil {
+		t.Fatalf("MeasureText failed: %v", err)
+	}
+	cjk, _ := renderer.MeasureText("中", style("cff"))
+	mixed, _ := renderer.MeasureText("A中", style("latin, 'cff'"))
+	if math.Abs(mixed.Advance-(latin.Advance+cjk.Advance)) > 1e-9 {
+		t.Errorf("expected the mixed advance to add the Latin and fallback advances, got %.2f, want %.2f", mixed.Advance, latin.Advance+cjk.Advance)
+	}
+
+	// 回退绘制的中文字符与单独用回退字体绘制的相同 / The fallback CJK rune matches the rune drawn with the fallback font alone
+	want := render("中", 10+latin.Advance, style("cff"))
+	ink := 0
+	for i := 3; i < len(want.Pix); i += 4 {
+		if want.Pix[i] > 0 {
+			ink++
+		}
+	}
+	if ink == 0 {
+		t.Fatal("expected the fallback font to draw ink for the CJK rune")
+	}
+	same := func(got *image.RGBA) bool {
+		for y := 0; y < 60; y++ {
+			for x := int(math.Ceil(10 + latin.Advance)); x < 160; x++ {
+				if got.RGBAAt(x, y) != want.RGBAAt(x, y) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	if !same(render("A中", 10, style("latin, 'cff'"))) {
+		t.Error("expected the next font family to supply the CJK glyph")
+	}
+	if same(render("A中", 10, style("latin"))) {
+		t.Error("expected the primary font alone to draw its missing glyph instead")
+	}
+
+	// 轮廓同样按字符回退，步进与MeasureText一致 / Outlines fall back per rune as well, with advances matching MeasureText
+	outline, err := renderer.TextToPath("A中", 10, 45, style("latin, 'cff'"))
+	if err != nil {
+		t.Fatalf("TextToPath failed: %v", err)
+	}
+	first, _ := renderer.TextToPath("A", 10, 45, style("latin"))
+	second, _ := renderer.TextToPath("中", 10+latin.Advance, 45, style("cff"))
+	if outline != first+" "+second {
+		t.Errorf("expected the fallback outline to join the per-font outlines, got %q", outline)
+	}
+
+	renderer.SetEmojiFont("cff")
+	if !same(render("A中", 10, style("latin"))) {
+		t.Error("expected the emoji font to supply the CJK glyph")
+	}
+}
+
+// TestUnkernedMetrics 测试禁用字偶距时仍使用表中读取的纵向度量 / Test disabling kerning still uses the vertical metrics read from the tables
+func TestUnkernedMetrics(t *testing.T) {
+	// 该字号下表中度量与提示后的度量不同 / At this size the table metrics differ from the hinted ones
+	face, err := newLockedFace(goregular.TTF, &truetype.Options{Size: 17.5, DPI: 72})
+	if err != nil {
+		t.Fatalf("newLockedFace failed: %v", err)
+	}
+	kerned := faceMetrics(face)
+	unkerned := faceMetrics(applyKerning(face, &TextStyle{FontKerning: FontKerningNone}))
+	if *kerned != *unkerned {
+		t.Errorf("expected the same metrics, got %+v and %+v", *kerned, *unkerned)
+	}
+}
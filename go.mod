@@ -6,3 +6,5 @@ require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	golang.org/x/image v0.18.0
 )
+
+require golang.org/x/text v0.16.0 // indirect
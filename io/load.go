@@ -69,12 +69,8 @@ func ParseSVG(data []byte) (*types.Document, error) {
 	if xmlDoc.ViewBox != "" {
 		doc.ViewBox = xmlDoc.ViewBox
 	}
-	if xmlDoc.Title != "" {
-		doc.SetAttribute("title", xmlDoc.Title)
-	}
-	if xmlDoc.Desc != "" {
-		doc.SetAttribute("desc", xmlDoc.Desc)
-	}
+	doc.SetTitle(strings.TrimSpace(xmlDoc.Title))
+	doc.SetDesc(strings.TrimSpace(xmlDoc.Desc))
 
 	// 解析元素
 	for _, xmlEl := range xmlDoc.Elements {
@@ -0,0 +1,239 @@
+package svg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hoonfeng/svg/path"
+	. "github.com/hoonfeng/svg/types"
+)
+
+// defaultPresentation 与SVG默认值相同、可安全省略的表现属性 / Presentation attributes whose SVG defaults make them safe to omit
+var defaultPresentation = map[string]string{
+	"opacity":           "1",
+	"fill-opacity":      "1",
+	"stroke-opacity":    "1",
+	"stroke":            "none",
+	"stroke-width":      "1",
+	"stroke-linecap":    "butt",
+	"stroke-linejoin":   "miter",
+	"stroke-miterlimit": "4",
+	"stroke-dashoffset": "0",
+	"fill-rule":         "nonzero",
+	"visibility":        "visible",
+	"display":           "inline",
+	"mix-blend-mode":    "normal",
+}
+
+// Optimize 在文档树上执行压缩：移除零尺寸或完全透明的元素，合并共线的连续直线命令，删除等于默认值的属性
+// Optimize compacts the document tree: it drops zero-size or fully transparent elements, merges consecutive collinear line commands and removes attributes equal to their defaults
+//
+// 带ID的元素可能被引用，始终保留 / Elements with an ID may be referenced and are always kept
+func (s *SVG) Optimize() *SVG {
+	s.doc.Elements = optimizeElements(s.doc.Elements, map[string]string{})
+	return s
+}
+
+// optimizeElements 优化元素列表并返回保留的元素，inherited为祖先显式设置的属性 / Optimize a list of elements and return those kept; inherited holds attributes set by ancestors
+func optimizeElements(list []Element, inherited map[string]string) []Element {
+	kept := list[:0]
+	for _, element := range list {
+		if element.ID() == "" && isNoOpElement(element, inherited) {
+			continue
+		}
+		if element.Tag() == "path" {
+			mergeCollinearLines(element)
+		}
+
+		attrs := element.GetAttributes()
+		childInherited := make(map[string]string, len(inherited))
+		for name, value := range inherited {
+			childInherited[name] = value
+		}
+		for name, value := range attrs {
+			if _, ok := defaultPresentation[name]; ok || name == "fill" {
+				childInherited[name] = value
+			}
+		}
+		// 仅当祖先未设置非默认值时才能省略，否则会改变继承结果 / Only omit when no ancestor sets a non-default value, or inheritance would change
+		for name, value := range attrs {
+			def, ok := defaultPresentation[name]
+			if !ok || !sameValue(value, def) {
+				continue
+			}
+			// 线类元素的默认描边为黑色，stroke="none"不能省略 / Line-like elements stroke black by default, so stroke="none" must stay
+			if name == "stroke" && isLineLike(element.Tag()) {
+				continue
+			}
+			if parent, set := inherited[name]; set && !sameValue(parent, def) {
+				continue
+			}
+			if remover, ok := element.(interface{ RemoveAttribute(string) }); ok {
+				remover.RemoveAttribute(name)
+			}
+		}
+
+		if children := element.Children(); len(children) > 0 {
+			remaining := optimizeElements(append([]Element(nil), children...), childInherited)
+			if remover, ok := element.(interface{ RemoveChild(Element) }); ok {
+				for _, child := range children {
+					if indexOf(remaining, child) < 0 {
+						remover.RemoveChild(child)
+					}
+				}
+			}
+			if element.Tag() == "g" && len(remaining) == 0 && element.ID() == "" {
+				continue
+			}
+		}
+		kept = append(kept, element)
+	}
+	return kept
+}
+
+// isLineLike 判断元素是否默认以黑色描边 / Report whether an element is stroked black by default
+func isLineLike(tag string) bool {
+	return tag == "line" || tag == "polyline" || tag == "polygon"
+}
+
+// isNoOpElement 判断元素是否不会产生任何可见输出，未设置的填充和描边取自inherited / Report whether an element cannot produce any visible output; unset fill and stroke come from inherited
+func isNoOpElement(element Element, inherited map[string]string) bool {
+	attrs := element.GetAttributes()
+	number := func(name string) float64 {
+		v, err := strconv.ParseFloat(strings.TrimSpace(attrs[name]), 64)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+
+	switch element.Tag() {
+	case "rect", "image":
+		if number("width") <= 0 || number("height") <= 0 {
+			return true
+		}
+	case "circle":
+		if number("r") <= 0 {
+			return true
+		}
+	case "ellipse":
+		if number("rx") <= 0 || number("ry") <= 0 {
+			return true
+		}
+	case "path":
+		if strings.TrimSpace(attrs["d"]) == "" {
+			return true
+		}
+	case "g":
+		return len(element.Children()) == 0
+	default:
+		return false
+	}
+
+	// style中的声明可能覆盖属性，保守地保留 / Declarations in style may override attributes, so keep such elements
+	if _, ok := attrs["style"]; ok || element.Tag() == "image" {
+		return false
+	}
+	if value, ok := attrs["opacity"]; ok && sameValue(value, "0") {
+		return true
+	}
+	transparent := func(paint, opacity string, def bool) bool {
+		value, ok := attrs[paint]
+		if !ok {
+			if value, ok = inherited[paint]; !ok {
+				return def
+			}
+		}
+		value = strings.TrimSpace(value)
+		if value == "none" || value == "transparent" {
+			return true
+		}
+		o, ok := attrs[opacity]
+		if !ok {
+			o, ok = inherited[opacity]
+		}
+		return ok && sameValue(o, "0")
+	}
+	width, ok := attrs["stroke-width"]
+	if !ok {
+		width = inherited["stroke-width"]
+	}
+	noStroke := transparent("stroke", "stroke-opacity", true) || sameValue(width, "0")
+	return transparent("fill", "fill-opacity", false) && noStroke
+}
+
+// mergeCollinearLines 合并路径中同向共线的连续直线命令 / Merge consecutive line commands that continue in the same direction
+func mergeCollinearLines(element Element) {
+	parsed, err := path.ParsePath(element.GetAttributes()["d"])
+	if err != nil {
+		return
+	}
+
+	ctx := path.NewPathContext()
+	merged := make([]path.Command, 0, len(parsed.Commands))
+	var last *path.LineToCommand
+	var lastStart, lastEnd Point
+	changed := false
+	for _, cmd := range parsed.Commands {
+		start := ctx.CurrentPoint
+		cmd.Execute(ctx, 0)
+		end := ctx.CurrentPoint
+
+		line, ok := cmd.(*path.LineToCommand)
+		if !ok {
+			last = nil
+			merged = append(merged, cmd)
+			continue
+		}
+		if last != nil && continuesLine(lastStart, lastEnd, end) {
+			if last.Relative {
+				last.X, last.Y = end.X-lastStart.X, end.Y-lastStart.Y
+			} else {
+				last.X, last.Y = end.X, end.Y
+			}
+			lastEnd = end
+			changed = true
+			continue
+		}
+		copied := *line
+		last, lastStart, lastEnd = &copied, start, end
+		merged = append(merged, last)
+	}
+
+	if changed {
+		parsed.Commands = merged
+		element.SetAttribute("d", parsed.String())
+	}
+}
+
+// continuesLine 判断b到c是否沿a到b的方向继续 / Report whether the segment b→c continues in the direction of a→b
+func continuesLine(a, b, c Point) bool {
+	u, v := b.Sub(a), c.Sub(b)
+	lu, lv := u.Length(), v.Length()
+	if lu == 0 || lv == 0 {
+		return true
+	}
+	return math.Abs(u.Cross(v)) <= 1e-9*lu*lv && u.Dot(v) > 0
+}
+
+// sameValue 比较属性值，数值按数值比较 / Compare attribute values, numerically when both are numbers
+func sameValue(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == b {
+		return true
+	}
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	return errA == nil && errB == nil && x == y
+}
+
+// indexOf 返回元素在列表中的下标，不存在时返回-1 / Return an element's index in a list, or -1 if absent
+func indexOf(list []Element, element Element) int {
+	for i, e := range list {
+		if e == element {
+			return i
+		}
+	}
+	return -1
+}
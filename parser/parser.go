@@ -83,6 +83,19 @@ func (p *XMLParser) parseChildren(decoder *xml.Decoder, doc *types.Document) err
 
 		switch se := token.(type) {
 		case xml.StartElement:
+			// 根级标题和描述保存为文档元数据 / Root-level title and description are kept as document metadata
+			if se.Name.Local == "title" || se.Name.Local == "desc" {
+				text, err := p.parseTextContent(decoder)
+				if err != nil {
+					return err
+				}
+				if se.Name.Local == "title" {
+					doc.SetTitle(text)
+				} else {
+					doc.SetDesc(text)
+				}
+				continue
+			}
 			element, err := p.parseElement(decoder, se)
 			if err != nil {
 				return err
@@ -98,6 +111,29 @@ func (p *XMLParser) parseChildren(decoder *xml.Decoder, doc *types.Document) err
 	return nil
 }
 
+// parseTextContent 读取元素内的文本直到其结束标签，忽略嵌套标签 / Read the text inside an element up to its end tag, ignoring nested tags
+func (p *XMLParser) parseTextContent(decoder *xml.Decoder) (string, error) {
+	var sb strings.Builder
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("XML解析错误: %v", err)
+		}
+		switch se := token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return strings.TrimSpace(sb.String()), nil
+			}
+			depth--
+		case xml.CharData:
+			sb.Write(se)
+		}
+	}
+}
+
 // parseElement 解析单个元素
 func (p *XMLParser) parseElement(decoder *xml.Decoder, start xml.StartElement) (types.Element, error) {
 	var element types.Element
@@ -120,6 +156,12 @@ func (p *XMLParser) parseElement(decoder *xml.Decoder, start xml.StartElement) (
 		element = p.parsePath(start)
 	case "text":
 		element = p.parseText(start)
+	case "textPath":
+		textPath := elements.NewTextPath("", "")
+		for _, attr := range start.Attr {
+			textPath.SetAttribute(attr.Name.Local, attr.Value)
+		}
+		element = textPath
 	case "g":
 		element = p.parseGroup(start)
 	case "svg":
@@ -158,10 +200,11 @@ func (p *XMLParser) parseElement(decoder *xml.Decoder, start xml.StartElement) (
 			}
 		case xml.CharData:
 			// 处理文本内容
-			if start.Name.Local == "text" {
-				if textElement, ok := element.(*elements.Text); ok {
-					textElement.SetContent(string(se))
-				}
+			switch textElement := element.(type) {
+			case *elements.Text:
+				textElement.SetContent(string(se))
+			case *elements.TextPath:
+				textElement.SetContent(string(se))
 			}
 		}
 	}
@@ -114,10 +114,17 @@ func recursiveQuadraticBezier(p0, p1, p2 types.Point, flatness float64, points *
 	recursiveQuadraticBezier(p012, p12, p2, flatness, points)
 }
 
+// newContext 创建使用路径平坦度设置的上下文 / Create a context using the path's flatness setting
+func (p *SVGPath) newContext() *PathContext {
+	ctx := NewPathContext()
+	ctx.Flatness = p.Flatness
+	return ctx
+}
+
 // FlattenPath 将路径平滑化为点列表
 func (p *SVGPath) FlattenPath(precision float64) []types.Point {
 	// 创建路径上下文
-	ctx := NewPathContext()
+	ctx := p.newContext()
 
 	// 执行所有命令
 	for _, cmd := range p.Commands {
@@ -127,97 +134,79 @@ func (p *SVGPath) FlattenPath(precision float64) []types.Point {
 	return ctx.Points
 }
 
-// FlattenSubPaths 将路径分解为多个子路径
-func (p *SVGPath) FlattenSubPaths(precision float64) [][]types.Point {
-	subPaths := [][]types.Point{}
-	ctx := NewPathContext()
+// SubPath 展平后的子路径，闭合子路径不重复起点 / A flattened subpath; closed subpaths do not repeat their start point
+type SubPath struct {
+	Points []types.Point
+	Closed bool // 是否以Z闭合 / Whether the subpath was closed with Z
+}
+
+// SubPaths 将路径分解为子路径及其闭合状态，是闭合信息的唯一来源
+// SubPaths splits the path into subpaths along with their closed state and is the single source of closure information
+//
+// 闭合子路径去掉回到起点的末尾顶点，闭合边由使用方负责；Z之后未经移动的命令从起点开始新的子路径
+// Closed subpaths drop the trailing vertex that returns to the start, leaving the closing edge to the consumer; commands after Z without a moveto start a new subpath at the start point
+func (p *SVGPath) SubPaths(precision float64) []SubPath {
+	subPaths := []SubPath{}
+	ctx := p.newContext()
 	subPathStartIndex := 0
 
-	for _, cmd := range p.Commands {
-		// 检查是否是闭合路径命令
-		if _, isClose := cmd.(*ClosePathCommand); isClose {
-			// 执行闭合命令
-			cmd.Execute(ctx, precision)
-			// 提取当前子路径的点
-			if len(ctx.Points) > subPathStartIndex {
-				currentSubPath := ctx.Points[subPathStartIndex:]
-				if len(currentSubPath) >= 2 { // 降低要求，允许线条
-					// 复制子路径点
-					subPath := make([]types.Point, len(currentSubPath))
-					copy(subPath, currentSubPath)
-					subPaths = append(subPaths, subPath)
-				}
-				// 更新下一个子路径的起始索引
-				subPathStartIndex = len(ctx.Points)
+	// finish 结束当前子路径，少于2个点的子路径被丢弃 / Finish the current subpath, dropping subpaths with fewer than 2 points
+	finish := func(closed bool) {
+		if len(ctx.Points)-subPathStartIndex >= 2 {
+			points := append([]types.Point(nil), ctx.Points[subPathStartIndex:]...)
+			if closed && len(points) > 2 && points[len(points)-1] == points[0] {
+				points = points[:len(points)-1]
 			}
-		} else {
-			// 执行其他命令
-			cmd.Execute(ctx, precision)
+			subPaths = append(subPaths, SubPath{Points: points, Closed: closed})
 		}
+		subPathStartIndex = len(ctx.Points)
 	}
 
-	// 处理最后一个未闭合的子路径
-	if len(ctx.Points) > subPathStartIndex {
-		currentSubPath := ctx.Points[subPathStartIndex:]
-		if len(currentSubPath) >= 2 { // 降低要求，允许线条
-			// 复制子路径点
-			subPath := make([]types.Point, len(currentSubPath))
-			copy(subPath, currentSubPath)
-			subPaths = append(subPaths, subPath)
+	afterClose := false
+	for _, cmd := range p.Commands {
+		switch cmd.(type) {
+		case *MoveToCommand:
+			finish(false)
+			afterClose = false
+		case *ClosePathCommand:
+			cmd.Execute(ctx, precision)
+			finish(true)
+			afterClose = true
+			continue
+		default:
+			if afterClose {
+				// 以起点开始新的子路径 / Seed the new subpath with the start point
+				ctx.Points = append(ctx.Points, ctx.CurrentPoint)
+				afterClose = false
+			}
 		}
+		cmd.Execute(ctx, precision)
 	}
+	finish(false)
 
 	return subPaths
 }
 
-// GetSubPathCloseInfo 获取每个子路径的闭合信息
-func (p *SVGPath) GetSubPathCloseInfo() []bool {
-	closeInfo := []bool{}
-	ctx := NewPathContext()
-	subPathStartIndex := 0
-	currentSubPathClosed := false
-
-	for _, cmd := range p.Commands {
-		// 检查是否是移动命令（开始新的子路径）
-		if _, isMove := cmd.(*MoveToCommand); isMove {
-			// 如果之前有子路径，记录其闭合状态
-			if len(ctx.Points) > subPathStartIndex {
-				currentSubPath := ctx.Points[subPathStartIndex:]
-				if len(currentSubPath) >= 2 {
-					closeInfo = append(closeInfo, currentSubPathClosed)
-				}
-				subPathStartIndex = len(ctx.Points)
-			}
-			currentSubPathClosed = false // 重置闭合状态
-		}
-
-		// 检查是否是闭合路径命令
-		if _, isClose := cmd.(*ClosePathCommand); isClose {
-			currentSubPathClosed = true
-			// 执行闭合命令
-			cmd.Execute(ctx, 0.001)
-			// 记录当前子路径的闭合状态
-			if len(ctx.Points) > subPathStartIndex {
-				currentSubPath := ctx.Points[subPathStartIndex:]
-				if len(currentSubPath) >= 2 {
-					closeInfo = append(closeInfo, true)
-				}
-				subPathStartIndex = len(ctx.Points)
-			}
-			currentSubPathClosed = false // 重置为下一个子路径
-		} else {
-			// 执行其他命令
-			cmd.Execute(ctx, 0.001) // 使用固定精度
+// FlattenSubPaths 将路径分解为多个子路径，闭合子路径以起点结尾
+// FlattenSubPaths splits the path into subpaths; closed subpaths end with their start point
+func (p *SVGPath) FlattenSubPaths(precision float64) [][]types.Point {
+	subPaths := [][]types.Point{}
+	for _, subPath := range p.SubPaths(precision) {
+		points := subPath.Points
+		if subPath.Closed && points[len(points)-1] != points[0] {
+			points = append(points, points[0])
 		}
+		subPaths = append(subPaths, points)
 	}
+	return subPaths
+}
 
-	// 处理最后一个未闭合的子路径
-	if len(ctx.Points) > subPathStartIndex {
-		currentSubPath := ctx.Points[subPathStartIndex:]
-		if len(currentSubPath) >= 2 {
-			closeInfo = append(closeInfo, currentSubPathClosed)
-		}
+// GetSubPathCloseInfo 获取每个子路径的闭合信息，与FlattenSubPaths一一对应
+// GetSubPathCloseInfo returns each subpath's closed state, matching FlattenSubPaths one to one
+func (p *SVGPath) GetSubPathCloseInfo() []bool {
+	closeInfo := []bool{}
+	for _, subPath := range p.SubPaths(0.001) {
+		closeInfo = append(closeInfo, subPath.Closed)
 	}
-
 	return closeInfo
 }
@@ -1,9 +1,7 @@
 package path
 
 import (
-	"fmt"
 	"math"
-	"strconv"
 
 	"github.com/hoonfeng/svg/types"
 )
@@ -14,6 +12,7 @@ type PathContext struct {
 	StartPoint   types.Point
 	PrevControl  types.Point // 上一个控制点（用于平滑曲线）
 	Points       []types.Point
+	Flatness     float64 // 曲线平坦度容差，0表示自适应 / Curve flatness tolerance, 0 for adaptive
 }
 
 // NewPathContext 创建新的路径上下文
@@ -23,6 +22,14 @@ func NewPathContext() *PathContext {
 	}
 }
 
+// flatness 返回曲线平坦度，未设置时使用自适应值 / Return the curve flatness, falling back to the adaptive value when unset
+func (ctx *PathContext) flatness(adaptive float64) float64 {
+	if ctx.Flatness > 0 {
+		return ctx.Flatness
+	}
+	return adaptive
+}
+
 // MoveToCommand 表示移动命令
 type MoveToCommand struct {
 	X, Y     float64
@@ -43,10 +50,7 @@ func (c *MoveToCommand) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *MoveToCommand) String() string {
-	if c.Relative {
-		return "m " + strconv.FormatFloat(c.X, 'f', -1, 64) + " " + strconv.FormatFloat(c.Y, 'f', -1, 64)
-	}
-	return "M " + strconv.FormatFloat(c.X, 'f', -1, 64) + " " + strconv.FormatFloat(c.Y, 'f', -1, 64)
+	return formatCommand("M", c.Relative, c.X, c.Y)
 }
 
 // LineToCommand 表示直线命令
@@ -74,10 +78,7 @@ func (c *LineToCommand) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *LineToCommand) String() string {
-	if c.Relative {
-		return "l " + strconv.FormatFloat(c.X, 'f', -1, 64) + " " + strconv.FormatFloat(c.Y, 'f', -1, 64)
-	}
-	return "L " + strconv.FormatFloat(c.X, 'f', -1, 64) + " " + strconv.FormatFloat(c.Y, 'f', -1, 64)
+	return formatCommand("L", c.Relative, c.X, c.Y)
 }
 
 // HorizontalLineToCommand 表示水平线命令
@@ -105,10 +106,7 @@ func (c *HorizontalLineToCommand) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *HorizontalLineToCommand) String() string {
-	if c.Relative {
-		return "h " + strconv.FormatFloat(c.X, 'f', -1, 64)
-	}
-	return "H " + strconv.FormatFloat(c.X, 'f', -1, 64)
+	return formatCommand("H", c.Relative, c.X)
 }
 
 // VerticalLineToCommand 表示垂直线命令
@@ -136,10 +134,7 @@ func (c *VerticalLineToCommand) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *VerticalLineToCommand) String() string {
-	if c.Relative {
-		return "v " + strconv.FormatFloat(c.Y, 'f', -1, 64)
-	}
-	return "V " + strconv.FormatFloat(c.Y, 'f', -1, 64)
+	return formatCommand("V", c.Relative, c.Y)
 }
 
 // CubicCurveToCommand 表示三次贝塞尔曲线命令
@@ -182,7 +177,7 @@ func (c *CubicCurveToCommand) Execute(ctx *PathContext, precision float64) {
 	// 基于曲线复杂度的智能flatness：控制点偏离越大，需要更精细的平滑
 	// Intelligent flatness based on curve complexity: greater control point deviation requires finer smoothing
 	complexityFactor := math.Min(10.0, maxControlDist/math.Max(1.0, curveLength))
-	flatness := math.Min(2.0, math.Max(0.05, 0.5/complexityFactor)) // 更精细的自适应范围 / More refined adaptive range
+	flatness := ctx.flatness(math.Min(2.0, math.Max(0.05, 0.5/complexityFactor))) // 更精细的自适应范围 / More refined adaptive range
 	bezierPoints := adaptiveCubicBezierFlattening(startPoint, control1, control2, endPoint, flatness)
 	// 跳过起点，因为它已经在路径中 / Skip start point as it's already in the path
 	if len(bezierPoints) > 1 {
@@ -193,12 +188,7 @@ func (c *CubicCurveToCommand) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *CubicCurveToCommand) String() string {
-	if c.Relative {
-		return fmt.Sprintf("c %.2f %.2f %.2f %.2f %.2f %.2f",
-			c.X1, c.Y1, c.X2, c.Y2, c.X, c.Y)
-	}
-	return fmt.Sprintf("C %.2f %.2f %.2f %.2f %.2f %.2f",
-		c.X1, c.Y1, c.X2, c.Y2, c.X, c.Y)
+	return formatCommand("C", c.Relative, c.X1, c.Y1, c.X2, c.Y2, c.X, c.Y)
 }
 
 // SmoothCubicCurveToCommand 表示平滑三次贝塞尔曲线命令
@@ -245,7 +235,7 @@ func (c *SmoothCubicCurveToCommand) Execute(ctx *PathContext, precision float64)
 	// 基于曲线复杂度的智能flatness：控制点偏离越大，需要更精细的平滑
 	// Intelligent flatness based on curve complexity: greater control point deviation requires finer smoothing
 	complexityFactor := math.Min(10.0, maxControlDist/math.Max(1.0, curveLength))
-	flatness := math.Min(2.0, math.Max(0.05, 0.5/complexityFactor)) // 更精细的自适应范围 / More refined adaptive range
+	flatness := ctx.flatness(math.Min(2.0, math.Max(0.05, 0.5/complexityFactor))) // 更精细的自适应范围 / More refined adaptive range
 	bezierPoints := adaptiveCubicBezierFlattening(startPoint, control1, control2, endPoint, flatness)
 	// 跳过起点，因为它已经在路径中 / Skip start point as it's already in the path
 	if len(bezierPoints) > 1 {
@@ -256,12 +246,7 @@ func (c *SmoothCubicCurveToCommand) Execute(ctx *PathContext, precision float64)
 }
 
 func (c *SmoothCubicCurveToCommand) String() string {
-	if c.Relative {
-		return fmt.Sprintf("s %.2f %.2f %.2f %.2f",
-			c.X2, c.Y2, c.X, c.Y)
-	}
-	return fmt.Sprintf("S %.2f %.2f %.2f %.2f",
-		c.X2, c.Y2, c.X, c.Y)
+	return formatCommand("S", c.Relative, c.X2, c.Y2, c.X, c.Y)
 }
 
 // QuadraticCurveToCommand 表示二次贝塞尔曲线命令
@@ -298,7 +283,7 @@ func (c *QuadraticCurveToCommand) Execute(ctx *PathContext, precision float64) {
 	// 基于曲线复杂度的智能flatness：控制点偏离越大，需要更精细的平滑
 	// Intelligent flatness based on curve complexity: greater control point deviation requires finer smoothing
 	complexityFactor := math.Min(10.0, controlDist/math.Max(1.0, curveLength/2))
-	flatness := math.Min(2.0, math.Max(0.05, 0.5/complexityFactor)) // 更精细的自适应范围 / More refined adaptive range
+	flatness := ctx.flatness(math.Min(2.0, math.Max(0.05, 0.5/complexityFactor))) // 更精细的自适应范围 / More refined adaptive range
 	bezierPoints := adaptiveQuadraticBezierFlattening(startPoint, control, endPoint, flatness)
 	// 跳过起点，因为它已经在路径中 / Skip start point as it's already in the path
 	if len(bezierPoints) > 1 {
@@ -309,12 +294,7 @@ func (c *QuadraticCurveToCommand) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *QuadraticCurveToCommand) String() string {
-	if c.Relative {
-		return fmt.Sprintf("q %.2f %.2f %.2f %.2f",
-			c.X1, c.Y1, c.X, c.Y)
-	}
-	return fmt.Sprintf("Q %.2f %.2f %.2f %.2f",
-		c.X1, c.Y1, c.X, c.Y)
+	return formatCommand("Q", c.Relative, c.X1, c.Y1, c.X, c.Y)
 }
 
 // SmoothQuadraticCurveToCommand 表示平滑二次贝塞尔曲线命令
@@ -355,7 +335,7 @@ func (c *SmoothQuadraticCurveToCommand) Execute(ctx *PathContext, precision floa
 	// 基于曲线复杂度的智能flatness：控制点偏离越大，需要更精细的平滑
 	// Intelligent flatness based on curve complexity: greater control point deviation requires finer smoothing
 	complexityFactor := math.Min(10.0, controlDist/math.Max(1.0, curveLength/2))
-	flatness := math.Min(2.0, math.Max(0.05, 0.5/complexityFactor)) // 更精细的自适应范围 / More refined adaptive range
+	flatness := ctx.flatness(math.Min(2.0, math.Max(0.05, 0.5/complexityFactor))) // 更精细的自适应范围 / More refined adaptive range
 	bezierPoints := adaptiveQuadraticBezierFlattening(startPoint, control, endPoint, flatness)
 	// 跳过起点，因为它已经在路径中 / Skip start point as it's already in the path
 	if len(bezierPoints) > 1 {
@@ -366,10 +346,7 @@ func (c *SmoothQuadraticCurveToCommand) Execute(ctx *PathContext, precision floa
 }
 
 func (c *SmoothQuadraticCurveToCommand) String() string {
-	if c.Relative {
-		return fmt.Sprintf("t %.2f %.2f", c.X, c.Y)
-	}
-	return fmt.Sprintf("T %.2f %.2f", c.X, c.Y)
+	return formatCommand("T", c.Relative, c.X, c.Y)
 }
 
 // boolToInt 将布尔值转换为0或1
@@ -442,13 +419,17 @@ func (c *ArcToCommand) Execute(ctx *PathContext, precision float64) {
 	if c.LargeArc == c.Sweep {
 		sign = -1.0
 	}
-	sqrt_val := (rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1) / (rx*rx*y1*y1 + ry*ry*x1*x1)
-	if sqrt_val < 0 {
+	// 以弦方向的单位向量计算，起点终点几乎重合时避免极小量相除 / Work with the unit chord direction so nearly coincident endpoints don't divide tiny quantities
+	halfChord := math.Hypot(x1, y1)
+	ux, uy := x1/halfChord, y1/halfChord
+	k := rx*rx*uy*uy + ry*ry*ux*ux
+	sqrt_val := (rx*rx*ry*ry - halfChord*halfChord*k) / k
+	if sqrt_val < 0 || math.IsNaN(sqrt_val) {
 		sqrt_val = 0
 	}
 	coeff := sign * math.Sqrt(sqrt_val)
-	cx1 := coeff * rx * y1 / ry
-	cy1 := -coeff * ry * x1 / rx
+	cx1 := coeff * rx * uy / ry
+	cy1 := -coeff * ry * ux / rx
 
 	cx := math.Cos(xAxisRot)*cx1 - math.Sin(xAxisRot)*cy1 + (startPoint.X+endPoint.X)/2
 	cy := math.Sin(xAxisRot)*cx1 + math.Cos(xAxisRot)*cy1 + (startPoint.Y+endPoint.Y)/2
@@ -467,6 +448,22 @@ func (c *ArcToCommand) Execute(ctx *PathContext, precision float64) {
 	} else if c.Sweep && dtheta < 0 {
 		dtheta += 2 * math.Pi
 	}
+	// 接近整圆时两端向量几乎相同，atan2可能舍入到错误的一侧使弧塌缩；大弧至少跨越π，据此纠正明显塌缩的情况
+	// Near a full circle both vectors almost coincide and atan2 may round to the wrong side, collapsing the arc; a large arc spans at least π, so clear collapses are corrected
+	if c.LargeArc && math.Abs(dtheta) < math.Pi/2 {
+		if c.Sweep {
+			dtheta += 2 * math.Pi
+		} else {
+			dtheta -= 2 * math.Pi
+		}
+	}
+	if math.IsNaN(theta1) || math.IsNaN(dtheta) {
+		// 无法参数化时退化为直线 / Fall back to a line when the arc cannot be parameterized
+		ctx.Points = append(ctx.Points, endPoint)
+		ctx.CurrentPoint = endPoint
+		ctx.PrevControl = types.Point{}
+		return
+	}
 
 	// 将椭圆弧转换为贝塞尔曲线段 / Convert elliptical arc to Bezier curve segments
 	segments := int(math.Ceil(math.Abs(dtheta) / (math.Pi / 2)))
@@ -503,7 +500,7 @@ func (c *ArcToCommand) Execute(ctx *PathContext, precision float64) {
 		// 使用更精细的flatness值进行自适应平滑化 / Use more refined flatness value for adaptive flattening
 		// 根据曲线复杂度动态调整flatness / Dynamically adjust flatness based on curve complexity
 		curveLength := math.Sqrt(math.Pow(p3.X-p0.X, 2) + math.Pow(p3.Y-p0.Y, 2))
-		flatness := math.Min(1.0, math.Max(0.1, curveLength/100.0)) // 基于曲线长度的自适应flatness / Adaptive flatness based on curve length
+		flatness := ctx.flatness(math.Min(1.0, math.Max(0.1, curveLength/100.0))) // 基于曲线长度的自适应flatness / Adaptive flatness based on curve length
 		bezierPoints := adaptiveCubicBezierFlattening(p0, p1, p2, p3, flatness)
 		// 跳过起点（除了第一段）/ Skip start point (except for first segment)
 		if i == 0 {
@@ -520,12 +517,8 @@ func (c *ArcToCommand) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *ArcToCommand) String() string {
-	if c.Relative {
-		return fmt.Sprintf("a %.2f %.2f %.2f %d %d %.2f %.2f",
-			c.RX, c.RY, c.XAxisRotation, boolToInt(c.LargeArc), boolToInt(c.Sweep), c.X, c.Y)
-	}
-	return fmt.Sprintf("A %.2f %.2f %.2f %d %d %.2f %.2f",
-		c.RX, c.RY, c.XAxisRotation, boolToInt(c.LargeArc), boolToInt(c.Sweep), c.X, c.Y)
+	return formatCommand("A", c.Relative, c.RX, c.RY, c.XAxisRotation,
+		float64(boolToInt(c.LargeArc)), float64(boolToInt(c.Sweep)), c.X, c.Y)
 }
 
 // ClosePathCommand 表示闭合路径命令
@@ -631,8 +624,8 @@ func (c *ArcToAbs) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *ArcToAbs) String() string {
-	return fmt.Sprintf("A %.2f %.2f %.2f %d %d %.2f %.2f",
-		c.RX, c.RY, c.XAxisRotation, boolToInt(c.LargeArc), boolToInt(c.Sweep), c.X, c.Y)
+	return formatCommand("A", false, c.RX, c.RY, c.XAxisRotation,
+		float64(boolToInt(c.LargeArc)), float64(boolToInt(c.Sweep)), c.X, c.Y)
 }
 
 // ArcToRel 表示相对坐标的椭圆弧命令
@@ -726,6 +719,6 @@ func (c *ArcToRel) Execute(ctx *PathContext, precision float64) {
 }
 
 func (c *ArcToRel) String() string {
-	return fmt.Sprintf("a %.2f %.2f %.2f %d %d %.2f %.2f",
-		c.RX, c.RY, c.XAxisRotation, boolToInt(c.LargeArc), boolToInt(c.Sweep), c.X, c.Y)
+	return formatCommand("A", true, c.RX, c.RY, c.XAxisRotation,
+		float64(boolToInt(c.LargeArc)), float64(boolToInt(c.Sweep)), c.X, c.Y)
 }
@@ -0,0 +1,73 @@
+package path
+
+import (
+	"math"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// Dash 按虚线模式将路径拆分为由直线组成的开放线段，offset为进入模式的起始距离
+// Dash splits the path into open line pieces following a dash pattern; offset is the distance into the pattern at which dashing starts
+//
+// 奇数个值的模式会重复一次；每个子路径从模式起点重新开始。模式无效（为空、含负值或总和为0）时返回nil
+// A pattern with an odd number of values is repeated once, and each subpath restarts the pattern. An invalid pattern (empty, negative or summing to zero) returns nil
+func (p *SVGPath) Dash(pattern []float64, offset, precision float64) *SVGPath {
+	if len(pattern)%2 == 1 {
+		pattern = append(append([]float64(nil), pattern...), pattern...)
+	}
+	total := 0.0
+	for _, v := range pattern {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		total += v
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	dashed := &SVGPath{Flatness: p.Flatness}
+	for _, subPath := range p.FlattenSubPaths(precision) {
+		dashPolyline(dashed, subPath, pattern, math.Mod(math.Mod(offset, total)+total, total))
+	}
+	return dashed
+}
+
+// dashPolyline 将一条折线按模式切分并追加到dashed / Cut one polyline by the pattern and append the pieces to dashed
+func dashPolyline(dashed *SVGPath, points []types.Point, pattern []float64, offset float64) {
+	// 从offset处确定模式中的位置 / Locate the position in the pattern at offset
+	index := 0
+	for offset >= pattern[index] {
+		offset -= pattern[index]
+		index = (index + 1) % len(pattern)
+	}
+	remaining := pattern[index] - offset
+	on := index%2 == 0
+	drawing := false
+
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		length := a.Distance(b)
+		travelled := 0.0
+		for length-travelled > 1e-12 {
+			step := math.Min(remaining, length-travelled)
+			from := a.Lerp(b, travelled/length)
+			travelled += step
+			remaining -= step
+			if on && step > 0 {
+				if !drawing {
+					dashed.Commands = append(dashed.Commands, &MoveToCommand{X: from.X, Y: from.Y})
+					drawing = true
+				}
+				to := a.Lerp(b, travelled/length)
+				dashed.Commands = append(dashed.Commands, &LineToCommand{X: to.X, Y: to.Y})
+			}
+			if remaining <= 1e-12 {
+				index = (index + 1) % len(pattern)
+				remaining = pattern[index]
+				on = index%2 == 0
+				drawing = false
+			}
+		}
+	}
+}
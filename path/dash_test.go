@@ -0,0 +1,37 @@
+package path
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDash 测试虚线模式切分直线并在子路径间重新开始 / Test dashing cuts lines and restarts per subpath
+func TestDash(t *testing.T) {
+	p, err := ParsePath("M 0 0 L 10 0 L 10 10 M 0 20 L 7 20")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+
+	// 模式按折线长度连续计算，每个子路径重新开始 / The pattern runs on along the polyline's length and restarts for each subpath
+	dashed := p.Dash([]float64{4, 2}, 0, 0.1)
+	if got, want := dashed.String(), "M 0 0 L 4 0 M 6 0 L 10 0 M 10 2 L 10 6 M 10 8 L 10 10 M 0 20 L 4 20 M 6 20 L 7 20"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	// 跨越拐角的虚线保持连续 / A dash crossing a corner stays connected
+	if got := p.Dash([]float64{5, 1}, 0, 0.1).String(); !strings.HasPrefix(got, "M 0 0 L 5 0 M 6 0 L 10 0 L 10 1 M 10 2") {
+		t.Errorf("expected the second dash to turn the corner, got %q", got)
+	}
+
+	// 奇数模式重复一次，偏移从模式中间开始 / An odd pattern repeats once and the offset starts mid-pattern
+	offsetDash := p.Dash([]float64{3}, 4, 0.1)
+	if got := offsetDash.Commands[0].(*MoveToCommand); got.X != 2 || got.Y != 0 {
+		t.Errorf("expected the first dash to start at 2,0 after the gap, got %v", got)
+	}
+
+	for _, pattern := range [][]float64{nil, {0, 0}, {2, -1}} {
+		if p.Dash(pattern, 0, 0.1) != nil {
+			t.Errorf("expected pattern %v to be rejected", pattern)
+		}
+	}
+}
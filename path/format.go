@@ -0,0 +1,191 @@
+package path
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// Precision 序列化路径坐标及构建器生成的数值属性时保留的小数位数，-1表示使用能精确还原数值的最短表示
+// Precision is the number of decimal places kept when serializing path coordinates and the numeric attributes builders generate; -1 uses the shortest exact representation
+//
+// 较小的精度可减小文件体积，代价是坐标被舍入 / Lower precision shrinks output at the cost of rounding coordinates
+var Precision = -1
+
+// RelativeCommands 为true时，String对每条命令选择绝对和相对形式中较短的一种，几何形状不变
+// RelativeCommands makes String pick the shorter of each command's absolute and relative forms, leaving the geometry unchanged
+//
+// 相对坐标只在重新解析后与绝对坐标按Precision格式化结果一致时使用，舍入误差不会沿路径累积
+// Relative coordinates are only used when they re-parse to values that format like the absolute ones under Precision, so rounding errors never accumulate along the path
+var RelativeCommands = false
+
+// FormatNumber 按Precision格式化数值并去掉多余的尾随零，生成SVG数值的代码都应经由此函数
+// FormatNumber formats a number per Precision, dropping redundant trailing zeros; all code generating SVG numbers should go through it
+func FormatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', Precision, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// formatCommand 生成路径命令字符串，相对命令使用小写字母 / Build a path command string, lowercasing the letter for relative commands
+func formatCommand(letter string, relative bool, values ...float64) string {
+	if relative {
+		letter = strings.ToLower(letter)
+	}
+	var sb strings.Builder
+	sb.WriteString(letter)
+	for _, v := range values {
+		sb.WriteByte(' ')
+		sb.WriteString(FormatNumber(v))
+	}
+	return sb.String()
+}
+
+// String 将路径序列化为d属性字符串 / Serialize the path into a d attribute string
+func (p *SVGPath) String() string {
+	if RelativeCommands {
+		return p.compactString()
+	}
+	parts := make([]string, len(p.Commands))
+	for i, cmd := range p.Commands {
+		parts[i] = cmd.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// 命令数值所属的坐标轴 / Coordinate axes of command values
+const (
+	axisNone = iota
+	axisX
+	axisY
+)
+
+// compactString 逐条命令输出较短的形式，并跟踪解析器将重建的当前点
+// compactString writes the shorter form of each command, tracking the current point a parser will reconstruct
+func (p *SVGPath) compactString() string {
+	ctx := p.newContext()
+	// parsed 和 parsedStart 为解析输出时得到的当前点和子路径起点 / parsed and parsedStart are the current point and subpath start a parser of the output gets
+	var parsed, parsedStart types.Point
+	parts := make([]string, len(p.Commands))
+	for i, cmd := range p.Commands {
+		start := ctx.CurrentPoint
+		cmd.Execute(ctx, 1)
+
+		letter, values, axes, relative := absoluteValues(cmd, start)
+		if letter == "" {
+			parts[i] = cmd.String()
+			if _, ok := cmd.(*ClosePathCommand); ok {
+				parsed = parsedStart
+			}
+			continue
+		}
+
+		absolute := formatCommand(letter, false, values...)
+		deltas := make([]float64, len(values))
+		valid := true
+		for j, v := range values {
+			switch axes[j] {
+			case axisX:
+				deltas[j] = roundTrip(v - parsed.X)
+				valid = valid && FormatNumber(parsed.X+deltas[j]) == FormatNumber(v)
+			case axisY:
+				deltas[j] = roundTrip(v - parsed.Y)
+				valid = valid && FormatNumber(parsed.Y+deltas[j]) == FormatNumber(v)
+			default:
+				deltas[j] = v
+			}
+		}
+		shorter := formatCommand(letter, true, deltas...)
+		useRelative := valid && (len(shorter) < len(absolute) || (len(shorter) == len(absolute) && relative))
+
+		end := parsed
+		for j, v := range values {
+			switch {
+			case axes[j] == axisX && useRelative:
+				end.X = parsed.X + deltas[j]
+			case axes[j] == axisY && useRelative:
+				end.Y = parsed.Y + deltas[j]
+			case axes[j] == axisX:
+				end.X = roundTrip(v)
+			case axes[j] == axisY:
+				end.Y = roundTrip(v)
+			}
+		}
+		parsed = end
+		if letter == "M" {
+			parsedStart = parsed
+		}
+
+		if useRelative {
+			parts[i] = shorter
+		} else {
+			parts[i] = absolute
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// absoluteValues 返回命令的字母、绝对坐标形式的数值、各数值所属坐标轴以及命令原本是否为相对形式，无坐标的命令返回空字母
+// absoluteValues returns a command's letter, its values in absolute form, the axis of each value and whether the command was relative; commands without coordinates return an empty letter
+func absoluteValues(cmd Command, start types.Point) (letter string, values []float64, axes []int, relative bool) {
+	xy := []int{axisX, axisY}
+	switch c := cmd.(type) {
+	case *MoveToCommand:
+		letter, values, axes, relative = "M", []float64{c.X, c.Y}, xy, c.Relative
+	case *LineToCommand:
+		letter, values, axes, relative = "L", []float64{c.X, c.Y}, xy, c.Relative
+	case *HorizontalLineToCommand:
+		letter, values, axes, relative = "H", []float64{c.X}, []int{axisX}, c.Relative
+	case *VerticalLineToCommand:
+		letter, values, axes, relative = "V", []float64{c.Y}, []int{axisY}, c.Relative
+	case *CubicCurveToCommand:
+		letter, values, axes, relative = "C", []float64{c.X1, c.Y1, c.X2, c.Y2, c.X, c.Y}, []int{axisX, axisY, axisX, axisY, axisX, axisY}, c.Relative
+	case *SmoothCubicCurveToCommand:
+		letter, values, axes, relative = "S", []float64{c.X2, c.Y2, c.X, c.Y}, []int{axisX, axisY, axisX, axisY}, c.Relative
+	case *QuadraticCurveToCommand:
+		letter, values, axes, relative = "Q", []float64{c.X1, c.Y1, c.X, c.Y}, []int{axisX, axisY, axisX, axisY}, c.Relative
+	case *SmoothQuadraticCurveToCommand:
+		letter, values, axes, relative = "T", []float64{c.X, c.Y}, xy, c.Relative
+	case *ArcToCommand:
+		letter, values, relative = "A", arcValues(c.RX, c.RY, c.XAxisRotation, c.LargeArc, c.Sweep, c.X, c.Y), c.Relative
+	case *ArcToAbs:
+		letter, values = "A", arcValues(c.RX, c.RY, c.XAxisRotation, c.LargeArc, c.Sweep, c.X, c.Y)
+	case *ArcToRel:
+		letter, values, relative = "A", arcValues(c.RX, c.RY, c.XAxisRotation, c.LargeArc, c.Sweep, c.X, c.Y), true
+	default:
+		return "", nil, nil, false
+	}
+	if letter == "A" {
+		axes = []int{axisNone, axisNone, axisNone, axisNone, axisNone, axisX, axisY}
+	}
+
+	if relative {
+		values = append([]float64(nil), values...)
+		for i := range values {
+			switch axes[i] {
+			case axisX:
+				values[i] += start.X
+			case axisY:
+				values[i] += start.Y
+			}
+		}
+	}
+	return letter, values, axes, relative
+}
+
+// arcValues 按A命令的参数顺序排列弧的数值 / Arrange an arc's values in A command parameter order
+func arcValues(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) []float64 {
+	return []float64{rx, ry, rotation, float64(boolToInt(largeArc)), float64(boolToInt(sweep)), x, y}
+}
+
+// roundTrip 返回数值序列化后再解析得到的值 / Return the value a number parses back to after serialization
+func roundTrip(v float64) float64 {
+	parsed, _ := strconv.ParseFloat(FormatNumber(v), 64)
+	return parsed
+}
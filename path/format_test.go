@@ -0,0 +1,72 @@
+package path
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPrecision 测试不同精度下的路径序列化 / Test serializing a path at different precisions
+func TestPrecision(t *testing.T) {
+	p, err := ParsePath("M 1.23456 2.5 C 3.14159 0 6.66666 1.99999 10 -0.00001 l 0.5 0.25 Z")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	defer func(old int) { Precision = old }(Precision)
+
+	Precision = 1
+	coarse := p.String()
+	if want := "M 1.2 2.5 C 3.1 0 6.7 2 10 0 l 0.5 0.2 Z"; coarse != want {
+		t.Errorf("expected %q, got %q", want, coarse)
+	}
+
+	Precision = 4
+	fine := p.String()
+	if want := "M 1.2346 2.5 C 3.1416 0 6.6667 2 10 0 l 0.5 0.25 Z"; fine != want {
+		t.Errorf("expected %q, got %q", want, fine)
+	}
+	if len(coarse) >= len(fine) {
+		t.Errorf("expected lower precision to be shorter: %d vs %d", len(coarse), len(fine))
+	}
+
+	Precision = -1
+	if exact := p.String(); !strings.Contains(exact, "1.23456") || !strings.Contains(exact, "1.99999") {
+		t.Errorf("expected the shortest exact form by default, got %q", exact)
+	}
+}
+
+// TestRelativeCommands 测试相对命令序列化更短且重新解析后展平点不变 / Test relative serialization is shorter and re-parses to the same flattened points
+func TestRelativeCommands(t *testing.T) {
+	p, err := ParsePath("M 1000.5 2000.25 L 1010.5 2000.25 H 1020 V 2010.75 C 1030 2020 1040.1 2020 1050 2010.75 " +
+		"S 1070 2000 1080 2010.75 Q 1090 2030 1100 2010.75 T 1120 2010.75 A 5 5 0 0 1 1130 2010.75 Z " +
+		"M 0.1 0.2 L 0.3 0.4 Z")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	defer func(old bool) { RelativeCommands = old }(RelativeCommands)
+
+	RelativeCommands = false
+	absolute := p.String()
+	RelativeCommands = true
+	relative := p.String()
+
+	if len(relative) >= len(absolute) {
+		t.Errorf("expected the relative form to be shorter:\n%s\n%s", relative, absolute)
+	}
+	if !strings.Contains(relative, "l 10 0") || !strings.Contains(relative, "a 5 5 0 0 1 10 0") {
+		t.Errorf("expected relative commands, got %q", relative)
+	}
+	// 0.1+0.2无法精确表示为0.3，保持绝对坐标 / 0.1+0.2 is not exactly 0.3, so the absolute coordinate is kept
+	if !strings.HasSuffix(relative, "M 0.1 0.2 L 0.3 0.4 Z") {
+		t.Errorf("expected inexact deltas to stay absolute, got %q", relative)
+	}
+
+	reparsed, err := ParsePath(relative)
+	if err != nil {
+		t.Fatalf("ParsePath of %q failed: %v", relative, err)
+	}
+	want, got := p.FlattenPath(0.5), reparsed.FlattenPath(0.5)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expected the relative form to flatten to the same points:\n%v\n%v", want, got)
+	}
+}
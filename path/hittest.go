@@ -0,0 +1,78 @@
+package path
+
+import (
+	"strings"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// FillRule 填充规则，决定自相交或嵌套路径的内部 / Fill rule deciding the interior of self-intersecting or nested paths
+type FillRule int
+
+const (
+	FillRuleNonZero FillRule = iota // 非零缠绕规则 / Nonzero winding rule
+	FillRuleEvenOdd                 // 奇偶规则 / Even-odd rule
+)
+
+// ParseFillRule 解析fill-rule属性值，无法识别时返回非零规则 / Parse a fill-rule value, defaulting to nonzero
+func ParseFillRule(value string) FillRule {
+	if strings.TrimSpace(value) == "evenodd" {
+		return FillRuleEvenOdd
+	}
+	return FillRuleNonZero
+}
+
+// Contains 判断点是否位于路径填充区域内，每个子路径都视为隐式闭合
+// Contains reports whether a point lies inside the path's fill area, treating every subpath as implicitly closed
+//
+// precision为曲线展平容差，0表示自适应 / precision is the curve flattening tolerance, 0 for adaptive
+func (p *SVGPath) Contains(x, y float64, rule FillRule, precision float64) bool {
+	winding := 0
+	crossings := 0
+	for _, subPath := range p.flattenForFill(precision) {
+		n := len(subPath)
+		if n < 3 {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			a, b := subPath[i], subPath[(i+1)%n]
+			// 向右的水平射线与边相交 / A horizontal ray to the right crosses the edge
+			if (a.Y <= y) == (b.Y <= y) {
+				continue
+			}
+			cross := (b.X-a.X)*(y-a.Y) - (x-a.X)*(b.Y-a.Y)
+			if a.Y <= y {
+				if cross > 0 {
+					winding++
+					crossings++
+				}
+			} else if cross < 0 {
+				winding--
+				crossings++
+			}
+		}
+	}
+
+	if rule == FillRuleEvenOdd {
+		return crossings%2 == 1
+	}
+	return winding != 0
+}
+
+// flattenForFill 按MoveTo拆分子路径并展平 / Flatten the path into subpaths split at every MoveTo
+func (p *SVGPath) flattenForFill(precision float64) [][]types.Point {
+	ctx := p.newContext()
+	var subPaths [][]types.Point
+	start := 0
+	for _, cmd := range p.Commands {
+		if _, isMove := cmd.(*MoveToCommand); isMove && len(ctx.Points) > start {
+			subPaths = append(subPaths, ctx.Points[start:])
+			start = len(ctx.Points)
+		}
+		cmd.Execute(ctx, precision)
+	}
+	if len(ctx.Points) > start {
+		subPaths = append(subPaths, ctx.Points[start:])
+	}
+	return subPaths
+}
@@ -0,0 +1,37 @@
+package path
+
+import "testing"
+
+const heartPath = "M 50 30 C 50 25 45 15 30 15 C 10 15 10 40 10 40 C 10 55 30 75 50 90 C 70 75 90 55 90 40 C 90 40 90 15 70 15 C 55 15 50 25 50 30 Z"
+
+// TestContains 测试路径命中检测 / Test path hit testing
+func TestContains(t *testing.T) {
+	heart, err := ParsePath(heartPath)
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+
+	for _, rule := range []FillRule{FillRuleNonZero, FillRuleEvenOdd} {
+		if !heart.Contains(30, 35, rule, 0.1) || !heart.Contains(50, 60, rule, 0.1) {
+			t.Errorf("rule %d: expected points inside the heart", rule)
+		}
+		if heart.Contains(50, 20, rule, 0.1) || heart.Contains(5, 5, rule, 0.1) || heart.Contains(50, 95, rule, 0.1) {
+			t.Errorf("rule %d: expected points outside the heart", rule)
+		}
+	}
+
+	// 同向的内层子路径：非零规则为内部，奇偶规则为空洞 / A same-direction inner subpath: inside for nonzero, a hole for even-odd
+	nested, err := ParsePath(heartPath + " M 40 40 L 40 60 L 60 60 L 60 40 Z")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	if !nested.Contains(50, 50, FillRuleNonZero, 0.1) {
+		t.Error("expected the nested square to be filled under nonzero")
+	}
+	if nested.Contains(50, 50, FillRuleEvenOdd, 0.1) {
+		t.Error("expected the nested square to be a hole under evenodd")
+	}
+	if ParseFillRule("evenodd") != FillRuleEvenOdd || ParseFillRule("") != FillRuleNonZero {
+		t.Error("unexpected fill-rule parsing")
+	}
+}
@@ -5,3 +5,11 @@ type Command interface {
 	Execute(ctx *PathContext, precision float64)
 	String() string
 }
+
+// ForEachCommand 按顺序对每条命令调用fn，命令为指针，fn可就地修改坐标
+// ForEachCommand calls fn for each command in order; commands are pointers, so fn may rewrite coordinates in place
+func (p *SVGPath) ForEachCommand(fn func(cmd Command)) {
+	for _, cmd := range p.Commands {
+		fn(cmd)
+	}
+}
@@ -0,0 +1,172 @@
+package path
+
+import (
+	"math"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// 8点高斯-勒让德求积在[-1,1]上的节点和权重 / Nodes and weights of 8-point Gauss-Legendre quadrature on [-1,1]
+var (
+	gaussLegendreNodes   = [8]float64{-0.9602898564975363, -0.7966664774136267, -0.5255324099163290, -0.1834346424956498, 0.1834346424956498, 0.5255324099163290, 0.7966664774136267, 0.9602898564975363}
+	gaussLegendreWeights = [8]float64{0.1012285362903763, 0.2223810344533745, 0.3137066458778873, 0.3626837833783620, 0.3626837833783620, 0.3137066458778873, 0.2223810344533745, 0.1012285362903763}
+)
+
+// bezierSegment 直线（2个点）、二次（3个点）或三次（4个点）贝塞尔段 / A line (2 points), quadratic (3 points) or cubic (4 points) Bezier segment
+type bezierSegment []types.Point
+
+// CubicBezierLength 使用高斯-勒让德求积计算三次贝塞尔曲线长度 / Compute the length of a cubic Bezier curve by Gauss-Legendre quadrature
+func CubicBezierLength(p0, p1, p2, p3 types.Point) float64 {
+	return bezierSegment{p0, p1, p2, p3}.length()
+}
+
+// QuadraticBezierLength 使用高斯-勒让德求积计算二次贝塞尔曲线长度 / Compute the length of a quadratic Bezier curve by Gauss-Legendre quadrature
+func QuadraticBezierLength(p0, p1, p2 types.Point) float64 {
+	return bezierSegment{p0, p1, p2}.length()
+}
+
+// point 用de Casteljau算法求t处的点 / Evaluate the point at t with de Casteljau's algorithm
+func (s bezierSegment) point(t float64) types.Point {
+	return deCasteljau(append([]types.Point(nil), s...), t)
+}
+
+// derivative 返回t处的导数向量 / Return the derivative vector at t
+func (s bezierSegment) derivative(t float64) types.Point {
+	n := float64(len(s) - 1)
+	diffs := make([]types.Point, len(s)-1)
+	for i := range diffs {
+		diffs[i] = types.Point{X: n * (s[i+1].X - s[i].X), Y: n * (s[i+1].Y - s[i].Y)}
+	}
+	return deCasteljau(diffs, t)
+}
+
+// deCasteljau 对控制点就地插值求值 / Evaluate control points in place by repeated interpolation
+func deCasteljau(points []types.Point, t float64) types.Point {
+	for n := len(points) - 1; n > 0; n-- {
+		for i := 0; i < n; i++ {
+			points[i] = types.Point{X: points[i].X + (points[i+1].X-points[i].X)*t, Y: points[i].Y + (points[i+1].Y-points[i].Y)*t}
+		}
+	}
+	return points[0]
+}
+
+// speed 返回t处的速率 / Return the speed at t
+func (s bezierSegment) speed(t float64) float64 {
+	d := s.derivative(t)
+	return math.Hypot(d.X, d.Y)
+}
+
+// angle 返回t处的切线方向（弧度），导数为零时退化为弦方向 / Return the tangent direction at t in radians, falling back to the chord when the derivative vanishes
+func (s bezierSegment) angle(t float64) float64 {
+	d := s.derivative(t)
+	if d.X == 0 && d.Y == 0 {
+		// 控制点与端点重合时在稍内侧取切线 / Coincident control points: take the tangent slightly inside
+		d = s.derivative(math.Min(math.Max(t, 1e-6), 1-1e-6))
+		if d.X == 0 && d.Y == 0 {
+			last := s[len(s)-1]
+			d = types.Point{X: last.X - s[0].X, Y: last.Y - s[0].Y}
+		}
+	}
+	return math.Atan2(d.Y, d.X)
+}
+
+// length 返回整段长度 / Return the length of the whole segment
+func (s bezierSegment) length() float64 {
+	return s.lengthBetween(0, 1)
+}
+
+// lengthBetween 自适应地对速率积分，得到参数t0到t1之间的弧长 / Adaptively integrate the speed to get the arc length between parameters t0 and t1
+func (s bezierSegment) lengthBetween(t0, t1 float64) float64 {
+	if len(s) == 2 {
+		return math.Hypot(s[1].X-s[0].X, s[1].Y-s[0].Y) * (t1 - t0)
+	}
+	return s.integrate(t0, t1, s.gaussLegendre(t0, t1), 0)
+}
+
+// integrate 比较整体与两半的积分，误差过大时二分递归 / Compare the whole interval against its halves and bisect while they disagree
+func (s bezierSegment) integrate(t0, t1, whole float64, depth int) float64 {
+	mid := (t0 + t1) / 2
+	left, right := s.gaussLegendre(t0, mid), s.gaussLegendre(mid, t1)
+	if depth >= 16 || math.Abs(left+right-whole) <= 1e-10*math.Max(1, whole) {
+		return left + right
+	}
+	return s.integrate(t0, mid, left, depth+1) + s.integrate(mid, t1, right, depth+1)
+}
+
+// gaussLegendre 在[t0,t1]上对速率做一次8点求积 / Apply one 8-point quadrature of the speed over [t0,t1]
+func (s bezierSegment) gaussLegendre(t0, t1 float64) float64 {
+	half, center := (t1-t0)/2, (t0+t1)/2
+	sum := 0.0
+	for i, x := range gaussLegendreNodes {
+		sum += gaussLegendreWeights[i] * s.speed(center+half*x)
+	}
+	return sum * half
+}
+
+// paramAtLength 返回从起点起弧长为length处的参数t / Return the parameter t at arc length length from the start
+//
+// 牛顿迭代并以二分区间保证收敛 / Newton iteration safeguarded by a bisection bracket
+func (s bezierSegment) paramAtLength(length, total float64) float64 {
+	if length <= 0 || total <= 0 {
+		return 0
+	}
+	if length >= total {
+		return 1
+	}
+	lo, hi := 0.0, 1.0
+	t := length / total
+	for i := 0; i < 32; i++ {
+		diff := s.lengthBetween(0, t) - length
+		if math.Abs(diff) <= 1e-9*math.Max(1, total) {
+			break
+		}
+		if diff > 0 {
+			hi = t
+		} else {
+			lo = t
+		}
+		next := t - diff/s.speed(t)
+		if math.IsNaN(next) || math.IsInf(next, 0) || next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		t = next
+	}
+	return t
+}
+
+// segments 将路径拆分为贝塞尔段，曲线保持解析形式，弧等其他命令按展平折线表示，子路径间的移动不产生段
+// segments splits the path into Bezier segments, keeping curves analytic and representing arcs and other commands by their flattened polylines; moves between subpaths produce no segment
+func (p *SVGPath) segments(precision float64) []bezierSegment {
+	ctx := p.newContext()
+	var segments []bezierSegment
+	for _, cmd := range p.Commands {
+		start, prevControl, flattened := ctx.CurrentPoint, ctx.PrevControl, len(ctx.Points)
+		cmd.Execute(ctx, precision)
+
+		switch c := cmd.(type) {
+		case *MoveToCommand:
+		case *CubicCurveToCommand:
+			control1 := types.Point{X: c.X1, Y: c.Y1}
+			if c.Relative {
+				control1 = types.Point{X: start.X + c.X1, Y: start.Y + c.Y1}
+			}
+			segments = append(segments, bezierSegment{start, control1, ctx.PrevControl, ctx.CurrentPoint})
+		case *SmoothCubicCurveToCommand:
+			// 与Execute相同地反射上一个控制点 / Reflect the previous control point as Execute does
+			control1 := start
+			if prevControl != (types.Point{}) {
+				control1 = types.Point{X: 2*start.X - prevControl.X, Y: 2*start.Y - prevControl.Y}
+			}
+			segments = append(segments, bezierSegment{start, control1, ctx.PrevControl, ctx.CurrentPoint})
+		case *QuadraticCurveToCommand, *SmoothQuadraticCurveToCommand:
+			segments = append(segments, bezierSegment{start, ctx.PrevControl, ctx.CurrentPoint})
+		default:
+			previous := start
+			for _, point := range ctx.Points[flattened:] {
+				segments = append(segments, bezierSegment{previous, point})
+				previous = point
+			}
+		}
+	}
+	return segments
+}
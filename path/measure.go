@@ -0,0 +1,84 @@
+package path
+
+import (
+	"math"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// TotalLength 返回路径的总长度，曲线按求积计算，子路径之间的移动不计入
+// TotalLength returns the length of the path, measuring curves by quadrature; moves between subpaths do not count
+//
+// precision为弧等展平命令的容差，0表示自适应 / precision is the flattening tolerance for arcs and other flattened commands, 0 for adaptive
+func (p *SVGPath) TotalLength(precision float64) float64 {
+	total := 0.0
+	for _, segment := range p.segments(precision) {
+		total += segment.length()
+	}
+	return total
+}
+
+// PointAtLength 返回沿路径距离起点length处的点及该处切线方向（弧度），length被限制在路径长度范围内
+// PointAtLength returns the point at distance length along the path and the tangent direction there in radians; length is clamped to the path
+func (p *SVGPath) PointAtLength(length, precision float64) (types.Point, float64) {
+	var last types.Point
+	angle := 0.0
+	found := false
+	for _, segment := range p.segments(precision) {
+		segmentLength := segment.length()
+		if segmentLength == 0 {
+			continue
+		}
+		if !found && length <= 0 {
+			return segment[0], segment.angle(0)
+		}
+		found = true
+		if length <= segmentLength {
+			t := segment.paramAtLength(length, segmentLength)
+			return segment.point(t), segment.angle(t)
+		}
+		length -= segmentLength
+		last, angle = segment[len(segment)-1], segment.angle(1)
+	}
+	return last, angle
+}
+
+// SplitAtLength 在距离起点dist处切开展平后的路径，按子路径返回切点之前和之后的折线，被切开的子路径两段都包含切点
+// SplitAtLength cuts the flattened path at distance dist from the start and returns the polylines before and after the cut, one per subpath, with both pieces of the cut subpath containing the cut point
+//
+// 子路径之间的移动不计入距离；dist被限制在路径长度范围内，precision为展平容差
+// Moves between subpaths do not count towards the distance; dist is clamped to the path, and precision is the flattening tolerance
+func (p *SVGPath) SplitAtLength(dist, precision float64) (before, after [][]types.Point) {
+	subPaths := p.FlattenSubPaths(precision)
+	for i, points := range subPaths {
+		if after != nil {
+			after = append(after, points)
+			continue
+		}
+		var head []types.Point
+		for j := range points {
+			if j > 0 {
+				length := points[j-1].Distance(points[j])
+				if length > 0 && dist < length {
+					cut := points[j-1].Lerp(points[j], math.Max(0, dist)/length)
+					// 切点落在顶点上时不重复该顶点 / Do not repeat a vertex the cut lands on
+					if cut != head[len(head)-1] {
+						head = append(head, cut)
+					}
+					after = [][]types.Point{append([]types.Point{cut}, points[j:]...)}
+					break
+				}
+				dist -= length
+			}
+			head = append(head, points[j])
+		}
+		if len(head) > 0 {
+			before = append(before, head)
+		}
+		if after == nil && i == len(subPaths)-1 && len(head) > 0 {
+			// 超出路径长度时整条路径都在切点之前 / Past the end the whole path lies before the cut
+			after = [][]types.Point{{head[len(head)-1]}}
+		}
+	}
+	return before, after
+}
@@ -0,0 +1,124 @@
+package path
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// TestPointAtLength 测试沿路径按长度取点及切线 / Test sampling points and tangents along a path by length
+func TestPointAtLength(t *testing.T) {
+	p, err := ParsePath("M 0 0 L 10 0 L 10 10 M 20 20 L 20 30")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	if total := p.TotalLength(0); total != 30 {
+		t.Errorf("expected length 30 excluding the move, got %f", total)
+	}
+
+	cases := []struct {
+		length, x, y, angle float64
+	}{
+		{-5, 0, 0, 0},
+		{4, 4, 0, 0},
+		{15, 10, 5, math.Pi / 2},
+		{25, 20, 25, math.Pi / 2},
+		{50, 20, 30, math.Pi / 2},
+	}
+	for _, c := range cases {
+		pt, angle := p.PointAtLength(c.length, 0)
+		if math.Abs(pt.X-c.x) > 1e-9 || math.Abs(pt.Y-c.y) > 1e-9 || math.Abs(angle-c.angle) > 1e-9 {
+			t.Errorf("PointAtLength(%v) = %v, %v; want (%v, %v), %v", c.length, pt, angle, c.x, c.y, c.angle)
+		}
+	}
+}
+
+// TestCubicBezierLength 测试求积长度与高分辨率展平长度一致 / Test the quadrature length matches a very high resolution flattened length
+func TestCubicBezierLength(t *testing.T) {
+	p0, p1, p2, p3 := types.Point{X: 0, Y: 0}, types.Point{X: 0, Y: 100}, types.Point{X: 100, Y: 100}, types.Point{X: 100, Y: 0}
+	curve := bezierSegment{p0, p1, p2, p3}
+
+	const steps = 200000
+	flattened := 0.0
+	previous := p0
+	for i := 1; i <= steps; i++ {
+		point := curve.point(float64(i) / steps)
+		flattened += math.Hypot(point.X-previous.X, point.Y-previous.Y)
+		previous = point
+	}
+
+	length := CubicBezierLength(p0, p1, p2, p3)
+	if math.Abs(length-flattened) > 1e-6 {
+		t.Errorf("expected quadrature length %.9f to match flattened length %.9f", length, flattened)
+	}
+
+	p, err := ParsePath("M 0 0 C 0 100 100 100 100 0")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	if total := p.TotalLength(0); math.Abs(total-length) > 1e-9 {
+		t.Errorf("expected TotalLength %.9f to use the quadrature length %.9f", total, length)
+	}
+	// 对称曲线的弧长中点位于t=0.5 / The arc-length midpoint of a symmetric curve lies at t=0.5
+	mid, angle := p.PointAtLength(length/2, 0)
+	if math.Abs(mid.X-50) > 1e-6 || math.Abs(mid.Y-75) > 1e-6 || math.Abs(angle) > 1e-6 {
+		t.Errorf("expected midpoint (50, 75) with a horizontal tangent, got %v, %v", mid, angle)
+	}
+}
+
+// TestSplitAtLength 测试按长度切分路径，切点两侧长度与切点位置正确 / Test splitting a path by length yields the right lengths on both sides of the cut
+func TestSplitAtLength(t *testing.T) {
+	polylineLength := func(polylines [][]types.Point) float64 {
+		total := 0.0
+		for _, points := range polylines {
+			for i := 1; i < len(points); i++ {
+				total += points[i-1].Distance(points[i])
+			}
+		}
+		return total
+	}
+
+	p, err := ParsePath("M 0 0 L 100 0")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	before, after := p.SplitAtLength(p.TotalLength(0)/2, 0)
+	if a, b := polylineLength(before), polylineLength(after); math.Abs(a-b) > 1e-9 || math.Abs(a-50) > 1e-9 {
+		t.Errorf("expected two halves of length 50, got %v and %v", a, b)
+	}
+	if len(before) != 1 || len(after) != 1 || before[0][len(before[0])-1] != (types.Point{X: 50, Y: 0}) || after[0][0] != (types.Point{X: 50, Y: 0}) {
+		t.Errorf("expected both halves to meet at (50,0), got %v and %v", before, after)
+	}
+
+	// 切点落在顶点上时不重复顶点，超出范围时被限制 / A cut on a vertex does not repeat it, and out-of-range distances are clamped
+	p, err = ParsePath("M 0 0 L 10 0 L 10 10")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	if before, after := p.SplitAtLength(10, 0); len(before[0]) != 2 || len(after[0]) != 2 {
+		t.Errorf("expected the cut at the corner to split into 2 and 2 points, got %v and %v", before, after)
+	}
+	if before, after := p.SplitAtLength(-1, 0); len(before[0]) != 1 || polylineLength(after) != 20 {
+		t.Errorf("expected an empty prefix for a negative distance, got %v and %v", before, after)
+	}
+	if before, after := p.SplitAtLength(99, 0); polylineLength(before) != 20 || len(after[0]) != 1 {
+		t.Errorf("expected an empty suffix past the end, got %v and %v", before, after)
+	}
+
+	// 子路径分别返回，不被连成一条折线 / Subpaths come back separately instead of joined into one polyline
+	p, err = ParsePath("M 0 0 L 10 0 M 0 10 L 10 10 M 0 20 L 10 20")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	before, after = p.SplitAtLength(15, 0)
+	if len(before) != 2 || len(after) != 2 {
+		t.Fatalf("expected 2 subpaths on each side, got %v and %v", before, after)
+	}
+	if got := before[1]; len(got) != 2 || got[1] != (types.Point{X: 5, Y: 10}) {
+		t.Errorf("expected the second subpath to end at the cut (5,10), got %v", got)
+	}
+	if got := after[1]; got[0] != (types.Point{X: 0, Y: 20}) {
+		t.Errorf("expected the last subpath to start at its own moveto, got %v", got)
+	}
+}
@@ -0,0 +1,310 @@
+package path
+
+import (
+	"math"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// StrokeJoinStyle 线段连接样式 / Stroke join style
+type StrokeJoinStyle int
+
+const (
+	JoinMiter StrokeJoinStyle = iota // 尖角连接 / Miter join
+	JoinRound                        // 圆角连接 / Round join
+	JoinBevel                        // 斜角连接 / Bevel join
+)
+
+// OffsetPolyline 生成折线一侧的偏移路径，拐角按连接样式处理
+// OffsetPolyline generates the offset of a polyline on one side, joining corners with the given style
+func OffsetPolyline(path []types.Point, offset float64, isLeft bool, join StrokeJoinStyle, miterLimit float64) []types.Point {
+	if len(path) < 2 {
+		return nil
+	}
+
+	offsetPath := make([]types.Point, 0)
+
+	for i := 0; i < len(path)-1; i++ {
+		current := path[i]
+		next := path[i+1]
+
+		// 计算线段的单位法向量 / Calculate the segment's unit normal
+		direction := next.Sub(current)
+		if direction.Length() < 1e-10 {
+			continue // 跳过长度为0的线段 / Skip zero-length segments
+		}
+		direction = direction.Normalize()
+		normal := types.Point{X: -direction.Y, Y: direction.X}
+
+		// 根据左右侧调整法向量方向 / Adjust normal direction for left/right
+		if !isLeft {
+			normal = normal.Scale(-1)
+		}
+
+		// 计算偏移点 / Calculate offset points
+		offsetStart := current.Add(normal.Scale(offset))
+		offsetEnd := next.Add(normal.Scale(offset))
+
+		// 处理线段连接 / Handle segment joins
+		if i == 0 {
+			// 第一个线段，直接添加起点 / First segment, add start point directly
+			offsetPath = append(offsetPath, offsetStart)
+		} else {
+			// 处理与前一个线段的连接 / Handle join with previous segment
+			joinPoints := JoinPoints(path[i-1], current, next, offset, isLeft, join, miterLimit)
+			offsetPath = append(offsetPath, joinPoints...)
+		}
+
+		// 添加线段终点 / Add segment end point
+		if i == len(path)-2 {
+			// 最后一个线段，添加终点 / Last segment, add end point
+			offsetPath = append(offsetPath, offsetEnd)
+		}
+	}
+
+	return offsetPath
+}
+
+// JoinPoints 生成两条线段在current处的偏移连接点 / Generate the offset join points of two segments meeting at current
+func JoinPoints(prev, current, next types.Point, offset float64, isLeft bool, join StrokeJoinStyle, miterLimit float64) []types.Point {
+	joinPoints := make([]types.Point, 0)
+
+	// 计算前一个线段的方向 / Calculate previous segment direction
+	prevDx := current.X - prev.X
+	prevDy := current.Y - prev.Y
+	prevLength := math.Sqrt(prevDx*prevDx + prevDy*prevDy)
+
+	// 计算下一个线段的方向 / Calculate next segment direction
+	nextDx := next.X - current.X
+	nextDy := next.Y - current.Y
+	nextLength := math.Sqrt(nextDx*nextDx + nextDy*nextDy)
+
+	if prevLength < 1e-10 || nextLength < 1e-10 {
+		return joinPoints // 跳过长度为0的线段 / Skip zero-length segments
+	}
+
+	// 归一化方向向量 / Normalize direction vectors
+	prevDx /= prevLength
+	prevDy /= prevLength
+	nextDx /= nextLength
+	nextDy /= nextLength
+
+	// 计算法向量 / Calculate normal vectors
+	prevNormalX := -prevDy
+	prevNormalY := prevDx
+	nextNormalX := -nextDy
+	nextNormalY := nextDx
+
+	// 根据左右侧调整法向量方向 / Adjust normal direction for left/right
+	if !isLeft {
+		prevNormalX = -prevNormalX
+		prevNormalY = -prevNormalY
+		nextNormalX = -nextNormalX
+		nextNormalY = -nextNormalY
+	}
+
+	// 计算偏移点 / Calculate offset points
+	prevOffset := types.Point{
+		X: current.X + prevNormalX*offset,
+		Y: current.Y + prevNormalY*offset,
+	}
+	nextOffset := types.Point{
+		X: current.X + nextNormalX*offset,
+		Y: current.Y + nextNormalY*offset,
+	}
+
+	// 根据连接样式生成连接点 / Generate join points based on join style
+	switch join {
+	case JoinMiter:
+		// 尖角连接，比例不超过限制时使用尖角 / Miter join, used while the ratio does not exceed the limit
+		if miterRatio(prevDx*nextDx+prevDy*nextDy) <= miterLimit*(1+1e-9) {
+			if miterPoint := calculateMiterJoin(prevOffset, current, nextOffset); miterPoint != nil {
+				joinPoints = append(joinPoints, *miterPoint)
+				break
+			}
+		}
+		// 尖角过长，按规范以连接两个偏移点的斜角边代替 / Miter too long, replaced per spec by a bevel edge joining the two offset points
+		joinPoints = append(joinPoints, prevOffset, nextOffset)
+	case JoinRound:
+		// 圆角连接，仅在转角外侧生成圆弧 / Round join, with the arc only on the outer side of the turn
+		cross := prevDx*nextDy - prevDy*nextDx
+		dot := prevDx*nextDx + prevDy*nextDy
+		if sweep, outer := roundJoinSweep(cross, dot, isLeft); outer {
+			joinPoints = append(joinPoints, generateRoundJoin(prevOffset, current, nextOffset, offset, sweep)...)
+		} else if inner := calculateMiterJoin(prevOffset, current, nextOffset); inner != nil {
+			// 内侧取两条偏移线的交点，避免轮廓自交 / The inner side uses the offset lines' intersection to avoid a self-intersecting outline
+			joinPoints = append(joinPoints, *inner)
+		} else {
+			joinPoints = append(joinPoints, prevOffset, nextOffset)
+		}
+	case JoinBevel:
+		// 斜角连接 / Bevel join
+		joinPoints = append(joinPoints, prevOffset, nextOffset)
+	}
+
+	return joinPoints
+}
+
+// miterRatio 返回SVG尖角比例，即尖角长度与线宽之比1/sin(θ/2)，θ为两线段的夹角；dot为两线段单位方向的点积
+// miterRatio returns the SVG miter ratio, miterLength/strokeWidth = 1/sin(θ/2) with θ the angle between the segments; dot is the dot product of their unit directions
+//
+// 尖角顶点到中心线拐点的距离为offset/sin(θ/2)，而线宽为2*offset，两者之比与偏移量无关
+// The miter tip lies offset/sin(θ/2) from the centerline corner and the stroke width is 2*offset, so the ratio does not depend on the offset
+func miterRatio(dot float64) float64 {
+	// sin²(θ/2) = (1+cos φ)/2，φ为转向角 / sin²(θ/2) = (1+cos φ)/2 with φ the turning angle
+	sinSquared := (1 + dot) / 2
+	if sinSquared <= 0 {
+		return math.Inf(1) // 折返 / Reversal
+	}
+	return 1 / math.Sqrt(sinSquared)
+}
+
+// calculateMiterJoin 计算两条偏移线的交点作为尖角顶点，平行时返回nil / Calculate the miter tip as the intersection of the two offset lines, or nil when they are parallel
+func calculateMiterJoin(prevOffset, center, nextOffset types.Point) *types.Point {
+	// 计算两条偏移线的交点 / Calculate intersection of two offset lines
+	// 使用线段交点公式 / Use line intersection formula
+	// 偏移线垂直于偏移点到中心的法向量 / Each offset line is perpendicular to the normal from the center to its offset point
+
+	// 前一条线的方向向量 / Previous line direction vector
+	prevDx := -(prevOffset.Y - center.Y)
+	prevDy := prevOffset.X - center.X
+
+	// 下一条线的方向向量 / Next line direction vector
+	nextDx := -(nextOffset.Y - center.Y)
+	nextDy := nextOffset.X - center.X
+
+	// 计算行列式 / Calculate determinant
+	det := prevDx*nextDy - prevDy*nextDx
+
+	if math.Abs(det) < 1e-10 {
+		return nil // 线段平行，无交点 / Lines are parallel, no intersection
+	}
+
+	// 计算参数 / Calculate parameters
+	dx := nextOffset.X - prevOffset.X
+	dy := nextOffset.Y - prevOffset.Y
+	t := (dx*nextDy - dy*nextDx) / det
+
+	// 计算交点 / Calculate intersection point
+	intersection := types.Point{
+		X: prevOffset.X + t*prevDx,
+		Y: prevOffset.Y + t*prevDy,
+	}
+
+	return &intersection
+}
+
+// roundJoinSweep 根据转向判断偏移侧是否为外侧，并返回外侧圆弧的扫掠角
+// roundJoinSweep reports whether the offset side is the outer side of the turn and returns the outer arc's sweep angle
+//
+// cross和dot为入射与出射单位方向的叉积和点积；左侧法向为方向逆时针旋转90度
+// cross and dot are the cross and dot products of the unit incoming and outgoing directions; the left normal is the direction rotated by +90 degrees
+func roundJoinSweep(cross, dot float64, isLeft bool) (float64, bool) {
+	if cross == 0 {
+		if dot >= 0 {
+			return 0, false // 共线，无需连接 / Collinear, no join needed
+		}
+		// 折返时两侧都是外侧，圆弧绕过前方 / On a reversal both sides are outer and the arc wraps around the front
+		if isLeft {
+			return -math.Pi, true
+		}
+		return math.Pi, true
+	}
+	// 法向随方向同角度旋转，向正角转弯时左侧在内 / Normals rotate with the direction, so turning by a positive angle puts the left side inside
+	if (cross > 0) == isLeft {
+		return 0, false
+	}
+	return math.Atan2(cross, dot), true
+}
+
+// generateRoundJoin 生成圆角连接，sweep为从prevOffset扫到nextOffset的有向角度 / Generate a round join; sweep is the signed angle from prevOffset to nextOffset
+func generateRoundJoin(prevOffset, center, nextOffset types.Point, offset, sweep float64) []types.Point {
+	roundPoints := make([]types.Point, 0)
+
+	// 计算起始角度 / Calculate the start angle
+	startAngle := math.Atan2(prevOffset.Y-center.Y, prevOffset.X-center.X)
+	angleDiff := sweep
+
+	// 计算圆弧分段数 / Calculate arc segments
+	segments := int(math.Ceil(math.Abs(angleDiff) / (math.Pi / 8))) // 每22.5度一个分段
+	if segments < 2 {
+		segments = 2
+	}
+
+	// 生成圆弧点 / Generate arc points
+	roundPoints = append(roundPoints, prevOffset)
+	for i := 1; i < segments; i++ {
+		t := float64(i) / float64(segments)
+		angle := startAngle + t*angleDiff
+		point := types.Point{
+			X: center.X + offset*math.Cos(angle),
+			Y: center.Y + offset*math.Sin(angle),
+		}
+		roundPoints = append(roundPoints, point)
+	}
+	roundPoints = append(roundPoints, nextOffset)
+
+	return roundPoints
+}
+
+// Offset 生成与路径平行的轮廓，正距离向外扩展，负距离向内收缩
+// Offset produces a parallel contour; a positive distance grows the path outward, a negative one insets it
+//
+// 闭合子路径按其方向判断外侧，开放子路径向左侧偏移；precision为曲线展平容差，0表示自适应
+// Closed subpaths determine the outside from their winding, open subpaths are offset to the left; precision is the flattening tolerance, 0 for adaptive
+func (p *SVGPath) Offset(distance float64, join StrokeJoinStyle, precision float64) *SVGPath {
+	result := &SVGPath{Commands: []Command{}, Flatness: p.Flatness}
+
+	flattened := p
+	if precision > 0 {
+		flattened = &SVGPath{Commands: p.Commands, Flatness: precision}
+	}
+	for _, subPath := range flattened.SubPaths(precision) {
+		points, closed := subPath.Points, subPath.Closed
+		if len(points) < 2 {
+			continue
+		}
+
+		var offsetPoints []types.Point
+		if closed && len(points) >= 3 {
+			// 面积为正时内部位于左侧，向外即向右 / With positive area the interior is on the left, so outward is right
+			isLeft := signedArea(points) < 0
+			if distance < 0 {
+				isLeft = !isLeft
+			}
+			// 首尾各多绕一段，使起点也生成连接 / Wrap around by one segment so the start vertex is joined too
+			ring := make([]types.Point, 0, len(points)+2)
+			ring = append(ring, points...)
+			ring = append(ring, points[0], points[1])
+			offsetPoints = OffsetPolyline(ring, math.Abs(distance), isLeft, join, 4.0)
+			if len(offsetPoints) > 2 {
+				offsetPoints = offsetPoints[1 : len(offsetPoints)-1]
+			}
+		} else {
+			offsetPoints = OffsetPolyline(points, math.Abs(distance), distance >= 0, join, 4.0)
+		}
+		if len(offsetPoints) == 0 {
+			continue
+		}
+
+		result.Commands = append(result.Commands, &MoveToCommand{X: offsetPoints[0].X, Y: offsetPoints[0].Y})
+		for _, pt := range offsetPoints[1:] {
+			result.Commands = append(result.Commands, &LineToCommand{X: pt.X, Y: pt.Y})
+		}
+		if closed {
+			result.Commands = append(result.Commands, &ClosePathCommand{})
+		}
+	}
+
+	return result
+}
+
+// signedArea 计算多边形的有向面积 / Compute the signed area of a polygon
+func signedArea(points []types.Point) float64 {
+	area := 0.0
+	for i := range points {
+		j := (i + 1) % len(points)
+		area += points[i].X*points[j].Y - points[j].X*points[i].Y
+	}
+	return area / 2
+}
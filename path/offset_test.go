@@ -0,0 +1,109 @@
+package path
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// pathBounds 计算展平路径的边界框 / Compute the bounding box of a flattened path
+func pathBounds(p *SVGPath) (minX, minY, maxX, maxY float64) {
+	points := p.FlattenPath(0.1)
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, pt := range points {
+		minX, maxX = math.Min(minX, pt.X), math.Max(maxX, pt.X)
+		minY, maxY = math.Min(minY, pt.Y), math.Max(maxY, pt.Y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// TestOffsetSquare 测试正方形偏移后边界框扩大2*distance / Test offsetting a square grows its bounding box by 2*distance
+func TestOffsetSquare(t *testing.T) {
+	for _, data := range []string{"M 10 10 L 50 10 L 50 50 L 10 50 Z", "M 10 10 L 10 50 L 50 50 L 50 10 Z"} {
+		square, err := ParsePath(data)
+		if err != nil {
+			t.Fatalf("ParsePath failed: %v", err)
+		}
+
+		const distance = 5.0
+		for _, join := range []StrokeJoinStyle{JoinMiter, JoinRound, JoinBevel} {
+			minX, minY, maxX, maxY := pathBounds(square.Offset(distance, join, 0))
+			if math.Abs((maxX-minX)-(40+2*distance)) > 1e-6 || math.Abs((maxY-minY)-(40+2*distance)) > 1e-6 {
+				t.Errorf("%s join %d: expected %vx%v bounds, got %vx%v", data, join, 40+2*distance, 40+2*distance, maxX-minX, maxY-minY)
+			}
+		}
+
+		// 负距离向内收缩 / A negative distance insets
+		minX, _, maxX, _ := pathBounds(square.Offset(-distance, JoinMiter, 0))
+		if math.Abs((maxX-minX)-(40-2*distance)) > 1e-6 {
+			t.Errorf("%s: expected inset width %v, got %v", data, 40-2*distance, maxX-minX)
+		}
+	}
+}
+
+// TestRoundJoinSide 测试尖锐转角的圆角连接只在外侧生成圆弧 / Test a sharp corner's round join arcs only on the outer side
+func TestRoundJoinSide(t *testing.T) {
+	corner := types.Point{X: 20, Y: 0}
+	polyline := []types.Point{{X: 0, Y: 0}, corner, {X: 0, Y: 5}}
+	const offset = 2.0
+
+	// 该转角向左折返，右侧为外侧，圆弧应绕过转角尖端 / The corner doubles back to the left, so the right side is outer and its arc wraps the tip
+	outer := OffsetPolyline(polyline, offset, false, JoinRound, 4)
+	maxX := math.Inf(-1)
+	for _, p := range outer[1 : len(outer)-1] {
+		if p.X < corner.X-1e-9 {
+			t.Errorf("outer join point %v lies behind the corner", p)
+		}
+		if d := math.Hypot(p.X-corner.X, p.Y-corner.Y); math.Abs(d-offset) > 1e-9 {
+			t.Errorf("outer join point %v is %v from the corner, want %v", p, d, offset)
+		}
+		maxX = math.Max(maxX, p.X)
+	}
+	if math.Abs(maxX-(corner.X+offset)) > 0.2 {
+		t.Errorf("expected the outer arc to reach the tip at x=%v, got %v", corner.X+offset, maxX)
+	}
+
+	// 内侧不生成圆弧，仅取偏移线交点 / The inner side gets no arc, only the offset lines' intersection
+	inner := OffsetPolyline(polyline, offset, true, JoinRound, 4)
+	if len(inner) != 3 {
+		t.Fatalf("expected start, intersection and end on the inner side, got %v", inner)
+	}
+	if p := inner[1]; p.X >= corner.X || p.Y <= 0 || p.Y >= 5 {
+		t.Errorf("expected the inner join inside the corner, got %v", p)
+	}
+}
+
+// TestMiterLimit 测试尖角在夹角越过1/sin(θ/2)=限制处切换为斜角 / Test miters switch to bevels where the angle crosses 1/sin(θ/2) = limit
+func TestMiterLimit(t *testing.T) {
+	const offset = 1.5
+	// joinAt 返回夹角为θ度的拐角在外侧的连接点 / Return the outer join points of a corner with an angle of θ degrees
+	joinAt := func(theta, limit float64) []types.Point {
+		turn := math.Pi - theta*math.Pi/180
+		next := types.Point{X: 20 * math.Cos(turn), Y: 20 * math.Sin(turn)}
+		return JoinPoints(types.Point{X: -20}, types.Point{}, next, offset, false, JoinMiter, limit)
+	}
+
+	// 限制为4时临界夹角为2·asin(1/4)≈28.955度 / With a limit of 4 the critical angle is 2·asin(1/4) ≈ 28.955 degrees
+	critical := 2 * math.Asin(0.25) * 180 / math.Pi
+	inside := joinAt(critical+0.5, 4)
+	if len(inside) != 1 {
+		t.Fatalf("expected a miter just inside the limit, got %d points", len(inside))
+	}
+	// 尖角长度(顶点到内侧拐点)与线宽之比即为1/sin(θ/2) / Miter length (tip to inner corner) over stroke width is 1/sin(θ/2)
+	ratio := inside[0].Length() / offset
+	if want := 1 / math.Sin((critical+0.5)*math.Pi/360); math.Abs(ratio-want) > 1e-9 {
+		t.Errorf("expected miter ratio %v, got %v", want, ratio)
+	}
+	if outside := joinAt(critical-0.5, 4); len(outside) != 2 {
+		t.Errorf("expected a bevel just outside the limit, got %d points", len(outside))
+	} else if math.Abs(outside[0].Length()-offset) > 1e-9 || math.Abs(outside[1].Length()-offset) > 1e-9 {
+		t.Errorf("expected the bevel edge to join the two offset points, got %v", outside)
+	}
+
+	// 恰好等于限制时仍为尖角：60度夹角的比例为2 / Exactly at the limit is still a miter: a 60 degree angle has a ratio of 2
+	if exact := joinAt(60, 2); len(exact) != 1 {
+		t.Errorf("expected a miter exactly at the limit, got %d points", len(exact))
+	}
+}
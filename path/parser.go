@@ -2,6 +2,7 @@ package path
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"unicode"
@@ -10,17 +11,29 @@ import (
 // SVGPath 表示SVG路径
 type SVGPath struct {
 	Commands []Command
+	Flatness float64 // 曲线平坦度容差，0表示自适应 / Curve flatness tolerance, 0 for adaptive
+}
+
+// ParseOptions 路径解析选项 / Path parsing options
+type ParseOptions struct {
+	// Flatness 贝塞尔曲线展平时允许的最大偏差（用户单位），越小越平滑，0表示按曲线自适应
+	// Flatness is the maximum deviation (in user units) allowed when flattening Bézier curves; smaller is smoother, 0 adapts per curve
+	Flatness float64
 }
 
 // ParsePath 解析SVG路径数据
-func ParsePath(data string) (*SVGPath, error) {
+// opts: 可选的解析选项 / Optional parsing options
+func ParsePath(data string, opts ...ParseOptions) (*SVGPath, error) {
 	// 创建路径对象
 	path := &SVGPath{
 		Commands: []Command{},
 	}
+	if len(opts) > 0 && opts[0].Flatness > 0 {
+		path.Flatness = opts[0].Flatness
+	}
 
 	// 解析路径数据
-	tokens, err := tokenizePath(data)
+	tokens, _, err := Tokenize(data)
 	if err != nil {
 		return nil, err
 	}
@@ -35,40 +48,95 @@ func ParsePath(data string) (*SVGPath, error) {
 	return path, nil
 }
 
-// tokenizePath 将路径数据分解为标记
-func tokenizePath(data string) ([]string, error) {
-	// 预处理数据
-	// 在命令字符前后添加空格
-	for _, cmd := range "MmLlHhVvCcSsQqTtAaZz" {
-		data = strings.ReplaceAll(data, string(cmd), " "+string(cmd)+" ")
-	}
+// pathCommandLetters 路径命令字符 / Path command letters
+const pathCommandLetters = "MmLlHhVvCcSsQqTtAaZz"
+
+// Tokenize 将路径数据分解为标记，flags标出弧命令的大弧和扫描标志
+// Tokenize splits path data into command and number tokens following the SVG path grammar, with flags marking the large-arc and sweep flags of arc commands
+//
+// 数字支持科学计数法和省略前导零的小数，".5.5"为两个数；弧命令的两个标志各为单个字符，如"015"为0、1和5
+// Numbers may use scientific notation and omit the leading zero, so ".5.5" is two numbers; each arc flag is a single character, so "015" is 0, 1 and 5
+func Tokenize(data string) (tokens []string, flags []bool, err error) {
+	tokens = []string{}
+	var command byte
+	argIndex := 0
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		if isWhitespace(c) || c == ',' {
+			i++
+			continue
+		}
+		if strings.IndexByte(pathCommandLetters, c) >= 0 {
+			tokens = append(tokens, string(c))
+			flags = append(flags, false)
+			command, argIndex = c, 0
+			i++
+			continue
+		}
 
-	// 将逗号替换为空格
-	data = strings.ReplaceAll(data, ",", " ")
-	
-	// 处理连续的负数：在负号前添加空格（除了字符串开头和已有空格后的负号）
-	result := ""
-	for i, char := range data {
-		if char == '-' && i > 0 && data[i-1] != ' ' && data[i-1] != ',' {
-			result += " -"
-		} else {
-			result += string(char)
+		// 弧命令的第4、5个参数是单字符标志 / The 4th and 5th arc arguments are single-character flags
+		if (command == 'A' || command == 'a') && (argIndex%7 == 3 || argIndex%7 == 4) {
+			if c != '0' && c != '1' {
+				return nil, nil, fmt.Errorf("无效的弧标志: %q", c)
+			}
+			tokens = append(tokens, string(c))
+			flags = append(flags, true)
+			argIndex++
+			i++
+			continue
 		}
-	}
-	data = result
 
-	// 将连续的空格替换为单个空格
-	for strings.Contains(data, "  ") {
-		data = strings.ReplaceAll(data, "  ", " ")
+		end := scanPathNumber(data, i)
+		if end == i {
+			return nil, nil, fmt.Errorf("路径数据中的无效字符: %q", c)
+		}
+		tokens = append(tokens, data[i:end])
+		flags = append(flags, false)
+		argIndex++
+		i = end
 	}
 
-	// 去除首尾空格
-	data = strings.TrimSpace(data)
+	return tokens, flags, nil
+}
 
-	// 分割为标记
-	tokens := strings.Fields(data)
+// scanPathNumber 返回从start开始的数字的结束位置，不是数字时返回start
+// scanPathNumber returns the end of the number starting at start, or start when there is none
+func scanPathNumber(data string, start int) int {
+	i := start
+	if i < len(data) && (data[i] == '+' || data[i] == '-') {
+		i++
+	}
+	digits := 0
+	for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+		i++
+		digits++
+	}
+	if i < len(data) && data[i] == '.' {
+		i++
+		for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return start
+	}
 
-	return tokens, nil
+	// 指数部分只在后面有数字时才属于该数 / The exponent only belongs to the number when digits follow
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		j := i + 1
+		if j < len(data) && (data[j] == '+' || data[j] == '-') {
+			j++
+		}
+		if j < len(data) && data[j] >= '0' && data[j] <= '9' {
+			for j < len(data) && data[j] >= '0' && data[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
 }
 
 // parseCommands 解析命令标记
@@ -0,0 +1,170 @@
+package path
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestParseOptionsFlatness 测试平坦度设置控制曲线展平的点数 / Test the flatness option controls the number of flattened points
+func TestParseOptionsFlatness(t *testing.T) {
+	const data = "M 0 0 C 0 100 100 100 100 0 Q 150 -80 200 0"
+
+	coarse, err := ParsePath(data, ParseOptions{Flatness: 2})
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	fine, err := ParsePath(data, ParseOptions{Flatness: 0.01})
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+
+	coarsePoints := len(coarse.FlattenPath(0.1))
+	finePoints := len(fine.FlattenPath(0.1))
+	if finePoints <= coarsePoints {
+		t.Errorf("expected finer flatness to yield more points, got %d <= %d", finePoints, coarsePoints)
+	}
+
+	// 默认使用自适应平坦度 / The default uses adaptive flatness
+	adaptive, err := ParsePath(data)
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	if adaptive.Flatness != 0 {
+		t.Errorf("expected adaptive flatness by default, got %v", adaptive.Flatness)
+	}
+}
+
+// TestForEachCommand 测试按顺序访问命令并可就地修改 / Test commands are visited in order and can be edited in place
+func TestForEachCommand(t *testing.T) {
+	p, err := ParsePath("M 0 0 L 10 0 C 10 10 20 10 20 0 Q 30 -10 40 0 A 5 5 0 0 1 50 0 Z")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+
+	var visited []string
+	curves := 0
+	p.ForEachCommand(func(cmd Command) {
+		visited = append(visited, fmt.Sprintf("%T", cmd))
+		switch c := cmd.(type) {
+		case *CubicCurveToCommand, *QuadraticCurveToCommand:
+			curves++
+		case *LineToCommand:
+			c.Y = 5
+		}
+	})
+
+	want := []string{"*path.MoveToCommand", "*path.LineToCommand", "*path.CubicCurveToCommand", "*path.QuadraticCurveToCommand", "*path.ArcToCommand", "*path.ClosePathCommand"}
+	if fmt.Sprint(visited) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, visited)
+	}
+	if curves != 2 {
+		t.Errorf("expected 2 curves, got %d", curves)
+	}
+	if line := p.Commands[1].(*LineToCommand); line.Y != 5 {
+		t.Errorf("expected the edit to apply to the path, got y=%v", line.Y)
+	}
+}
+
+// TestSubPaths 测试闭合子路径只标记一次闭合且不重复起点 / Test closed subpaths are flagged once and do not repeat their start point
+func TestSubPaths(t *testing.T) {
+	for _, data := range []string{"M 0 0 L 10 0 L 5 10 Z", "M 0 0 L 10 0 L 5 10 L 0 0 Z"} {
+		p, err := ParsePath(data)
+		if err != nil {
+			t.Fatalf("ParsePath failed: %v", err)
+		}
+		subPaths := p.SubPaths(0.1)
+		if len(subPaths) != 1 || !subPaths[0].Closed || len(subPaths[0].Points) != 3 {
+			t.Errorf("%s: expected one closed subpath with 3 points, got %+v", data, subPaths)
+		}
+		// FlattenSubPaths以起点结尾且只结尾一次 / FlattenSubPaths ends with the start point exactly once
+		if flat := p.FlattenSubPaths(0.1); len(flat) != 1 || len(flat[0]) != 4 || flat[0][3] != flat[0][0] {
+			t.Errorf("%s: expected the flattened subpath to end at its start once, got %v", data, flat)
+		}
+	}
+
+	// Z之后的命令从起点开始新的开放子路径 / Commands after Z start a new open subpath at the start point
+	p, err := ParsePath("M 0 0 L 10 0 L 5 10 Z L 0 20")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	subPaths := p.SubPaths(0.1)
+	if len(subPaths) != 2 || subPaths[1].Closed || fmt.Sprint(subPaths[1].Points) != "[{0 0} {0 20}]" {
+		t.Errorf("expected an open subpath from the start point after Z, got %+v", subPaths)
+	}
+	if info := p.GetSubPathCloseInfo(); fmt.Sprint(info) != "[true false]" {
+		t.Errorf("expected close info [true false], got %v", info)
+	}
+}
+
+// TestNearFullCircleArc 测试几乎闭合的弧绘制为完整的圆而不塌缩 / Test an arc that nearly closes a circle renders as a full circle rather than collapsing
+func TestNearFullCircleArc(t *testing.T) {
+	for _, sweep := range []int{0, 1} {
+		// 终点与起点相差约1e-7弧度 / The end point sits about 1e-7 radians from the start
+		end := -1e-7
+		if sweep == 0 {
+			end = -end
+		}
+		data := fmt.Sprintf("M 60 50 A 10 10 0 1 %d %.12f %.12f", sweep, 50+10*math.Cos(end), 50+10*math.Sin(end))
+		p, err := ParsePath(data)
+		if err != nil {
+			t.Fatalf("ParsePath failed: %v", err)
+		}
+
+		points := p.FlattenPath(0.1)
+		if len(points) < 8 {
+			t.Fatalf("sweep %d: expected a flattened circle, got %d points", sweep, len(points))
+		}
+		minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, pt := range points {
+			if math.IsNaN(pt.X) || math.IsNaN(pt.Y) {
+				t.Fatalf("sweep %d: NaN point in flattened arc", sweep)
+			}
+			if r := math.Hypot(pt.X-50, pt.Y-50); math.Abs(r-10) > 0.05 {
+				t.Errorf("sweep %d: point %v is %.3f from the center, want 10", sweep, pt, r)
+			}
+			minX, minY = math.Min(minX, pt.X), math.Min(minY, pt.Y)
+			maxX, maxY = math.Max(maxX, pt.X), math.Max(maxY, pt.Y)
+		}
+		if maxX-minX < 19.9 || maxY-minY < 19.9 {
+			t.Errorf("sweep %d: expected a round 20x20 extent, got %.2fx%.2f", sweep, maxX-minX, maxY-minY)
+		}
+	}
+}
+
+// TestParsePathNumbers 测试科学计数法、省略前导零的数字和紧贴的弧标志 / Test scientific notation, leading-dot numbers and glued arc flags
+func TestParsePathNumbers(t *testing.T) {
+	tests := []struct {
+		data string
+		want Command // 最后一个命令 / The last command
+	}{
+		{"M1e-3 2E2", &MoveToCommand{X: 0.001, Y: 200}},
+		{"M1.5e+2-.5", &MoveToCommand{X: 150, Y: -0.5}},
+		{"M.5.5", &MoveToCommand{X: 0.5, Y: 0.5}},
+		{"M0 0l-1-2", &LineToCommand{X: -1, Y: -2, Relative: true}},
+		{"M0 0L3e1-4e-1", &LineToCommand{X: 30, Y: -0.4}},
+		{"M0 0a5 5 0 015 5", &ArcToCommand{RX: 5, RY: 5, Sweep: true, X: 5, Y: 5, Relative: true}},
+		{"M0 0A5,5,30,1,0,10,0", &ArcToCommand{RX: 5, RY: 5, XAxisRotation: 30, LargeArc: true, X: 10}},
+		{"M0 0a5 5 0 1110 10", &ArcToCommand{RX: 5, RY: 5, LargeArc: true, Sweep: true, X: 10, Y: 10, Relative: true}},
+		{"M0 0a5 5 0 00.5.5", &ArcToCommand{RX: 5, RY: 5, X: 0.5, Y: 0.5, Relative: true}},
+		// 隐式重复的弧命令 / Implicitly repeated arc commands
+		{"M0 0a1 1 0 015 5 1 1 0 10-5-5", &ArcToCommand{RX: 1, RY: 1, LargeArc: true, X: -5, Y: -5, Relative: true}},
+	}
+	for _, tt := range tests {
+		p, err := ParsePath(tt.data)
+		if err != nil {
+			t.Errorf("ParsePath(%q) failed: %v", tt.data, err)
+			continue
+		}
+		if got := p.Commands[len(p.Commands)-1]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePath(%q): expected %#v, got %#v", tt.data, tt.want, got)
+		}
+	}
+
+	for _, data := range []string{"M0 0a5 5 0 2 1 5 5", "M 10 10 X", "M ."} {
+		if _, err := ParsePath(data); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
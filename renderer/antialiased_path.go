@@ -12,6 +12,8 @@ import (
 // AntiAliasedPathRenderer 抗锯齿路径渲染器 / Anti-aliased path renderer
 type AntiAliasedPathRenderer struct {
 	*AntiAliasedRenderer
+	// FillRule 填充时判断内部的规则，默认非零 / Rule deciding the interior when filling, nonzero by default
+	FillRule path.FillRule
 }
 
 // NewAntiAliasedPathRenderer 创建抗锯齿路径渲染器 / Create anti-aliased path renderer
@@ -24,7 +26,7 @@ func NewAntiAliasedPathRenderer() *AntiAliasedPathRenderer {
 // RenderPath 渲染抗锯齿路径 / Render anti-aliased path
 func (r *AntiAliasedPathRenderer) RenderPath(img *image.RGBA, pathData string, fillColor, strokeColor color.RGBA, strokeWidth float64, viewBox []float64, scaleX, scaleY float64) error {
 	// 解析路径 / Parse path
-	parsedPath, err := path.ParsePath(pathData)
+	parsedPath, err := path.ParsePath(pathData, r.pathParseOptions(scaleX, scaleY))
 	if err != nil {
 		return err
 	}
@@ -63,6 +65,8 @@ func (r *AntiAliasedPathRenderer) RenderPath(img *image.RGBA, pathData string, f
 	if strokeColor.A > 0 && strokeWidth > 0 {
 		// 创建真正的描边渲染器
 		trueStrokeRenderer := NewTrueStrokeRenderer()
+		// 共享采样数与混合设置 / Share sample count and blending settings
+		trueStrokeRenderer.AntiAliasedPathRenderer = r
 		// 使用真正的描边路径渲染复杂路径描边
 		trueStrokeRenderer.RenderTrueStrokeComplexPath(img, transformedSubPaths, strokeColor, strokeWidth*math.Min(scaleX, scaleY), transformedCloseInfo)
 	}
@@ -143,7 +147,7 @@ func (r *AntiAliasedPathRenderer) fillAntiAliasedComplexPath(img *image.RGBA, su
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 使用增强web级别的多重采样抗锯齿(MSAA) / Use enhanced web-level Multi-Sample Anti-Aliasing (MSAA)
-			coverage := r.calculateWebLevelMSAA(float64(x), float64(y), subPaths, 16)
+			coverage := r.calculateWebLevelMSAA(float64(x), float64(y), subPaths, r.sampleCount())
 
 			// 使用更低的覆盖率阈值和边缘平滑处理 / Use lower coverage threshold and edge smoothing
 			minCoverage := 0.05 // 降低阈值以获得更平滑的边缘 / Lower threshold for smoother edges
@@ -151,7 +155,7 @@ func (r *AntiAliasedPathRenderer) fillAntiAliasedComplexPath(img *image.RGBA, su
 				// 对边缘区域应用额外的平滑处理 / Apply additional smoothing for edge areas
 				smoothedCoverage := r.applyCoverageSmoothing(coverage, float64(x), float64(y))
 				// 混合颜色 / Blend color
-				blendedColor := blendColors(getPixelColor(img, x, y), fillColor, smoothedCoverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), fillColor, smoothedCoverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -192,7 +196,7 @@ func (r *AntiAliasedPathRenderer) fillAntiAliasedPath(img *image.RGBA, path []ty
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 使用增强web级别的多重采样抗锯齿(MSAA) / Use enhanced web-level Multi-Sample Anti-Aliasing (MSAA)
-			coverage := r.calculateWebLevelPathMSAA(float64(x), float64(y), path, 16)
+			coverage := r.calculateWebLevelPathMSAA(float64(x), float64(y), path, r.sampleCount())
 
 			// 使用更低的覆盖率阈值和边缘平滑处理 / Use lower coverage threshold and edge smoothing
 			minCoverage := 0.05 // 降低阈值以获得更平滑的边缘 / Lower threshold for smoother edges
@@ -200,7 +204,7 @@ func (r *AntiAliasedPathRenderer) fillAntiAliasedPath(img *image.RGBA, path []ty
 				// 对边缘区域应用额外的平滑处理 / Apply additional smoothing for edge areas
 				smoothedCoverage := r.applyCoverageSmoothing(coverage, float64(x), float64(y))
 				// 混合颜色 / Blend color
-				blendedColor := blendColors(getPixelColor(img, x, y), fillColor, smoothedCoverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), fillColor, smoothedCoverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -251,7 +255,7 @@ func (r *AntiAliasedPathRenderer) strokeAntiAliasedPath(img *image.RGBA, path []
 			minCoverage := 0.1 // 只有覆盖率大于10%才进行描边 / Only stroke if coverage is greater than 10%
 			if coverage > minCoverage {
 				// 混合颜色 / Blend color
-				blendedColor := blendColors(getPixelColor(img, x, y), strokeColor, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), strokeColor, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -346,18 +350,18 @@ func (r *AntiAliasedPathRenderer) calculateWebLevelMSAA(pixelX, pixelY float64,
 	}
 
 	// 计算到第一个路径的粗略距离 / Calculate rough distance to first path
-	bounds := r.calculatePathBounds(firstPath)
+	bounds := types.RectFromPoints(firstPath)
 	roughDistance := 0.0
-	if centerX < bounds.MinX {
-		roughDistance = bounds.MinX - centerX
-	} else if centerX > bounds.MaxX {
-		roughDistance = centerX - bounds.MaxX
+	if centerX < bounds.X {
+		roughDistance = bounds.X - centerX
+	} else if centerX > bounds.MaxX() {
+		roughDistance = centerX - bounds.MaxX()
 	}
-	if centerY < bounds.MinY {
-		dy := bounds.MinY - centerY
+	if centerY < bounds.Y {
+		dy := bounds.Y - centerY
 		roughDistance = math.Sqrt(roughDistance*roughDistance + dy*dy)
-	} else if centerY > bounds.MaxY {
-		dy := centerY - bounds.MaxY
+	} else if centerY > bounds.MaxY() {
+		dy := centerY - bounds.MaxY()
 		roughDistance = math.Sqrt(roughDistance*roughDistance + dy*dy)
 	}
 
@@ -370,16 +374,18 @@ func (r *AntiAliasedPathRenderer) calculateWebLevelMSAA(pixelX, pixelY float64,
 		}
 	}
 
-	// 只在边缘附近使用2x2采样 / Only use 2x2 samples near edges
-	quickSamples := 2 // 减少到2x2采样
+	// 只在边缘附近使用NxN采样 / Only use NxN samples near edges
+	if samples < 1 {
+		samples = 1
+	}
 	insideCount := 0
-	totalSamples := quickSamples * quickSamples
+	totalSamples := samples * samples
 
-	// 使用固定偏移而不是计算偏移以提高性能 / Use fixed offsets instead of calculated offsets for better performance
-	offsets := []float64{0.25, 0.75}
+	// 均匀分布的采样偏移 / Evenly distributed sample offsets
+	offsets := sampleOffsets(samples)
 
-	for i := 0; i < quickSamples; i++ {
-		for j := 0; j < quickSamples; j++ {
+	for i := 0; i < samples; i++ {
+		for j := 0; j < samples; j++ {
 			sampleX := pixelX + offsets[i]
 			sampleY := pixelY + offsets[j]
 
@@ -410,18 +416,18 @@ func (r *AntiAliasedPathRenderer) calculateWebLevelPathMSAA(pixelX, pixelY float
 	isInside := r.isPointInPath(centerX, centerY, path)
 
 	// 快速距离估算（使用边界框） / Fast distance estimation (using bounding box)
-	bounds := r.calculatePathBounds(path)
+	bounds := types.RectFromPoints(path)
 	roughDistance := 0.0
-	if centerX < bounds.MinX {
-		roughDistance = bounds.MinX - centerX
-	} else if centerX > bounds.MaxX {
-		roughDistance = centerX - bounds.MaxX
+	if centerX < bounds.X {
+		roughDistance = bounds.X - centerX
+	} else if centerX > bounds.MaxX() {
+		roughDistance = centerX - bounds.MaxX()
 	}
-	if centerY < bounds.MinY {
-		dy := bounds.MinY - centerY
+	if centerY < bounds.Y {
+		dy := bounds.Y - centerY
 		roughDistance = math.Sqrt(roughDistance*roughDistance + dy*dy)
-	} else if centerY > bounds.MaxY {
-		dy := centerY - bounds.MaxY
+	} else if centerY > bounds.MaxY() {
+		dy := centerY - bounds.MaxY()
 		roughDistance = math.Sqrt(roughDistance*roughDistance + dy*dy)
 	}
 
@@ -434,16 +440,18 @@ func (r *AntiAliasedPathRenderer) calculateWebLevelPathMSAA(pixelX, pixelY float
 		}
 	}
 
-	// 只在边缘附近使用2x2采样 / Only use 2x2 samples near edges
-	quickSamples := 2 // 减少到2x2采样
+	// 只在边缘附近使用NxN采样 / Only use NxN samples near edges
+	if samples < 1 {
+		samples = 1
+	}
 	insideCount := 0
-	totalSamples := quickSamples * quickSamples
+	totalSamples := samples * samples
 
-	// 使用固定偏移而不是计算偏移以提高性能 / Use fixed offsets instead of calculated offsets for better performance
-	offsets := []float64{0.25, 0.75}
+	// 均匀分布的采样偏移 / Evenly distributed sample offsets
+	offsets := sampleOffsets(samples)
 
-	for i := 0; i < quickSamples; i++ {
-		for j := 0; j < quickSamples; j++ {
+	for i := 0; i < samples; i++ {
+		for j := 0; j < samples; j++ {
 			sampleX := pixelX + offsets[i]
 			sampleY := pixelY + offsets[j]
 
@@ -464,63 +472,45 @@ func (r *AntiAliasedPathRenderer) calculateWebLevelPathMSAA(pixelX, pixelY float
 	return coverage
 }
 
-// PathBounds 路径边界框结构 / Path bounds structure
-type PathBounds struct {
-	MinX, MinY, MaxX, MaxY float64
-}
-
-// isPointInComplexPath 使用缠绕数规则检查点是否在复杂路径内 / Check if point is inside complex path using winding rule
+// isPointInComplexPath 按填充规则检查点是否在复杂路径内，子路径视为隐式闭合 / Check whether a point is inside a complex path under the fill rule, treating subpaths as implicitly closed
 func (r *AntiAliasedPathRenderer) isPointInComplexPath(x, y float64, subPaths [][]types.Point) bool {
-	// 使用缠绕数规则：计算所有子路径的缠绕数总和 / Use winding rule: calculate sum of winding numbers for all sub-paths
-	windingNumber := 0
-	
+	winding, crossings := 0, 0
 	for _, subPath := range subPaths {
-		if len(subPath) < 3 {
+		n := len(subPath)
+		if n < 3 {
 			continue
 		}
 
 		// 计算子路径边界 / Calculate sub-path bounds
-		bounds := r.calculatePathBounds(subPath)
+		bounds := types.RectFromPoints(subPath)
 		// 如果点在边界框外，该子路径对缠绕数的贡献为0 / If point is outside bounds, this sub-path contributes 0 to winding number
-		if x < bounds.MinX || x > bounds.MaxX || y < bounds.MinY || y > bounds.MaxY {
+		if x < bounds.X || x > bounds.MaxX() || y < bounds.Y || y > bounds.MaxY() {
 			continue
 		}
 
-		// 计算该子路径的缠绕数贡献 / Calculate winding number contribution from this sub-path
-		if r.isPointInPathOptimized(x, y, subPath) {
-			windingNumber++
+		// 向右的水平射线与各边相交，按方向累计缠绕数 / Cross a rightward ray with each edge, accumulating winding by direction
+		for i := 0; i < n; i++ {
+			a, b := subPath[i], subPath[(i+1)%n]
+			if (a.Y <= y) == (b.Y <= y) {
+				continue
+			}
+			cross := r.isLeft(a.X, a.Y, b.X, b.Y, x, y)
+			if a.Y <= y {
+				if cross > 0 {
+					winding++
+					crossings++
+				}
+			} else if cross < 0 {
+				winding--
+				crossings++
+			}
 		}
 	}
-	
-	// 缠绕数为奇数时点在路径内 / Point is inside when winding number is odd
-	return windingNumber%2 == 1
-}
-
-// calculatePathBounds 计算路径边界框 / Calculate path bounds
-func (r *AntiAliasedPathRenderer) calculatePathBounds(path []types.Point) PathBounds {
-	if len(path) == 0 {
-		return PathBounds{}
-	}
-
-	bounds := PathBounds{
-		MinX: path[0].X, MaxX: path[0].X,
-		MinY: path[0].Y, MaxY: path[0].Y,
-	}
 
-	for _, point := range path[1:] {
-		if point.X < bounds.MinX {
-			bounds.MinX = point.X
-		} else if point.X > bounds.MaxX {
-			bounds.MaxX = point.X
-		}
-		if point.Y < bounds.MinY {
-			bounds.MinY = point.Y
-		} else if point.Y > bounds.MaxY {
-			bounds.MaxY = point.Y
-		}
+	if r.FillRule == path.FillRuleEvenOdd {
+		return crossings%2 == 1
 	}
-
-	return bounds
+	return winding != 0
 }
 
 // isLeft 测试点是否在有向线段的左侧 / Test if point is left of directed line segment
@@ -550,39 +540,6 @@ func (r *AntiAliasedPathRenderer) isPointInPath(x, y float64, path []types.Point
 	return inside
 }
 
-// isPointInPathOptimized 优化的点在路径内检测 / Optimized point-in-path detection
-func (r *AntiAliasedPathRenderer) isPointInPathOptimized(x, y float64, path []types.Point) bool {
-	if len(path) < 3 {
-		return false
-	}
-
-	// 使用更高效的射线投射算法 / Use more efficient ray casting algorithm
-	inside := false
-	j := len(path) - 1
-
-	for i := 0; i < len(path); i++ {
-		xi, yi := path[i].X, path[i].Y
-		xj, yj := path[j].X, path[j].Y
-
-		// 优化：跳过明显不相交的边 / Optimization: skip obviously non-intersecting edges
-		if (yi <= y && yj <= y) || (yi > y && yj > y) {
-			j = i
-			continue
-		}
-
-		// 计算交点的x坐标 / Calculate x-coordinate of intersection
-		if yi != yj {
-			xIntersect := xi + (y-yi)*(xj-xi)/(yj-yi)
-			if x < xIntersect {
-				inside = !inside
-			}
-		}
-		j = i
-	}
-
-	return inside
-}
-
 // calculateDistanceToPath 计算点到路径的最短距离（优化版） / Calculate shortest distance from point to path (optimized)
 func (r *AntiAliasedPathRenderer) calculateDistanceToPath(x, y float64, path []types.Point) float64 {
 	if len(path) < 2 {
@@ -590,22 +547,22 @@ func (r *AntiAliasedPathRenderer) calculateDistanceToPath(x, y float64, path []t
 	}
 
 	// 首先计算路径边界框 / First calculate path bounds
-	bounds := r.calculatePathBounds(path)
+	bounds := types.RectFromPoints(path)
 
 	// 如果点在边界框外，计算到边界框的距离作为下界 / If point is outside bounds, calculate distance to bounds as lower bound
-	if x < bounds.MinX || x > bounds.MaxX || y < bounds.MinY || y > bounds.MaxY {
+	if x < bounds.X || x > bounds.MaxX() || y < bounds.Y || y > bounds.MaxY() {
 		// 计算到边界框的距离 / Calculate distance to bounding box
 		dx := 0.0
 		dy := 0.0
-		if x < bounds.MinX {
-			dx = bounds.MinX - x
-		} else if x > bounds.MaxX {
-			dx = x - bounds.MaxX
+		if x < bounds.X {
+			dx = bounds.X - x
+		} else if x > bounds.MaxX() {
+			dx = x - bounds.MaxX()
 		}
-		if y < bounds.MinY {
-			dy = bounds.MinY - y
-		} else if y > bounds.MaxY {
-			dy = y - bounds.MaxY
+		if y < bounds.Y {
+			dy = bounds.Y - y
+		} else if y > bounds.MaxY() {
+			dy = y - bounds.MaxY()
 		}
 		boundsDistance := math.Sqrt(dx*dx + dy*dy)
 
@@ -623,25 +580,20 @@ func (r *AntiAliasedPathRenderer) calculateDistanceToPath(x, y float64, path []t
 		j := (i + 1) % len(path)
 
 		// 快速跳过距离过远的线段 / Quick skip for segments that are too far
-		segmentBounds := PathBounds{
-			MinX: math.Min(path[i].X, path[j].X),
-			MaxX: math.Max(path[i].X, path[j].X),
-			MinY: math.Min(path[i].Y, path[j].Y),
-			MaxY: math.Max(path[i].Y, path[j].Y),
-		}
+		segmentBounds := types.RectFromPoints([]types.Point{path[i], path[j]})
 
 		// 计算到线段边界框的距离 / Calculate distance to segment bounds
 		dx := 0.0
 		dy := 0.0
-		if x < segmentBounds.MinX {
-			dx = segmentBounds.MinX - x
-		} else if x > segmentBounds.MaxX {
-			dx = x - segmentBounds.MaxX
-		}
-		if y < segmentBounds.MinY {
-			dy = segmentBounds.MinY - y
-		} else if y > segmentBounds.MaxY {
-			dy = y - segmentBounds.MaxY
+		if x < segmentBounds.X {
+			dx = segmentBounds.X - x
+		} else if x > segmentBounds.MaxX() {
+			dx = x - segmentBounds.MaxX()
+		}
+		if y < segmentBounds.Y {
+			dy = segmentBounds.Y - y
+		} else if y > segmentBounds.MaxY() {
+			dy = y - segmentBounds.MaxY()
 		}
 		segmentBoundsDistance := math.Sqrt(dx*dx + dy*dy)
 
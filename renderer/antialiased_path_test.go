@@ -0,0 +1,60 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// distinctEdgeCoverages 统计对角边缘上不同的部分覆盖率数量 / Count distinct partial coverage values along a diagonal edge
+func distinctEdgeCoverages(r *AntiAliasedPathRenderer, subPaths [][]types.Point) int {
+	levels := make(map[float64]bool)
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			coverage := r.calculateWebLevelMSAA(float64(x), float64(y), subPaths, r.sampleCount())
+			if coverage > 0 && coverage < 1 {
+				levels[coverage] = true
+			}
+		}
+	}
+	return len(levels)
+}
+
+// TestSampleCountGradations 测试更高采样数产生更多覆盖率层级 / Test higher sample counts produce more coverage gradations
+func TestSampleCountGradations(t *testing.T) {
+	// 斜边不与采样网格对齐的三角形 / Triangle whose hypotenuse is not aligned with the sample grid
+	subPaths := [][]types.Point{{
+		{X: 0, Y: 0},
+		{X: 37, Y: 0},
+		{X: 0, Y: 17},
+		{X: 0, Y: 0},
+	}}
+
+	low := NewAntiAliasedPathRenderer()
+	low.SetSampleCount(2)
+	high := NewAntiAliasedPathRenderer()
+	high.SetSampleCount(8)
+
+	lowLevels := distinctEdgeCoverages(low, subPaths)
+	highLevels := distinctEdgeCoverages(high, subPaths)
+
+	if lowLevels == 0 {
+		t.Fatal("expected partial coverage on the diagonal edge at 2 samples")
+	}
+	if highLevels <= lowLevels {
+		t.Errorf("expected more gradations at 8 samples than at 2, got %d <= %d", highLevels, lowLevels)
+	}
+}
+
+// TestSetSampleCountDefault 测试无效采样数恢复默认值 / Test invalid sample counts restore the default
+func TestSetSampleCountDefault(t *testing.T) {
+	r := NewAntiAliasedRenderer()
+	if r.SampleCount != DefaultSampleCount {
+		t.Errorf("expected default sample count %d, got %d", DefaultSampleCount, r.SampleCount)
+	}
+
+	r.SetSampleCount(0)
+	if r.SampleCount != DefaultSampleCount {
+		t.Errorf("expected sample count reset to %d, got %d", DefaultSampleCount, r.SampleCount)
+	}
+}
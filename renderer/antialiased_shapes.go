@@ -6,16 +6,53 @@ import (
 	"math"
 )
 
+// DefaultSampleCount 默认每轴多重采样数 / Default number of MSAA samples per axis
+const DefaultSampleCount = 4
+
 // AntiAliasedRenderer 抗锯齿渲染器 / Anti-aliased renderer
 type AntiAliasedRenderer struct {
 	*ImageRenderer
+	// SampleCount 每轴采样数，像素内共 SampleCount*SampleCount 个采样点 / Samples per axis, SampleCount*SampleCount samples per pixel
+	SampleCount int
 }
 
+// defaultAntiAliasedRenderer 包级绘制函数共用的默认设置渲染器 / Renderer with default settings shared by the package-level drawing functions
+var defaultAntiAliasedRenderer = NewAntiAliasedRenderer()
+
 // NewAntiAliasedRenderer 创建抗锯齿渲染器 / Create anti-aliased renderer
 func NewAntiAliasedRenderer() *AntiAliasedRenderer {
 	return &AntiAliasedRenderer{
 		ImageRenderer: NewImageRenderer(),
+		SampleCount:   DefaultSampleCount,
+	}
+}
+
+// SetSampleCount 设置每轴采样数，小于1时恢复默认值 / Set samples per axis, values below 1 restore the default
+func (r *AntiAliasedRenderer) SetSampleCount(samples int) {
+	if samples < 1 {
+		samples = DefaultSampleCount
+	}
+	r.SampleCount = samples
+}
+
+// sampleCount 获取有效的每轴采样数 / Get the effective samples per axis
+func (r *AntiAliasedRenderer) sampleCount() int {
+	if r == nil || r.SampleCount < 1 {
+		return DefaultSampleCount
 	}
+	return r.SampleCount
+}
+
+// sampleOffsets 生成像素内均匀分布的采样偏移 / Generate evenly distributed sample offsets within a pixel
+func sampleOffsets(samples int) []float64 {
+	if samples < 1 {
+		samples = 1
+	}
+	offsets := make([]float64, samples)
+	for i := range offsets {
+		offsets[i] = (float64(i) + 0.5) / float64(samples)
+	}
+	return offsets
 }
 
 // DrawAntiAliasedCircle 绘制抗锯齿圆形描边 / Draw anti-aliased circle stroke
@@ -33,11 +70,11 @@ func (r *AntiAliasedRenderer) DrawAntiAliasedCircle(img *image.RGBA, centerX, ce
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 使用距离场计算描边覆盖率 / Use distance field to calculate stroke coverage
-			coverage := calculateCircleStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radius, strokeWidth, 4)
+			coverage := calculateCircleStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radius, strokeWidth, r.sampleCount())
 
 			if coverage > 0 {
 				// 混合颜色 / Blend color
-				blendedColor := blendColors(getPixelColor(img, x, y), c, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), c, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -56,11 +93,11 @@ func (r *AntiAliasedRenderer) FillAntiAliasedCircle(img *image.RGBA, centerX, ce
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 使用超采样计算覆盖率 / Use supersampling to calculate coverage
-			coverage := calculateCircleCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radius, 4)
+			coverage := calculateCircleCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radius, r.sampleCount())
 
 			if coverage > 0 {
 				// 混合颜色 / Blend color
-				blendedColor := blendColors(getPixelColor(img, x, y), c, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), c, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -83,11 +120,11 @@ func (r *AntiAliasedRenderer) DrawAntiAliasedEllipse(img *image.RGBA, centerX, c
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 使用距离场计算椭圆描边覆盖率 / Use distance field to calculate ellipse stroke coverage
-			coverage := calculateEllipseStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radiusX, radiusY, strokeWidth, 4)
+			coverage := calculateEllipseStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radiusX, radiusY, strokeWidth, r.sampleCount())
 
 			if coverage > 0 {
 				// 混合颜色 / Blend color
-				blendedColor := blendColors(getPixelColor(img, x, y), c, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), c, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -106,11 +143,11 @@ func (r *AntiAliasedRenderer) FillAntiAliasedEllipse(img *image.RGBA, centerX, c
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 计算椭圆内部覆盖率 / Calculate ellipse interior coverage
-			coverage := calculateEllipseInteriorCoverage(float64(x), float64(y), centerX, centerY, radiusX, radiusY, 4)
+			coverage := calculateEllipseInteriorCoverage(float64(x), float64(y), centerX, centerY, radiusX, radiusY, r.sampleCount())
 
 			if coverage > 0 {
 				// 混合颜色 / Blend color
-				blendedColor := blendColors(getPixelColor(img, x, y), c, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), c, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -145,6 +182,11 @@ func calculateCircleCoverageWithSupersampling(pixelX, pixelY, centerX, centerY,
 
 // DrawAntiAliasedCircle 绘制抗锯齿圆形（描边模式）
 func DrawAntiAliasedCircle(img *image.RGBA, centerX, centerY, radius float64, color color.RGBA, strokeWidth float64) {
+	defaultAntiAliasedRenderer.drawAntiAliasedCircleStroke(img, centerX, centerY, radius, color, strokeWidth)
+}
+
+// drawAntiAliasedCircleStroke 按渲染器设置绘制抗锯齿圆形描边 / Draw anti-aliased circle stroke using renderer settings
+func (r *AntiAliasedRenderer) drawAntiAliasedCircleStroke(img *image.RGBA, centerX, centerY, radius float64, color color.RGBA, strokeWidth float64) {
 	// 计算边界框，考虑描边宽度
 	halfStroke := strokeWidth / 2
 	minX := int(math.Floor(centerX - radius - halfStroke))
@@ -156,11 +198,11 @@ func DrawAntiAliasedCircle(img *image.RGBA, centerX, centerY, radius float64, co
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 计算覆盖率
-			coverage := calculateCircleStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radius, strokeWidth, 4)
+			coverage := calculateCircleStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radius, strokeWidth, r.sampleCount())
 
 			if coverage > 0 {
 				// 混合颜色
-				blendedColor := blendColors(getPixelColor(img, x, y), color, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), color, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -169,7 +211,7 @@ func DrawAntiAliasedCircle(img *image.RGBA, centerX, centerY, radius float64, co
 
 // DrawAntiAliasedCircleWithWidth 绘制带描边宽度的抗锯齿圆形
 func (r *AntiAliasedRenderer) DrawAntiAliasedCircleWithWidth(img *image.RGBA, centerX, centerY, radius float64, color color.RGBA, strokeWidth float64) {
-	DrawAntiAliasedCircle(img, centerX, centerY, radius, color, strokeWidth)
+	r.drawAntiAliasedCircleStroke(img, centerX, centerY, radius, color, strokeWidth)
 }
 
 // calculateCircleStrokeCoverageWithSupersampling 使用超采样计算圆形描边覆盖率 / Calculate circle stroke coverage with supersampling
@@ -258,6 +300,11 @@ func calculateEllipseInteriorCoverage(pixelX, pixelY, centerX, centerY, radiusX,
 
 // DrawAntiAliasedEllipse 绘制抗锯齿椭圆（描边模式）
 func DrawAntiAliasedEllipse(img *image.RGBA, centerX, centerY, radiusX, radiusY float64, color color.RGBA, strokeWidth float64) {
+	defaultAntiAliasedRenderer.drawAntiAliasedEllipseStroke(img, centerX, centerY, radiusX, radiusY, color, strokeWidth)
+}
+
+// drawAntiAliasedEllipseStroke 按渲染器设置绘制抗锯齿椭圆描边 / Draw anti-aliased ellipse stroke using renderer settings
+func (r *AntiAliasedRenderer) drawAntiAliasedEllipseStroke(img *image.RGBA, centerX, centerY, radiusX, radiusY float64, color color.RGBA, strokeWidth float64) {
 	// 计算边界框，考虑描边宽度
 	halfStroke := strokeWidth / 2
 	maxRadius := math.Max(radiusX, radiusY)
@@ -270,11 +317,11 @@ func DrawAntiAliasedEllipse(img *image.RGBA, centerX, centerY, radiusX, radiusY
 	for y := minY; y <= maxY; y++ {
 		for x := minX; x <= maxX; x++ {
 			// 计算覆盖率
-			coverage := calculateEllipseStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radiusX, radiusY, strokeWidth, 4)
+			coverage := calculateEllipseStrokeCoverageWithSupersampling(float64(x), float64(y), centerX, centerY, radiusX, radiusY, strokeWidth, r.sampleCount())
 
 			if coverage > 0 {
 				// 混合颜色
-				blendedColor := blendColors(getPixelColor(img, x, y), color, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), color, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -283,12 +330,12 @@ func DrawAntiAliasedEllipse(img *image.RGBA, centerX, centerY, radiusX, radiusY
 
 // DrawAntiAliasedEllipseWithWidth 绘制带描边宽度的抗锯齿椭圆
 func (r *AntiAliasedRenderer) DrawAntiAliasedEllipseWithWidth(img *image.RGBA, centerX, centerY, radiusX, radiusY float64, color color.RGBA, strokeWidth float64) {
-	DrawAntiAliasedEllipse(img, centerX, centerY, radiusX, radiusY, color, strokeWidth)
+	r.drawAntiAliasedEllipseStroke(img, centerX, centerY, radiusX, radiusY, color, strokeWidth)
 }
 
 // getPixelColor 获取像素颜色
 func getPixelColor(img *image.RGBA, x, y int) color.RGBA {
-	if x < 0 || y < 0 || x >= img.Bounds().Dx() || y >= img.Bounds().Dy() {
+	if !image.Pt(x, y).In(img.Bounds()) {
 		return color.RGBA{0, 0, 0, 0}
 	}
 	return img.RGBAAt(x, y)
@@ -303,13 +350,13 @@ func blendColors(bg, fg color.RGBA, alpha float64) color.RGBA {
 		return fg
 	}
 
-	alpha16 := uint16(alpha * 65535)
-	invAlpha := 65535 - alpha16
+	alpha32 := uint32(alpha * 65535)
+	invAlpha := 65535 - alpha32
 
-	r := (uint16(bg.R)*invAlpha + uint16(fg.R)*alpha16) / 65535
-	g := (uint16(bg.G)*invAlpha + uint16(fg.G)*alpha16) / 65535
-	b := (uint16(bg.B)*invAlpha + uint16(fg.B)*alpha16) / 65535
-	a := (uint16(bg.A)*invAlpha + uint16(fg.A)*alpha16) / 65535
+	r := (uint32(bg.R)*invAlpha + uint32(fg.R)*alpha32) / 65535
+	g := (uint32(bg.G)*invAlpha + uint32(fg.G)*alpha32) / 65535
+	b := (uint32(bg.B)*invAlpha + uint32(fg.B)*alpha32) / 65535
+	a := (uint32(bg.A)*invAlpha + uint32(fg.A)*alpha32) / 65535
 
 	return color.RGBA{
 		R: uint8(r),
@@ -319,6 +366,24 @@ func blendColors(bg, fg color.RGBA, alpha float64) color.RGBA {
 	}
 }
 
+// blendColorsLinear 在线性光空间中混合两种颜色 / Blend two colors in linear-light space
+func blendColorsLinear(bg, fg color.RGBA, alpha float64) color.RGBA {
+	if alpha <= 0 {
+		return bg
+	}
+	if alpha >= 1 {
+		return fg
+	}
+
+	return color.RGBA{
+		R: blendChannel(bg.R, fg.R, alpha, true),
+		G: blendChannel(bg.G, fg.G, alpha, true),
+		B: blendChannel(bg.B, fg.B, alpha, true),
+		// Alpha通道本身是线性的 / The alpha channel is already linear
+		A: uint8(float64(bg.A)*(1-alpha) + float64(fg.A)*alpha + 0.5),
+	}
+}
+
 // smoothStep 平滑步函数 / Smooth step function
 func smoothStep(t float64) float64 {
 	if t <= 0 {
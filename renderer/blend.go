@@ -0,0 +1,104 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+	"strings"
+)
+
+// BlendMode 混合模式，决定源颜色与背景颜色的合成方式 / Blend mode deciding how source and backdrop colors combine
+type BlendMode int
+
+const (
+	BlendNormal   BlendMode = iota // 正常（源覆盖）/ Normal source-over
+	BlendMultiply                  // 正片叠底 / Multiply
+	BlendScreen                    // 滤色 / Screen
+	BlendOverlay                   // 叠加 / Overlay
+	BlendDarken                    // 变暗 / Darken
+	BlendLighten                   // 变亮 / Lighten
+)
+
+// ParseBlendMode 解析mix-blend-mode属性值 / Parse a mix-blend-mode value
+func ParseBlendMode(value string) (BlendMode, bool) {
+	switch strings.TrimSpace(strings.ToLower(value)) {
+	case "normal":
+		return BlendNormal, true
+	case "multiply":
+		return BlendMultiply, true
+	case "screen":
+		return BlendScreen, true
+	case "overlay":
+		return BlendOverlay, true
+	case "darken":
+		return BlendDarken, true
+	case "lighten":
+		return BlendLighten, true
+	}
+	return BlendNormal, false
+}
+
+// String 返回CSS中的混合模式名称 / Return the CSS name of the blend mode
+func (m BlendMode) String() string {
+	switch m {
+	case BlendMultiply:
+		return "multiply"
+	case BlendScreen:
+		return "screen"
+	case BlendOverlay:
+		return "overlay"
+	case BlendDarken:
+		return "darken"
+	case BlendLighten:
+		return "lighten"
+	}
+	return "normal"
+}
+
+// SetBlendMode 设置后续绘制使用的混合模式 / Set the blend mode used for subsequent drawing
+func (r *ImageRenderer) SetBlendMode(mode BlendMode) {
+	r.BlendMode = mode
+}
+
+// blendChannelMode 按混合模式计算单个通道，参数和结果均为0..1 / Apply the blend function to one channel, in and out in 0..1
+func blendChannelMode(mode BlendMode, cb, cs float64) float64 {
+	switch mode {
+	case BlendMultiply:
+		return cb * cs
+	case BlendScreen:
+		return cb + cs - cb*cs
+	case BlendOverlay:
+		// 叠加即交换参数的强光 / Overlay is hard-light with the arguments swapped
+		if cb <= 0.5 {
+			return 2 * cb * cs
+		}
+		return 1 - 2*(1-cb)*(1-cs)
+	case BlendDarken:
+		return math.Min(cb, cs)
+	case BlendLighten:
+		return math.Max(cb, cs)
+	}
+	return cs
+}
+
+// applyBlendMode 将源颜色与背景按混合模式合成为新的源颜色，随后再进行源覆盖混合
+// applyBlendMode mixes the source with the backdrop per the blend mode, producing the color that is then composited source-over
+//
+// 按W3C合成规范，背景透明度决定混合结果所占比例 / Per the W3C compositing spec, backdrop alpha weights the blended result
+func applyBlendMode(mode BlendMode, bg, fg color.RGBA) color.RGBA {
+	if mode == BlendNormal || bg.A == 0 {
+		return fg
+	}
+	ab := float64(bg.A) / 255
+	channel := func(b, s uint8) uint8 {
+		cb := float64(b) / 255
+		cs := float64(s) / 255
+		mixed := (1-ab)*cs + ab*blendChannelMode(mode, cb, cs)
+		return uint8(math.Max(0, math.Min(255, mixed*255+0.5)))
+	}
+	return color.RGBA{
+		R: channel(bg.R, fg.R),
+		G: channel(bg.G, fg.G),
+		B: channel(bg.B, fg.B),
+		A: fg.A,
+	}
+}
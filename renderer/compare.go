@@ -0,0 +1,51 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+)
+
+// diffHighlight 差异图中不同像素的颜色 / Color of differing pixels in the diff image
+var diffHighlight = color.RGBA{255, 0, 0, 255}
+
+// diffFade 差异图中相同像素保留的暗度比例 / Share of darkness kept for matching pixels in the diff image
+const diffFade = 0.25
+
+// CompareImages 逐像素比较两幅图像，任一RGBA通道相差超过tolerance的像素计为不同
+// CompareImages compares two images pixel by pixel; a pixel differs when any RGBA channel differs by more than tolerance
+//
+// 比较覆盖两幅图像边界的并集，只存在于一幅图像中的像素总是计为不同。差异图中不同的像素为红色，
+// 相同的像素为淡化的灰度，便于定位回归
+// The comparison covers the union of both bounds, and pixels present in only one image always differ. In the diff image
+// differing pixels are red and matching pixels a faded grayscale, which makes regressions easy to locate
+func CompareImages(a, b image.Image, tolerance uint8) (equal bool, diffCount int, diffImage *image.RGBA) {
+	bounds := a.Bounds().Union(b.Bounds())
+	diffImage = image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+			if !p.In(a.Bounds()) || !p.In(b.Bounds()) {
+				diffImage.SetRGBA(x, y, diffHighlight)
+				diffCount++
+				continue
+			}
+			ca := color.RGBAModel.Convert(a.At(x, y)).(color.RGBA)
+			cb := color.RGBAModel.Convert(b.At(x, y)).(color.RGBA)
+			if channelDiffers(ca.R, cb.R, tolerance) || channelDiffers(ca.G, cb.G, tolerance) ||
+				channelDiffers(ca.B, cb.B, tolerance) || channelDiffers(ca.A, cb.A, tolerance) {
+				diffImage.SetRGBA(x, y, diffHighlight)
+				diffCount++
+				continue
+			}
+			gray := color.GrayModel.Convert(ca).(color.Gray).Y
+			faded := uint8(255 - float64(255-gray)*diffFade)
+			diffImage.SetRGBA(x, y, color.RGBA{faded, faded, faded, 255})
+		}
+	}
+	return diffCount == 0, diffCount, diffImage
+}
+
+// channelDiffers 判断两个通道值之差是否超过容差 / Report whether two channel values differ by more than the tolerance
+func channelDiffers(a, b, tolerance uint8) bool {
+	return abs(int(a)-int(b)) > int(tolerance)
+}
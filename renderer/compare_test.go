@@ -0,0 +1,42 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestCompareImages 测试逐像素比较的计数、容差和差异图 / Test per-pixel comparison counts, tolerance and the diff image
+func TestCompareImages(t *testing.T) {
+	a := CreateImage(20, 10, color.White)
+	DrawRect(a, 2, 2, 6, 4, color.RGBA{0, 0, 255, 255}, true)
+
+	equal, count, diff := CompareImages(a, a, 0)
+	if !equal || count != 0 {
+		t.Errorf("expected an image to equal itself, got equal=%v count=%d", equal, count)
+	}
+	if diff.Bounds() != a.Bounds() {
+		t.Errorf("expected diff bounds %v, got %v", a.Bounds(), diff.Bounds())
+	}
+
+	b := image.NewRGBA(a.Bounds())
+	copy(b.Pix, a.Pix)
+	b.SetRGBA(15, 5, color.RGBA{250, 250, 250, 255})
+	equal, count, diff = CompareImages(a, b, 0)
+	if equal || count != 1 {
+		t.Errorf("expected exactly one differing pixel, got equal=%v count=%d", equal, count)
+	}
+	if got := diff.RGBAAt(15, 5); got != diffHighlight {
+		t.Errorf("expected the differing pixel highlighted, got %v", got)
+	}
+	if got := diff.RGBAAt(0, 0); got == diffHighlight {
+		t.Errorf("expected matching pixels not highlighted")
+	}
+
+	if equal, _, _ := CompareImages(a, b, 5); !equal {
+		t.Errorf("expected a 5-level difference within tolerance 5")
+	}
+	if _, count, _ := CompareImages(a, CreateImage(20, 12, color.White), 0); count < 40 {
+		t.Errorf("expected the extra rows to differ, got %d differing pixels", count)
+	}
+}
@@ -0,0 +1,64 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+)
+
+// flattenOnWhite 将半透明像素合成到白色背景上 / Composite a translucent pixel onto a white background
+//
+// 渲染结果为非预乘颜色，打印输出没有透明通道 / Rendered pixels are straight alpha and print outputs have no alpha channel
+func flattenOnWhite(c color.RGBA) (uint8, uint8, uint8) {
+	if c.A == 255 {
+		return c.R, c.G, c.B
+	}
+	a := uint32(c.A)
+	flatten := func(v uint8) uint8 {
+		return uint8((uint32(v)*a + 255*(255-a) + 127) / 255)
+	}
+	return flatten(c.R), flatten(c.G), flatten(c.B)
+}
+
+// Luminance 按ITU-R BT.601权重计算颜色亮度 / Compute the luminance of a color with ITU-R BT.601 weights
+func Luminance(r, g, b uint8) uint8 {
+	y := (19595*uint32(r) + 38470*uint32(g) + 7471*uint32(b) + 1<<15) >> 16
+	return uint8(y)
+}
+
+// ToGray 将渲染结果转换为8位灰度图，透明区域视为白色 / Convert a rendered image to 8-bit grayscale, treating transparency as white
+func ToGray(src *image.RGBA) *image.Gray {
+	bounds := src.Bounds()
+	dst := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b := flattenOnWhite(src.RGBAAt(x, y))
+			dst.SetGray(x, y, color.Gray{Y: Luminance(r, g, b)})
+		}
+	}
+	return dst
+}
+
+// ToCMYK 将渲染结果转换为CMYK图像，透明区域视为白色（无墨）/ Convert a rendered image to CMYK, treating transparency as white (no ink)
+func ToCMYK(src *image.RGBA) *image.CMYK {
+	bounds := src.Bounds()
+	dst := image.NewCMYK(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dst.SetCMYK(x, y, RGBAToCMYK(src.RGBAAt(x, y)))
+		}
+	}
+	return dst
+}
+
+// RGBAToCMYK 将颜色转换为CMYK，半透明颜色先合成到白色上 / Convert a color to CMYK, flattening translucency onto white first
+func RGBAToCMYK(c color.RGBA) color.CMYK {
+	r, g, b := flattenOnWhite(c)
+	cy, m, ye, k := color.RGBToCMYK(r, g, b)
+	return color.CMYK{C: cy, M: m, Y: ye, K: k}
+}
+
+// CMYKToRGBA 将CMYK颜色转换回不透明的RGBA / Convert a CMYK color back to opaque RGBA
+func CMYKToRGBA(c color.CMYK) color.RGBA {
+	r, g, b := color.CMYKToRGB(c.C, c.M, c.Y, c.K)
+	return color.RGBA{R: r, G: g, B: b, A: 255}
+}
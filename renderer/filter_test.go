@@ -0,0 +1,44 @@
+package renderer
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hoonfeng/svg/parser"
+)
+
+// TestFilterOffsetMerge 测试feOffset与feMerge组合的滤镜 / Test a filter composed of feOffset and feMerge
+func TestFilterOffsetMerge(t *testing.T) {
+	const content = `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50" viewBox="0 0 100 50">
+	<defs>
+		<filter id="shadow">
+			<feOffset in="SourceAlpha" dx="40" dy="0" result="moved"/>
+			<feMerge>
+				<feMergeNode in="moved"/>
+				<feMergeNode in="SourceGraphic"/>
+			</feMerge>
+		</filter>
+	</defs>
+	<rect x="10" y="10" width="20" height="20" fill="#ff0000" filter="url(#shadow)"/>
+</svg>`
+
+	doc, err := parser.NewXMLParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 100, 50)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if got := img.RGBAAt(20, 20); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the source graphic at its original position, got %v", got)
+	}
+	// SourceAlpha的偏移副本为黑色 / The offset copy of SourceAlpha is black
+	if got := img.RGBAAt(60, 20); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("expected the offset shadow at +40px, got %v", got)
+	}
+	if got := img.RGBAAt(80, 20); got.A != 0 {
+		t.Errorf("expected nothing beyond the offset copy, got %v", got)
+	}
+}
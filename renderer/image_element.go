@@ -0,0 +1,200 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"  // 注册GIF解码器 / Register the GIF decoder
+	_ "image/jpeg" // 注册JPEG解码器 / Register the JPEG decoder
+	_ "image/png"  // 注册PNG解码器 / Register the PNG decoder
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// AspectRatio 表示preserveAspectRatio的对齐方式和缩放策略 / The alignment and scaling strategy of preserveAspectRatio
+type AspectRatio struct {
+	None   bool    // 为true时非等比拉伸填满视口 / When true, stretch non-uniformly to fill the viewport
+	AlignX float64 // 水平对齐，xMin/xMid/xMax分别为0/0.5/1 / Horizontal alignment: 0, 0.5 and 1 for xMin, xMid and xMax
+	AlignY float64 // 垂直对齐，yMin/yMid/yMax分别为0/0.5/1 / Vertical alignment: 0, 0.5 and 1 for yMin, yMid and yMax
+	Slice  bool    // 为true时覆盖视口并裁剪溢出部分，否则完整放入视口 / When true, cover the viewport and crop the overflow; otherwise fit inside it
+}
+
+// ParseAspectRatio 解析preserveAspectRatio属性，空值或无效值按默认的xMidYMid meet处理
+// ParseAspectRatio parses a preserveAspectRatio attribute; empty or invalid values fall back to the default xMidYMid meet
+func ParseAspectRatio(value string) AspectRatio {
+	ratio := AspectRatio{AlignX: 0.5, AlignY: 0.5}
+	fields := strings.Fields(value)
+	if len(fields) > 0 && fields[0] == "defer" {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ratio
+	}
+
+	align := fields[0]
+	if align == "none" {
+		ratio.None = true
+	} else if len(align) == 8 {
+		positions := map[string]float64{"Min": 0, "Mid": 0.5, "Max": 1}
+		x, okX := positions[align[1:4]]
+		y, okY := positions[align[5:8]]
+		if align[0] != 'x' || align[4] != 'Y' || !okX || !okY {
+			return AspectRatio{AlignX: 0.5, AlignY: 0.5}
+		}
+		ratio.AlignX, ratio.AlignY = x, y
+	} else {
+		return ratio
+	}
+	if len(fields) > 1 && fields[1] == "slice" {
+		ratio.Slice = true
+	}
+	return ratio
+}
+
+// Place 返回尺寸为width×height的内容在视口中按该方式放置后的矩形 / Return the rectangle content of size width×height occupies when placed in the viewport this way
+func (a AspectRatio) Place(viewport types.Rect, width, height float64) types.Rect {
+	if a.None || width <= 0 || height <= 0 {
+		return viewport
+	}
+	scale := math.Min(viewport.W/width, viewport.H/height)
+	if a.Slice {
+		scale = math.Max(viewport.W/width, viewport.H/height)
+	}
+	w, h := width*scale, height*scale
+	return types.Rect{
+		X: viewport.X + (viewport.W-w)*a.AlignX,
+		Y: viewport.Y + (viewport.H-h)*a.AlignY,
+		W: w,
+		H: h,
+	}
+}
+
+// renderImage 渲染image元素，按preserveAspectRatio放置栅格图像，slice时裁剪到视口
+// renderImage renders an image element, placing the raster per preserveAspectRatio and clipping to the viewport for slice
+func (r *ImageRenderer) renderImage(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
+	attrs := styledAttributes(element)
+	href := attrs["href"]
+	if href == "" {
+		href = attrs["xlink:href"]
+	}
+	src, err := r.loadImageHref(href)
+	if err != nil {
+		if r.StrictMode {
+			return err
+		}
+		// 尽力渲染：跳过无法加载的图像并记录 / Best-effort rendering: skip images that cannot be loaded and record them
+		r.warnings = append(r.warnings, fmt.Sprintf("image not rendered: %v", err))
+		return nil
+	}
+
+	x, _ := parseFloat(attrs["x"], 0)
+	y, _ := parseFloat(attrs["y"], 0)
+	size := src.Bounds().Size()
+	width, _ := parseFloat(attrs["width"], float64(size.X))
+	height, _ := parseFloat(attrs["height"], float64(size.Y))
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	viewport := types.Rect{X: x, Y: y, W: width, H: height}
+	ratio := ParseAspectRatio(attrs["preserveAspectRatio"])
+	placed := ratio.Place(viewport, float64(size.X), float64(size.Y))
+
+	target := r.target(img)
+	if ratio.Slice {
+		target.SetClip(deviceRect(viewport, viewBox, scaleX, scaleY))
+		defer target.SetClip(image.Rectangle{})
+	}
+	target.DrawImage(src, deviceRect(placed, viewBox, scaleX, scaleY))
+	return nil
+}
+
+// deviceRect 将用户空间矩形转换为取整后的设备矩形 / Convert a user-space rectangle to a rounded device rectangle
+func deviceRect(rect types.Rect, viewBox []float64, scaleX, scaleY float64) image.Rectangle {
+	return image.Rect(
+		int(math.Round((rect.X-viewBox[0])*scaleX)), int(math.Round((rect.Y-viewBox[1])*scaleY)),
+		int(math.Round((rect.X+rect.W-viewBox[0])*scaleX)), int(math.Round((rect.Y+rect.H-viewBox[1])*scaleY)),
+	)
+}
+
+// loadImageHref 解码href引用的图像，支持data URI，设置ImageDir时还支持其中的本地文件
+// loadImageHref decodes the image an href references; data URIs are supported, as are local files inside ImageDir when it is set
+func (r *ImageRenderer) loadImageHref(href string) (image.Image, error) {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return nil, fmt.Errorf("图像元素缺少href属性")
+	}
+
+	var data []byte
+	if strings.HasPrefix(href, "data:") {
+		comma := strings.Index(href, ",")
+		if comma < 0 {
+			return nil, fmt.Errorf("无效的data URI")
+		}
+		header, payload := href[len("data:"):comma], href[comma+1:]
+		if strings.HasSuffix(header, ";base64") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(payload), ""))
+			if err != nil {
+				return nil, fmt.Errorf("解码图像数据失败: %v", err)
+			}
+			data = decoded
+		} else {
+			unescaped, err := url.PathUnescape(payload)
+			if err != nil {
+				return nil, fmt.Errorf("解码图像数据失败: %v", err)
+			}
+			data = []byte(unescaped)
+		}
+	} else {
+		name, err := r.imageFile(strings.TrimPrefix(href, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		file, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("读取图像文件失败: %v", err)
+		}
+		data = file
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解码图像失败: %v", err)
+	}
+	return decoded, nil
+}
+
+// imageFile 将图像引用解析为ImageDir内的文件路径，未设置ImageDir或解析到目录外时报错
+// imageFile resolves an image reference to a file path inside ImageDir, failing when ImageDir is unset or the path resolves outside it
+//
+// 相对路径相对于ImageDir，符号链接解析后再检查 / Relative paths are relative to ImageDir, and symbolic links are resolved before checking
+func (r *ImageRenderer) imageFile(name string) (string, error) {
+	if r.ImageDir == "" {
+		return "", fmt.Errorf("未启用本地图像文件访问: %q", name)
+	}
+	dir, err := filepath.Abs(r.ImageDir)
+	if err == nil {
+		dir, err = filepath.EvalSymlinks(dir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("无效的图像目录: %v", err)
+	}
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(dir, name)
+	}
+	resolved, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return "", fmt.Errorf("读取图像文件失败: %v", err)
+	}
+	rel, err := filepath.Rel(dir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("图像文件不在图像目录内: %q", name)
+	}
+	return resolved, nil
+}
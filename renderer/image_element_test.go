@@ -0,0 +1,151 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hoonfeng/svg/parser"
+)
+
+// renderPlacedImage 将左红右蓝的40x20图像放入20x20的框中渲染 / Render a 40x20 image, red on the left and blue on the right, into a 20x20 frame
+func renderPlacedImage(t *testing.T, preserveAspectRatio string) *image.RGBA {
+	t.Helper()
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			c := color.RGBA{255, 0, 0, 255}
+			if x >= 20 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			src.SetRGBA(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+
+	doc, err := parser.NewXMLParser().ParseString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="40" viewBox="0 0 40 40">
+	<image x="0" y="0" width="20" height="20" preserveAspectRatio="%s" href="data:image/png;base64,%s"/>
+</svg>`, preserveAspectRatio, base64.StdEncoding.EncodeToString(buf.Bytes())))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 40, 40)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	return img
+}
+
+// TestImageAspectRatio 测试preserveAspectRatio的slice和meet放置 / Test slice and meet placement under preserveAspectRatio
+func TestImageAspectRatio(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	tests := []struct {
+		value string
+		want  map[image.Point]color.RGBA
+	}{
+		// 放大到40x20并居中，裁去两侧各10像素 / Scaled to 40x20 and centred, cropping 10 pixels on each side
+		{"xMidYMid slice", map[image.Point]color.RGBA{
+			{1, 1}: red, {5, 10}: red, {15, 10}: blue, {18, 18}: blue, {25, 10}: {}, {5, 25}: {},
+		}},
+		// 缩小到20x10并靠左上 / Scaled to 20x10 and aligned top-left
+		{"xMinYMin meet", map[image.Point]color.RGBA{
+			{5, 5}: red, {15, 5}: blue, {5, 15}: {}, {25, 5}: {},
+		}},
+		// 缩小到20x10并靠下 / Scaled to 20x10 and aligned to the bottom
+		{"xMaxYMax meet", map[image.Point]color.RGBA{
+			{5, 5}: {}, {5, 15}: red, {15, 15}: blue,
+		}},
+		// 非等比拉伸填满框 / Stretched non-uniformly to fill the frame
+		{"none", map[image.Point]color.RGBA{
+			{5, 18}: red, {15, 1}: blue, {25, 10}: {},
+		}},
+	}
+	for _, tt := range tests {
+		img := renderPlacedImage(t, tt.value)
+		for p, want := range tt.want {
+			if got := img.RGBAAt(p.X, p.Y); got != want {
+				t.Errorf("%s: expected %v at %v, got %v", tt.value, want, p, got)
+			}
+		}
+	}
+}
+
+// TestParseAspectRatio 测试preserveAspectRatio的解析和默认值 / Test parsing preserveAspectRatio and its default
+func TestParseAspectRatio(t *testing.T) {
+	tests := map[string]AspectRatio{
+		"":                    {AlignX: 0.5, AlignY: 0.5},
+		"xMinYMax slice":      {AlignX: 0, AlignY: 1, Slice: true},
+		"defer xMaxYMid meet": {AlignX: 1, AlignY: 0.5},
+		"none":                {None: true, AlignX: 0.5, AlignY: 0.5},
+		"bogus":               {AlignX: 0.5, AlignY: 0.5},
+	}
+	for value, want := range tests {
+		if got := ParseAspectRatio(value); got != want {
+			t.Errorf("ParseAspectRatio(%q): expected %+v, got %+v", value, want, got)
+		}
+	}
+}
+
+// TestImageDir 测试本地图像文件只在ImageDir内可读 / Test local image files are only readable inside ImageDir
+func TestImageDir(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "images")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range src.Pix {
+		src.Pix[i] = 255
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	for _, name := range []string{filepath.Join(dir, "inside.png"), filepath.Join(root, "outside.png")} {
+		if err := os.WriteFile(name, buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name, imageDir, href string
+		drawn                bool
+	}{
+		{"disabled by default", "", filepath.Join(dir, "inside.png"), false},
+		{"relative to the directory", dir, "inside.png", true},
+		{"absolute inside the directory", dir, "file://" + filepath.Join(dir, "inside.png"), true},
+		{"escaping the directory", dir, "../outside.png", false},
+		{"absolute outside the directory", dir, filepath.Join(root, "outside.png"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.NewXMLParser().ParseString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="4" height="4" viewBox="0 0 4 4">
+	<image x="0" y="0" width="4" height="4" href="%s"/>
+</svg>`, tt.href))
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			r := NewImageRenderer()
+			r.ImageDir = tt.imageDir
+			img, err := r.Render(doc, 4, 4)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if drawn := img.RGBAAt(2, 2).A != 0; drawn != tt.drawn {
+				t.Errorf("expected drawn=%v, got %v (warnings %q)", tt.drawn, drawn, r.Warnings())
+			}
+			if !tt.drawn && len(r.Warnings()) == 0 {
+				t.Error("expected a warning for the image that was not loaded")
+			}
+		})
+	}
+}
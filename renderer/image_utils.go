@@ -23,7 +23,7 @@ func CreateImage(width, height int, background color.Color) *image.RGBA {
 // DrawPixel 在图像上绘制像素
 func DrawPixel(img *image.RGBA, x, y int, c color.Color) {
 	// 检查边界
-	if x < 0 || y < 0 || x >= img.Bounds().Dx() || y >= img.Bounds().Dy() {
+	if !image.Pt(x, y).In(img.Bounds()) {
 		return
 	}
 
@@ -67,8 +67,13 @@ func DrawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
 	}
 }
 
-// DrawAntiAliasedLine 绘制抗锯齿直线
+// DrawAntiAliasedLine 使用默认设置绘制抗锯齿直线 / Draw an anti-aliased line with the default settings
 func DrawAntiAliasedLine(img *image.RGBA, x0, y0, x1, y1 float64, c color.Color, strokeWidth float64) {
+	defaultAntiAliasedRenderer.DrawAntiAliasedLine(img, x0, y0, x1, y1, c, strokeWidth)
+}
+
+// DrawAntiAliasedLine 绘制抗锯齿直线，LinearBlending决定混合空间 / Draw an anti-aliased line, blending in the space chosen by LinearBlending
+func (r *ImageRenderer) DrawAntiAliasedLine(img *image.RGBA, x0, y0, x1, y1 float64, c color.Color, strokeWidth float64) {
 	// 使用改进的超采样抗锯齿算法
 	dx := x1 - x0
 	dy := y1 - y0
@@ -76,7 +81,7 @@ func DrawAntiAliasedLine(img *image.RGBA, x0, y0, x1, y1 float64, c color.Color,
 	
 	// 如果线条太短，直接绘制点
 	if length < 0.5 {
-		DrawAntiAliasedPixel(img, x0, y0, c, 1.0)
+		r.DrawAntiAliasedPixel(img, x0, y0, c, 1.0)
 		return
 	}
 	
@@ -99,19 +104,24 @@ func DrawAntiAliasedLine(img *image.RGBA, x0, y0, x1, y1 float64, c color.Color,
 			// 使用4x4超采样计算覆盖率
 			coverage := calculateLineCoverage(float64(px), float64(py), x0, y0, x1, y1, strokeWidth)
 			if coverage > 0 {
-				blendPixelWithCoverage(img, px, py, c, coverage)
+				blendPixelWithCoverage(img, px, py, c, coverage, r.LinearBlending)
 			}
 		}
 	}
 }
 
-// DrawAntiAliasedPixel 绘制抗锯齿像素
+// DrawAntiAliasedPixel 使用默认设置按alpha混合单个像素 / Blend a single pixel by alpha with the default settings
 func DrawAntiAliasedPixel(img *image.RGBA, x, y float64, c color.Color, alpha float64) {
+	defaultAntiAliasedRenderer.DrawAntiAliasedPixel(img, x, y, c, alpha)
+}
+
+// DrawAntiAliasedPixel 按alpha混合单个像素 / Blend a single pixel by alpha
+func (r *ImageRenderer) DrawAntiAliasedPixel(img *image.RGBA, x, y float64, c color.Color, alpha float64) {
 	px := int(math.Floor(x))
 	py := int(math.Floor(y))
 	
 	// 检查边界
-	if px < 0 || py < 0 || px >= img.Bounds().Dx() || py >= img.Bounds().Dy() {
+	if !image.Pt(px, py).In(img.Bounds()) {
 		return
 	}
 	
@@ -124,7 +134,7 @@ func DrawAntiAliasedPixel(img *image.RGBA, x, y float64, c color.Color, alpha fl
 	}
 	
 	// 直接使用blendPixelWithCoverage函数，保持一致性
-	blendPixelWithCoverage(img, px, py, c, alpha)
+	blendPixelWithCoverage(img, px, py, c, alpha, r.LinearBlending)
 }
 
 // calculateLineCoverage 计算像素在线条中的覆盖率 / Calculate pixel coverage in line
@@ -212,22 +222,32 @@ func DrawCircle(img *image.RGBA, centerX, centerY, radius int, c color.Color, fi
 	}
 }
 
-// DrawAntiAliasedFilledCircle 绘制抗锯齿填充圆形 / Draw anti-aliased filled circle
+// DrawAntiAliasedFilledCircle 使用默认设置绘制抗锯齿填充圆形 / Draw an anti-aliased filled circle with the default settings
 func DrawAntiAliasedFilledCircle(img *image.RGBA, centerX, centerY, radius int, c color.Color) {
+	defaultAntiAliasedRenderer.DrawAntiAliasedFilledCircle(img, centerX, centerY, radius, c)
+}
+
+// DrawAntiAliasedFilledCircle 绘制抗锯齿填充圆形 / Draw an anti-aliased filled circle
+func (r *ImageRenderer) DrawAntiAliasedFilledCircle(img *image.RGBA, centerX, centerY, radius int, c color.Color) {
 	// 使用超采样抗锯齿算法 / Use supersampling anti-aliasing
 	for y := centerY - radius - 1; y <= centerY + radius + 1; y++ {
 		for x := centerX - radius - 1; x <= centerX + radius + 1; x++ {
 			// 计算覆盖率 / Calculate coverage
 			coverage := calculateCircleCoverage(float64(x), float64(y), float64(centerX), float64(centerY), float64(radius))
 			if coverage > 0 {
-				blendPixelWithCoverage(img, x, y, c, coverage)
+				blendPixelWithCoverage(img, x, y, c, coverage, r.LinearBlending)
 			}
 		}
 	}
 }
 
-// DrawAntiAliasedCircleOutline 绘制抗锯齿圆形轮廓 / Draw anti-aliased circle outline
+// DrawAntiAliasedCircleOutline 使用默认设置绘制抗锯齿圆形轮廓 / Draw an anti-aliased circle outline with the default settings
 func DrawAntiAliasedCircleOutline(img *image.RGBA, centerX, centerY, radius int, c color.Color) {
+	defaultAntiAliasedRenderer.DrawAntiAliasedCircleOutline(img, centerX, centerY, radius, c)
+}
+
+// DrawAntiAliasedCircleOutline 绘制抗锯齿圆形轮廓 / Draw an anti-aliased circle outline
+func (r *ImageRenderer) DrawAntiAliasedCircleOutline(img *image.RGBA, centerX, centerY, radius int, c color.Color) {
 	// 使用距离场抗锯齿算法 / Use distance field anti-aliasing
 	strokeWidth := 1.0 // 默认描边宽度
 	innerRadius := float64(radius) - strokeWidth/2
@@ -241,7 +261,7 @@ func DrawAntiAliasedCircleOutline(img *image.RGBA, centerX, centerY, radius int,
 			// 计算轮廓覆盖率
 			coverage := calculateCircleOutlineCoverage(float64(x), float64(y), float64(centerX), float64(centerY), innerRadius, outerRadius)
 			if coverage > 0 {
-				blendPixelWithCoverage(img, x, y, c, coverage)
+				blendPixelWithCoverage(img, x, y, c, coverage, r.LinearBlending)
 			}
 		}
 	}
@@ -267,8 +287,13 @@ func DrawEllipse(img *image.RGBA, centerX, centerY, radiusX, radiusY int, c colo
 	}
 }
 
-// DrawAntiAliasedFilledEllipse 绘制抗锯齿填充椭圆 / Draw anti-aliased filled ellipse
+// DrawAntiAliasedFilledEllipse 使用默认设置绘制抗锯齿填充椭圆 / Draw an anti-aliased filled ellipse with the default settings
 func DrawAntiAliasedFilledEllipse(img *image.RGBA, centerX, centerY, radiusX, radiusY int, c color.Color) {
+	defaultAntiAliasedRenderer.DrawAntiAliasedFilledEllipse(img, centerX, centerY, radiusX, radiusY, c)
+}
+
+// DrawAntiAliasedFilledEllipse 绘制抗锯齿填充椭圆 / Draw an anti-aliased filled ellipse
+func (r *ImageRenderer) DrawAntiAliasedFilledEllipse(img *image.RGBA, centerX, centerY, radiusX, radiusY int, c color.Color) {
 	// 使用超采样抗锯齿算法 / Use supersampling anti-aliasing
 	maxRadius := int(math.Max(float64(radiusX), float64(radiusY)))
 	for y := centerY - maxRadius - 1; y <= centerY + maxRadius + 1; y++ {
@@ -276,14 +301,19 @@ func DrawAntiAliasedFilledEllipse(img *image.RGBA, centerX, centerY, radiusX, ra
 			// 计算覆盖率 / Calculate coverage
 			coverage := calculateEllipseCoverage(float64(x), float64(y), float64(centerX), float64(centerY), float64(radiusX), float64(radiusY))
 			if coverage > 0 {
-				blendPixelWithCoverage(img, x, y, c, coverage)
+				blendPixelWithCoverage(img, x, y, c, coverage, r.LinearBlending)
 			}
 		}
 	}
 }
 
-// DrawAntiAliasedEllipseOutline 绘制抗锯齿椭圆轮廓 / Draw anti-aliased ellipse outline
+// DrawAntiAliasedEllipseOutline 使用默认设置绘制抗锯齿椭圆轮廓 / Draw an anti-aliased ellipse outline with the default settings
 func DrawAntiAliasedEllipseOutline(img *image.RGBA, centerX, centerY, radiusX, radiusY int, c color.Color) {
+	defaultAntiAliasedRenderer.DrawAntiAliasedEllipseOutline(img, centerX, centerY, radiusX, radiusY, c)
+}
+
+// DrawAntiAliasedEllipseOutline 绘制抗锯齿椭圆轮廓 / Draw an anti-aliased ellipse outline
+func (r *ImageRenderer) DrawAntiAliasedEllipseOutline(img *image.RGBA, centerX, centerY, radiusX, radiusY int, c color.Color) {
 	// 使用距离场抗锯齿算法 / Use distance field anti-aliasing
 	strokeWidth := 1.0 // 默认描边宽度
 	
@@ -301,7 +331,7 @@ func DrawAntiAliasedEllipseOutline(img *image.RGBA, centerX, centerY, radiusX, r
 			// 计算椭圆轮廓覆盖率
 			coverage := calculateEllipseOutlineCoverage(float64(x), float64(y), float64(centerX), float64(centerY), innerRadiusX, innerRadiusY, outerRadiusX, outerRadiusY)
 			if coverage > 0 {
-				blendPixelWithCoverage(img, x, y, c, coverage)
+				blendPixelWithCoverage(img, x, y, c, coverage, r.LinearBlending)
 			}
 		}
 	}
@@ -418,9 +448,9 @@ func calculateEllipseOutlineCoverage(x, y, centerX, centerY, innerRadiusX, inner
 	return float64(coveredSamples) / float64(samples*samples)
 }
 
-// blendPixelWithCoverage 根据覆盖率混合像素 / Blend pixel with coverage
-func blendPixelWithCoverage(img *image.RGBA, x, y int, c color.Color, coverage float64) {
-	if x < 0 || y < 0 || x >= img.Bounds().Dx() || y >= img.Bounds().Dy() {
+// blendPixelWithCoverage 根据覆盖率混合像素，linear为true时在线性光空间中混合 / Blend pixel with coverage, in linear light when linear is true
+func blendPixelWithCoverage(img *image.RGBA, x, y int, c color.Color, coverage float64, linear bool) {
+	if !image.Pt(x, y).In(img.Bounds()) {
 		return
 	}
 	
@@ -448,14 +478,55 @@ func blendPixelWithCoverage(img *image.RGBA, x, y int, c color.Color, coverage f
 	invAlpha := 1.0 - alpha
 	
 	// 计算最终颜色 / Calculate final color
-	finalR := uint8(r1*invAlpha + newR*alpha)
-	finalG := uint8(g1*invAlpha + newG*alpha)
-	finalB := uint8(b1*invAlpha + newB*alpha)
+	var finalR, finalG, finalB uint8
+	if linear {
+		finalR = blendChannel(uint8(r1), uint8(newR), alpha, true)
+		finalG = blendChannel(uint8(g1), uint8(newG), alpha, true)
+		finalB = blendChannel(uint8(b1), uint8(newB), alpha, true)
+	} else {
+		finalR = uint8(r1*invAlpha + newR*alpha)
+		finalG = uint8(g1*invAlpha + newG*alpha)
+		finalB = uint8(b1*invAlpha + newB*alpha)
+	}
 	finalA := uint8(math.Max(a1, newA*coverage))
 	
 	img.SetRGBA(x, y, color.RGBA{R: finalR, G: finalG, B: finalB, A: finalA})
 }
 
+// srgbToLinearTable sRGB到线性光的查找表 / Lookup table from sRGB to linear light
+var srgbToLinearTable = func() [256]float64 {
+	var table [256]float64
+	for i := range table {
+		table[i] = srgbToLinear(float64(i) / 255.0)
+	}
+	return table
+}()
+
+// srgbToLinear 将sRGB分量转换为线性光 / Convert an sRGB component to linear light
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB 将线性光分量转换为sRGB / Convert a linear-light component to sRGB
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// blendChannel 混合单个颜色通道，linear为true时在线性光空间中混合 / Blend a single color channel, in linear light when linear is true
+func blendChannel(bg, fg uint8, alpha float64, linear bool) uint8 {
+	if !linear {
+		return uint8(float64(bg)*(1-alpha) + float64(fg)*alpha)
+	}
+	mixed := srgbToLinearTable[bg]*(1-alpha) + srgbToLinearTable[fg]*alpha
+	return uint8(math.Max(0, math.Min(255, linearToSRGB(mixed)*255+0.5)))
+}
+
 // abs 返回整数的绝对值
 func abs(x int) int {
 	if x < 0 {
@@ -0,0 +1,141 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/types"
+)
+
+// TestLinearBlending 测试线性光混合与sRGB混合的差异 / Test linear-light blending differs from sRGB blending
+func TestLinearBlending(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	naive := blendColors(black, white, 0.5)
+	if naive.R != 127 {
+		t.Errorf("expected sRGB blend of 127, got %d", naive.R)
+	}
+
+	linear := blendColorsLinear(black, white, 0.5)
+	if linear.R < 186 || linear.R > 189 {
+		t.Errorf("expected linear blend around 188, got %d", linear.R)
+	}
+
+	r := NewImageRenderer()
+	if got := r.compositeColors(black, white, 0.5); got != naive {
+		t.Errorf("expected sRGB compositing by default, got %v", got)
+	}
+	r.SetLinearBlending(true)
+	if got := r.compositeColors(black, white, 0.5); got != linear {
+		t.Errorf("expected linear compositing when enabled, got %v", got)
+	}
+}
+
+// TestBlendPixelWithCoverageLinear 测试按覆盖率进行线性光像素混合 / Test linear-light pixel blending with coverage
+func TestBlendPixelWithCoverageLinear(t *testing.T) {
+	img := CreateImage(2, 1, color.RGBA{0, 0, 0, 255})
+	white := color.RGBA{255, 255, 255, 255}
+
+	blendPixelWithCoverage(img, 0, 0, white, 0.5, false)
+	blendPixelWithCoverage(img, 1, 0, white, 0.5, true)
+
+	srgb := img.RGBAAt(0, 0)
+	linear := img.RGBAAt(1, 0)
+	if srgb.R != 127 {
+		t.Errorf("expected sRGB pixel of 127, got %d", srgb.R)
+	}
+	if linear.R <= srgb.R {
+		t.Errorf("expected linear pixel brighter than sRGB pixel, got %d <= %d", linear.R, srgb.R)
+	}
+}
+
+// TestRendererLinearBlendingHelpers 测试仅设置渲染器标志即可让抗锯齿绘制函数使用线性光混合
+// TestRendererLinearBlendingHelpers tests that setting only the renderer flag makes the anti-aliased drawing helpers blend in linear light
+func TestRendererLinearBlendingHelpers(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	// 线性光混合使部分覆盖的边缘像素更亮 / Linear blending brightens partially covered edge pixels
+	brighter := func(name string, srgb, linear *image.RGBA) {
+		t.Helper()
+		var sumSRGB, sumLinear int
+		for i := 0; i < len(srgb.Pix); i += 4 {
+			sumSRGB += int(srgb.Pix[i])
+			sumLinear += int(linear.Pix[i])
+		}
+		if sumLinear <= sumSRGB {
+			t.Errorf("%s: expected LinearBlending to brighten the edges, got %d <= %d", name, sumLinear, sumSRGB)
+		}
+	}
+
+	r := NewImageRenderer()
+	r.SetLinearBlending(true)
+	srgb := CreateImage(20, 20, color.RGBA{0, 0, 0, 255})
+	linear := CreateImage(20, 20, color.RGBA{0, 0, 0, 255})
+	DrawAntiAliasedFilledCircle(srgb, 10, 10, 6, white)
+	r.DrawAntiAliasedFilledCircle(linear, 10, 10, 6, white)
+	brighter("filled circle", srgb, linear)
+
+	ar := NewAntiAliasedRenderer()
+	ar.SetLinearBlending(true)
+	srgb = CreateImage(20, 20, color.RGBA{0, 0, 0, 255})
+	linear = CreateImage(20, 20, color.RGBA{0, 0, 0, 255})
+	DrawAntiAliasedEllipse(srgb, 10, 10, 7, 4, white, 1.5)
+	ar.DrawAntiAliasedEllipseWithWidth(linear, 10, 10, 7, 4, white, 1.5)
+	brighter("ellipse stroke", srgb, linear)
+}
+
+// TestBlendModes 测试正片叠底、滤色和叠加混合 / Test multiply, screen and overlay blending
+func TestBlendModes(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	gray := color.RGBA{128, 128, 128, 255}
+	dark := color.RGBA{64, 64, 64, 255}
+
+	r := NewImageRenderer()
+	r.SetBlendMode(BlendMultiply)
+	if got := r.compositeColors(white, gray, 0.5); got.R < 190 || got.R > 192 {
+		t.Errorf("expected 50%% multiply of gray over white around 191, got %v", got)
+	}
+	if got := r.compositeColors(dark, gray, 1); got.R != 32 {
+		t.Errorf("expected multiply of 64 and 128 to be 32, got %v", got)
+	}
+
+	r.SetBlendMode(BlendScreen)
+	if got := r.compositeColors(dark, gray, 1); got.R != 160 {
+		t.Errorf("expected screen of 64 and 128 to be 160, got %v", got)
+	}
+
+	r.SetBlendMode(BlendOverlay)
+	if got := r.compositeColors(dark, dark, 1); got.R != 32 {
+		t.Errorf("expected overlay on a dark backdrop to multiply, got %v", got)
+	}
+	light := color.RGBA{192, 192, 192, 255}
+	if got := r.compositeColors(light, light, 1); got.R != 224 {
+		t.Errorf("expected overlay on a light backdrop to screen, got %v", got)
+	}
+}
+
+// TestMixBlendModeAttribute 测试元素的mix-blend-mode属性 / Test an element's mix-blend-mode attribute
+func TestMixBlendModeAttribute(t *testing.T) {
+	doc := types.NewDocument(10, 10)
+	doc.ViewBox = "0 0 10 10"
+
+	backdrop := elements.NewRect(0, 0, 10, 10)
+	backdrop.SetAttribute("fill", "#ff8080")
+	layer := elements.NewRect(0, 0, 10, 10)
+	layer.SetAttribute("fill", "#808080")
+	layer.SetAttribute("mix-blend-mode", "multiply")
+	doc.AppendElement(backdrop)
+	doc.AppendElement(layer)
+
+	img, err := RenderDocument(doc, 10, 10)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	// 正常混合会得到纯灰色 / Normal blending would give plain gray
+	got := img.RGBAAt(5, 5)
+	if got.R != 128 || got.G != 64 || got.B != 64 {
+		t.Errorf("expected multiplied color (128,64,64), got %v", got)
+	}
+}
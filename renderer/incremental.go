@@ -0,0 +1,127 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/types"
+)
+
+// trackedFrame 一次完整渲染的文档、画布尺寸和元素设备边界 / Document, canvas size and element device bounds of one full render
+type trackedFrame struct {
+	doc    *types.Document
+	size   image.Point
+	bounds map[types.Element]image.Rectangle
+}
+
+// RenderIncremental 只重绘改变的元素：将changed中元素在上一帧和当前文档中的设备边界的并集重新渲染到prev上，返回重绘的矩形
+// RenderIncremental repaints only what changed: the union of the changed elements' device bounds in the previous frame and in the current document is re-rendered onto prev, and the repainted rectangle is returned
+//
+// prev须为启用TrackBounds时由该渲染器完整渲染的同一文档，之后可连续增量渲染；changed可包含已移除的元素。
+// 边界无法确定、带滤镜、标记或非组变换的元素按整个画布处理。通过url(#id)等引用受影响的其他元素不会被自动计入
+// prev must be a full render of the same document by this renderer with TrackBounds enabled, after which incremental renders can follow one another; changed may include removed elements.
+// Elements with unknown bounds, filters, markers or non-group transforms count as the whole canvas. Other elements affected through references such as url(#id) are not included automatically
+func (r *ImageRenderer) RenderIncremental(prev *image.RGBA, changed []types.Element) (image.Rectangle, error) {
+	frame := r.tracked
+	if frame == nil {
+		return image.Rectangle{}, fmt.Errorf("增量渲染前需要启用TrackBounds完成一次完整渲染")
+	}
+	if prev == nil || prev.Bounds().Size() != frame.size {
+		return image.Rectangle{}, fmt.Errorf("上一帧图像与跟踪的画布尺寸%dx%d不一致", frame.size.X, frame.size.Y)
+	}
+
+	canvas := image.Rectangle{Max: frame.size}
+	current := trackBounds(frame.doc, frame.size.X, frame.size.Y)
+	var dirty image.Rectangle
+	for _, element := range changed {
+		before, hadBefore := frame.bounds[element]
+		after, hasAfter := current[element]
+		if !hadBefore && !hasAfter {
+			// 从未被跟踪的元素，例如新增到文档外的元素，保守地重绘整个画布 / An element never tracked, such as one outside the document, conservatively repaints the whole canvas
+			dirty = canvas
+			break
+		}
+		dirty = dirty.Union(before).Union(after)
+	}
+	frame.bounds = current
+
+	dirty = dirty.Intersect(canvas)
+	if dirty.Empty() {
+		return image.Rectangle{}, nil
+	}
+	region, err := r.RenderRegion(frame.doc, frame.size.X, frame.size.Y, dirty)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	xdraw.Draw(prev, dirty.Add(prev.Bounds().Min), region, image.Point{}, xdraw.Src)
+	return dirty, nil
+}
+
+// trackBounds 按渲染时的视口和组变换计算文档中每个元素的设备边界 / Compute every element's device bounds using the viewport and group transforms rendering uses
+func trackBounds(doc *types.Document, width, height int) map[types.Element]image.Rectangle {
+	viewBox, scaleX, scaleY := documentView(doc, width, height)
+	bounds := make(map[types.Element]image.Rectangle)
+	trackElementBounds(doc.Elements, viewBox, scaleX, scaleY, image.Rect(0, 0, width, height), bounds)
+	return bounds
+}
+
+// trackElementBounds 递归记录元素的设备边界，组的边界为子元素边界的并集 / Recursively record element device bounds; a group's bounds are the union of its children's
+func trackElementBounds(list []types.Element, viewBox []float64, scaleX, scaleY float64, canvas image.Rectangle, out map[types.Element]image.Rectangle) image.Rectangle {
+	var union image.Rectangle
+	for _, element := range list {
+		attrs := styledAttributes(element)
+		var rect image.Rectangle
+		switch {
+		case strings.TrimSpace(attrs["filter"]) != "" || hasMarkers(attrs):
+			rect = canvas
+		case element.Tag() == "g":
+			childViewBox, childScaleX, childScaleY := groupView(element, viewBox, scaleX, scaleY)
+			rect = trackElementBounds(element.Children(), childViewBox, childScaleX, childScaleY, canvas, out)
+		default:
+			rect = canvas
+			if _, transformed := attrs["transform"]; !transformed {
+				if b, ok := elements.Bounds(element); ok {
+					margin := paintMargin(attrs, scaleX, scaleY)
+					b = b.Inset(-margin)
+					rect = image.Rect(
+						int(math.Floor((b.X-viewBox[0])*scaleX)), int(math.Floor((b.Y-viewBox[1])*scaleY)),
+						int(math.Ceil((b.X+b.W-viewBox[0])*scaleX)), int(math.Ceil((b.Y+b.H-viewBox[1])*scaleY)),
+					).Intersect(canvas)
+				}
+			}
+		}
+		out[element] = rect
+		union = union.Union(rect)
+	}
+	return union
+}
+
+// hasMarkers 判断元素是否引用了标记，标记可绘制到几何边界之外任意远处 / Report whether the element references markers, which may paint arbitrarily far past the geometry bounds
+func hasMarkers(attrs map[string]string) bool {
+	for _, name := range []string{"marker-start", "marker-mid", "marker-end"} {
+		if _, ok := urlReference(attrs[name]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// paintMargin 返回描边、斜接和抗锯齿可能超出几何边界的用户单位距离 / Return the user-space distance strokes, miters and anti-aliasing may reach past the geometry bounds
+func paintMargin(attrs map[string]string, scaleX, scaleY float64) float64 {
+	margin := 0.0
+	if strings.TrimSpace(attrs["stroke"]) != "" && strings.TrimSpace(attrs["stroke"]) != "none" {
+		width, _ := parseFloat(attrs["stroke-width"], 1)
+		if nonScalingStroke(attrs) {
+			width /= math.Min(scaleX, scaleY)
+		}
+		miterLimit, _ := parseFloat(attrs["stroke-miterlimit"], 4)
+		margin = width / 2 * math.Max(1, miterLimit)
+	}
+	// 两个设备像素的抗锯齿余量 / Two device pixels of anti-aliasing slack
+	return margin + 2/math.Min(scaleX, scaleY)
+}
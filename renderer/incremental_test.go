@@ -0,0 +1,124 @@
+package renderer
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hoonfeng/svg/parser"
+	"github.com/hoonfeng/svg/types"
+)
+
+// TestRenderIncremental 测试移动小圆后只重绘受影响区域且结果与完整渲染一致 / Test moving a small circle repaints only the affected region and matches a full render
+func TestRenderIncremental(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="400" height="400" viewBox="0 0 400 400">
+		<rect x="290" y="100" width="60" height="20" fill="#0000ff" stroke="#00ff00" stroke-width="4"/>
+		<g transform="translate(10,10)">
+			<circle id="dot" cx="50" cy="50" r="6" fill="#ff0000" stroke="#000000" stroke-width="2"/>
+		</g>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+
+	if _, err := NewImageRenderer().RenderIncremental(NewImage(400, 400), nil); err == nil {
+		t.Error("expected an error before a tracked full render")
+	}
+
+	// 第二种尺寸的缩放为0.725倍 / The second size scales by 0.725
+	for _, size := range []int{400, 290} {
+		dot := doc.FindElementByID("dot")
+		dot.SetAttribute("cx", "50")
+		dot.SetAttribute("cy", "50")
+		scale := float64(size) / 400
+
+		r := NewImageRenderer()
+		r.TrackBounds = true
+		frame, err := r.Render(doc, size, size)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		before := image.NewRGBA(frame.Bounds())
+		copy(before.Pix, frame.Pix)
+
+		dot.SetAttribute("cx", "300")
+		dot.SetAttribute("cy", "120")
+		dirty, err := r.RenderIncremental(frame, []types.Element{dot})
+		if err != nil {
+			t.Fatalf("RenderIncremental failed: %v", err)
+		}
+		// 脏区为新旧位置的并集，远小于画布 / The dirty region is the union of the old and new positions, far smaller than the canvas
+		if !image.Pt(int(60*scale), int(60*scale)).In(dirty) || !image.Pt(int(310*scale), int(130*scale)).In(dirty) {
+			t.Errorf("size %d: expected the dirty region %v to cover both circle positions", size, dirty)
+		}
+		if area := dirty.Dx() * dirty.Dy(); area > size*size/4 {
+			t.Errorf("size %d: expected a small dirty region, got %v", size, dirty)
+		}
+
+		full, err := NewImageRenderer().Render(doc, size, size)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if equal, count, _ := CompareImages(frame, full, 0); !equal {
+			t.Errorf("size %d: expected the incremental frame to match a full render, %d pixels differ", size, count)
+		}
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				if !image.Pt(x, y).In(dirty) && frame.RGBAAt(x, y) != before.RGBAAt(x, y) {
+					t.Fatalf("size %d: pixel (%d,%d) outside the dirty region %v changed", size, x, y, dirty)
+				}
+			}
+		}
+	}
+}
+
+// TestRenderIncrementalUnitLengths 测试脏区按单位换算元素坐标 / Test the dirty region resolves element coordinates with units
+func TestRenderIncrementalUnitLengths(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200" viewBox="0 0 200 200">
+		<rect id="box" x="0" y="0" width="0" height="0" fill="#ff0000"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	box := doc.FindElementByID("box")
+	for name, value := range map[string]string{"x": "1in", "y": "1in", "width": "0.25in", "height": "0.25in"} {
+		box.SetAttribute(name, value)
+	}
+	r := NewImageRenderer()
+	r.TrackBounds = true
+	frame, err := r.Render(doc, 200, 200)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	box.SetAttribute("x", "0.5in")
+	dirty, err := r.RenderIncremental(frame, []types.Element{box})
+	if err != nil {
+		t.Fatalf("RenderIncremental failed: %v", err)
+	}
+	// 旧位置在(96,96)，新位置在(48,96) / The old position is at (96,96) and the new one at (48,96)
+	if !image.Pt(100, 100).In(dirty) || !image.Pt(52, 100).In(dirty) {
+		t.Errorf("expected the dirty region %v to cover both positions", dirty)
+	}
+	full, err := NewImageRenderer().Render(doc, 200, 200)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if equal, count, _ := CompareImages(frame, full, 0); !equal {
+		t.Errorf("expected the incremental frame to match a full render, %d pixels differ", count)
+	}
+}
+
+// TestTrackBoundsMarkers 测试带标记的元素按整个画布跟踪 / Test elements with markers are tracked as the whole canvas
+func TestTrackBoundsMarkers(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+		<defs><marker id="m" markerWidth="10" markerHeight="10"><rect width="10" height="10"/></marker></defs>
+		<line id="l" x1="10" y1="10" x2="20" y2="10" stroke="#000000" marker-end="url(#m)"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	bounds := trackBounds(doc, 100, 100)
+	if got := bounds[doc.FindElementByID("l")]; got != image.Rect(0, 0, 100, 100) {
+		t.Errorf("expected the whole canvas, got %v", got)
+	}
+}
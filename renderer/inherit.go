@@ -0,0 +1,84 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// inheritedProperties 按CSS规则自动继承的表现属性 / Presentation properties that inherit automatically under CSS rules
+var inheritedProperties = map[string]bool{
+	"clip-rule": true, "color": true, "cursor": true, "direction": true, "dominant-baseline": true,
+	"fill": true, "fill-opacity": true, "fill-rule": true,
+	"font-family": true, "font-size": true, "font-style": true, "font-variant": true, "font-weight": true,
+	"letter-spacing": true, "paint-order": true, "shape-rendering": true,
+	"stroke": true, "stroke-dasharray": true, "stroke-dashoffset": true, "stroke-linecap": true,
+	"stroke-linejoin": true, "stroke-miterlimit": true, "stroke-opacity": true, "stroke-width": true,
+	"text-anchor": true, "text-rendering": true, "visibility": true, "word-spacing": true, "writing-mode": true,
+}
+
+// inheritedElement 以解析过inherit关键字的属性替代原元素的属性 / Stands in for an element with its inherit keywords resolved
+type inheritedElement struct {
+	types.Element
+	attrs map[string]string
+}
+
+// GetAttributes 返回解析后的属性，style声明已合并 / Return the resolved attributes, with style declarations already merged
+func (e *inheritedElement) GetAttributes() map[string]string {
+	return e.attrs
+}
+
+// GetAttribute 获取解析后的属性 / Get a resolved attribute
+func (e *inheritedElement) GetAttribute(name string, defaultValue ...string) (string, bool) {
+	value, ok := e.attrs[name]
+	if !ok && len(defaultValue) > 0 {
+		return defaultValue[0], true
+	}
+	return value, ok
+}
+
+// GetContent 返回原元素的文本内容 / Return the original element's text content
+func (e *inheritedElement) GetContent() string {
+	if content, ok := e.Element.(interface{ GetContent() string }); ok {
+		return content.GetContent()
+	}
+	return ""
+}
+
+// resolveInherit 将值为inherit的属性替换为父元素的计算值，父元素没有该值时使用初始值，返回可能替换后的元素和其属性
+// resolveInherit replaces attributes whose value is inherit with the parent's computed value, falling back to the initial value when the parent has none; it returns the possibly substituted element and its attributes
+func resolveInherit(element types.Element, parent map[string]string) (types.Element, map[string]string) {
+	attrs := styledAttributes(element)
+	inherits := false
+	for name, value := range attrs {
+		if strings.TrimSpace(value) != "inherit" {
+			continue
+		}
+		inherits = true
+		if parentValue, ok := parent[name]; ok {
+			attrs[name] = parentValue
+		} else {
+			delete(attrs, name)
+		}
+	}
+	if !inherits {
+		return element, attrs
+	}
+	delete(attrs, "style")
+	return &inheritedElement{Element: element, attrs: attrs}, attrs
+}
+
+// computedStyle 返回子元素看到的父元素计算值：可继承属性取自身值或向上继承的值，其余属性只取自身值
+// computedStyle returns the parent values a child sees: inherited properties take the element's own or further inherited value, other properties only its own
+func computedStyle(attrs, parent map[string]string) map[string]string {
+	style := make(map[string]string, len(attrs))
+	for name, value := range parent {
+		if inheritedProperties[name] {
+			style[name] = value
+		}
+	}
+	for name, value := range attrs {
+		style[name] = value
+	}
+	return style
+}
@@ -0,0 +1,168 @@
+package renderer
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/hoonfeng/svg/parser"
+)
+
+// TestLineStroke 测试粗虚线的宽度、虚线间隔和线帽 / Test the width, dashes and caps of a thick dashed line
+func TestLineStroke(t *testing.T) {
+	render := func(lineCap string) func(x, y int) bool {
+		doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="60" viewBox="0 0 200 60">
+			<line x1="20" y1="30" x2="180" y2="30" stroke="#000000" stroke-width="10" stroke-dasharray="20 20" stroke-linecap="` + lineCap + `"/>
+		</svg>`)
+		if err != nil {
+			t.Fatalf("ParseString failed: %v", err)
+		}
+		img, err := NewImageRenderer().Render(doc, 200, 60)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return func(x, y int) bool { return img.RGBAAt(x, y).A > 128 }
+	}
+
+	painted := render("round")
+	for _, check := range []struct {
+		x, y int
+		want bool
+		what string
+	}{
+		{30, 26, true, "inside the stroke width above the center line"},
+		{30, 33, true, "inside the stroke width below the center line"},
+		{30, 22, false, "outside the stroke width"},
+		{30, 38, false, "outside the stroke width"},
+		{70, 30, true, "the second dash"},
+		{50, 30, false, "the gap between the first dashes"},
+		{170, 30, false, "the gap after the last dash"},
+		{17, 30, true, "the round cap before the first dash"},
+		{16, 25, false, "the corner a square cap would fill"},
+	} {
+		if got := painted(check.x, check.y); got != check.want {
+			t.Errorf("pixel (%d,%d), %s: expected painted=%v", check.x, check.y, check.what, check.want)
+		}
+	}
+
+	if butt := render("butt"); butt(17, 30) {
+		t.Error("expected a butt cap to end at the dash end")
+	}
+	if square := render("square"); !square(16, 25) {
+		t.Error("expected a square cap to fill the corner before the first dash")
+	}
+}
+
+// TestLineMarkers 测试线段末端的标记按线段方向旋转 / Test a marker at a line's end is rotated to the line's direction
+func TestLineMarkers(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+		<defs>
+			<marker id="arrow" viewBox="0 0 10 10" refX="0" refY="5" markerWidth="4" markerHeight="4" orient="auto">
+				<path d="M 0 0 L 10 5 L 0 10 Z" fill="#ff0000"/>
+			</marker>
+		</defs>
+		<line x1="50" y1="10" x2="50" y2="50" stroke="#000000" stroke-width="2" marker-end="url(#arrow)"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 100, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	red := func(x, y int) bool {
+		c := img.RGBAAt(x, y)
+		return c.R > 200 && c.G < 60 && c.A > 200
+	}
+	// 箭头按strokeWidth缩放为8个单位，沿线段方向向下 / The arrow scales by strokeWidth to 8 units and points down the line
+	if !red(50, 53) || !red(48, 51) {
+		t.Error("expected the arrow below the line's end")
+	}
+	if red(55, 50) || red(50, 60) {
+		t.Error("expected the arrow to be rotated to the line's direction and sized by the stroke width")
+	}
+	if c := img.RGBAAt(50, 30); c.R > 60 || c.A < 200 {
+		t.Errorf("expected the black line stroke above the arrow, got %v", c)
+	}
+}
+
+// TestShapeMarkers 测试路径、折线和多边形在各顶点绘制标记 / Test paths, polylines and polygons draw markers at their vertices
+func TestShapeMarkers(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+		<defs>
+			<marker id="dot" markerUnits="userSpaceOnUse" markerWidth="6" markerHeight="6" refX="3" refY="3">
+				<rect width="6" height="6" fill="#ff0000"/>
+			</marker>
+		</defs>
+		<path d="M 10 10 L 40 10 Q 40 25 25 25" fill="none" stroke="#000000" marker-start="url(#dot)" marker-mid="url(#dot)" marker-end="url(#dot)"/>
+		<polyline points="10 50 40 50 40 70" fill="none" stroke="#000000" marker-mid="url(#dot)"/>
+		<polygon points="60 60 90 60 90 90" fill="none" stroke="#000000" marker-start="url(#dot)" marker-end="url(#dot)"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 100, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	red := func(x, y int) bool {
+		c := img.RGBAAt(x, y)
+		return c.R > 200 && c.G < 60 && c.A > 200
+	}
+	for _, p := range [][2]int{{10, 10}, {40, 10}, {25, 25}, {40, 50}, {60, 60}} {
+		if !red(p[0], p[1]) {
+			t.Errorf("expected a marker at (%d,%d)", p[0], p[1])
+		}
+	}
+	// 折线只设置了marker-mid，端点没有标记 / The polyline only sets marker-mid, so its ends carry no marker
+	if red(10, 50) || red(40, 70) {
+		t.Error("expected no markers at the polyline's ends")
+	}
+}
+
+// TestHairlineCap 测试细线遵循stroke-linecap / Test thin lines follow stroke-linecap
+func TestHairlineCap(t *testing.T) {
+	endAlpha := func(lineCap string) uint8 {
+		doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20" viewBox="0 0 40 20">
+			<line x1="10" y1="10" x2="30" y2="10" stroke="#000000" stroke-linecap="` + lineCap + `"/>
+		</svg>`)
+		if err != nil {
+			t.Fatalf("ParseString failed: %v", err)
+		}
+		img, err := NewImageRenderer().Render(doc, 40, 20)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return img.RGBAAt(30, 10).A
+	}
+	if butt, square := endAlpha("butt"), endAlpha("square"); butt >= square {
+		t.Errorf("expected a square cap to cover more of the end pixel than a butt cap, got %d and %d", square, butt)
+	}
+}
+
+// TestPolygonFillRule 测试多边形按fill-rule填充自相交区域 / Test polygons fill self-intersecting areas by fill-rule
+func TestPolygonFillRule(t *testing.T) {
+	center := func(rule string) color.RGBA {
+		// 五角星的中心被环绕两次 / The center of the pentagram is wound twice
+		doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+			<polygon points="50,5 79,95 2,40 98,40 21,95" fill="#0000ff" stroke="none" fill-rule="` + rule + `"/>
+		</svg>`)
+		if err != nil {
+			t.Fatalf("ParseString failed: %v", err)
+		}
+		img, err := NewImageRenderer().Render(doc, 100, 100)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if got := img.RGBAAt(50, 20); got != (color.RGBA{0, 0, 255, 255}) {
+			t.Errorf("%s: expected a point of the star to be filled, got %v", rule, got)
+		}
+		return img.RGBAAt(50, 55)
+	}
+	if got := center("nonzero"); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected nonzero to fill the center, got %v", got)
+	}
+	if got := center("evenodd"); got.A != 0 {
+		t.Errorf("expected evenodd to leave the center empty, got %v", got)
+	}
+}
@@ -0,0 +1,232 @@
+package renderer
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// lookupMarker 按ID查找文档中的marker元素 / Look up a marker element in the document by ID
+func (r *ImageRenderer) lookupMarker(id string) types.Element {
+	if r.doc == nil {
+		return nil
+	}
+	element := findDefinition(r.doc.Defs, id)
+	if element == nil {
+		element = r.doc.FindElementByID(id)
+	}
+	if element == nil || element.Tag() != "marker" {
+		return nil
+	}
+	return element
+}
+
+// markerVertex 可放置标记的顶点及其进出方向，方向为用户空间向量，零向量表示没有该方向
+// markerVertex is a vertex that can carry a marker, with its incoming and outgoing directions as user-space vectors; a zero vector means there is none
+type markerVertex struct {
+	at      types.Point
+	in, out types.Point
+}
+
+// polylineVertices 返回折线或多边形的标记顶点，闭合时终点回到起点 / Return the marker vertices of a polyline or polygon, ending back at the start when closed
+func polylineVertices(points []types.Point, closed bool) []markerVertex {
+	if len(points) < 2 {
+		return nil
+	}
+	if closed {
+		points = append(append([]types.Point{}, points...), points[0])
+	}
+	vertices := make([]markerVertex, len(points))
+	for i, p := range points {
+		vertices[i].at = p
+		if i > 0 {
+			vertices[i].in = types.Point{X: p.X - points[i-1].X, Y: p.Y - points[i-1].Y}
+		}
+		if i < len(points)-1 {
+			vertices[i].out = types.Point{X: points[i+1].X - p.X, Y: points[i+1].Y - p.Y}
+		}
+	}
+	if closed {
+		// 闭合子路径的起点方向平分闭合边和第一条边 / A closed subpath's start bisects the closing edge and the first edge
+		vertices[0].in = vertices[len(vertices)-1].in
+		vertices[len(vertices)-1].out = vertices[0].out
+	}
+	return vertices
+}
+
+// pathVertices 返回路径每条命令端点处的标记顶点，曲线的进出方向取其端点处的切线
+// pathVertices returns the marker vertices at the end point of every path command, taking the tangents at a curve's ends as its directions
+func pathVertices(parsed *path.SVGPath) []markerVertex {
+	ctx := path.NewPathContext()
+	var vertices []markerVertex
+	subpathStart := -1
+	for _, cmd := range parsed.Commands {
+		start, flattened := ctx.CurrentPoint, len(ctx.Points)
+		cmd.Execute(ctx, 0.1)
+
+		if _, move := cmd.(*path.MoveToCommand); move {
+			vertices = append(vertices, markerVertex{at: ctx.CurrentPoint})
+			subpathStart = len(vertices) - 1
+			continue
+		}
+		if subpathStart < 0 {
+			continue
+		}
+		_, closePath := cmd.(*path.ClosePathCommand)
+
+		// 绘制命令的折线，首尾非零线段给出进出方向 / The command's polyline, whose first and last non-zero edges give the directions
+		polyline := append([]types.Point{start}, ctx.Points[flattened:]...)
+		var first, last types.Point
+		for i := 1; i < len(polyline); i++ {
+			d := types.Point{X: polyline[i].X - polyline[i-1].X, Y: polyline[i].Y - polyline[i-1].Y}
+			if d == (types.Point{}) {
+				continue
+			}
+			if first == (types.Point{}) {
+				first = d
+			}
+			last = d
+		}
+		previous := &vertices[len(vertices)-1]
+		if previous.out == (types.Point{}) {
+			previous.out = first
+		}
+		if last == (types.Point{}) {
+			// 零长度的闭合边仍在起点放置顶点 / A zero-length closing edge still places a vertex at the start
+			if !closePath {
+				continue
+			}
+			last = previous.in
+		}
+		vertices = append(vertices, markerVertex{at: ctx.CurrentPoint, in: last})
+
+		if closePath {
+			closing := &vertices[len(vertices)-1]
+			closing.out = vertices[subpathStart].out
+			vertices[subpathStart].in = closing.in
+		}
+	}
+	return vertices
+}
+
+// renderMarkers 在顶点处绘制marker-start、marker-mid和marker-end引用的标记
+// renderMarkers draws the markers referenced by marker-start, marker-mid and marker-end at the vertices
+//
+// 自动方向取进出方向的平分线，只有一个方向时取该方向 / The automatic orientation bisects the incoming and outgoing directions, or follows the one there is
+func (r *ImageRenderer) renderMarkers(img *image.RGBA, attrs map[string]string, vertices []markerVertex, viewBox []float64, scaleX, scaleY float64) error {
+	if len(vertices) < 2 || !hasMarkers(attrs) {
+		return nil
+	}
+	// 设备空间中的方向角 / Direction angles in device space
+	direction := func(v types.Point) float64 {
+		return math.Atan2(v.Y*scaleY, v.X*scaleX)
+	}
+	orientation := func(v markerVertex) float64 {
+		switch {
+		case v.in == (types.Point{}) && v.out == (types.Point{}):
+			return 0
+		case v.in == (types.Point{}):
+			return direction(v.out)
+		case v.out == (types.Point{}):
+			return direction(v.in)
+		}
+		in, out := direction(v.in), direction(v.out)
+		// 取两方向夹角的一半，按最短转向计算 / Take half the turn between the directions, along the shorter way round
+		return in + math.Remainder(out-in, 2*math.Pi)/2
+	}
+	last := len(vertices) - 1
+	strokeWidth := r.getStrokeWidth(attrs)
+
+	draw := func(property string, v markerVertex, start bool) error {
+		id, ok := urlReference(attrs[property])
+		if !ok {
+			return nil
+		}
+		marker := r.lookupMarker(id)
+		if marker == nil {
+			return nil
+		}
+		return r.drawMarker(img, marker, v.at, orientation(v), start, strokeWidth, viewBox, scaleX, scaleY)
+	}
+
+	if err := draw("marker-start", vertices[0], true); err != nil {
+		return err
+	}
+	for i := 1; i < last; i++ {
+		if err := draw("marker-mid", vertices[i], false); err != nil {
+			return err
+		}
+	}
+	return draw("marker-end", vertices[last], false)
+}
+
+// drawMarker 将标记内容离屏渲染，再以refX/refY为锚点旋转放置到at处
+// drawMarker renders the marker's content offscreen, then rotates it and places its refX/refY anchor at at
+//
+// 支持markerWidth、markerHeight、markerUnits、viewBox（等比缩放）和orient，标记视口外的内容被裁剪
+// markerWidth, markerHeight, markerUnits, viewBox (uniformly scaled) and orient are supported; content outside the marker viewport is clipped
+func (r *ImageRenderer) drawMarker(img *image.RGBA, marker types.Element, at types.Point, angle float64, start bool, strokeWidth float64, viewBox []float64, scaleX, scaleY float64) error {
+	attrs := marker.GetAttributes()
+	width, _ := parseFloat(attrs["markerWidth"], 3)
+	height, _ := parseFloat(attrs["markerHeight"], 3)
+	refX, _ := parseFloat(attrs["refX"], 0)
+	refY, _ := parseFloat(attrs["refY"], 0)
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	// markerUnits默认为strokeWidth，即标记坐标按描边宽度缩放 / markerUnits defaults to strokeWidth, scaling the marker's coordinates by the stroke width
+	units := strokeWidth
+	if strings.TrimSpace(attrs["markerUnits"]) == "userSpaceOnUse" {
+		units = 1
+	}
+	markerX, markerY := scaleX*units, scaleY*units
+
+	// 标记内容的视口和设备缩放 / The marker content's viewport and device scale
+	contentView := []float64{0, 0, width, height}
+	if value, ok := attrs["viewBox"]; ok {
+		contentView = parseViewBox(value)
+		fit := math.Min(width/(contentView[2]-contentView[0]), height/(contentView[3]-contentView[1]))
+		markerX, markerY = markerX*fit, markerY*fit
+	}
+	layer := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(width*units*scaleX)), int(math.Ceil(height*units*scaleY))))
+	if layer.Bounds().Empty() {
+		return nil
+	}
+	childViewBox := []float64{
+		contentView[0], contentView[1],
+		contentView[0] + float64(layer.Bounds().Dx())/markerX, contentView[1] + float64(layer.Bounds().Dy())/markerY,
+	}
+
+	// 标记内容不继承引用元素的样式 / Marker content does not inherit from the referencing element
+	previousStyle := r.parentStyle
+	r.parentStyle = nil
+	defer func() { r.parentStyle = previousStyle }()
+	for _, child := range marker.Children() {
+		if err := r.renderElement(layer, child, childViewBox, markerX, markerY); err != nil {
+			return err
+		}
+	}
+
+	switch orient := strings.TrimSpace(attrs["orient"]); orient {
+	case "auto":
+	case "auto-start-reverse":
+		if start {
+			angle += math.Pi
+		}
+	default:
+		degrees, _ := strconv.ParseFloat(strings.TrimSuffix(orient, "deg"), 64)
+		angle = degrees * math.Pi / 180
+	}
+
+	// 旋转后的标记先绘制到整幅图层，再经绘制目标合成 / The rotated marker is drawn onto a full-size layer and then composited through the draw target
+	placed := image.NewRGBA(img.Bounds())
+	r.drawRotated(placed, layer, (refX-contentView[0])*markerX, (refY-contentView[1])*markerY,
+		(at.X-viewBox[0])*scaleX, (at.Y-viewBox[1])*scaleY, angle, sampleStraight)
+	r.target(img).DrawImage(placed, placed.Bounds())
+	return nil
+}
@@ -0,0 +1,346 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/font"
+	"github.com/hoonfeng/svg/types"
+)
+
+// Paint 表示填充或描边使用的绘制源 / Paint is the source used to fill or stroke a shape
+//
+// x、y为用户空间坐标，bounds为元素在用户空间的边界框
+// x and y are user-space coordinates, bounds is the element's user-space bounding box
+type Paint interface {
+	ColorAt(x, y float64, bounds types.Rect) color.RGBA
+}
+
+// SolidPaint 纯色绘制源 / Solid color paint
+type SolidPaint struct {
+	Color color.RGBA
+}
+
+// ColorAt 返回固定颜色 / Return the constant color
+func (p SolidPaint) ColorAt(x, y float64, bounds types.Rect) color.RGBA {
+	return p.Color
+}
+
+// GradientPaint 渐变绘制源 / Gradient paint
+type GradientPaint struct {
+	Gradient *attributes.Gradient
+	// UserSpace 为true时渐变坐标使用用户空间(userSpaceOnUse)，否则使用边界框比例(objectBoundingBox)
+	// UserSpace selects userSpaceOnUse coordinates; otherwise objectBoundingBox fractions are used
+	UserSpace bool
+}
+
+// NewGradientPaint 创建渐变绘制源 / Create a gradient paint
+func NewGradientPaint(g *attributes.Gradient) *GradientPaint {
+	userSpace := false
+	if g != nil && g.Attrs != nil {
+		userSpace = g.Attrs["gradientUnits"] == "userSpaceOnUse"
+	}
+	return &GradientPaint{Gradient: g, UserSpace: userSpace}
+}
+
+// ColorAt 计算渐变在指定点的颜色 / Compute the gradient color at a point
+func (p *GradientPaint) ColorAt(x, y float64, bounds types.Rect) color.RGBA {
+	if p.Gradient == nil {
+		return color.RGBA{}
+	}
+	if p.UserSpace {
+		return p.Gradient.ColorAtPoint(x, y)
+	}
+
+	w := bounds.W
+	h := bounds.H
+	if w <= 0 || h <= 0 {
+		// 退化的边界框无法映射，SVG规定此时不绘制 / A degenerate bbox cannot be mapped and is not painted
+		return color.RGBA{}
+	}
+	return p.Gradient.ColorAtPoint((x-bounds.X)/w, (y-bounds.Y)/h)
+}
+
+// RegisterPaint 注册可通过url(#id)引用的自定义绘制源 / Register a custom paint referenced by url(#id)
+func (r *ImageRenderer) RegisterPaint(id string, paint Paint) {
+	if r.paints == nil {
+		r.paints = make(map[string]Paint)
+	}
+	r.paints[id] = paint
+}
+
+// resolvePaint 将fill/stroke属性值解析为绘制源，"none"或空值返回nil
+// resolvePaint resolves a fill/stroke value to a Paint, returning nil for "none" or empty values
+func (r *ImageRenderer) resolvePaint(value string) Paint {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "none" {
+		return nil
+	}
+
+	if strings.HasPrefix(value, "url(") {
+		end := strings.Index(value, ")")
+		if end < 0 {
+			return nil
+		}
+		id := strings.TrimPrefix(strings.Trim(strings.TrimSpace(value[4:end]), `'"`), "#")
+		if paint := r.lookupPaint(id); paint != nil {
+			return paint
+		}
+		// 引用无效时使用回退颜色 / Use the fallback color when the reference is invalid
+		value = strings.TrimSpace(value[end+1:])
+	}
+
+	// 无法解析的颜色视为未指定，不绘制 / A color that cannot be parsed is treated as unspecified and paints nothing
+	c, ok := r.resolveColor(value)
+	if !ok {
+		return nil
+	}
+	return SolidPaint{Color: c}
+}
+
+// lookupPaint 按ID查找已注册的绘制源或文档中的渐变 / Look up a registered paint or a document gradient by ID
+func (r *ImageRenderer) lookupPaint(id string) Paint {
+	if paint, ok := r.paints[id]; ok {
+		return paint
+	}
+	if r.doc == nil {
+		return nil
+	}
+
+	element := findDefinition(r.doc.Defs, id)
+	if element == nil {
+		element = r.doc.FindElementByID(id)
+	}
+	if element == nil {
+		return nil
+	}
+
+	if gradient := gradientFromElement(element, r.currentColor); gradient != nil {
+		return NewGradientPaint(gradient)
+	}
+	return nil
+}
+
+// findDefinition 在定义区域中递归查找元素 / Recursively find an element among definitions
+func findDefinition(elements []types.Element, id string) types.Element {
+	for _, element := range elements {
+		if element.ID() == id {
+			return element
+		}
+		if found := findDefinition(element.Children(), id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// gradientFromElement 由linearGradient/radialGradient元素构建渐变（包括锥形扩展），currentColor为stop-color=currentColor的回退颜色
+// gradientFromElement builds a gradient from a linearGradient/radialGradient element, including the conic extension; currentColor is the fallback for stop-color="currentColor"
+func gradientFromElement(element types.Element, currentColor string) *attributes.Gradient {
+	var gradType string
+	switch element.Tag() {
+	case "linearGradient":
+		gradType = "linear"
+	case "radialGradient":
+		gradType = "radial"
+		// 带扩展标记的radialGradient按锥形渐变渲染 / A radialGradient carrying the extension marker renders as a conic gradient
+		if element.GetAttributes()["data-svg-extension"] == attributes.ConicExtension {
+			gradType = "conic"
+		}
+	default:
+		return nil
+	}
+
+	gradient := &attributes.Gradient{
+		ID:       element.ID(),
+		GradType: gradType,
+		Attrs:    make(map[string]string),
+	}
+	for name, value := range element.GetAttributes() {
+		gradient.Attrs[name] = value
+	}
+	// color-interpolation也可以在style中设置 / color-interpolation may also be set in style
+	if value := styledAttributes(element)["color-interpolation"]; value != "" {
+		gradient.Attrs["color-interpolation"] = strings.TrimSpace(value)
+	}
+
+	for _, child := range element.Children() {
+		if child.Tag() != "stop" {
+			continue
+		}
+		attrs := styledAttributes(child)
+
+		offset := parseStopOffset(attrs["offset"])
+		stopColor := attrs["stop-color"]
+		if strings.TrimSpace(stopColor) == "currentColor" {
+			// 优先使用stop或渐变元素自身的color / Prefer the color set on the stop or the gradient element itself
+			stopColor = currentColor
+			if value := strings.TrimSpace(styledAttributes(element)["color"]); value != "" && value != "currentColor" {
+				stopColor = value
+			}
+			if value := strings.TrimSpace(attrs["color"]); value != "" && value != "currentColor" {
+				stopColor = value
+			}
+		}
+		opacity, err := strconv.ParseFloat(strings.TrimSpace(attrs["stop-opacity"]), 64)
+		if err != nil {
+			opacity = 1
+		}
+		gradient.AddStop(offset, parseColor(strings.TrimSpace(stopColor), color.RGBA{0, 0, 0, 255}), opacity)
+	}
+
+	return gradient
+}
+
+// styledAttributes 合并元素的表现属性和style声明，style优先 / Merge an element's presentation attributes with its style declarations, style taking precedence
+func styledAttributes(element types.Element) map[string]string {
+	attrs := make(map[string]string)
+	for name, value := range element.GetAttributes() {
+		attrs[name] = value
+	}
+	for _, decl := range strings.Split(attrs["style"], ";") {
+		parts := strings.SplitN(decl, ":", 2)
+		if len(parts) == 2 {
+			attrs[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return attrs
+}
+
+// parseStopOffset 解析stop偏移，支持数字和百分比 / Parse a stop offset as a number or percentage
+func parseStopOffset(s string) float64 {
+	s = strings.TrimSpace(s)
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSuffix(s, "%")
+		scale = 0.01
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return math.Max(0, math.Min(1, v*scale))
+}
+
+// paintShape 使用绘制源绘制形状 / Draw a shape with a paint
+//
+// 纯色在正常混合下直接绘制；其他情况先将形状绘制为覆盖率遮罩，再逐像素着色
+// Solid paints with normal blending draw directly; otherwise the shape is first drawn as a coverage mask and each pixel is then shaded
+func (r *ImageRenderer) paintShape(img *image.RGBA, paint Paint, bounds types.Rect, viewBox []float64, scaleX, scaleY float64, draw func(dst *image.RGBA, c color.RGBA) error) error {
+	if paint == nil {
+		return nil
+	}
+	// 自定义目标无法逐像素着色，以边界框中心的颜色近似 / Custom targets cannot shade per pixel, so approximate with the color at the bounding box center
+	if r.custom != nil {
+		c := paint.ColorAt(bounds.X+bounds.W/2, bounds.Y+bounds.H/2, bounds)
+		if c.A == 0 {
+			return nil
+		}
+		return draw(img, c)
+	}
+	if solid, ok := paint.(SolidPaint); ok {
+		if solid.Color == (color.RGBA{0, 0, 0, 0}) {
+			return nil
+		}
+		// 非正常混合模式需逐像素读取背景 / Non-normal blend modes need the backdrop per pixel
+		if r.BlendMode == BlendNormal {
+			return draw(img, solid.Color)
+		}
+	}
+
+	// 遮罩本身使用正常混合绘制 / The mask itself is drawn with normal blending
+	mode := r.BlendMode
+	r.BlendMode = BlendNormal
+	mask := image.NewRGBA(img.Bounds())
+	err := draw(mask, color.RGBA{255, 255, 255, 255})
+	r.BlendMode = mode
+	if err != nil {
+		return err
+	}
+
+	rect := img.Bounds()
+	for py := rect.Min.Y; py < rect.Max.Y; py++ {
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			coverage := mask.RGBAAt(px, py).A
+			if coverage == 0 {
+				continue
+			}
+
+			// 像素中心转换回用户空间 / Map the pixel center back to user space
+			ux := (float64(px)+0.5)/scaleX + viewBox[0]
+			uy := (float64(py)+0.5)/scaleY + viewBox[1]
+			c := paint.ColorAt(ux, uy, bounds)
+			if c.A == 0 {
+				continue
+			}
+
+			alpha := float64(coverage) / 255 * float64(c.A) / 255
+			bg := img.RGBAAt(px, py)
+			fg := color.RGBA{c.R, c.G, c.B, 255}
+			if bg.A == 0 {
+				img.SetRGBA(px, py, color.RGBA{c.R, c.G, c.B, uint8(alpha*255 + 0.5)})
+				continue
+			}
+			img.SetRGBA(px, py, r.compositeColors(bg, fg, alpha))
+		}
+	}
+
+	return nil
+}
+
+// solidPaintColor 返回纯色绘制源的颜色，nil视为透明纯色 / Return the color of a solid paint, treating nil as transparent
+func solidPaintColor(paint Paint) (color.RGBA, bool) {
+	if paint == nil {
+		return color.RGBA{0, 0, 0, 0}, true
+	}
+	solid, ok := paint.(SolidPaint)
+	return solid.Color, ok
+}
+
+// textBounds 估算文本在用户空间的边界框 / Estimate the user-space bounding box of text
+func textBounds(x, y float64, metrics *font.FontMetrics, anchor font.TextAnchor, scaleX, scaleY float64) types.Rect {
+	width := metrics.Advance / scaleX
+	switch anchor {
+	case font.TextAnchorMiddle:
+		x -= width / 2
+	case font.TextAnchorEnd:
+		x -= width
+	}
+	return types.Rect{
+		X: x,
+		Y: y - metrics.Ascent/scaleY,
+		W: width,
+		H: (metrics.Ascent + metrics.Descent) / scaleY,
+	}
+}
+
+// paintImage 将绘制源适配为设备空间的image.Image / Adapt a paint to a device-space image.Image
+type paintImage struct {
+	paint          Paint
+	bounds         types.Rect
+	viewBox        []float64
+	scaleX, scaleY float64
+}
+
+// ColorModel 返回颜色模型 / Return the color model
+func (p *paintImage) ColorModel() color.Model {
+	return color.NRGBAModel
+}
+
+// Bounds 返回无限大的边界 / Return unbounded bounds
+func (p *paintImage) Bounds() image.Rectangle {
+	return image.Rectangle{Min: image.Point{X: -1e9, Y: -1e9}, Max: image.Point{X: 1e9, Y: 1e9}}
+}
+
+// At 返回设备像素处的颜色 / Return the color at a device pixel
+func (p *paintImage) At(x, y int) color.Color {
+	ux := (float64(x)+0.5)/p.scaleX + p.viewBox[0]
+	uy := (float64(y)+0.5)/p.scaleY + p.viewBox[1]
+	c := p.paint.ColorAt(ux, uy, p.bounds)
+	// Paint返回非预乘颜色 / Paints return straight-alpha colors
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
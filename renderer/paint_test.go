@@ -0,0 +1,164 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/hoonfeng/svg/api"
+	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/parser"
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// checkerPaint 测试用棋盘格绘制源 / Checkerboard paint used in tests
+type checkerPaint struct {
+	size   float64
+	c1, c2 color.RGBA
+}
+
+// ColorAt 按格子奇偶返回颜色 / Return a color by cell parity
+func (p checkerPaint) ColorAt(x, y float64, bounds types.Rect) color.RGBA {
+	cx := int(math.Floor((x - bounds.X) / p.size))
+	cy := int(math.Floor((y - bounds.Y) / p.size))
+	if (cx+cy)%2 == 0 {
+		return p.c1
+	}
+	return p.c2
+}
+
+// TestCustomPaint 测试注册自定义棋盘格绘制源并填充矩形 / Test registering a custom checkerboard paint and filling a rect
+func TestCustomPaint(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	doc := types.NewDocument(40, 40)
+	doc.SetViewBox(0, 0, 40, 40)
+	rect := elements.NewRect(0, 0, 40, 40)
+	rect.SetAttribute("fill", "url(#checker)")
+	doc.AppendElement(rect)
+
+	r := NewImageRenderer()
+	r.RegisterPaint("checker", checkerPaint{size: 10, c1: red, c2: blue})
+
+	img, err := r.Render(doc, 40, 40)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	for cy := 0; cy < 4; cy++ {
+		for cx := 0; cx < 4; cx++ {
+			want := red
+			if (cx+cy)%2 == 1 {
+				want = blue
+			}
+			if got := img.RGBAAt(cx*10+5, cy*10+5); got != want {
+				t.Errorf("cell (%d,%d): got %v, want %v", cx, cy, got, want)
+			}
+		}
+	}
+}
+
+// TestGradientPaintFromDocument 测试解析文档中的线性渐变引用 / Test resolving a linear gradient referenced from the document
+func TestGradientPaintFromDocument(t *testing.T) {
+	const content = `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="10" viewBox="0 0 100 10">
+	<defs>
+		<linearGradient id="fade">
+			<stop offset="0%" stop-color="#000000"/>
+			<stop offset="100%" stop-color="#ffffff"/>
+		</linearGradient>
+	</defs>
+	<rect x="0" y="0" width="100" height="10" fill="url(#fade)"/>
+</svg>`
+
+	doc, err := parser.NewXMLParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	img, err := NewImageRenderer().Render(doc, 100, 10)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	left := img.RGBAAt(5, 5)
+	mid := img.RGBAAt(50, 5)
+	right := img.RGBAAt(95, 5)
+	if !(left.R < mid.R && mid.R < right.R) {
+		t.Errorf("expected increasing brightness, got %d, %d, %d", left.R, mid.R, right.R)
+	}
+	if mid.R < 118 || mid.R > 138 {
+		t.Errorf("expected mid-gray near the center, got %d", mid.R)
+	}
+
+	// linearRGB插值使中点更亮 / linearRGB interpolation brightens the midpoint
+	linear, err := parser.NewXMLParser().ParseString(strings.Replace(content, `<linearGradient id="fade">`, `<linearGradient id="fade" color-interpolation="linearRGB">`, 1))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	img, err = NewImageRenderer().Render(linear, 100, 10)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := img.RGBAAt(50, 5).R; got < 180 || got > 195 {
+		t.Errorf("expected a linearRGB midpoint near 188, got %d", got)
+	}
+}
+
+// TestConicGradient 测试锥形渐变按角度着色，且序列化为带扩展标记的radialGradient
+// TestConicGradient tests that a conic gradient colors by angle and serializes as a marked radialGradient
+func TestConicGradient(t *testing.T) {
+	builder := api.NewSVGBuilder(100, 100)
+	paint := builder.AddConicGradient(50, 50,
+		api.GradientStop{Offset: 0, Color: color.RGBA{255, 0, 0, 255}},
+		api.GradientStop{Offset: 0.5, Color: color.RGBA{0, 0, 255, 255}},
+		api.GradientStop{Offset: 1, Color: color.RGBA{255, 0, 0, 255}},
+	)
+	builder.AddCircle(50, 50, 40).FillPaint(paint)
+	doc := builder.GetDocument()
+
+	img, err := NewImageRenderer().Render(doc, 100, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	// 右侧角度为0，左侧角度为半圈 / The right side is at angle 0, the left side half a turn away
+	right := img.RGBAAt(85, 50)
+	left := img.RGBAAt(15, 50)
+	if right.R < 200 || right.B > 55 {
+		t.Errorf("expected red on the right, got %v", right)
+	}
+	if left.B < 200 || left.R > 55 {
+		t.Errorf("expected blue on the left, got %v", left)
+	}
+	// 上下两侧位于四分之一圈处，颜色相同 / Top and bottom sit a quarter turn away and share a color
+	if top, bottom := img.RGBAAt(50, 15), img.RGBAAt(50, 85); top != bottom {
+		t.Errorf("expected matching colors above and below the center, got %v and %v", top, bottom)
+	}
+
+	xml := doc.ToXML()
+	if !strings.Contains(xml, "<radialGradient") || !strings.Contains(xml, `data-svg-extension="conic"`) {
+		t.Errorf("expected a marked radialGradient approximation, got %s", xml)
+	}
+}
+
+// TestConicGradientPrecision 测试锥形渐变按path.Precision格式化数值 / Test conic gradients format numbers per path.Precision
+func TestConicGradientPrecision(t *testing.T) {
+	defer func(old int) { path.Precision = old }(path.Precision)
+	path.Precision = 2
+
+	builder := api.NewSVGBuilder(100, 100)
+	builder.AddConicGradient(100.0/3, 50, api.GradientStop{Offset: 1.0 / 3, Color: color.RGBA{255, 0, 0, 255}})
+	gradient := builder.GetDocument().Defs[0]
+	if got := gradient.GetAttributes()["cx"]; got != "33.33" {
+		t.Errorf("expected cx rounded to 33.33, got %q", got)
+	}
+	if got := gradient.GetAttributes()["r"]; got != "70.71" {
+		t.Errorf("expected r rounded to 70.71, got %q", got)
+	}
+	if got := gradient.Children()[0].GetAttributes()["offset"]; got != "0.33" {
+		t.Errorf("expected the stop offset rounded to 0.33, got %q", got)
+	}
+}
@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hoonfeng/svg/parser"
+	"github.com/hoonfeng/svg/types"
+)
+
+// rectFillDocument 返回含若干填充矩形的文档，general为true时矩形带恒等变换以走通用填充路径
+// rectFillDocument returns a document of filled rects; with general true the rects carry an identity transform so they take the general fill path
+func rectFillDocument(tb testing.TB, size int, general bool) *types.Document {
+	tb.Helper()
+	transform := ""
+	if general {
+		transform = ` transform="translate(0,0)"`
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 100 100">`, size, size)
+	for _, rect := range []string{
+		`x="0" y="0" width="100" height="100" fill="#336699"`,
+		`x="10.3" y="12.7" width="40.2" height="30.9" fill="#ff0000"`,
+		`x="30" y="30" width="50" height="50" fill="#0f08" stroke="#000000"`,
+		`x="-20" y="90" width="200" height="30" fill="#ff08"`,
+		`x="60" y="5" width="0" height="50" fill="#000000"`,
+	} {
+		fmt.Fprintf(&sb, `<rect %s%s/>`, rect, transform)
+	}
+	sb.WriteString(`</svg>`)
+	doc, err := parser.NewXMLParser().ParseString(sb.String())
+	if err != nil {
+		tb.Fatalf("ParseString failed: %v", err)
+	}
+	return doc
+}
+
+// TestFastRectFill 测试矩形快速填充与通用填充路径的输出完全相同 / Test the fast rect fill produces exactly the general fill path's output
+func TestFastRectFill(t *testing.T) {
+	for _, linear := range []bool{false, true} {
+		fast := NewImageRenderer()
+		fast.LinearBlending = linear
+		got, err := fast.Render(rectFillDocument(t, 173, false), 173, 173)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		general := NewImageRenderer()
+		general.LinearBlending = linear
+		want, err := general.Render(rectFillDocument(t, 173, true), 173, 173)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if equal, count, _ := CompareImages(got, want, 0); !equal {
+			t.Errorf("linear=%v: expected identical output, %d pixels differ", linear, count)
+		}
+	}
+}
+
+// BenchmarkRectFill 比较矩形快速填充与通用填充路径 / Compare the fast rect fill with the general fill path
+func BenchmarkRectFill(b *testing.B) {
+	for _, bench := range []struct {
+		name    string
+		general bool
+	}{{"fast", false}, {"general", true}} {
+		b.Run(bench.name, func(b *testing.B) {
+			doc := rectFillDocument(b, 512, bench.general)
+			r := NewImageRenderer()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := r.Render(doc, 512, 512); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
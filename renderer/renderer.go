@@ -7,13 +7,54 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/elements"
 	"github.com/hoonfeng/svg/font"
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/types"
 )
 
 // ImageRenderer 表示SVG到图像的渲染器
 type ImageRenderer struct {
+	// LinearBlending 在线性光空间中进行Alpha混合 / Perform alpha compositing in linear-light space
+	LinearBlending bool
+	// CrispEdges 禁用抗锯齿，使用扫描线填充和Bresenham直线 / Disable anti-aliasing and use scanline fills and Bresenham lines
+	CrispEdges bool
+	// Flatness 曲线展平容差（设备像素），0表示按曲线自适应 / Curve flattening tolerance in device pixels, 0 adapts per curve
+	Flatness float64
+	// BlendMode 绘制时与背景的混合模式，元素的mix-blend-mode会临时覆盖它 / Blend mode against the backdrop; an element's mix-blend-mode overrides it while rendering
+	BlendMode BlendMode
+	// StrictMode 遇到不支持的元素时返回错误，默认跳过并记录警告 / Fail on unsupported elements instead of skipping them and recording a warning
+	StrictMode bool
+	// TrackBounds 完整渲染时记录每个元素的设备边界，供RenderIncremental使用 / Record every element's device bounds on full renders for RenderIncremental
+	TrackBounds bool
+	// ImageDir image元素可读取本地文件的目录，相对路径相对于它；为空时不读取文件，只接受data URI
+	// ImageDir is the directory image elements may read local files from, relative paths being relative to it; when empty no files are read and only data URIs are accepted
+	ImageDir string
+
+	// paints 通过url(#id)引用的自定义绘制源 / Custom paints referenced by url(#id)
+	paints map[string]Paint
+	// doc 当前渲染的文档，用于解析渐变引用 / Document being rendered, used to resolve gradient references
+	doc *types.Document
+	// hidden 继承的visibility为hidden，子元素可用visible覆盖 / Inherited visibility is hidden; descendants may override it with visible
+	hidden bool
+	// currentColor 继承的color属性值，空表示黑色 / Inherited value of the color property; empty means black
+	currentColor string
+	// warnings 最近一次渲染中跳过的元素说明 / Notes about elements skipped during the latest render
+	warnings []string
+	// textRenderer 用于文本的渲染器，首次使用时创建 / Renderer used for text, created on first use
+	textRenderer font.TextRenderer
+	// custom RenderToTarget期间使用的自定义绘图后端 / Custom drawing backend used during RenderToTarget
+	custom DrawTarget
+	// parentStyle 正在渲染的元素的父元素计算值，用于解析inherit关键字 / Computed values of the parent of the element being rendered, used to resolve the inherit keyword
+	parentStyle map[string]string
+	// tracked 启用TrackBounds时最近一次完整渲染的文档、画布和元素设备边界 / Document, canvas and element device bounds of the latest full render with TrackBounds
+	tracked *trackedFrame
 }
 
 // NewImageRenderer 创建新的图像渲染器
@@ -21,6 +62,89 @@ func NewImageRenderer() *ImageRenderer {
 	return &ImageRenderer{}
 }
 
+// Warnings 返回最近一次渲染中被跳过的不支持元素 / Return the unsupported elements skipped during the latest render
+func (r *ImageRenderer) Warnings() []string {
+	return r.warnings
+}
+
+// SetTextRenderer 设置渲染文本所用的文本渲染器，例如配置了自定义字体路径的实例，nil恢复使用font.DefaultTextRenderer
+// SetTextRenderer sets the text renderer used for text, e.g. one configured with custom font paths; nil goes back to font.DefaultTextRenderer
+func (r *ImageRenderer) SetTextRenderer(textRenderer font.TextRenderer) {
+	r.textRenderer = textRenderer
+}
+
+// text 返回渲染器的文本渲染器，未设置时使用共享的font.DefaultTextRenderer，其字体和度量缓存在渲染之间保留
+// text returns the renderer's text renderer, using the shared font.DefaultTextRenderer when unset so its font and measurement caches survive between renders
+func (r *ImageRenderer) text() font.TextRenderer {
+	if r.textRenderer != nil {
+		return r.textRenderer
+	}
+	if font.DefaultTextRenderer == nil {
+		// 默认实例被清空时创建渲染器自己的实例 / Create the renderer's own instance when the default has been cleared
+		r.textRenderer = font.NewSVGTextRenderer()
+		return r.textRenderer
+	}
+	return font.DefaultTextRenderer
+}
+
+// SetLinearBlending 设置是否使用伽马校正的线性光混合 / Set whether to use gamma-correct linear-light blending
+func (r *ImageRenderer) SetLinearBlending(enabled bool) {
+	r.LinearBlending = enabled
+}
+
+// SetAntiAliasing 设置是否启用抗锯齿，禁用后输出像素完全不透明 / Set whether anti-aliasing is enabled; when disabled painted pixels are fully opaque
+func (r *ImageRenderer) SetAntiAliasing(enabled bool) {
+	r.CrispEdges = !enabled
+}
+
+// SetFlatness 设置曲线展平容差（设备像素），越小越平滑，0恢复自适应 / Set the curve flattening tolerance in device pixels; smaller is smoother, 0 restores adaptive flattening
+func (r *ImageRenderer) SetFlatness(flatness float64) {
+	r.Flatness = math.Max(0, flatness)
+}
+
+// pathParseOptions 将设备像素容差换算为用户单位的解析选项 / Convert the device-pixel tolerance into user-space parse options
+func (r *ImageRenderer) pathParseOptions(scaleX, scaleY float64) path.ParseOptions {
+	if r == nil || r.Flatness <= 0 {
+		return path.ParseOptions{}
+	}
+	return path.ParseOptions{Flatness: r.Flatness / math.Max(scaleX, scaleY)}
+}
+
+// compositeColors 按渲染器混合模式混合两种颜色，alpha为覆盖率 / Blend two colors using the renderer's compositing mode, alpha being the coverage
+//
+// 半透明的前景色按非预乘source-over合成，其透明度与覆盖率相乘 / A translucent foreground composites source-over in straight alpha, its alpha multiplied by the coverage
+func (r *ImageRenderer) compositeColors(bg, fg color.RGBA, alpha float64) color.RGBA {
+	if r != nil && r.BlendMode != BlendNormal {
+		fg = applyBlendMode(r.BlendMode, bg, fg)
+	}
+	linear := r != nil && r.LinearBlending
+	if fg.A < 255 {
+		return sourceOver(bg, fg, alpha, linear)
+	}
+	if linear {
+		return blendColorsLinear(bg, fg, alpha)
+	}
+	return blendColors(bg, fg, alpha)
+}
+
+// sourceOver 将非预乘的前景色按覆盖率source-over合成到背景上 / Composite a straight-alpha foreground over the background, scaled by coverage
+func sourceOver(bg, fg color.RGBA, coverage float64, linear bool) color.RGBA {
+	srcA := math.Max(0, math.Min(1, coverage)) * float64(fg.A) / 255
+	if srcA <= 0 {
+		return bg
+	}
+	dstA := float64(bg.A) / 255
+	outA := srcA + dstA*(1-srcA)
+	// 结果颜色中前景所占的比例 / The foreground's share of the resulting color
+	share := srcA / outA
+	return color.RGBA{
+		R: blendChannel(bg.R, fg.R, share, linear),
+		G: blendChannel(bg.G, fg.G, share, linear),
+		B: blendChannel(bg.B, fg.B, share, linear),
+		A: uint8(outA*255 + 0.5),
+	}
+}
+
 // NewImage 创建新的图像（为了兼容测试）
 func NewImage(width, height int) *image.RGBA {
 	return CreateImage(width, height, color.RGBA{0, 0, 0, 0})
@@ -43,27 +167,253 @@ func RenderDocument(doc *types.Document, width, height int) (*image.RGBA, error)
 func (r *ImageRenderer) Render(doc *types.Document, width, height int) (*image.RGBA, error) {
 	// 创建图像，使用透明背景 / Create image with transparent background
 	img := CreateImage(width, height, color.RGBA{0, 0, 0, 0})
+	if err := r.RenderInto(img, doc); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
 
-	// 解析视口
-	viewBox := parseViewBox(doc.ViewBox)
+// RenderInto 将SVG文档渲染到已有图像上，以图像边界作为视口，不清除原有内容
+// RenderInto renders the document onto an existing image using its bounds as the viewport, without clearing it
+func (r *ImageRenderer) RenderInto(img *image.RGBA, doc *types.Document) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid render target size: %dx%d", width, height)
+	}
+
+	// 绘制函数以(0,0)为原点，子图像需共享像素并平移坐标 / Drawing assumes a (0,0) origin, so sub-images share pixels with a translated rect
+	if bounds.Min != (image.Point{}) {
+		img = &image.RGBA{Pix: img.Pix, Stride: img.Stride, Rect: image.Rect(0, 0, width, height)}
+	}
+
+	// 解析视口并计算缩放比例 / Parse the viewport and compute the scale
+	viewBox, scaleX, scaleY := documentView(doc, width, height)
 
-	// 计算缩放比例
+	if err := r.renderView(img, doc, viewBox, scaleX, scaleY); err != nil {
+		return err
+	}
+	if r.TrackBounds {
+		r.tracked = &trackedFrame{doc: doc, size: image.Pt(width, height), bounds: trackBounds(doc, width, height)}
+	}
+	return nil
+}
+
+// RenderRegion 只渲染文档按fullWidth×fullHeight渲染时region范围内的像素，返回region大小的图像
+// RenderRegion renders only the pixels inside region of the document as it would be rendered at fullWidth×fullHeight, returning an image the size of region
+//
+// 绘制时的设备坐标与完整渲染相同，因而任意缩放下像素一致；不与区域相交的元素被跳过 / Drawing uses the same device coordinates as the full render, so pixels match at any scale; elements outside the region are skipped
+func (r *ImageRenderer) RenderRegion(doc *types.Document, fullWidth, fullHeight int, region image.Rectangle) (*image.RGBA, error) {
+	if fullWidth <= 0 || fullHeight <= 0 {
+		return nil, fmt.Errorf("invalid render size: %dx%d", fullWidth, fullHeight)
+	}
+	region = region.Intersect(image.Rect(0, 0, fullWidth, fullHeight))
+	if region.Empty() {
+		return nil, fmt.Errorf("render region does not overlap the %dx%d canvas", fullWidth, fullHeight)
+	}
+
+	viewBox, scaleX, scaleY := documentView(doc, fullWidth, fullHeight)
+
+	// 图像边界设为区域本身，设备坐标与完整渲染一致，缩放后才偏移 / The image's bounds are the region itself, so device coordinates match the full render and the offset applies after scaling
+	img := CreateImage(region.Dx(), region.Dy(), color.RGBA{0, 0, 0, 0})
+	img.Rect = region
+	if err := r.renderView(img, doc, viewBox, scaleX, scaleY); err != nil {
+		return nil, err
+	}
+	// 像素布局不变，只将原点移回左上角 / The pixel layout is unchanged; only the origin moves back to the top-left
+	img.Rect = image.Rect(0, 0, region.Dx(), region.Dy())
+	return img, nil
+}
+
+// documentView 返回将文档映射到width×height画布的视口和缩放
+// documentView returns the viewport and scale that map the document onto a width×height canvas
+//
+// 根元素设置了preserveAspectRatio时按其等比缩放并对齐，否则视图框拉伸填满画布
+// With preserveAspectRatio set on the root the viewBox scales uniformly and aligns accordingly; otherwise it stretches to fill the canvas
+func documentView(doc *types.Document, width, height int) ([]float64, float64, float64) {
+	viewBox := parseViewBox(doc.ViewBox)
 	scaleX := float64(width) / (viewBox[2] - viewBox[0])
 	scaleY := float64(height) / (viewBox[3] - viewBox[1])
 
+	value, ok := doc.Attributes["preserveAspectRatio"]
+	if !ok {
+		return viewBox, scaleX, scaleY
+	}
+	ratio := ParseAspectRatio(value)
+	if ratio.None {
+		return viewBox, scaleX, scaleY
+	}
+	scale := math.Min(scaleX, scaleY)
+	if ratio.Slice {
+		scale = math.Max(scaleX, scaleY)
+	}
+	// 对齐留下的空白换算为视口原点的偏移 / Convert the alignment slack into an offset of the viewport origin
+	offsetX := (float64(width) - (viewBox[2]-viewBox[0])*scale) * ratio.AlignX / scale
+	offsetY := (float64(height) - (viewBox[3]-viewBox[1])*scale) * ratio.AlignY / scale
+	minX, minY := viewBox[0]-offsetX, viewBox[1]-offsetY
+	return []float64{minX, minY, minX + float64(width)/scale, minY + float64(height)/scale}, scale, scale
+}
+
+// renderView 以给定视口和缩放渲染文档的元素，img的原点对应视口左上角
+// renderView renders the document's elements with the given viewport and scale; img's origin maps to the viewport's top-left
+func (r *ImageRenderer) renderView(img *image.RGBA, doc *types.Document, viewBox []float64, scaleX, scaleY float64) error {
+	// 记录文档以解析url(#id)引用 / Keep the document to resolve url(#id) references
+	r.doc = doc
+	defer func() { r.doc = nil }()
+	r.warnings = nil
+
+	// 视口在用户空间中的范围，用于跳过不可见元素 / The viewport in user space, used to skip invisible elements
+	bounds := img.Bounds()
+	viewport := types.Rect{
+		X: viewBox[0] + float64(bounds.Min.X)/scaleX, Y: viewBox[1] + float64(bounds.Min.Y)/scaleY,
+		W: float64(bounds.Dx()) / scaleX, H: float64(bounds.Dy()) / scaleY,
+	}
+
 	// 渲染元素
 	for _, element := range doc.Elements {
+		if !elementVisible(element, viewport, scaleX, scaleY) {
+			continue
+		}
 		err := r.renderElement(img, element, viewBox, scaleX, scaleY)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return img, nil
+	return nil
+}
+
+// elementVisible 判断元素是否可能与视口相交，无法确定边界时视为可见
+// elementVisible reports whether an element may intersect the viewport; elements with unknown bounds are treated as visible
+func elementVisible(element types.Element, viewport types.Rect, scaleX, scaleY float64) bool {
+	bounds, ok := cullBounds(element)
+	if !ok {
+		return true
+	}
+	// 按一个设备像素的抗锯齿余量扩展 / Grow by one device pixel of anti-aliasing
+	margin := 1 / math.Min(scaleX, scaleY)
+	return viewport.Intersects(bounds.Inset(-margin))
+}
+
+// cullBounds 计算元素及其子树在父坐标系中含描边的边界，计入各层的transform；子树中有无法确定边界的元素时返回false
+// cullBounds computes the stroked bounds of an element and its subtree in the parent's coordinate system, honoring transforms at every level; returns false when any element of the subtree has unknown bounds
+func cullBounds(element types.Element) (types.Rect, bool) {
+	// 标记和滤镜绘制到几何边界之外 / Markers and filters paint outside the geometry bounds
+	attrs := styledAttributes(element)
+	for _, name := range []string{"marker-start", "marker-mid", "marker-end", "filter"} {
+		if value := strings.TrimSpace(attrs[name]); value != "" && value != "none" {
+			return types.Rect{}, false
+		}
+	}
+
+	var bounds types.Rect
+	switch element.Tag() {
+	case "g":
+		// 手动累计最小最大值，零宽或零高的子元素也计入 / Accumulate min/max directly so zero-width or zero-height children count too
+		found := false
+		var minX, minY, maxX, maxY float64
+		for _, child := range element.Children() {
+			b, ok := cullBounds(child)
+			if !ok {
+				return types.Rect{}, false
+			}
+			if !found {
+				minX, minY, maxX, maxY, found = b.X, b.Y, b.MaxX(), b.MaxY(), true
+				continue
+			}
+			minX, minY = math.Min(minX, b.X), math.Min(minY, b.Y)
+			maxX, maxY = math.Max(maxX, b.MaxX()), math.Max(maxY, b.MaxY())
+		}
+		if !found {
+			return types.Rect{}, false
+		}
+		bounds = types.Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+	case "use", "svg":
+		// use引用别处的内容，嵌套svg建立新视口，都不做剔除 / use draws content from elsewhere and a nested svg sets up a new viewport, so neither is culled
+		return types.Rect{}, false
+	default:
+		b, ok := elements.Bounds(element)
+		if !ok {
+			return types.Rect{}, false
+		}
+		bounds = b
+	}
+
+	// 按描边宽度扩展 / Grow by the stroke width
+	if width, err := strconv.ParseFloat(strings.TrimSpace(attrs["stroke-width"]), 64); err == nil {
+		bounds = bounds.Inset(-math.Max(1, width))
+	} else {
+		bounds = bounds.Inset(-1)
+	}
+
+	value := strings.TrimSpace(element.GetAttributes()["transform"])
+	if value == "" {
+		return bounds, true
+	}
+	m := attributes.ParseTransform(value).GetMatrix()
+	corners := []types.Point{
+		{X: bounds.X, Y: bounds.Y}, {X: bounds.MaxX(), Y: bounds.Y},
+		{X: bounds.MaxX(), Y: bounds.MaxY()}, {X: bounds.X, Y: bounds.MaxY()},
+	}
+	for i, c := range corners {
+		corners[i] = types.Point{X: m.A*c.X + m.C*c.Y + m.E, Y: m.B*c.X + m.D*c.Y + m.F}
+	}
+	return types.RectFromPoints(corners), true
 }
 
 // renderElement 渲染单个SVG元素
 func (r *ImageRenderer) renderElement(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
+	// inherit关键字取父元素的计算值，子元素再以本元素的计算值为父值 / inherit takes the parent's computed value, and children see this element's computed values in turn
+	element, attrs := resolveInherit(element, r.parentStyle)
+	previousStyle := r.parentStyle
+	r.parentStyle = computedStyle(attrs, previousStyle)
+	defer func() { r.parentStyle = previousStyle }()
+
+	// display:none跳过整个子树 / display:none skips the whole subtree
+	if strings.TrimSpace(attrs["display"]) == "none" {
+		return nil
+	}
+	// visibility可继承，隐藏的元素不绘制但仍处理子元素 / visibility inherits; hidden elements are not painted but their children are still processed
+	switch strings.TrimSpace(attrs["visibility"]) {
+	case "hidden", "collapse":
+		previous := r.hidden
+		r.hidden = true
+		defer func() { r.hidden = previous }()
+	case "visible":
+		previous := r.hidden
+		r.hidden = false
+		defer func() { r.hidden = previous }()
+	}
+
+	// color可继承，供currentColor引用 / color inherits and is what currentColor refers to
+	if value := strings.TrimSpace(attrs["color"]); value != "" && value != "currentColor" && value != "inherit" {
+		previous := r.currentColor
+		r.currentColor = value
+		defer func() { r.currentColor = previous }()
+	}
+
+	// mix-blend-mode作用于元素及其子元素 / mix-blend-mode applies to the element and its descendants
+	if mode, ok := ParseBlendMode(attrs["mix-blend-mode"]); ok {
+		previous := r.BlendMode
+		r.BlendMode = mode
+		defer func() { r.BlendMode = previous }()
+	}
+
+	// 引用了滤镜的元素先离屏渲染再应用滤镜，自定义目标不支持滤镜 / Elements referencing a filter render offscreen and are then filtered; custom targets do not support filters
+	if id, ok := urlReference(attrs["filter"]); ok && r.custom == nil {
+		if filter := r.lookupFilter(id); filter != nil {
+			return r.renderFiltered(img, element, filter, viewBox, scaleX, scaleY)
+		}
+	}
+
+	return r.renderElementContent(img, element, viewBox, scaleX, scaleY)
+}
+
+// renderElementContent 按标签渲染元素本身，不处理滤镜 / Render the element itself by tag, without filters
+func (r *ImageRenderer) renderElementContent(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
+	if r.hidden && element.Tag() != "g" {
+		return nil
+	}
 	switch element.Tag() {
 	case "rect":
 		return r.renderRect(img, element, viewBox, scaleX, scaleY)
@@ -81,18 +431,104 @@ func (r *ImageRenderer) renderElement(img *image.RGBA, element types.Element, vi
 		return r.renderPath(img, element, viewBox, scaleX, scaleY)
 	case "text":
 		return r.renderText(img, element, viewBox, scaleX, scaleY)
-	case "g":
-		// 组元素的渲染需要解析内容中的子元素
-		// 简化实现，暂不支持组元素
+	case "image":
+		return r.renderImage(img, element, viewBox, scaleX, scaleY)
+	case "textPath":
+		return r.renderTextPath(img, element, styledAttributes(element), viewBox, scaleX, scaleY)
+	case "defs", "linearGradient", "radialGradient", "filter", "marker":
+		// 定义元素仅通过url(#id)引用，不直接渲染 / Definitions are only referenced via url(#id) and not rendered directly
+		return nil
+	case "title", "desc", "metadata":
+		// 元数据不产生可见输出 / Metadata produces no visible output
 		return nil
+	case "g":
+		return r.renderGroup(img, element, viewBox, scaleX, scaleY)
 	default:
-		return fmt.Errorf("不支持的元素类型: %s", element.Tag())
+		if r.StrictMode {
+			return fmt.Errorf("不支持的元素类型: %s", element.Tag())
+		}
+		// 尽力渲染：跳过未知元素并记录 / Best-effort rendering: skip unknown elements and record them
+		r.warnings = append(r.warnings, fmt.Sprintf("unsupported element type: %s", element.Tag()))
+		return nil
+	}
+}
+
+// renderGroup 渲染组元素的子元素 / Render a group's children
+//
+// 目前仅支持平移和缩放变换，其他变换被忽略 / Only translate and scale transforms are supported for now; other transforms are ignored
+func (r *ImageRenderer) renderGroup(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
+	childViewBox, scaleX, scaleY := groupView(element, viewBox, scaleX, scaleY)
+	for _, child := range element.Children() {
+		if err := r.renderElement(img, child, childViewBox, scaleX, scaleY); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupView 返回组的变换作用后子元素使用的视口和缩放 / Return the viewport and scale a group's children use after its transform
+func groupView(element types.Element, viewBox []float64, scaleX, scaleY float64) ([]float64, float64, float64) {
+	transform := element.GetAttributes()["transform"]
+	if tx, ty, ok := parseTranslate(transform); ok {
+		// 平移内容等价于反向平移视口 / Translating the content is equivalent to translating the viewport the other way
+		return []float64{viewBox[0] - tx, viewBox[1] - ty, viewBox[2] - tx, viewBox[3] - ty}, scaleX, scaleY
+	}
+	if sx, sy, ok := parseScale(transform); ok && sx > 0 && sy > 0 {
+		// 缩放内容等价于按比例缩小视口并放大设备比例 / Scaling the content is equivalent to shrinking the viewport and growing the device scale by the same factor
+		return []float64{viewBox[0] / sx, viewBox[1] / sy, viewBox[2] / sx, viewBox[3] / sy}, scaleX * sx, scaleY * sy
 	}
+	return viewBox, scaleX, scaleY
+}
+
+// parseTranslate 解析translate(tx[,ty])变换 / Parse a translate(tx[,ty]) transform
+func parseTranslate(transform string) (tx, ty float64, ok bool) {
+	args, ok := parseTransformArgs(transform, "translate")
+	if !ok {
+		return 0, 0, false
+	}
+	if len(args) == 2 {
+		ty = args[1]
+	}
+	return args[0], ty, true
+}
+
+// parseScale 解析scale(sx[,sy])变换，省略sy时等于sx / Parse a scale(sx[,sy]) transform; an omitted sy equals sx
+func parseScale(transform string) (sx, sy float64, ok bool) {
+	args, ok := parseTransformArgs(transform, "scale")
+	if !ok {
+		return 0, 0, false
+	}
+	sy = args[0]
+	if len(args) == 2 {
+		sy = args[1]
+	}
+	return args[0], sy, true
+}
+
+// parseTransformArgs 解析单个name(a[,b])变换的一到两个参数 / Parse the one or two arguments of a single name(a[,b]) transform
+func parseTransformArgs(transform, name string) ([]float64, bool) {
+	transform = strings.TrimSpace(transform)
+	if !strings.HasPrefix(transform, name+"(") || !strings.HasSuffix(transform, ")") {
+		return nil, false
+	}
+	fields := strings.Fields(strings.ReplaceAll(transform[len(name)+1:len(transform)-1], ",", " "))
+	if len(fields) < 1 || len(fields) > 2 {
+		return nil, false
+	}
+	args := make([]float64, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, false
+		}
+		args[i] = v
+	}
+	return args, true
 }
 
 // renderRect 渲染矩形元素
 func (r *ImageRenderer) renderRect(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
 
 	// 解析属性
 	x, _ := parseFloat(attrs["x"], 0)
@@ -106,34 +542,40 @@ func (r *ImageRenderer) renderRect(img *image.RGBA, element types.Element, viewB
 	w := int(width * scaleX)
 	h := int(height * scaleY)
 
-	// 解析颜色
-	fillColor := parseColor(attrs["fill"], color.RGBA{0, 0, 0, 0})
-	strokeColor := parseColor(attrs["stroke"], color.RGBA{0, 0, 0, 255})
+	// 解析绘制源 / Resolve paints
+	fillPaint := r.resolvePaint(attrs["fill"])
+	strokePaint := r.resolvePaint(attrs["stroke"])
+	bounds := types.Rect{X: x, Y: y, W: width, H: height}
 
-	// 判断是填充还是描边 / Determine if fill or stroke
-	hasFill := attrs["fill"] != "none" && attrs["fill"] != ""
-	hasStroke := attrs["stroke"] != "none" && attrs["stroke"] != ""
-
-	// 绘制矩形
-	if hasFill && fillColor != (color.RGBA{0, 0, 0, 0}) {
-		DrawRect(img, x1, y1, w, h, fillColor, true)
+	// 如果既没有填充也没有描边，默认使用黑色填充 / Default to a black fill if neither fill nor stroke
+	if fillPaint == nil && strokePaint == nil && attrs["fill"] == "" {
+		fillPaint = SolidPaint{Color: color.RGBA{0, 0, 0, 255}}
 	}
 
-	if hasStroke && strokeColor != (color.RGBA{0, 0, 0, 0}) {
-		DrawRect(img, x1, y1, w, h, strokeColor, false)
+	// 绘制矩形
+	fill := func() error {
+		if solid, ok := fillPaint.(SolidPaint); ok && solid.Color.A == 255 && r.fastRectFill(attrs) {
+			r.fillDeviceRect(img, image.Rect(x1, y1, x1+w, y1+h), solid.Color)
+			return nil
+		}
+		return r.paintShape(img, fillPaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.target(dst).FillPolygon([][]types.Point{devicePolygon(x1, y1, w, h)}, c, path.FillRuleNonZero)
+			return nil
+		})
 	}
-
-	// 如果既没有填充也没有描边，默认使用填充 / Default to fill if neither fill nor stroke
-	if !hasFill && !hasStroke {
-		DrawRect(img, x1, y1, w, h, color.RGBA{0, 0, 0, 255}, true)
+	stroke := func() error {
+		corners := []types.Point{{X: x, Y: y}, {X: x + width, Y: y}, {X: x + width, Y: y + height}, {X: x, Y: y + height}}
+		return r.paintShape(img, strokePaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.strokeOutline(dst, corners, true, attrs, c, viewBox, scaleX, scaleY)
+			return nil
+		})
 	}
-
-	return nil
+	return paintInOrder(attrs, fill, stroke)
 }
 
 // renderCircle 渲染圆形元素
 func (r *ImageRenderer) renderCircle(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
 
 	// 解析属性
 	cx, _ := parseFloat(attrs["cx"], 0)
@@ -145,34 +587,35 @@ func (r *ImageRenderer) renderCircle(img *image.RGBA, element types.Element, vie
 	centerY := int((cy - viewBox[1]) * scaleY)
 	circleRadius := int(radius * ((scaleX + scaleY) / 2))
 
-	// 解析颜色
-	fillColor := parseColor(attrs["fill"], color.RGBA{0, 0, 0, 0})
-	strokeColor := parseColor(attrs["stroke"], color.RGBA{0, 0, 0, 255})
-
-	// 判断是填充还是描边 / Determine if fill or stroke
-	hasFill := attrs["fill"] != "none" && attrs["fill"] != ""
-	hasStroke := attrs["stroke"] != "none" && attrs["stroke"] != ""
+	// 解析绘制源 / Resolve paints
+	fillPaint := r.resolvePaint(attrs["fill"])
+	strokePaint := r.resolvePaint(attrs["stroke"])
+	bounds := types.Rect{X: cx - radius, Y: cy - radius, W: 2 * radius, H: 2 * radius}
 
-	// 绘制圆形
-	if hasFill && fillColor != (color.RGBA{0, 0, 0, 0}) {
-		DrawCircle(img, centerX, centerY, circleRadius, fillColor, true)
+	// 如果既没有填充也没有描边，默认使用黑色填充 / Default to a black fill if neither fill nor stroke
+	if fillPaint == nil && strokePaint == nil && attrs["fill"] == "" {
+		fillPaint = SolidPaint{Color: color.RGBA{0, 0, 0, 255}}
 	}
 
-	if hasStroke && strokeColor != (color.RGBA{0, 0, 0, 0}) {
-		DrawCircle(img, centerX, centerY, circleRadius, strokeColor, false)
+	// 绘制圆形
+	fill := func() error {
+		return r.paintShape(img, fillPaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.target(dst).FillPolygon([][]types.Point{r.ellipsePolygon(centerX, centerY, circleRadius, circleRadius)}, c, path.FillRuleNonZero)
+			return nil
+		})
 	}
-
-	// 如果既没有填充也没有描边，默认使用填充 / Default to fill if neither fill nor stroke
-	if !hasFill && !hasStroke {
-		DrawCircle(img, centerX, centerY, circleRadius, color.RGBA{0, 0, 0, 255}, true)
+	stroke := func() error {
+		return r.paintShape(img, strokePaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.target(dst).StrokePolyline(r.ellipsePolygon(centerX, centerY, circleRadius, circleRadius), true, c, hairline)
+			return nil
+		})
 	}
-
-	return nil
+	return paintInOrder(attrs, fill, stroke)
 }
 
 // renderEllipse 渲染椭圆元素
 func (r *ImageRenderer) renderEllipse(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
 
 	// 解析属性
 	cx, _ := parseFloat(attrs["cx"], 0)
@@ -186,34 +629,38 @@ func (r *ImageRenderer) renderEllipse(img *image.RGBA, element types.Element, vi
 	radiusX := int(rx * scaleX)
 	radiusY := int(ry * scaleY)
 
-	// 解析颜色
-	fillColor := parseColor(attrs["fill"], color.RGBA{0, 0, 0, 0})
-	strokeColor := parseColor(attrs["stroke"], color.RGBA{0, 0, 0, 255})
+	// 解析绘制源 / Resolve paints
+	fillPaint := r.resolvePaint(attrs["fill"])
+	strokePaint := r.resolvePaint(attrs["stroke"])
+	bounds := types.Rect{X: cx - rx, Y: cy - ry, W: 2 * rx, H: 2 * ry}
 
-	// 判断是填充还是描边 / Determine if fill or stroke
-	hasFill := attrs["fill"] != "none" && attrs["fill"] != ""
-	hasStroke := attrs["stroke"] != "none" && attrs["stroke"] != ""
-
-	// 绘制椭圆
-	if hasFill && fillColor != (color.RGBA{0, 0, 0, 0}) {
-		DrawEllipse(img, centerX, centerY, radiusX, radiusY, fillColor, true)
+	// 如果既没有填充也没有描边，默认使用黑色填充 / Default to a black fill if neither fill nor stroke
+	if fillPaint == nil && strokePaint == nil && attrs["fill"] == "" {
+		fillPaint = SolidPaint{Color: color.RGBA{0, 0, 0, 255}}
 	}
 
-	if hasStroke && strokeColor != (color.RGBA{0, 0, 0, 0}) {
-		DrawEllipse(img, centerX, centerY, radiusX, radiusY, strokeColor, false)
+	// 绘制椭圆
+	fill := func() error {
+		return r.paintShape(img, fillPaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.target(dst).FillPolygon([][]types.Point{r.ellipsePolygon(centerX, centerY, radiusX, radiusY)}, c, path.FillRuleNonZero)
+			return nil
+		})
 	}
-
-	// 如果既没有填充也没有描边，默认使用填充 / Default to fill if neither fill nor stroke
-	if !hasFill && !hasStroke {
-		DrawEllipse(img, centerX, centerY, radiusX, radiusY, color.RGBA{0, 0, 0, 255}, true)
+	stroke := func() error {
+		return r.paintShape(img, strokePaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.target(dst).StrokePolyline(r.ellipsePolygon(centerX, centerY, radiusX, radiusY), true, c, hairline)
+			return nil
+		})
 	}
-
-	return nil
+	return paintInOrder(attrs, fill, stroke)
 }
 
 // renderLine 渲染线段元素
+//
+// 细实线按像素中心绘制以保持清晰，粗线和虚线与路径共用描边流程，随后绘制标记
+// Thin solid lines snap to pixel centers to stay crisp; thick and dashed lines share the path stroke pipeline, and markers are drawn afterwards
 func (r *ImageRenderer) renderLine(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
 
 	// 解析属性
 	x1, _ := parseFloat(attrs["x1"], 0)
@@ -227,76 +674,276 @@ func (r *ImageRenderer) renderLine(img *image.RGBA, element types.Element, viewB
 	px2 := int((x2 - viewBox[0]) * scaleX)
 	py2 := int((y2 - viewBox[1]) * scaleY)
 
-	// 解析颜色
-	strokeColor := parseColor(attrs["stroke"], color.RGBA{0, 0, 0, 255})
-
-	// 绘制线段
-	DrawLine(img, px1, py1, px2, py2, strokeColor)
-
-	return nil
+	// 解析绘制源 / Resolve paint
+	strokePaint := r.getLineStrokePaint(attrs)
+	points := []types.Point{{X: x1, Y: y1}, {X: x2, Y: y2}}
+	bounds := types.RectFromPoints(points)
+
+	line := &path.SVGPath{Commands: []path.Command{&path.MoveToCommand{X: x1, Y: y1}, &path.LineToCommand{X: x2, Y: y2}}}
+	strokePath := r.dashedPath(line, attrs)
+	strokeWidth := r.getStrokeWidth(attrs) * strokeScale(attrs, scaleX, scaleY)
+	err := r.paintShape(img, strokePaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+		if strokeWidth <= 1 && strokePath == line {
+			r.target(dst).StrokePolyline([]types.Point{pixelCenter(px1, py1), pixelCenter(px2, py2)}, false, c, hairlineStyle(attrs))
+			return nil
+		}
+		if len(strokePath.Commands) == 0 {
+			return nil
+		}
+		// 路径绘制函数按min(scaleX, scaleY)缩放描边宽度 / The path drawing functions scale the stroke width by min(scaleX, scaleY)
+		r.drawPath(dst, strokePath, color.RGBA{}, c, strokeWidth/math.Min(scaleX, scaleY), parseLineCap(attrs["stroke-linecap"]), viewBox, scaleX, scaleY, path.FillRuleNonZero)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return r.renderMarkers(img, attrs, polylineVertices(points, false), viewBox, scaleX, scaleY)
 }
 
 // renderPolyline 渲染折线元素
 func (r *ImageRenderer) renderPolyline(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
 
 	// 解析属性
 	pointsStr := attrs["points"]
 	points := parsePoints(pointsStr)
 
-	// 解析颜色
-	strokeColor := parseColor(attrs["stroke"], color.RGBA{0, 0, 0, 255})
-
-	// 绘制折线
-	for i := 1; i < len(points); i++ {
-		x1 := int((points[i-1].X - viewBox[0]) * scaleX)
-		y1 := int((points[i-1].Y - viewBox[1]) * scaleY)
-		x2 := int((points[i].X - viewBox[0]) * scaleX)
-		y2 := int((points[i].Y - viewBox[1]) * scaleY)
+	// 解析绘制源 / Resolve paint
+	strokePaint := r.getLineStrokePaint(attrs)
 
-		DrawLine(img, x1, y1, x2, y2, strokeColor)
+	// 绘制折线，粗线按描边轮廓绘制 / Draw the polyline; thick lines are drawn as their stroke outline
+	thick := r.getStrokeWidth(attrs)*strokeScale(attrs, scaleX, scaleY) > 1
+	err := r.paintShape(img, strokePaint, types.RectFromPoints(points), viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+		if thick {
+			r.strokeOutline(dst, points, false, attrs, c, viewBox, scaleX, scaleY)
+			return nil
+		}
+		device := make([]types.Point, len(points))
+		for i, p := range points {
+			device[i] = pixelCenter(int((p.X-viewBox[0])*scaleX), int((p.Y-viewBox[1])*scaleY))
+		}
+		r.target(dst).StrokePolyline(device, false, c, hairlineStyle(attrs))
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return r.renderMarkers(img, attrs, polylineVertices(points, false), viewBox, scaleX, scaleY)
 }
 
 // renderPolygon 渲染多边形元素
 func (r *ImageRenderer) renderPolygon(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
 
 	// 解析属性
 	pointsStr := attrs["points"]
 	points := parsePoints(pointsStr)
 
-	// 解析颜色
-	strokeColor := parseColor(attrs["stroke"], color.RGBA{0, 0, 0, 255})
+	// 解析绘制源 / Resolve paint
+	fillPaint := r.getFillPaint(attrs)
+	strokePaint := r.getLineStrokePaint(attrs)
+	bounds := types.RectFromPoints(points)
 
-	// 绘制多边形
-	for i := 1; i < len(points); i++ {
-		x1 := int((points[i-1].X - viewBox[0]) * scaleX)
-		y1 := int((points[i-1].Y - viewBox[1]) * scaleY)
-		x2 := int((points[i].X - viewBox[0]) * scaleX)
-		y2 := int((points[i].Y - viewBox[1]) * scaleY)
+	// 按填充规则填充并描边多边形 / Fill the polygon under its fill rule and stroke it
+	fill := func() error {
+		if len(points) < 3 {
+			return nil
+		}
+		device := make([]types.Point, len(points))
+		for i, p := range points {
+			device[i] = types.Point{X: (p.X - viewBox[0]) * scaleX, Y: (p.Y - viewBox[1]) * scaleY}
+		}
+		return r.paintShape(img, fillPaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.target(dst).FillPolygon([][]types.Point{device}, c, path.ParseFillRule(attrs["fill-rule"]))
+			return nil
+		})
+	}
+	stroke := func() error {
+		return r.paintShape(img, strokePaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			r.strokeOutline(dst, points, true, attrs, c, viewBox, scaleX, scaleY)
+			return nil
+		})
+	}
+	if err := paintInOrder(attrs, fill, stroke); err != nil {
+		return err
+	}
+	return r.renderMarkers(img, attrs, polylineVertices(points, true), viewBox, scaleX, scaleY)
+}
+
+// strokeOutline 使用描边路径生成器描绘折线，连接方式取自stroke-linejoin和stroke-miterlimit
+// strokeOutline strokes a polyline through the stroke path generator, taking joins from stroke-linejoin and stroke-miterlimit
+func (r *ImageRenderer) strokeOutline(dst *image.RGBA, points []types.Point, closed bool, attrs map[string]string, c color.RGBA, viewBox []float64, scaleX, scaleY float64) {
+	if len(points) < 2 {
+		return
+	}
+	device := make([]types.Point, len(points))
+	for i, p := range points {
+		device[i] = types.Point{X: (p.X - viewBox[0]) * scaleX, Y: (p.Y - viewBox[1]) * scaleY}
+	}
+
+	miterLimit, _ := parseFloat(attrs["stroke-miterlimit"], 4)
+	r.target(dst).StrokePolyline(device, closed, c, StrokeStyle{
+		Width:      r.getStrokeWidth(attrs) * strokeScale(attrs, scaleX, scaleY),
+		Join:       parseLineJoin(attrs["stroke-linejoin"]),
+		MiterLimit: miterLimit,
+		Cap:        parseLineCap(attrs["stroke-linecap"]),
+	})
+}
+
+// hairline 细线元素和椭圆轮廓使用的1像素描边 / The 1 pixel stroke used by thin line elements and ellipse outlines
+var hairline = StrokeStyle{Width: 1, Join: JoinRound, Cap: CapRound}
+
+// hairlineStyle 返回细线元素的1像素描边，线帽取自stroke-linecap / Return the 1 pixel stroke of a thin line element, taking its cap from stroke-linecap
+func hairlineStyle(attrs map[string]string) StrokeStyle {
+	style := hairline
+	style.Cap = parseLineCap(attrs["stroke-linecap"])
+	return style
+}
+
+// pixelCenter 返回像素的中心，细线穿过像素中心以覆盖整像素 / Return the center of a pixel; hairlines run through pixel centers to cover whole pixels
+func pixelCenter(x, y int) types.Point {
+	return types.Point{X: float64(x) + 0.5, Y: float64(y) + 0.5}
+}
 
-		DrawLine(img, x1, y1, x2, y2, strokeColor)
+// ellipsePolygon 返回设备空间椭圆的多边形，抗锯齿时圆心位于像素中心
+// ellipsePolygon returns the polygon of a device-space ellipse, centered on the pixel center when anti-aliasing
+func (r *ImageRenderer) ellipsePolygon(centerX, centerY, radiusX, radiusY int) []types.Point {
+	points := ellipsePoints(centerX, centerY, radiusX, radiusY)
+	points = points[:len(points)-1]
+	if !r.CrispEdges {
+		for i := range points {
+			points[i].X += 0.5
+			points[i].Y += 0.5
+		}
 	}
+	return points
+}
 
-	// 闭合多边形
-	if len(points) > 1 {
-		x1 := int((points[len(points)-1].X - viewBox[0]) * scaleX)
-		y1 := int((points[len(points)-1].Y - viewBox[1]) * scaleY)
-		x2 := int((points[0].X - viewBox[0]) * scaleX)
-		y2 := int((points[0].Y - viewBox[1]) * scaleY)
+// fastRectFill 判断矩形填充能否使用逐行复制的快速路径：正常混合、内置图像目标、无变换且无圆角
+// fastRectFill reports whether a rect fill can take the row-copy fast path: normal blending, the built-in image target, no transform and no rounded corners
+func (r *ImageRenderer) fastRectFill(attrs map[string]string) bool {
+	if r.custom != nil || r.BlendMode != BlendNormal {
+		return false
+	}
+	if _, transformed := attrs["transform"]; transformed {
+		return false
+	}
+	rx, _ := parseFloat(attrs["rx"], 0)
+	ry, _ := parseFloat(attrs["ry"], 0)
+	return rx == 0 && ry == 0
+}
 
-		DrawLine(img, x1, y1, x2, y2, strokeColor)
+// fillDeviceRect 以不透明纯色填充整像素对齐的设备矩形，结果与通用多边形填充相同
+// fillDeviceRect fills a pixel-aligned device rectangle with an opaque solid color, matching the general polygon fill
+//
+// 矩形边位于整像素上，每个像素完全覆盖，正常混合下完全覆盖的像素等于不透明的填充色，因此可以先写一行再逐行复制；
+// 半透明颜色须与背景混合，由调用方交给通用填充
+// The edges lie on whole pixels so every pixel is fully covered, and under normal blending a fully covered pixel equals the opaque fill color, so one row is written and then copied;
+// translucent colors must blend with the background and are left to the general fill by the caller
+func (r *ImageRenderer) fillDeviceRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	if rect.Empty() {
+		return
+	}
+	first := img.Pix[img.PixOffset(rect.Min.X, rect.Min.Y):img.PixOffset(rect.Max.X, rect.Min.Y)]
+	for i := 0; i < len(first); i += 4 {
+		first[i], first[i+1], first[i+2], first[i+3] = c.R, c.G, c.B, c.A
+	}
+	for y := rect.Min.Y + 1; y < rect.Max.Y; y++ {
+		copy(img.Pix[img.PixOffset(rect.Min.X, y):], first)
 	}
+}
 
-	return nil
+// devicePolygon 返回设备空间矩形的四个角 / Return the four corners of a device-space rectangle
+func devicePolygon(x, y, w, h int) []types.Point {
+	left, top, right, bottom := float64(x), float64(y), float64(x+w), float64(y+h)
+	return []types.Point{{X: left, Y: top}, {X: right, Y: top}, {X: right, Y: bottom}, {X: left, Y: bottom}}
+}
+
+// strokeDevicePolyline 将设备空间折线的描边轮廓作为区域填充，CrispEdges时不抗锯齿
+// strokeDevicePolyline fills the stroke outline of a device-space polyline as an area, without anti-aliasing under CrispEdges
+//
+// 闭合边由描边生成器添加，points无需重复起点 / The stroke generator adds the closing edge, so points need not repeat the start
+func (r *ImageRenderer) strokeDevicePolyline(dst *image.RGBA, points []types.Point, closed bool, generator *TrueStrokePathGenerator, c color.RGBA, strokeWidth float64) {
+	if len(points) < 2 {
+		return
+	}
+
+	if r.CrispEdges {
+		r.fillPathWithWindingRule(dst, generator.GenerateStrokePath(points, strokeWidth, closed), c)
+		return
+	}
+	stroker := NewTrueStrokeRenderer()
+	stroker.AntiAliasedPathRenderer.ImageRenderer = r
+	stroker.PathGenerator = generator
+	stroker.RenderTrueStroke(dst, points, c, strokeWidth, closed)
+}
+
+// dashedPath 按stroke-dasharray和stroke-dashoffset切分路径，未设置或模式无效时返回原路径 / Cut a path by stroke-dasharray and stroke-dashoffset, returning the path itself when unset or invalid
+func (r *ImageRenderer) dashedPath(parsed *path.SVGPath, attrs map[string]string) *path.SVGPath {
+	pattern := parseDashArray(attrs["stroke-dasharray"])
+	if pattern == nil {
+		return parsed
+	}
+	// stroke-dashoffset为进入模式的起始距离 / stroke-dashoffset is the distance into the pattern at which dashing starts
+	offset, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(attrs["stroke-dashoffset"]), "px"), 64)
+	// 声明pathLength时虚线按逻辑长度给出，按几何长度与其之比缩放 / With pathLength the dashes are given in logical length and scale by geometric length over pathLength
+	if pathLength, err := strconv.ParseFloat(strings.TrimSpace(attrs["pathLength"]), 64); err == nil && pathLength > 0 {
+		scale := parsed.TotalLength(0.001) / pathLength
+		for i := range pattern {
+			pattern[i] *= scale
+		}
+		offset *= scale
+	}
+	dashed := parsed.Dash(pattern, offset, 0.001)
+	if dashed == nil {
+		return parsed
+	}
+	return dashed
+}
+
+// parseDashArray 解析stroke-dasharray，"none"或无法解析时返回nil / Parse stroke-dasharray, returning nil for "none" or unparsable values
+func parseDashArray(value string) []float64 {
+	fields := strings.FieldsFunc(value, func(c rune) bool { return c == ',' || c == ' ' || c == '\t' || c == '\n' || c == '\r' })
+	if len(fields) == 0 || fields[0] == "none" {
+		return nil
+	}
+	pattern := make([]float64, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(field, "px"), 64)
+		if err != nil {
+			return nil
+		}
+		pattern[i] = v
+	}
+	return pattern
+}
+
+// parseLineJoin 解析stroke-linejoin，默认尖角连接 / Parse stroke-linejoin, defaulting to miter
+func parseLineJoin(value string) StrokeJoinStyle {
+	switch strings.TrimSpace(value) {
+	case "round":
+		return JoinRound
+	case "bevel":
+		return JoinBevel
+	}
+	return JoinMiter
+}
+
+// parseLineCap 解析stroke-linecap，默认平头线帽 / Parse stroke-linecap, defaulting to butt
+func parseLineCap(value string) StrokeCapStyle {
+	switch strings.TrimSpace(value) {
+	case "round":
+		return CapRound
+	case "square":
+		return CapSquare
+	}
+	return CapButt
 }
 
 // renderPath 渲染路径元素（使用抗锯齿） / Render path element (with anti-aliasing)
 func (r *ImageRenderer) renderPath(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
 
 	// 获取路径数据 / Get path data
 	pathData, exists := attrs["d"]
@@ -305,20 +952,156 @@ func (r *ImageRenderer) renderPath(img *image.RGBA, element types.Element, viewB
 	}
 
 	// 获取样式 / Get styles
-	fillColor := r.getFillColor(attrs)
-	strokeColor := r.getStrokeColor(attrs)
-	strokeWidth := r.getStrokeWidth(attrs)
+	fillPaint := r.getFillPaint(attrs)
+	strokePaint := r.getStrokePaint(attrs)
+	// 路径绘制函数按min(scaleX, scaleY)缩放描边宽度 / The path drawing functions scale the stroke width by min(scaleX, scaleY)
+	strokeWidth := r.getStrokeWidth(attrs) * strokeScale(attrs, scaleX, scaleY) / math.Min(scaleX, scaleY)
+
+	parsed, err := path.ParsePath(pathData, r.pathParseOptions(scaleX, scaleY))
+	if err != nil {
+		return err
+	}
+	fillRule := path.ParseFillRule(attrs["fill-rule"])
+	lineCap := parseLineCap(attrs["stroke-linecap"])
+	drawPath := func(dst *image.RGBA, p *path.SVGPath, fillColor, strokeColor color.RGBA, strokeWidth float64) {
+		r.drawPath(dst, p, fillColor, strokeColor, strokeWidth, lineCap, viewBox, scaleX, scaleY, fillRule)
+	}
+
+	// 虚线描边使用切分后的路径 / Dashed strokes use the path cut into dashes
+	strokePath := parsed
+	if strokePaint != nil {
+		strokePath = r.dashedPath(parsed, attrs)
+	}
 
-	// 创建抗锯齿路径渲染器 / Create anti-aliased path renderer
-	aaPathRenderer := NewAntiAliasedPathRenderer()
+	// 纯色且默认绘制顺序时一次完成填充和描边 / Fill and stroke in a single pass for solid paints in the default order
+	fillColor, fillSolid := solidPaintColor(fillPaint)
+	strokeColor, strokeSolid := solidPaintColor(strokePaint)
+	if fillSolid && strokeSolid && strokePath == parsed && !strokeBeforeFill(attrs["paint-order"]) {
+		drawPath(img, parsed, fillColor, strokeColor, strokeWidth)
+		return r.renderMarkers(img, attrs, pathVertices(parsed), viewBox, scaleX, scaleY)
+	}
+
+	bounds := types.RectFromPoints(parsed.FlattenPath(0.1))
+	transparent := color.RGBA{0, 0, 0, 0}
 
-	// 使用抗锯齿路径渲染器渲染路径 / Render path using anti-aliased path renderer
-	return aaPathRenderer.RenderPath(img, pathData, fillColor, strokeColor, strokeWidth, viewBox, scaleX, scaleY)
+	fill := func() error {
+		return r.paintShape(img, fillPaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			drawPath(dst, parsed, c, transparent, 0)
+			return nil
+		})
+	}
+	stroke := func() error {
+		if len(strokePath.Commands) == 0 {
+			return nil
+		}
+		return r.paintShape(img, strokePaint, bounds, viewBox, scaleX, scaleY, func(dst *image.RGBA, c color.RGBA) error {
+			drawPath(dst, strokePath, transparent, c, strokeWidth)
+			return nil
+		})
+	}
+	if err := paintInOrder(attrs, fill, stroke); err != nil {
+		return err
+	}
+	return r.renderMarkers(img, attrs, pathVertices(parsed), viewBox, scaleX, scaleY)
+}
+
+// strokeBeforeFill 判断paint-order是否要求先描边后填充 / Report whether paint-order puts the stroke before the fill
+//
+// 未列出的绘制项按默认顺序（fill、stroke、markers）追加在后 / Unlisted keywords follow in the default order (fill, stroke, markers)
+func strokeBeforeFill(paintOrder string) bool {
+	for _, keyword := range strings.Fields(paintOrder) {
+		switch keyword {
+		case "fill":
+			return false
+		case "stroke":
+			return true
+		}
+	}
+	return false
+}
+
+// paintInOrder 按元素的paint-order依次执行填充和描边 / Run the fill and stroke steps in the element's paint-order
+func paintInOrder(attrs map[string]string, fill, stroke func() error) error {
+	first, second := fill, stroke
+	if strokeBeforeFill(attrs["paint-order"]) {
+		first, second = stroke, fill
+	}
+	if err := first(); err != nil {
+		return err
+	}
+	return second()
+}
+
+// flattenHook 测试用钩子，每次drawPath展平路径后以生成的线段数调用 / Test hook called with the number of generated segments each time drawPath flattens a path
+var flattenHook func(segments int)
+
+// drawPath 将路径展平到设备空间，并通过绘制目标填充和描边
+// drawPath flattens a path into device space and fills and strokes it through the draw target
+//
+// strokeWidth为用户单位，按min(scaleX, scaleY)缩放；lineCap作用于开放子路径 / strokeWidth is in user units and scales by min(scaleX, scaleY); lineCap applies to open subpaths
+func (r *ImageRenderer) drawPath(dst *image.RGBA, parsedPath *path.SVGPath, fillColor, strokeColor color.RGBA, strokeWidth float64, lineCap StrokeCapStyle, viewBox []float64, scaleX, scaleY float64, rule path.FillRule) {
+	// 抗锯齿时使用更细的展平精度并去掉过短的线段 / Anti-aliasing flattens more finely and drops very short segments
+	precision := 0.001
+	if r.CrispEdges {
+		precision = 0.1
+	}
+	filter := NewAntiAliasedPathRenderer()
+	var subPaths [][]types.Point
+	var closed []bool
+	for _, sub := range parsedPath.SubPaths(precision) {
+		device := make([]types.Point, len(sub.Points))
+		for i, p := range sub.Points {
+			device[i] = types.Point{X: (p.X - viewBox[0]) * scaleX, Y: (p.Y - viewBox[1]) * scaleY}
+		}
+		if !r.CrispEdges {
+			device = filter.filterShortSegments(device)
+		}
+		if len(device) < 2 {
+			continue
+		}
+		subPaths = append(subPaths, device)
+		closed = append(closed, sub.Closed)
+	}
+	if flattenHook != nil {
+		segments := 0
+		for _, sub := range subPaths {
+			segments += len(sub) - 1
+		}
+		flattenHook(segments)
+	}
+
+	target := r.target(dst)
+	if fillColor.A > 0 && len(subPaths) > 0 {
+		target.FillPolygon(subPaths, fillColor, rule)
+	}
+	if strokeColor.A > 0 && strokeWidth > 0 {
+		style := StrokeStyle{Width: strokeWidth * math.Min(scaleX, scaleY), Join: JoinRound, Cap: lineCap}
+		for i, sub := range subPaths {
+			target.StrokePolyline(sub, closed[i], strokeColor, style)
+		}
+	}
 }
 
 // renderText 渲染文本元素
 func (r *ImageRenderer) renderText(img *image.RGBA, element types.Element, viewBox []float64, scaleX, scaleY float64) error {
-	attrs := element.GetAttributes()
+	attrs := styledAttributes(element)
+
+	// textPath子元素继承文本属性并沿路径排列 / textPath children inherit the text's attributes and are laid out along their paths
+	for _, child := range element.Children() {
+		if child.Tag() != "textPath" {
+			continue
+		}
+		merged := make(map[string]string, len(attrs))
+		for name, value := range attrs {
+			merged[name] = value
+		}
+		for name, value := range styledAttributes(child) {
+			merged[name] = value
+		}
+		if err := r.renderTextPath(img, child, merged, viewBox, scaleX, scaleY); err != nil {
+			return err
+		}
+	}
 
 	// 解析位置属性
 	x, _ := parseFloat(attrs["x"], 0)
@@ -344,8 +1127,89 @@ func (r *ImageRenderer) renderText(img *image.RGBA, element types.Element, viewB
 	style := r.createTextStyleFromAttributes(attrs, scaleX, scaleY)
 
 	// 使用SVG文本渲染器渲染文本
-	textRenderer := font.DefaultTextRenderer
-	return textRenderer.RenderText(img, textContent, renderX, renderY, style)
+	textRenderer := r.text()
+
+	// 非纯色填充和描边按文本边界框逐像素着色 / Shade non-solid fills and strokes per pixel over the text's bounding box
+	var bounds *types.Rect
+	paintFor := func(value string) image.Image {
+		paint := r.resolvePaint(value)
+		if _, solid := paint.(SolidPaint); paint == nil || solid {
+			return nil
+		}
+		if bounds == nil {
+			bounds = &types.Rect{}
+			if metrics, err := textRenderer.MeasureText(textContent, style); err == nil {
+				*bounds = textBounds(x, y, metrics, style.TextAnchor, scaleX, scaleY)
+			}
+		}
+		return &paintImage{paint: paint, bounds: *bounds, viewBox: viewBox, scaleX: scaleX, scaleY: scaleY}
+	}
+	if fill := paintFor(attrs["fill"]); fill != nil {
+		style.Fill = fill
+	}
+	if stroke := paintFor(attrs["stroke"]); stroke != nil && style.Stroke != nil {
+		style.Stroke = stroke
+	}
+
+	transform, ok := textTransform(element.GetAttributes()["transform"], viewBox, scaleX, scaleY)
+	if !ok {
+		return r.target(img).DrawText(textContent, renderX, renderY, style)
+	}
+	if transform[0] == 1 && transform[1] == 0 && transform[3] == 0 && transform[4] == 1 {
+		// 纯平移只移动基线起点 / A pure translation only moves the baseline origin
+		return r.target(img).DrawText(textContent, renderX+transform[2], renderY+transform[5], style)
+	}
+	if transform[0]*transform[4]-transform[1]*transform[3] == 0 {
+		return nil // 退化变换不可见 / A degenerate transform is invisible
+	}
+	// 其他变换先在离屏图层上按原位置绘制，再经变换双线性采样后合成 / Other transforms draw the text in place on an offscreen layer, then sample it bilinearly through the transform and composite
+	metrics, err := textRenderer.MeasureText(textContent, style)
+	if err != nil {
+		return err
+	}
+	// 图层覆盖任意锚点和基线下的未变换文本 / The layer covers the untransformed text under any anchor and baseline
+	runes := float64(utf8.RuneCountInString(textContent))
+	width := math.Max(metrics.Advance, style.TextLength) + runes*(math.Abs(style.LetterSpacing)+math.Abs(style.WordSpacing))
+	height := metrics.Ascent + metrics.Descent
+	pad := style.FontSize + style.StrokeWidth
+	layer := image.NewRGBA(image.Rect(
+		int(math.Floor(renderX-width-pad)), int(math.Floor(renderY-height-pad)),
+		int(math.Ceil(renderX+width+pad)), int(math.Ceil(renderY+height+pad)),
+	))
+	if err := textRenderer.RenderText(layer, textContent, renderX, renderY, style); err != nil {
+		return err
+	}
+	// 变换后的图层只覆盖图层四角的包围盒，外扩一像素容纳双线性采样 / The transformed layer only covers the bounding box of the layer's corners, grown by a pixel for bilinear sampling
+	lb := layer.Bounds()
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range [][2]float64{{float64(lb.Min.X), float64(lb.Min.Y)}, {float64(lb.Max.X), float64(lb.Min.Y)}, {float64(lb.Min.X), float64(lb.Max.Y)}, {float64(lb.Max.X), float64(lb.Max.Y)}} {
+		tx := transform[0]*c[0] + transform[1]*c[1] + transform[2]
+		ty := transform[3]*c[0] + transform[4]*c[1] + transform[5]
+		minX, maxX = math.Min(minX, tx), math.Max(maxX, tx)
+		minY, maxY = math.Min(minY, ty), math.Max(maxY, ty)
+	}
+	area := image.Rect(int(math.Floor(minX))-1, int(math.Floor(minY))-1, int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1).Intersect(img.Bounds())
+	if area.Empty() {
+		return nil
+	}
+	transformed := image.NewRGBA(area)
+	xdraw.BiLinear.Transform(transformed, transform, layer, lb, xdraw.Over, nil)
+	r.target(img).DrawImage(transformed, area)
+	return nil
+}
+
+// textTransform 将文本的transform属性转换为设备空间的仿射变换S·M·S⁻¹，视图框原点并入平移；无变换时返回false
+// textTransform converts a text's transform attribute to the device-space affine S·M·S⁻¹, folding the viewBox origin into the translation; returns false without a transform
+func textTransform(value string, viewBox []float64, scaleX, scaleY float64) (f64.Aff3, bool) {
+	if strings.TrimSpace(value) == "" {
+		return f64.Aff3{}, false
+	}
+	m := attributes.ParseTransform(value).GetMatrix()
+	return f64.Aff3{
+		m.A, m.C * scaleX / scaleY, scaleX * (m.A*viewBox[0] + m.C*viewBox[1] + m.E - viewBox[0]),
+		m.B * scaleY / scaleX, m.D, scaleY * (m.B*viewBox[0] + m.D*viewBox[1] + m.F - viewBox[1]),
+	}, true
 }
 
 // createTextStyleFromAttributes 从SVG属性创建文本样式
@@ -403,30 +1267,81 @@ func (r *ImageRenderer) createTextStyleFromAttributes(attrs map[string]string, s
 		}
 	}
 
+	// 解析主基线 / Parse dominant baseline
+	if dominantBaseline, ok := attrs["dominant-baseline"]; ok {
+		switch dominantBaseline {
+		case "auto":
+			style.DominantBaseline = font.DominantBaselineAuto
+		case "central":
+			style.DominantBaseline = font.DominantBaselineCentral
+		case "middle":
+			style.DominantBaseline = font.DominantBaselineMiddle
+		case "hanging":
+			style.DominantBaseline = font.DominantBaselineHanging
+		case "text-top":
+			style.DominantBaseline = font.DominantBaselineTextTop
+		case "text-bottom":
+			style.DominantBaseline = font.DominantBaselineTextBottom
+		}
+	}
+
 	// 解析填充颜色
 	if fill, ok := attrs["fill"]; ok {
-		fillColor := parseColor(fill, color.RGBA{0, 0, 0, 255})
-		style.Fill = &image.Uniform{C: fillColor}
+		if strings.TrimSpace(fill) == "none" {
+			style.Fill = nil
+		} else if fillColor, ok := r.resolveColor(fill); ok {
+			style.Fill = &image.Uniform{C: fillColor}
+		} else {
+			// 无法解析的颜色不绘制 / Colors that cannot be parsed paint nothing
+			style.Fill = nil
+		}
 	}
 
 	// 解析描边颜色
-	if stroke, ok := attrs["stroke"]; ok && stroke != "none" {
-		strokeColor := parseColor(stroke, color.RGBA{0, 0, 0, 255})
+	if strokeColor, ok := r.resolveColor(attrs["stroke"]); ok {
 		style.Stroke = &image.Uniform{C: strokeColor}
+		// stroke-width默认为1 / stroke-width defaults to 1
+		style.StrokeWidth = (scaleX + scaleY) / 2
+	}
+
+	// 解析绘制顺序 / Parse paint order
+	style.StrokeFirst = strokeBeforeFill(attrs["paint-order"])
+
+	// 解析目标文本宽度 / Parse target text length
+	if textLengthStr, ok := attrs["textLength"]; ok {
+		if textLength, err := parseFloat(textLengthStr, 0); err == nil && textLength > 0 {
+			style.TextLength = textLength * scaleX
+		}
+	}
+	if lengthAdjust, ok := attrs["lengthAdjust"]; ok {
+		style.LengthAdjust = font.LengthAdjust(lengthAdjust)
+	}
+
+	// 解析字偶距，兼容旧的kerning属性 / Parse kerning, accepting the legacy kerning attribute
+	if kerning, ok := attrs["font-kerning"]; ok {
+		style.FontKerning = font.FontKerning(strings.TrimSpace(kerning))
+	} else if kerning, ok := attrs["kerning"]; ok {
+		if value, err := parseFloat(kerning, 0); err == nil && value == 0 {
+			style.FontKerning = font.FontKerningNone
+		}
 	}
 
 	// 解析描边宽度
 	if strokeWidthStr, ok := attrs["stroke-width"]; ok {
 		if strokeWidth, err := parseFloat(strokeWidthStr, 0); err == nil {
-			// 应用缩放
+			// 应用缩放，non-scaling-stroke时保持设备像素宽度 / Apply scaling, keeping the device-pixel width for non-scaling-stroke
 			style.StrokeWidth = strokeWidth * ((scaleX + scaleY) / 2)
+			if nonScalingStroke(attrs) {
+				style.StrokeWidth = strokeWidth
+			}
 		}
 	}
 
 	return style
 }
 
-// parseViewBox 解析视口
+// parseViewBox 解析视口，返回[minX, minY, maxX, maxY]，宽高为后两项减去前两项
+// parseViewBox parses the viewBox into [minX, minY, maxX, maxY]; the width and height are the last two minus the first two
 func parseViewBox(viewBox string) []float64 {
 	// 如果viewBox为空，返回默认值
 	if viewBox == "" {
@@ -449,10 +1364,17 @@ func parseViewBox(viewBox string) []float64 {
 		result[3] = 600 // 默认高度
 	}
 
+	// 转换为右下角坐标，使缩放和平移对非零原点同样成立 / Convert to the bottom-right corner so scaling and translation hold for a non-zero origin
+	result[2] += result[0]
+	result[3] += result[1]
 	return result
 }
 
-// parseFloat 解析浮点数
+// parseFloat 解析浮点数，带绝对单位或字号单位的长度按96 DPI和默认字号换算为用户单位，百分比无法解析
+// parseFloat parses a number; lengths with absolute or font units convert to user units at 96 DPI and the default font size, while percentages cannot be parsed
+//
+// em和ex始终相对types.DefaultFontSize（16px）而非元素自身的font-size，调用处不传入字号
+// em and ex always resolve against types.DefaultFontSize (16px) rather than the element's own font-size, since callers do not pass one
 func parseFloat(s string, defaultValue float64) (float64, error) {
 	if s == "" {
 		return defaultValue, nil
@@ -460,68 +1382,54 @@ func parseFloat(s string, defaultValue float64) (float64, error) {
 
 	value, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		return defaultValue, err
+		length, lengthErr := types.ParseLength(s)
+		if lengthErr != nil || length.Unit == types.UnitPercent {
+			return defaultValue, err
+		}
+		return length.Resolve(0, types.DefaultDPI), nil
 	}
 
 	return value, nil
 }
 
-// parseColor 解析颜色
-func parseColor(s string, defaultColor color.RGBA) color.RGBA {
-	if s == "" || s == "none" {
-		return defaultColor
-	}
-
-	// 处理十六进制颜色
-	if strings.HasPrefix(s, "#") {
-		hex := s[1:]
-
-		// 处理简写形式 (#RGB)
-		if len(hex) == 3 {
-			r := parseHex(hex[0:1] + hex[0:1])
-			g := parseHex(hex[1:2] + hex[1:2])
-			b := parseHex(hex[2:3] + hex[2:3])
-			return color.RGBA{r, g, b, 255}
-		}
-
-		// 处理完整形式 (#RRGGBB)
-		if len(hex) == 6 {
-			r := parseHex(hex[0:2])
-			g := parseHex(hex[2:4])
-			b := parseHex(hex[4:6])
-			return color.RGBA{r, g, b, 255}
+// resolveColor 解析颜色，currentColor取继承的color属性（未设置时为黑色），无法解析时ok为false
+// resolveColor parses a color, resolving currentColor to the inherited color property (black when unset); ok is false when the value cannot be parsed
+func (r *ImageRenderer) resolveColor(value string) (c color.RGBA, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "currentColor" {
+		if r.currentColor == "" {
+			return color.RGBA{0, 0, 0, 255}, true
 		}
+		value = strings.TrimSpace(r.currentColor)
 	}
+	return lookupColor(value)
+}
 
-	// 处理命名颜色
-	switch s {
-	case "black":
-		return color.RGBA{0, 0, 0, 255}
-	case "white":
-		return color.RGBA{255, 255, 255, 255}
-	case "red":
-		return color.RGBA{255, 0, 0, 255}
-	case "green":
-		return color.RGBA{0, 128, 0, 255}
-	case "blue":
-		return color.RGBA{0, 0, 255, 255}
-	case "yellow":
-		return color.RGBA{255, 255, 0, 255}
-	case "cyan":
-		return color.RGBA{0, 255, 255, 255}
-	case "magenta":
-		return color.RGBA{255, 0, 255, 255}
-	case "gray":
-		return color.RGBA{128, 128, 128, 255}
+// parseColor 解析颜色，无法解析时返回defaultColor / Parse a color, returning defaultColor when it cannot be parsed
+func parseColor(s string, defaultColor color.RGBA) color.RGBA {
+	if c, ok := lookupColor(s); ok {
+		return c
 	}
-
 	return defaultColor
 }
 
-// parseHex 解析十六进制颜色值
-func parseHex(s string) uint8 {
-	value, _ := strconv.ParseUint(s, 16, 8)
-	return uint8(value)
+// lookupColor 通过attributes.ParseColor解析十六进制、rgb()/rgba()和命名颜色，返回非预乘颜色
+// lookupColor parses hex, rgb()/rgba() and named colors through attributes.ParseColor, returning a straight-alpha color
+func lookupColor(s string) (color.RGBA, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "none" {
+		return color.RGBA{}, false
+	}
+	parsed, err := attributes.ParseColor(s)
+	if err != nil {
+		return color.RGBA{}, false
+	}
+	if c, ok := parsed.(color.RGBA); ok {
+		return c, true
+	}
+	// 其他颜色类型按非预乘分量取值 / Take the straight-alpha components of other color types
+	c := color.NRGBAModel.Convert(parsed).(color.NRGBA)
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: c.A}, true
 }
 
 // parseFontWeight 解析字体粗细 / Parse font weight
@@ -568,6 +1476,20 @@ func parseFontStyle(s string) font.FontStyle {
 	}
 }
 
+// ellipsePoints 以多边形近似椭圆，用于无抗锯齿渲染 / Approximate an ellipse with a polygon for non-anti-aliased rendering
+func ellipsePoints(centerX, centerY, radiusX, radiusY int) []types.Point {
+	segments := int(math.Max(16, math.Ceil(float64(radiusX+radiusY)*math.Pi/2)))
+	points := make([]types.Point, segments+1)
+	for i := 0; i <= segments; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(segments)
+		points[i] = types.Point{
+			X: float64(centerX) + float64(radiusX)*math.Cos(angle),
+			Y: float64(centerY) + float64(radiusY)*math.Sin(angle),
+		}
+	}
+	return points
+}
+
 // parsePoints 解析点列表
 func parsePoints(s string) []types.Point {
 	parts := strings.Fields(strings.Replace(s, ",", " ", -1))
@@ -585,7 +1507,11 @@ func parsePoints(s string) []types.Point {
 	return points
 }
 
-// validateAndFixPath 验证并修复路径，确保路径正确闭合
+// validateAndFixPath 验证并修复路径，去掉重复的相邻点，不添加闭合点
+// validateAndFixPath cleans up a path by dropping repeated adjacent points, without adding a closing point
+//
+// 闭合边由使用者按子路径自身决定：填充的边表总是连接首尾，描边按子路径的Z标志闭合
+// The closing edge is left to the consumer of each subpath: the fill edge table always joins the ends, and strokes close by the subpath's own Z flag
 func (r *ImageRenderer) validateAndFixPath(points []types.Point) []types.Point {
 	if len(points) < 3 {
 		return points
@@ -609,21 +1535,6 @@ func (r *ImageRenderer) validateAndFixPath(points []types.Point) []types.Point {
 		}
 	}
 
-	// 确保路径闭合
-	if len(cleanPoints) >= 3 {
-		first := cleanPoints[0]
-		last := cleanPoints[len(cleanPoints)-1]
-
-		dx := last.X - first.X
-		dy := last.Y - first.Y
-		distSq := dx*dx + dy*dy
-
-		// 如果路径未闭合（距离大于1像素），添加闭合点
-		if distSq > 1.0 {
-			cleanPoints = append(cleanPoints, first)
-		}
-	}
-
 	return cleanPoints
 }
 
@@ -646,7 +1557,23 @@ func (r *ImageRenderer) FillPath(img *image.RGBA, points []types.Point, fillColo
 
 // FillSubPathsWithWindingRule 公开的填充多个子路径方法 / Public fill multiple sub-paths method
 func (r *ImageRenderer) FillSubPathsWithWindingRule(img *image.RGBA, subPaths [][]types.Point, fillColor color.RGBA) {
-	r.fillSubPathsWithWindingRule(img, subPaths, fillColor)
+	r.fillSubPathsWithWindingRule(img, subPaths, fillColor, path.FillRuleNonZero)
+}
+
+// StrokeSubPaths 描边多个子路径，仅闭合标记为闭合(Z)的子路径
+// StrokeSubPaths strokes several subpaths, closing only those flagged closed (Z)
+func (r *ImageRenderer) StrokeSubPaths(img *image.RGBA, subPaths [][]types.Point, closeInfo []bool, strokeColor color.RGBA, strokeWidth float64) {
+	for i, subPath := range subPaths {
+		closed := i < len(closeInfo) && closeInfo[i]
+		if len(subPath) < 2 {
+			continue
+		}
+		if len(subPath) >= 3 {
+			// 闭合边由strokePath按closed添加 / The closing edge is added by strokePath according to closed
+			subPath = r.validateAndFixPath(subPath)
+		}
+		r.strokePath(img, subPath, closed, strokeColor, strokeWidth)
+	}
 }
 
 // fillPath 填充路径 / Fill path using high-precision scanline algorithm with anti-aliasing
@@ -690,13 +1617,13 @@ func (r *ImageRenderer) fillPathWithWindingRule(img *image.RGBA, points []types.
 		r.sortIntersections(intersections)
 
 		// 使用非零缠绕规则填充
-		r.fillScanlineWithWinding(img, intersections, y, fillColor)
+		r.fillScanlineWithWinding(img, intersections, y, fillColor, path.FillRuleNonZero)
 	}
 }
 
-// fillSubPathsWithWindingRule 使用缠绕规则填充多个子路径
+// fillSubPathsWithWindingRule 按填充规则填充多个子路径
 // 每个子路径独立处理，避免跨子路径的连接线问题
-func (r *ImageRenderer) fillSubPathsWithWindingRule(img *image.RGBA, subPaths [][]types.Point, fillColor color.RGBA) {
+func (r *ImageRenderer) fillSubPathsWithWindingRule(img *image.RGBA, subPaths [][]types.Point, fillColor color.RGBA, rule path.FillRule) {
 	if len(subPaths) == 0 {
 		return
 	}
@@ -745,8 +1672,8 @@ func (r *ImageRenderer) fillSubPathsWithWindingRule(img *image.RGBA, subPaths []
 		// 按x坐标排序交点
 		r.sortIntersections(intersections)
 
-		// 使用非零缠绕规则填充
-		r.fillScanlineWithWinding(img, intersections, y, fillColor)
+		// 按填充规则填充
+		r.fillScanlineWithWinding(img, intersections, y, fillColor, rule)
 	}
 }
 
@@ -836,8 +1763,8 @@ func (r *ImageRenderer) sortIntersections(intersections []IntersectionInfo) {
 	}
 }
 
-// fillScanlineWithWinding 使用缠绕数规则填充扫描线 / Fill scanline using winding number rule
-func (r *ImageRenderer) fillScanlineWithWinding(img *image.RGBA, intersections []IntersectionInfo, y int, fillColor color.RGBA) {
+// fillScanlineWithWinding 按填充规则填充扫描线 / Fill a scanline under the fill rule
+func (r *ImageRenderer) fillScanlineWithWinding(img *image.RGBA, intersections []IntersectionInfo, y int, fillColor color.RGBA, rule path.FillRule) {
 	if len(intersections) == 0 {
 		return
 	}
@@ -848,8 +1775,12 @@ func (r *ImageRenderer) fillScanlineWithWinding(img *image.RGBA, intersections [
 	for i, intersection := range intersections {
 		currentX := int(math.Floor(intersection.X))
 
-		// 如果缠绕数非零，填充从lastX到currentX的像素
-		if windingNumber != 0 {
+		// 区间在内部时填充从lastX到currentX的像素 / Fill pixels from lastX to currentX when the span is inside
+		inside := windingNumber != 0
+		if rule == path.FillRuleEvenOdd {
+			inside = i%2 == 1
+		}
+		if inside {
 			for x := lastX; x < currentX; x++ {
 				DrawPixel(img, x, y, fillColor)
 			}
@@ -948,7 +1879,7 @@ func (r *ImageRenderer) fillPathLegacy(img *image.RGBA, points []types.Point, fi
 
 // drawAntiAliasedPixel 绘制抗锯齿像素 / Draw anti-aliased pixel
 func (r *ImageRenderer) drawAntiAliasedPixel(img *image.RGBA, x, y int, fillColor color.RGBA, alpha float64) {
-	if x < 0 || y < 0 || x >= img.Bounds().Dx() || y >= img.Bounds().Dy() {
+	if !image.Pt(x, y).In(img.Bounds()) {
 		return
 	}
 
@@ -956,16 +1887,16 @@ func (r *ImageRenderer) drawAntiAliasedPixel(img *image.RGBA, x, y int, fillColo
 	currentColor := img.RGBAAt(x, y)
 
 	// Alpha混合 / Alpha blending
-	newR := uint8(float64(currentColor.R)*(1.0-alpha) + float64(fillColor.R)*alpha)
-	newG := uint8(float64(currentColor.G)*(1.0-alpha) + float64(fillColor.G)*alpha)
-	newB := uint8(float64(currentColor.B)*(1.0-alpha) + float64(fillColor.B)*alpha)
+	newR := blendChannel(currentColor.R, fillColor.R, alpha, r.LinearBlending)
+	newG := blendChannel(currentColor.G, fillColor.G, alpha, r.LinearBlending)
+	newB := blendChannel(currentColor.B, fillColor.B, alpha, r.LinearBlending)
 	newA := uint8(math.Max(float64(currentColor.A), float64(fillColor.A)*alpha))
 
 	img.SetRGBA(x, y, color.RGBA{newR, newG, newB, newA})
 }
 
 // strokePath 描边路径
-func (r *ImageRenderer) strokePath(img *image.RGBA, points []types.Point, strokeColor color.RGBA, strokeWidth float64) {
+func (r *ImageRenderer) strokePath(img *image.RGBA, points []types.Point, closed bool, strokeColor color.RGBA, strokeWidth float64) {
 	if len(points) < 2 {
 		return // 至少需要2个点才能绘制线条
 	}
@@ -979,66 +1910,37 @@ func (r *ImageRenderer) strokePath(img *image.RGBA, points []types.Point, stroke
 			y2 := int(points[i].Y)
 			DrawLine(img, x1, y1, x2, y2, strokeColor)
 		}
+		// 闭合边只在末点未回到起点时绘制一次 / Draw the closing edge once, only when the last point has not returned to the start
+		if first, last := points[0], points[len(points)-1]; closed && len(points) >= 3 && first.Distance(last) > 0.1 {
+			DrawLine(img, int(last.X), int(last.Y), int(first.X), int(first.Y), strokeColor)
+		}
 		return
 	}
 
-	// 对于较粗的线条，使用多条平行线来模拟
-	halfWidth := strokeWidth / 2.0
-	for i := 1; i < len(points); i++ {
-		x1, y1 := points[i-1].X, points[i-1].Y
-		x2, y2 := points[i].X, points[i].Y
-
-		// 计算线段的方向向量
-		dx := x2 - x1
-		dy := y2 - y1
-		length := math.Sqrt(dx*dx + dy*dy)
-
-		if length == 0 {
-			continue
-		}
-
-		// 计算垂直于线段的单位向量
-		perpX := -dy / length
-		perpY := dx / length
-
-		// 绘制多条平行线来模拟粗线
-		steps := int(math.Ceil(strokeWidth))
-		for step := -steps / 2; step <= steps/2; step++ {
-			offset := float64(step) * (strokeWidth / float64(steps))
-			if math.Abs(offset) > halfWidth {
-				continue
-			}
-
-			startX := int(x1 + perpX*offset)
-			startY := int(y1 + perpY*offset)
-			endX := int(x2 + perpX*offset)
-			endY := int(y2 + perpY*offset)
-
-			DrawLine(img, startX, startY, endX, endY, strokeColor)
-		}
-	}
+	// 较粗的线条按描边轮廓填充，避免平行线之间的缝隙 / Thicker lines fill their stroke outline, avoiding gaps between parallel lines
+	r.strokeDevicePolyline(img, points, closed, NewTrueStrokePathGenerator(), strokeColor, strokeWidth)
 }
 
-// getFillColor 获取填充颜色 / Get fill color
-func (r *ImageRenderer) getFillColor(attrs map[string]string) color.RGBA {
-	fillAttr := attrs["fill"]
-	if fillAttr == "none" {
-		return color.RGBA{0, 0, 0, 0} // 透明 / Transparent
-	}
-	if fillAttr == "" {
+// getFillPaint 获取填充绘制源 / Get fill paint
+func (r *ImageRenderer) getFillPaint(attrs map[string]string) Paint {
+	if attrs["fill"] == "" {
 		// SVG标准：如果没有设置fill属性，默认为黑色 / SVG standard: default to black if no fill attribute
-		return color.RGBA{0, 0, 0, 255} // 默认黑色 / Default black
+		return SolidPaint{Color: color.RGBA{0, 0, 0, 255}}
 	}
-	return parseColor(fillAttr, color.RGBA{0, 0, 0, 255})
+	return r.resolvePaint(attrs["fill"])
+}
+
+// getStrokePaint 获取描边绘制源，未设置时返回nil / Get stroke paint, nil when unset
+func (r *ImageRenderer) getStrokePaint(attrs map[string]string) Paint {
+	return r.resolvePaint(attrs["stroke"])
 }
 
-// getStrokeColor 获取描边颜色
-func (r *ImageRenderer) getStrokeColor(attrs map[string]string) color.RGBA {
-	strokeAttr := attrs["stroke"]
-	if strokeAttr == "none" || strokeAttr == "" {
-		return color.RGBA{0, 0, 0, 0} // 透明
+// getLineStrokePaint 获取线条类元素的描边绘制源，未设置时默认为黑色 / Get the stroke paint of line-like elements, defaulting to black
+func (r *ImageRenderer) getLineStrokePaint(attrs map[string]string) Paint {
+	if attrs["stroke"] == "" {
+		return SolidPaint{Color: color.RGBA{0, 0, 0, 255}}
 	}
-	return parseColor(strokeAttr, color.RGBA{0, 0, 0, 255})
+	return r.resolvePaint(attrs["stroke"])
 }
 
 // getStrokeWidth 获取描边宽度
@@ -1046,3 +1948,17 @@ func (r *ImageRenderer) getStrokeWidth(attrs map[string]string) float64 {
 	strokeWidth, _ := parseFloat(attrs["stroke-width"], 1)
 	return strokeWidth
 }
+
+// nonScalingStroke 判断元素是否设置了vector-effect="non-scaling-stroke" / Report whether an element sets vector-effect="non-scaling-stroke"
+func nonScalingStroke(attrs map[string]string) bool {
+	return strings.TrimSpace(attrs["vector-effect"]) == "non-scaling-stroke"
+}
+
+// strokeScale 返回描边宽度从用户单位到设备像素的缩放，non-scaling-stroke时为1
+// strokeScale returns the user-to-device scale applied to the stroke width, 1 for non-scaling-stroke
+func strokeScale(attrs map[string]string, scaleX, scaleY float64) float64 {
+	if nonScalingStroke(attrs) {
+		return 1
+	}
+	return math.Min(scaleX, scaleY)
+}
@@ -0,0 +1,1117 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/tiff"
+
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/font"
+	"github.com/hoonfeng/svg/parser"
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// inkRows 返回图像中有像素的最上和最下行 / Return the top and bottom rows containing ink
+func inkRows(img *image.RGBA) (top, bottom int) {
+	top, bottom = -1, -1
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y).A > 0 {
+				if top < 0 {
+					top = y
+				}
+				bottom = y
+				break
+			}
+		}
+	}
+	return top, bottom
+}
+
+// renderBaselineText 渲染设置了基线属性的文本 / Render text with a baseline attribute set
+func renderBaselineText(t *testing.T, name, value string) *image.RGBA {
+	t.Helper()
+	doc := types.NewDocument(200, 100)
+	doc.SetViewBox(0, 0, 200, 100)
+	text := elements.NewText(10, 50, "Hello")
+	text.SetAttribute("font-size", "20")
+	if name != "" {
+		text.SetAttribute(name, value)
+	}
+	doc.AppendElement(text)
+
+	img, err := NewImageRenderer().Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	return img
+}
+
+// TestRotatedText 测试rotate(90)使文本沿竖直方向延伸 / Test rotate(90) makes text extend vertically
+func TestRotatedText(t *testing.T) {
+	inkBounds := func(img *image.RGBA) image.Rectangle {
+		var ink image.Rectangle
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if img.RGBAAt(x, y).A > 0 {
+					ink = ink.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		return ink
+	}
+
+	horizontal := inkBounds(renderBaselineText(t, "", ""))
+	// (10,-150)经rotate(90)落在(150,10)，基线方向转为向下 / rotate(90) maps (10,-150) to (150,10) and turns the baseline downward
+	doc := types.NewDocument(200, 100)
+	doc.SetViewBox(0, 0, 200, 100)
+	text := elements.NewText(10, -150, "Hello")
+	text.SetAttribute("font-size", "20")
+	text.SetAttribute("transform", "rotate(90)")
+	doc.AppendElement(text)
+	img, err := NewImageRenderer().Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	rotated := inkBounds(img)
+
+	if horizontal.Empty() || rotated.Empty() {
+		t.Fatal("expected text to be rendered")
+	}
+	if rotated.Dy() <= rotated.Dx() {
+		t.Errorf("expected rotated text to be taller than wide, got %v", rotated)
+	}
+	if math.Abs(float64(rotated.Dy()-horizontal.Dx())) > 2 || math.Abs(float64(rotated.Dx()-horizontal.Dy())) > 2 {
+		t.Errorf("expected the rotated ink %v to swap the horizontal extent %v", rotated, horizontal)
+	}
+	// 旋转前位于基线上方的字形转到基线右侧 / Glyphs above the baseline before rotating end up right of it
+	if rotated.Min.Y < 9 || rotated.Min.X < 149 {
+		t.Errorf("expected the text to start at (150,10) and lie right of x=150, got %v", rotated)
+	}
+}
+
+// TestDominantBaseline 测试dominant-baseline与alignment-baseline的位置一致 / Test dominant-baseline places text like alignment-baseline
+func TestDominantBaseline(t *testing.T) {
+	centralTop, centralBottom := inkRows(renderBaselineText(t, "dominant-baseline", "central"))
+	middleTop, middleBottom := inkRows(renderBaselineText(t, "alignment-baseline", "middle"))
+	autoTop, _ := inkRows(renderBaselineText(t, "", ""))
+
+	if centralTop < 0 || middleTop < 0 || autoTop < 0 {
+		t.Fatal("expected text to be rendered")
+	}
+	if centralTop != middleTop || centralBottom != middleBottom {
+		t.Errorf("expected central to match middle, got rows %d-%d vs %d-%d", centralTop, centralBottom, middleTop, middleBottom)
+	}
+	if centralTop <= autoTop {
+		t.Errorf("expected central text below the alphabetic placement, got top %d vs %d", centralTop, autoTop)
+	}
+}
+
+// openCSubPaths 返回开口向右的开放C形子路径 / Return the subpaths of an open 'C' shape opening to the right
+func openCSubPaths(t *testing.T) ([][]types.Point, []bool) {
+	t.Helper()
+	p, err := path.ParsePath("M 71.2 28.8 A 30 30 0 1 0 71.2 71.2")
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	return p.FlattenSubPaths(0.1), p.GetSubPathCloseInfo()
+}
+
+// TestOpenSubPathFill 测试填充开放C形不会被闭合成O形 / Test filling an open 'C' does not close it into an 'O'
+func TestOpenSubPathFill(t *testing.T) {
+	subPaths, _ := openCSubPaths(t)
+	black := color.RGBA{0, 0, 0, 255}
+
+	img := NewImage(100, 100)
+	NewImageRenderer().FillSubPathsWithWindingRule(img, subPaths, black)
+
+	if img.RGBAAt(40, 50).A == 0 {
+		t.Error("expected the inside of the 'C' to be filled")
+	}
+	// 开口处位于弦的右侧，不应被填充 / The mouth lies right of the chord and must stay empty
+	if a := img.RGBAAt(80, 50).A; a != 0 {
+		t.Errorf("expected the mouth of the 'C' to stay empty, got alpha %d", a)
+	}
+}
+
+// TestValidateAndFixPathKeepsEnds 测试清理路径只去掉重复点而不添加闭合点 / Test cleaning a path only drops repeated points and adds no closing point
+func TestValidateAndFixPathKeepsEnds(t *testing.T) {
+	points := []types.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0.05}, {X: 10, Y: 10}}
+	got := NewImageRenderer().validateAndFixPath(points)
+	want := []types.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestOpenSubPathStroke 测试描边只闭合标记为闭合的子路径 / Test stroking closes only subpaths flagged closed
+func TestOpenSubPathStroke(t *testing.T) {
+	subPaths, closeInfo := openCSubPaths(t)
+	black := color.RGBA{0, 0, 0, 255}
+	r := NewImageRenderer()
+
+	open := NewImage(100, 100)
+	r.StrokeSubPaths(open, subPaths, closeInfo, black, 1)
+	if a := open.RGBAAt(71, 50).A; a != 0 {
+		t.Errorf("expected no closing segment on an open subpath, got alpha %d", a)
+	}
+
+	closed := NewImage(100, 100)
+	r.StrokeSubPaths(closed, subPaths, []bool{true}, black, 1)
+	if closed.RGBAAt(71, 50).A == 0 {
+		t.Error("expected a closing segment on a closed subpath")
+	}
+}
+
+// TestAntiAliasingDisabled 测试禁用抗锯齿后所有像素完全不透明 / Test all painted pixels are fully opaque with anti-aliasing off
+func TestAntiAliasingDisabled(t *testing.T) {
+	const content = `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<path d="M 5 5 L 95 60" fill="none" stroke="black" stroke-width="1"/>
+	<circle cx="60" cy="75" r="15" fill="black"/>
+</svg>`
+
+	doc, err := parser.NewXMLParser().ParseString(content)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	countPixels := func(antiAliasing bool) (painted, partial int) {
+		r := NewImageRenderer()
+		r.SetAntiAliasing(antiAliasing)
+		img, err := r.Render(doc, 100, 100)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		for y := 0; y < 100; y++ {
+			for x := 0; x < 100; x++ {
+				switch a := img.RGBAAt(x, y).A; {
+				case a == 255:
+					painted++
+				case a > 0:
+					painted++
+					partial++
+				}
+			}
+		}
+		return painted, partial
+	}
+
+	if _, partial := countPixels(true); partial == 0 {
+		t.Error("expected partially covered pixels with anti-aliasing on")
+	}
+	painted, partial := countPixels(false)
+	if painted == 0 {
+		t.Fatal("expected pixels to be painted with anti-aliasing off")
+	}
+	if partial != 0 {
+		t.Errorf("expected no partial alpha with anti-aliasing off, got %d pixels", partial)
+	}
+}
+
+// countFillStroke 统计红色填充和蓝色描边像素数 / Count red fill and blue stroke pixels
+func countFillStroke(img *image.RGBA) (red, blue int) {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			if c.A < 200 {
+				continue
+			}
+			if c.R > 200 && c.B < 50 {
+				red++
+			}
+			if c.B > 200 && c.R < 50 {
+				blue++
+			}
+		}
+	}
+	return red, blue
+}
+
+// TestTextFillAndStroke 测试文本同时绘制填充和描边 / Test text renders both its fill and its stroke
+func TestTextFillAndStroke(t *testing.T) {
+	// 使用可缩放字体，使字干宽于描边 / Use a scalable font so the stems are wider than the stroke
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("write font: %v", err)
+	}
+	textRenderer := font.NewSVGTextRenderer()
+	if err := textRenderer.LoadFontFromFile(fontPath, "GoStrokeTest", 60); err != nil {
+		t.Fatalf("load font: %v", err)
+	}
+	renderer := NewImageRenderer()
+	renderer.SetTextRenderer(textRenderer)
+
+	doc := types.NewDocument(200, 100)
+	doc.SetViewBox(0, 0, 200, 100)
+	text := elements.NewText(10, 70, "HI")
+	text.SetAttribute("font-family", "GoStrokeTest")
+	text.SetAttribute("font-size", "60")
+	text.SetAttribute("font-weight", "normal")
+	text.SetAttribute("fill", "#ff0000")
+	text.SetAttribute("stroke", "#0000ff")
+	text.SetAttribute("stroke-width", "2")
+	doc.AppendElement(text)
+
+	img, err := renderer.Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	red, blue := countFillStroke(img)
+	b := img.Bounds()
+	if red == 0 || blue == 0 {
+		t.Errorf("expected both fill and stroke colors, got %d red and %d blue pixels", red, blue)
+	}
+
+	// 先描边时填充覆盖描边的内半部分 / Stroking first lets the fill cover the inner half of the stroke
+	text.SetAttribute("paint-order", "stroke")
+	img, err = renderer.Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	redFirst, blueFirst := countFillStroke(img)
+	if redFirst <= red || blueFirst >= blue {
+		t.Errorf("expected more fill and less stroke with paint-order=stroke, got %d/%d vs %d/%d", redFirst, blueFirst, red, blue)
+	}
+	text.SetAttribute("paint-order", "normal")
+
+	// 仅描边时字形内部应保持透明 / With only a stroke the glyph interiors stay transparent
+	text.SetAttribute("fill", "none")
+	img, err = renderer.Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if c := img.RGBAAt(x, y); c.R > 100 {
+				t.Fatalf("expected no fill with fill=none, found %v at %d,%d", c, x, y)
+			}
+		}
+	}
+}
+
+// TestPaintOrder 测试paint-order先描边后填充 / Test paint-order painting the stroke before the fill
+func TestPaintOrder(t *testing.T) {
+	render := func(paintOrder string) *image.RGBA {
+		doc := types.NewDocument(100, 100)
+		doc.SetViewBox(0, 0, 100, 100)
+		square := elements.NewPath("M 20 20 L 80 20 L 80 80 L 20 80 Z")
+		square.SetAttribute("fill", "#ff0000")
+		square.SetAttribute("stroke", "#0000ff")
+		square.SetAttribute("stroke-width", "10")
+		if paintOrder != "" {
+			square.SetAttribute("paint-order", paintOrder)
+		}
+		doc.AppendElement(square)
+
+		img, err := NewImageRenderer().Render(doc, 100, 100)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return img
+	}
+
+	// 边界内侧2像素处被描边的内半部分覆盖 / Two pixels inside the edge lies under the inner half of the stroke
+	if got := render("").RGBAAt(50, 22); got.B < 200 || got.R > 50 {
+		t.Errorf("expected the stroke on top by default, got %v", got)
+	}
+	reversed := render("stroke fill")
+	if got := reversed.RGBAAt(50, 22); got.R < 200 || got.B > 50 {
+		t.Errorf("expected the fill on top with paint-order=\"stroke fill\", got %v", got)
+	}
+	if got := reversed.RGBAAt(50, 17); got.B < 200 {
+		t.Errorf("expected the outer half of the stroke to remain visible, got %v", got)
+	}
+
+	if !strokeBeforeFill("markers stroke") || strokeBeforeFill("normal") || strokeBeforeFill("fill stroke") {
+		t.Error("unexpected paint-order keyword parsing")
+	}
+}
+
+// TestBringToFrontRenderOrder 测试重排后重叠区域的颜色 / Test which color wins an overlap after reordering
+func TestBringToFrontRenderOrder(t *testing.T) {
+	doc := types.NewDocument(30, 30)
+	doc.SetViewBox(0, 0, 30, 30)
+	colors := []string{"#ff0000", "#00ff00", "#0000ff"}
+	rects := make([]*elements.Rect, len(colors))
+	for i, fill := range colors {
+		rects[i] = elements.NewRect(float64(i*5), float64(i*5), 20, 20)
+		rects[i].SetAttribute("fill", fill)
+		doc.AppendElement(rects[i])
+	}
+
+	render := func() color.RGBA {
+		img, err := NewImageRenderer().Render(doc, 30, 30)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return img.RGBAAt(15, 15)
+	}
+
+	if got := render(); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected the last rect on top, got %v", got)
+	}
+	doc.BringToFront(rects[0])
+	if got := render(); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the first rect on top after BringToFront, got %v", got)
+	}
+}
+
+// TestElementStyle 测试通过Style修改的属性写回style并参与渲染 / Test that changes through Style reach the style attribute and the render
+func TestElementStyle(t *testing.T) {
+	doc := types.NewDocument(40, 40)
+	doc.SetViewBox(0, 0, 40, 40)
+	rect := elements.NewRect(10, 10, 20, 20)
+	rect.SetAttribute("fill", "#000000")
+	style := rect.Style()
+	style.SetFill(color.RGBA{255, 0, 0, 255})
+	style.SetStroke(color.RGBA{0, 0, 255, 255})
+	style.SetStrokeWidth(4)
+	doc.AppendElement(rect)
+
+	want := "fill: #ff0000; stroke: #0000ff; stroke-width: 4.000000"
+	if got, _ := rect.GetAttribute("style"); got != want {
+		t.Errorf("expected style %q, got %q", want, got)
+	}
+	if got, _ := rect.GetStyle().Get("stroke-width"); got != "4.000000" {
+		t.Errorf("expected GetStyle to read stroke-width back, got %q", got)
+	}
+
+	img, err := NewImageRenderer().Render(doc, 40, 40)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := img.RGBAAt(20, 20); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the style fill to override the attribute, got %v", got)
+	}
+	if got := img.RGBAAt(20, 10); got.B < 200 {
+		t.Errorf("expected the style stroke outside the edge, got %v", got)
+	}
+
+	rect.SetStyle(attributes.NewStyle())
+	if _, ok := rect.GetAttribute("style"); ok {
+		t.Error("expected an empty style to remove the attribute")
+	}
+}
+
+// TestRectStrokeJoins 测试粗描边矩形的尖角连接 / Test miter joins on a thickly stroked rect
+func TestRectStrokeJoins(t *testing.T) {
+	render := func(miterLimit string) *image.RGBA {
+		doc := types.NewDocument(100, 100)
+		doc.SetViewBox(0, 0, 100, 100)
+		rect := elements.NewRect(20, 20, 60, 60)
+		rect.SetAttribute("fill", "none")
+		rect.SetAttribute("stroke", "#0000ff")
+		rect.SetAttribute("stroke-width", "10")
+		if miterLimit != "" {
+			rect.SetAttribute("stroke-miterlimit", miterLimit)
+		}
+		doc.AppendElement(rect)
+
+		img, err := NewImageRenderer().Render(doc, 100, 100)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return img
+	}
+
+	img := render("")
+	// 外角像素被完整覆盖，且尖角不超出描边外缘 / The outer corner pixel is fully covered and the miter stops at the stroke's outer edge
+	for _, p := range [][2]int{{15, 15}, {84, 15}, {84, 84}, {15, 84}} {
+		if got := img.RGBAAt(p[0], p[1]); got.A != 255 || got.B != 255 {
+			t.Errorf("expected a filled corner at %v, got %v", p, got)
+		}
+	}
+	for _, p := range [][2]int{{14, 14}, {85, 14}, {85, 85}, {14, 85}} {
+		if got := img.RGBAAt(p[0], p[1]); got.A != 0 {
+			t.Errorf("expected no overshoot at %v, got %v", p, got)
+		}
+	}
+	if got := img.RGBAAt(50, 50); got.A != 0 {
+		t.Errorf("expected an unfilled interior, got %v", got)
+	}
+
+	// 尖角比例sqrt(2)超过限制1时回退为斜角 / A miter ratio of sqrt(2) above a limit of 1 falls back to a bevel
+	if got := render("1").RGBAAt(15, 15); got.A != 0 {
+		t.Errorf("expected a beveled corner with stroke-miterlimit=1, got %v", got)
+	}
+}
+
+// TestVisibilityAndDisplay 测试隐藏元素不被绘制 / Test that hidden elements are not painted
+func TestVisibilityAndDisplay(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20" viewBox="0 0 40 20">
+		<rect x="0" y="0" width="10" height="10" fill="#ff0000" visibility="hidden"/>
+		<rect x="10" y="0" width="10" height="10" fill="#00ff00"/>
+		<rect x="20" y="0" width="10" height="10" fill="#0000ff" style="display: none"/>
+		<g visibility="hidden">
+			<rect x="0" y="10" width="10" height="10" fill="#ff0000"/>
+			<rect x="10" y="10" width="10" height="10" fill="#00ff00" visibility="visible"/>
+		</g>
+		<g display="none">
+			<rect x="20" y="10" width="10" height="10" fill="#0000ff" visibility="visible"/>
+		</g>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 40, 20)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	for _, p := range [][2]int{{5, 5}, {25, 5}, {5, 15}, {25, 15}} {
+		if got := img.RGBAAt(p[0], p[1]); got.A != 0 {
+			t.Errorf("expected nothing painted at %v, got %v", p, got)
+		}
+	}
+	for _, p := range [][2]int{{15, 5}, {15, 15}} {
+		if got := img.RGBAAt(p[0], p[1]); got != (color.RGBA{0, 255, 0, 255}) {
+			t.Errorf("expected the visible sibling at %v, got %v", p, got)
+		}
+	}
+}
+
+// TestCurrentColor 测试currentColor取自继承的color属性 / Test currentColor resolves to the inherited color property
+func TestCurrentColor(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="30" height="10" viewBox="0 0 30 10">
+		<defs>
+			<linearGradient id="g">
+				<stop offset="0" stop-color="currentColor"/>
+				<stop offset="1" stop-color="currentColor"/>
+			</linearGradient>
+		</defs>
+		<g color="blue">
+			<rect x="0" y="0" width="10" height="10" fill="currentColor"/>
+			<rect x="10" y="0" width="10" height="10" fill="url(#g)"/>
+		</g>
+		<rect x="20" y="0" width="10" height="10" fill="currentColor"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 30, 10)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	blue := color.RGBA{0, 0, 255, 255}
+	for _, p := range [][2]int{{5, 5}, {15, 5}} {
+		if got := img.RGBAAt(p[0], p[1]); got != blue {
+			t.Errorf("expected inherited blue at %v, got %v", p, got)
+		}
+	}
+	if got := img.RGBAAt(25, 5); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("expected currentColor to default to black, got %v", got)
+	}
+}
+
+// TestMetadataIgnored 测试title/desc/metadata不影响渲染且标题可读取 / Test title/desc/metadata do not affect rendering and the title is readable
+func TestMetadataIgnored(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="10" height="10" viewBox="0 0 10 10">
+		<title>Status badge</title>
+		<desc>A green square</desc>
+		<metadata><rdf>ignored</rdf></metadata>
+		<g><title>Square</title><rect width="10" height="10" fill="#00ff00"/></g>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	if got := doc.GetTitle(); got != "Status badge" {
+		t.Errorf("expected title %q, got %q", "Status badge", got)
+	}
+	if got := doc.GetDesc(); got != "A green square" {
+		t.Errorf("expected desc %q, got %q", "A green square", got)
+	}
+
+	img, err := NewImageRenderer().Render(doc, 10, 10)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := img.RGBAAt(5, 5); got != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("expected the square to render, got %v", got)
+	}
+}
+
+// TestUnsupportedElement 测试未知元素被跳过并记录，严格模式下返回错误 / Test unknown elements are skipped and recorded, or fail in strict mode
+func TestUnsupportedElement(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="10" viewBox="0 0 20 10">
+		<rect x="0" y="0" width="10" height="10" fill="#ff0000"/>
+		<foreignObject x="0" y="0" width="20" height="10"/>
+		<rect x="10" y="0" width="10" height="10" fill="#00ff00"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+
+	r := NewImageRenderer()
+	img, err := r.Render(doc, 20, 10)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := img.RGBAAt(5, 5); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the first rect, got %v", got)
+	}
+	if got := img.RGBAAt(15, 5); got != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("expected the rect after the unknown element, got %v", got)
+	}
+	if warnings := r.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "foreignObject") {
+		t.Errorf("expected one warning about foreignObject, got %v", warnings)
+	}
+
+	r.StrictMode = true
+	if _, err := r.Render(doc, 20, 10); err == nil {
+		t.Error("expected an error in strict mode")
+	}
+}
+
+// TestThickPolylineStroke 测试粗折线的描边带内部没有缝隙 / Test the band of a thick polyline stroke has no gaps
+func TestThickPolylineStroke(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="50" height="50" viewBox="0 0 50 50">
+		<polyline points="5,10 40,10 40,45" fill="none" stroke="#000000" stroke-width="8"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+
+	for _, crisp := range []bool{false, true} {
+		r := NewImageRenderer()
+		r.CrispEdges = crisp
+		img, err := r.Render(doc, 50, 50)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		// 带从中心线向两侧各延伸4像素，检查不受边缘抗锯齿影响的内部 / The band spans 4px either side of the centre line; check the interior clear of edge anti-aliasing
+		for x := 6; x < 39; x++ {
+			for y := 7; y <= 12; y++ {
+				if a := img.RGBAAt(x, y).A; a != 255 {
+					t.Fatalf("crisp=%v: expected an opaque horizontal band at (%d,%d), got alpha %d", crisp, x, y, a)
+				}
+			}
+		}
+		for y := 11; y < 44; y++ {
+			for x := 37; x <= 42; x++ {
+				if a := img.RGBAAt(x, y).A; a != 255 {
+					t.Fatalf("crisp=%v: expected an opaque vertical band at (%d,%d), got alpha %d", crisp, x, y, a)
+				}
+			}
+		}
+		if a := img.RGBAAt(20, 20).A; a != 0 {
+			t.Errorf("crisp=%v: expected nothing inside the corner, got alpha %d", crisp, a)
+		}
+	}
+}
+
+// TestRenderRegion 测试区域渲染与完整渲染的对应子矩形逐像素相同 / Test a region render matches the corresponding sub-rectangle of a full render pixel for pixel
+func TestRenderRegion(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50" viewBox="0 0 100 50">
+		<defs>
+			<linearGradient id="fade"><stop offset="0" stop-color="#ff0000"/><stop offset="1" stop-color="#0000ff"/></linearGradient>
+		</defs>
+		<rect x="5" y="5" width="40" height="30" fill="url(#fade)"/>
+		<circle cx="60" cy="25" r="15" fill="#00ff00" stroke="#000000" stroke-width="2"/>
+		<path d="M 10 45 C 30 20 60 60 95 10" fill="none" stroke="#000000" stroke-width="3"/>
+		<polygon points="70,5 95,5 85,20" stroke="#0000ff" stroke-width="2"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+
+	// 第二种情况的缩放为0.75倍且区域偏移为奇数 / The second case scales by 0.75 with odd region offsets
+	for _, tc := range []struct {
+		width, height int
+		region        image.Rectangle
+	}{
+		{200, 100, image.Rect(30, 20, 150, 90)},
+		{150, 75, image.Rect(31, 17, 140, 70)},
+	} {
+		full, err := NewImageRenderer().Render(doc, tc.width, tc.height)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		region := tc.region
+		crop, err := NewImageRenderer().RenderRegion(doc, tc.width, tc.height, region)
+		if err != nil {
+			t.Fatalf("RenderRegion failed: %v", err)
+		}
+		if crop.Bounds() != image.Rect(0, 0, region.Dx(), region.Dy()) {
+			t.Fatalf("expected a %dx%d image, got %v", region.Dx(), region.Dy(), crop.Bounds())
+		}
+
+		for y := 0; y < region.Dy(); y++ {
+			for x := 0; x < region.Dx(); x++ {
+				if got, want := crop.RGBAAt(x, y), full.RGBAAt(region.Min.X+x, region.Min.Y+y); got != want {
+					t.Fatalf("%dx%d pixel (%d,%d): expected %v, got %v", tc.width, tc.height, x, y, want, got)
+				}
+			}
+		}
+	}
+
+	if _, err := NewImageRenderer().RenderRegion(doc, 200, 100, image.Rect(300, 0, 400, 10)); err == nil {
+		t.Error("expected an error for a region outside the canvas")
+	}
+}
+
+// TestNonScalingStroke 测试non-scaling-stroke在缩放渲染时保持描边像素宽度 / Test non-scaling-stroke keeps the stroke's pixel width when rendering scaled
+func TestNonScalingStroke(t *testing.T) {
+	// bandWidth 返回中间列中被描边覆盖过半的像素数 / Return how many pixels in the middle column are more than half covered
+	bandWidth := func(vectorEffect string, scale int) int {
+		doc := types.NewDocument(40, 20)
+		doc.SetViewBox(0, 0, 40, 20)
+		line := elements.NewPath("M 0 10 L 40 10")
+		line.SetAttribute("fill", "none")
+		line.SetAttribute("stroke", "#000000")
+		line.SetAttribute("stroke-width", "2")
+		if vectorEffect != "" {
+			line.SetAttribute("vector-effect", vectorEffect)
+		}
+		doc.AppendElement(line)
+
+		img, err := NewImageRenderer().Render(doc, 40*scale, 20*scale)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		count := 0
+		for y := 0; y < img.Bounds().Dy(); y++ {
+			if img.RGBAAt(img.Bounds().Dx()/2, y).A > 128 {
+				count++
+			}
+		}
+		return count
+	}
+
+	if got := bandWidth("", 3); got != 6 {
+		t.Errorf("expected a scaled stroke 6px wide, got %d", got)
+	}
+	for _, scale := range []int{1, 3} {
+		if got := bandWidth("non-scaling-stroke", scale); got != 2 {
+			t.Errorf("expected a 2px stroke at %dx, got %d", scale, got)
+		}
+	}
+}
+
+// TestSetTextRenderer 测试不同渲染器使用各自注入的文本渲染器 / Test renderers use their own injected text renderers
+func TestSetTextRenderer(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("write font: %v", err)
+	}
+	// 仅一个文本渲染器为该字体族加载了Go字体，另一个回退到位图字体
+	// Only one text renderer loads the Go font for the family; the other falls back to the bitmap font
+	scalable := font.NewSVGTextRenderer()
+	if err := scalable.LoadFontFromFile(fontPath, "Injected", 20); err != nil {
+		t.Fatalf("load font: %v", err)
+	}
+
+	doc := types.NewDocument(120, 40)
+	doc.SetViewBox(0, 0, 120, 40)
+	text := elements.NewText(5, 30, "HH")
+	text.SetAttribute("font-family", "Injected")
+	text.SetAttribute("font-size", "20")
+	text.SetAttribute("font-weight", "normal")
+	doc.AppendElement(text)
+
+	render := func(textRenderer font.TextRenderer) (top, bottom int) {
+		renderer := NewImageRenderer()
+		renderer.SetTextRenderer(textRenderer)
+		img, err := renderer.Render(doc, 120, 40)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return inkRows(img)
+	}
+
+	top, bottom := render(scalable)
+	fallbackTop, fallbackBottom := render(font.NewSVGTextRenderer())
+	if top < 0 || fallbackTop < 0 {
+		t.Fatalf("expected both renders to draw text, got rows %d and %d", top, fallbackTop)
+	}
+	// 20px的Go字体比13px高的位图字体更高 / The 20px Go font is taller than the 13px-high bitmap font
+	if bottom-top <= fallbackBottom-fallbackTop {
+		t.Errorf("expected the injected scalable font to be taller than the fallback, got %d vs %d rows", bottom-top, fallbackBottom-fallbackTop)
+	}
+}
+
+// TestClosedPathStroke 测试Z闭合的三角形描边只闭合一次，起点与其他顶点同样生成连接
+// TestClosedPathStroke tests that a Z-closed triangle is stroked closed exactly once, joining the start vertex like the others
+func TestClosedPathStroke(t *testing.T) {
+	// 显式回到起点的闭合点不应产生重复的闭合边 / An explicit return to the start must not produce a second closing edge
+	triangle := []types.Point{{X: 20, Y: 80}, {X: 80, Y: 80}, {X: 50, Y: 20}}
+	generator := NewTrueStrokePathGenerator()
+	outline := generator.GenerateStrokePath(triangle, 8, true)
+	withReturn := generator.GenerateStrokePath(append(append([]types.Point(nil), triangle...), triangle[0]), 8, true)
+	if len(outline) != len(withReturn) {
+		t.Errorf("expected the same outline with and without a repeated start point, got %d and %d points", len(outline), len(withReturn))
+	}
+	for i := 1; i < len(outline); i++ {
+		if outline[i] == outline[i-1] {
+			t.Errorf("expected no doubled vertex in the outline, found %v at %d", outline[i], i)
+		}
+	}
+
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<path d="M 20 80 L 80 80 L 50 20 Z" fill="none" stroke="#000000" stroke-width="8"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 100, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 三角形关于x=50对称，起点角应与其镜像角一致 / The triangle is symmetric about x=50, so the start corner must mirror its partner
+	for y := 72; y < 88; y++ {
+		for x := 10; x < 26; x++ {
+			start, mirror := img.RGBAAt(x, y).A, img.RGBAAt(99-x, y).A
+			if math.Abs(float64(start)-float64(mirror)) > 16 {
+				t.Fatalf("expected the start corner to match its mirror at (%d,%d), got alpha %d vs %d", x, y, start, mirror)
+			}
+		}
+	}
+}
+
+// TestRenderAnimationToTIFF 测试多页TIFF每帧一页且各页可解码 / Test the multi-page TIFF has one decodable page per frame
+func TestRenderAnimationToTIFF(t *testing.T) {
+	frame := func(fill string) *types.Document {
+		doc := types.NewDocument(20, 20)
+		doc.SetViewBox(0, 0, 20, 20)
+		rect := elements.NewRect(0, 0, 20, 20)
+		rect.SetAttribute("fill", fill)
+		doc.AppendElement(rect)
+		return doc
+	}
+	filename := filepath.Join(t.TempDir(), "frames.tiff")
+	if err := RenderAnimationToTIFF([]*types.Document{frame("#ff0000"), frame("#0000ff")}, 20, 20, filename); err != nil {
+		t.Fatalf("RenderAnimationToTIFF failed: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("reading the TIFF failed: %v", err)
+	}
+
+	// 沿IFD链收集各页偏移 / Follow the IFD chain to collect page offsets
+	var ifds []uint32
+	for off := binary.LittleEndian.Uint32(data[4:]); off != 0 && len(ifds) < 10; {
+		ifds = append(ifds, off)
+		count := uint32(binary.LittleEndian.Uint16(data[off:]))
+		off = binary.LittleEndian.Uint32(data[off+2+count*12:])
+	}
+	if len(ifds) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(ifds))
+	}
+
+	want := []color.RGBA{{255, 0, 0, 255}, {0, 0, 255, 255}}
+	for i, off := range ifds {
+		// 让文件头指向该页后用标准解码器读取 / Point the header at the page and read it with the standard decoder
+		page := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint32(page[4:], off)
+		img, err := tiff.Decode(bytes.NewReader(page))
+		if err != nil {
+			t.Fatalf("decoding page %d failed: %v", i, err)
+		}
+		if got := color.RGBAModel.Convert(img.At(10, 10)); got != want[i] {
+			t.Errorf("page %d: expected %v, got %v", i, want[i], got)
+		}
+	}
+}
+
+// TestConvertTextToOutlines 测试文本替换为路径后渲染结果近似一致 / Test text replaced by paths renders about the same
+func TestConvertTextToOutlines(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("write font: %v", err)
+	}
+	textRenderer := font.NewSVGTextRenderer()
+	if err := textRenderer.LoadFontFromFile(fontPath, "GoOutlineTest", 60); err != nil {
+		t.Fatalf("load font: %v", err)
+	}
+	renderer := NewImageRenderer()
+	renderer.SetTextRenderer(textRenderer)
+
+	doc := types.NewDocument(200, 100)
+	doc.SetViewBox(0, 0, 200, 100)
+	group := elements.NewGroup()
+	text := elements.NewText(100, 70, "HI")
+	text.SetAttribute("font-family", "GoOutlineTest")
+	text.SetAttribute("font-size", "60")
+	text.SetAttribute("text-anchor", "middle")
+	text.SetAttribute("fill", "#ff0000")
+	group.AppendChild(text)
+	// 位图字体没有轮廓，该文本保持原样并产生警告 / The bitmap font has no outlines, so this text is kept with a warning
+	bitmap := elements.NewText(5, 95, "kept")
+	bitmap.SetAttribute("font-family", "NoSuchOutlineFont")
+	group.AppendChild(bitmap)
+	doc.AppendElement(group)
+
+	before, err := renderer.Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if err := renderer.ConvertTextToOutlines(doc); err != nil {
+		t.Fatalf("ConvertTextToOutlines failed: %v", err)
+	}
+
+	children := group.Children()
+	if len(children) != 2 || children[0].Tag() != "path" || children[1].Tag() != "text" {
+		t.Fatalf("expected the outline text to become a path and the bitmap text to stay, got %v", children)
+	}
+	if warnings := renderer.Warnings(); len(warnings) != 1 {
+		t.Errorf("expected one warning for the kept text, got %v", warnings)
+	}
+	if renderer.doc != nil {
+		t.Error("expected the document reference to be cleared")
+	}
+	if fill, _ := children[0].GetAttribute("fill"); fill != "#ff0000" {
+		t.Errorf("expected the fill to carry over, got %q", fill)
+	}
+	if _, ok := children[0].GetAttribute("font-family"); ok {
+		t.Error("expected text-only attributes to be dropped")
+	}
+
+	after, err := renderer.Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 比较两次渲染的覆盖区域 / Compare the coverage of both renders
+	var both, either int
+	for i := 3; i < len(before.Pix); i += 4 {
+		a, b := before.Pix[i] > 127, after.Pix[i] > 127
+		if a && b {
+			both++
+		}
+		if a || b {
+			either++
+		}
+	}
+	if either == 0 || float64(both)/float64(either) < 0.85 {
+		t.Errorf("expected the outlines to cover the same pixels as the text, overlap %d of %d", both, either)
+	}
+}
+
+// TestDashPathLength 测试pathLength按逻辑长度缩放虚线 / Test pathLength scales dashes by the logical length
+func TestDashPathLength(t *testing.T) {
+	// 几何长度200，逻辑长度100，"50 50"将路径分为两半 / Geometric length 200 with logical length 100, so "50 50" splits the path in halves
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="20" viewBox="0 0 200 20">
+	<path d="M 0 10 L 200 10" pathLength="100" stroke="#000000" stroke-width="4" stroke-dasharray="50 50" fill="none"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 200, 20)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, x := range []int{10, 50, 90} {
+		if img.RGBAAt(x, 10).A == 0 {
+			t.Errorf("expected the first half to be stroked at x=%d", x)
+		}
+	}
+	for _, x := range []int{110, 150, 190} {
+		if a := img.RGBAAt(x, 10).A; a != 0 {
+			t.Errorf("expected the second half to be a gap at x=%d, got alpha %d", x, a)
+		}
+	}
+}
+
+// TestDashIgnoresPrecision 测试虚线切分不受序列化精度影响 / Test dashing is not affected by the serialization precision
+func TestDashIgnoresPrecision(t *testing.T) {
+	defer func(old int) { path.Precision = old }(path.Precision)
+	path.Precision = 0
+
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="300" height="200" viewBox="0 0 30 20">
+	<path d="M 0.4 10 L 20.4 10" stroke="#000000" stroke-width="4" stroke-dasharray="10 10" fill="none"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 300, 200)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if a := img.RGBAAt(1, 100).A; a != 0 {
+		t.Errorf("expected nothing before the dash starts at x=0.4, got alpha %d", a)
+	}
+	if a := img.RGBAAt(60, 100).A; a == 0 {
+		t.Error("expected the first dash to be stroked")
+	}
+}
+
+// TestParseColorShorthandAlpha 测试渲染器解析带透明度的四位十六进制简写 / Test the renderer parses the four-digit hex shorthand with alpha
+func TestParseColorShorthandAlpha(t *testing.T) {
+	if got, want := parseColor("#f008", color.RGBA{}), (color.RGBA{255, 0, 0, 136}); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestFunctionalColorFill 测试rgba()填充保留其颜色和透明度，无法解析的填充不绘制
+// TestFunctionalColorFill tests an rgba() fill keeps its color and alpha and an unparsable fill paints nothing
+func TestFunctionalColorFill(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20" viewBox="0 0 40 20">
+		<rect x="0" y="0" width="20" height="20" fill="rgba(255,0,0,0.5)"/>
+		<rect x="20" y="0" width="20" height="20" fill="hsl(nonsense)"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 40, 20)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if c := img.RGBAAt(10, 10); c.R != 255 || c.G != 0 || c.B != 0 || c.A < 120 || c.A > 135 {
+		t.Errorf("expected a half-transparent red fill, got %v", c)
+	}
+	if c := img.RGBAAt(30, 10); c.A != 0 {
+		t.Errorf("expected an unparsable fill to paint nothing, got %v", c)
+	}
+}
+
+// TestInheritKeyword 测试inherit关键字取父元素的计算值 / Test the inherit keyword takes the parent's computed value
+func TestInheritKeyword(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="60" height="20" viewBox="0 0 60 20">
+		<g fill="green" stroke-width="4">
+			<rect x="0" y="0" width="20" height="20" fill="inherit"/>
+			<g fill="#0000ff">
+				<rect x="20" y="0" width="20" height="20" style="fill: inherit"/>
+			</g>
+		</g>
+		<rect x="40" y="0" width="20" height="20" fill="inherit"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 60, 20)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if got, want := img.RGBAAt(10, 10), (color.RGBA{0, 128, 0, 255}); got != want {
+		t.Errorf("expected the child to inherit green, got %v", got)
+	}
+	if got, want := img.RGBAAt(30, 10), (color.RGBA{0, 0, 255, 255}); got != want {
+		t.Errorf("expected the nearest group's blue through style, got %v", got)
+	}
+	// 没有父值时使用初始值黑色 / Without a parent value the initial black is used
+	if got, want := img.RGBAAt(50, 10), (color.RGBA{0, 0, 0, 255}); got != want {
+		t.Errorf("expected the initial black fill, got %v", got)
+	}
+}
+
+// TestFlatnessSegments 测试展平容差控制路径生成的线段数，并随渲染缩放 / Test the flattening tolerance controls the segments generated for a path and scales with the render
+func TestFlatnessSegments(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+		<path d="M 10 50 C 10 0 90 0 90 50 S 10 100 10 50 Z" fill="#000000"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+
+	var segments int
+	flattenHook = func(n int) { segments += n }
+	defer func() { flattenHook = nil }()
+	count := func(flatness float64, size int) int {
+		segments = 0
+		r := NewImageRenderer()
+		r.SetFlatness(flatness)
+		if _, err := r.Render(doc, size, size); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return segments
+	}
+
+	coarse, fine := count(2, 100), count(0.2, 100)
+	if coarse == 0 || fine <= coarse {
+		t.Errorf("expected a finer tolerance to generate more segments, got %d at 2px and %d at 0.2px", coarse, fine)
+	}
+	// 容差以设备像素计，放大渲染需要更多线段 / The tolerance is in device pixels, so a larger render needs more segments
+	if zoomed := count(2, 200); zoomed <= coarse {
+		t.Errorf("expected a 2x render to generate more segments at the same tolerance, got %d and %d", coarse, zoomed)
+	}
+}
+
+// TestCullingNestedTransform 测试剔除计入子元素的transform / Test culling honors transforms on descendants
+func TestCullingNestedTransform(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+		<g><g transform="translate(150,150)"><rect x="-100" y="-100" width="20" height="20" fill="red"/></g></g>
+		<g><rect x="200" y="200" width="20" height="20" fill="blue"/></g>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 100, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if c := img.RGBAAt(60, 60); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the translated rect to be drawn, got %v", c)
+	}
+
+	viewport := types.Rect{W: 100, H: 100}
+	if !elementVisible(doc.Elements[0], viewport, 1, 1) {
+		t.Error("expected the group with a translated child to be visible")
+	}
+	if elementVisible(doc.Elements[1], viewport, 1, 1) {
+		t.Error("expected the group outside the viewport to be culled")
+	}
+}
+
+// TestCullingUnitLengths 测试剔除按单位换算坐标 / Test culling resolves coordinates with units
+func TestCullingUnitLengths(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100" viewBox="200 0 200 100">
+		<rect id="box" x="0" y="0" width="20" height="20" fill="#ff0000"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	doc.FindElementByID("box").SetAttribute("x", "2.5in")
+	img, err := NewImageRenderer().Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 2.5in为240个用户单位 / 2.5in is 240 user units
+	if c := img.RGBAAt(50, 10); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the rect at 2.5in to be drawn, got %v", c)
+	}
+}
+
+// TestTranslucentFill 测试半透明填充按source-over与背景混合，与描边结果一致
+// TestTranslucentFill tests translucent fills composite source-over onto the background, matching strokes
+func TestTranslucentFill(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="60" height="20" viewBox="0 0 60 20">
+		<rect x="0" y="0" width="40" height="20" fill="#0000ff"/>
+		<circle cx="10" cy="10" r="8" fill="#f008"/>
+		<rect x="20" y="0" width="20" height="20" fill="#f008"/>
+		<circle cx="50" cy="10" r="8" fill="#f008"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	img, err := NewImageRenderer().Render(doc, 60, 20)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 136/255的红色覆盖在蓝色上 / Red at 136/255 over blue
+	want := color.RGBA{136, 0, 119, 255}
+	for _, p := range []image.Point{{10, 10}, {30, 10}} {
+		if c := img.RGBAAt(p.X, p.Y); math.Abs(float64(c.R)-float64(want.R)) > 1 || c.G != 0 || math.Abs(float64(c.B)-float64(want.B)) > 1 || c.A != 255 {
+			t.Errorf("at %v: expected about %v, got %v", p, want, c)
+		}
+	}
+	// 透明背景上保留非预乘的颜色和透明度 / Over a transparent background the straight color and alpha are kept
+	if c := img.RGBAAt(50, 10); c != (color.RGBA{255, 0, 0, 136}) {
+		t.Errorf("expected {255 0 0 136} over transparency, got %v", c)
+	}
+}
@@ -0,0 +1,104 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/hoonfeng/svg/font"
+	"github.com/hoonfeng/svg/parser"
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// recordingTarget 记录绘制调用的目标 / A target that records its draw calls
+type recordingTarget struct {
+	calls  []string
+	images []image.Rectangle
+}
+
+func (t *recordingTarget) FillPolygon(subPaths [][]types.Point, c color.RGBA, rule path.FillRule) {
+	t.calls = append(t.calls, fmt.Sprintf("fill %d %v", len(subPaths), c))
+}
+
+func (t *recordingTarget) StrokePolyline(points []types.Point, closed bool, c color.RGBA, style StrokeStyle) {
+	t.calls = append(t.calls, fmt.Sprintf("stroke %t %v %.0f", closed, c, style.Width))
+}
+
+func (t *recordingTarget) DrawImage(src image.Image, dst image.Rectangle) {
+	t.calls = append(t.calls, fmt.Sprintf("image %v", dst))
+	t.images = append(t.images, dst)
+}
+
+func (t *recordingTarget) DrawText(text string, x, y float64, style *font.TextStyle) error {
+	t.calls = append(t.calls, fmt.Sprintf("text %q %.0f %.0f", text, x, y))
+	return nil
+}
+
+func (t *recordingTarget) SetClip(clip image.Rectangle) {
+	t.calls = append(t.calls, fmt.Sprintf("clip %v", clip))
+}
+
+// TestRenderToTarget 测试元素渲染通过绘制目标输出 / Test element rendering goes through the draw target
+func TestRenderToTarget(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<rect x="10" y="10" width="20" height="20" fill="#ff0000"/>
+	<path d="M 40 40 L 80 40 L 80 80 Z" fill="#00ff00" stroke="#0000ff" stroke-width="2"/>
+	<text x="10" y="90" fill="#000000">hi</text>
+</svg>`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	target := &recordingTarget{}
+	if err := NewImageRenderer().RenderToTarget(target, doc, 200, 200); err != nil {
+		t.Fatalf("RenderToTarget failed: %v", err)
+	}
+	want := []string{
+		"fill 1 {255 0 0 255}",
+		"fill 1 {0 255 0 255}",
+		"stroke true {0 0 255 255} 4",
+		`text "hi" 20 180`,
+	}
+	if !reflect.DeepEqual(target.calls, want) {
+		t.Errorf("expected calls %q, got %q", want, target.calls)
+	}
+}
+
+// TestTransformedTextLayer 测试旋转文本的图层只覆盖文本附近而非整幅画布 / Test the layer of rotated text only covers the text's surroundings rather than the whole canvas
+func TestTransformedTextLayer(t *testing.T) {
+	doc, err := parser.NewXMLParser().ParseString(`<svg xmlns="http://www.w3.org/2000/svg" width="400" height="400" viewBox="0 0 400 400">
+	<text x="200" y="200" font-size="10" fill="#000000" transform="rotate(30 200 200)">hi</text>
+</svg>`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	target := &recordingTarget{}
+	if err := NewImageRenderer().RenderToTarget(target, doc, 400, 400); err != nil {
+		t.Fatalf("RenderToTarget failed: %v", err)
+	}
+	if len(target.images) != 1 {
+		t.Fatalf("expected the rotated text as one image, got calls %q", target.calls)
+	}
+	if area := target.images[0]; area.Dx() > 100 || area.Dy() > 100 || !area.In(image.Rect(0, 0, 400, 400)) || !image.Pt(200, 200).In(area) {
+		t.Errorf("expected a small layer around (200,200), got %v", area)
+	}
+}
+
+// TestImageTargetClip 测试图像目标的裁剪矩形 / Test the image target's clip rectangle
+func TestImageTargetClip(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	target := NewImageRenderer().target(img)
+	target.SetClip(image.Rect(0, 0, 10, 20))
+	target.FillPolygon([][]types.Point{devicePolygon(0, 0, 20, 20)}, color.RGBA{255, 0, 0, 255}, path.FillRuleNonZero)
+
+	if got := img.RGBAAt(5, 10); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the clipped area to be filled, got %v", got)
+	}
+	if got := img.RGBAAt(15, 10); got.A != 0 {
+		t.Errorf("expected nothing outside the clip, got %v", got)
+	}
+}
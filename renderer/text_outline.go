@@ -0,0 +1,93 @@
+package renderer
+
+import (
+	"fmt"
+
+	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/font"
+	"github.com/hoonfeng/svg/types"
+)
+
+// outlineTextRenderer 能将文本转换为字形轮廓路径的文本渲染器 / A text renderer that can convert text into glyph outline paths
+type outlineTextRenderer interface {
+	TextToPath(text string, x, y float64, style *font.TextStyle) (string, error)
+}
+
+// textOnlyAttributes 只对文本有意义、转换为路径时丢弃的属性 / Attributes that only apply to text and are dropped when converting to a path
+var textOnlyAttributes = map[string]bool{
+	"x": true, "y": true, "dx": true, "dy": true,
+	"font-family": true, "font-size": true, "font-weight": true, "font-style": true,
+	"text-anchor": true, "alignment-baseline": true, "dominant-baseline": true,
+	"textLength": true, "lengthAdjust": true, "font-kerning": true, "kerning": true,
+	"letter-spacing": true, "word-spacing": true,
+}
+
+// ConvertTextToOutlines 将文档中的文本元素替换为等效的路径元素，输出不再依赖系统字体
+// ConvertTextToOutlines replaces the document's text elements with equivalent path elements so the output no longer depends on system fonts
+//
+// 字体按渲染时的规则解析，填充、描边、变换等表现属性保留在路径上；含textPath的文本保持不变。
+// 无法转换的文本（如字体没有轮廓）保持原样并记入Warnings，只有文本渲染器不支持轮廓时返回错误
+// Fonts resolve as they do when rendering, and presentation attributes such as fill, stroke and transform carry over to the path; text with textPath children is left as is.
+// Text that cannot be converted, e.g. because its font has no outlines, is kept and noted in Warnings; an error is returned only when the text renderer cannot produce outlines
+func (r *ImageRenderer) ConvertTextToOutlines(doc *types.Document) error {
+	outliner, ok := r.text().(outlineTextRenderer)
+	if !ok {
+		return fmt.Errorf("文本渲染器不支持轮廓转换 / The text renderer cannot convert text to outlines")
+	}
+	r.doc = doc
+	defer func() { r.doc = nil }()
+	r.warnings = nil
+	r.convertTextElements(doc.Elements, outliner)
+	return nil
+}
+
+// convertTextElements 原地替换元素列表中的文本，并递归处理子元素 / Replace text in an element list in place, recursing into children
+func (r *ImageRenderer) convertTextElements(list []types.Element, outliner outlineTextRenderer) {
+	for i, element := range list {
+		if element.Tag() != "text" {
+			r.convertTextElements(element.Children(), outliner)
+			continue
+		}
+		path, err := r.textOutline(element, outliner)
+		if err != nil {
+			r.warnings = append(r.warnings, fmt.Sprintf("text kept as is: %v", err))
+			continue
+		}
+		if path != nil {
+			if child, ok := element.(interface{ Parent() types.Element }); ok {
+				path.SetParent(child.Parent())
+			}
+			list[i] = path
+		}
+	}
+}
+
+// textOutline 将单个文本元素转换为路径，无法转换时返回nil / Convert a single text element to a path, or nil when it cannot be converted
+func (r *ImageRenderer) textOutline(element types.Element, outliner outlineTextRenderer) (*elements.Path, error) {
+	for _, child := range element.Children() {
+		if child.Tag() == "textPath" {
+			return nil, nil
+		}
+	}
+	content, ok := element.(interface{ GetContent() string })
+	if !ok {
+		return nil, nil
+	}
+
+	// 在用户空间中排版，路径坐标与原文本一致 / Lay out in user space so the path coordinates match the original text
+	attrs := styledAttributes(element)
+	x, _ := parseFloat(attrs["x"], 0)
+	y, _ := parseFloat(attrs["y"], 0)
+	d, err := outliner.TextToPath(content.GetContent(), x, y, r.createTextStyleFromAttributes(attrs, 1, 1))
+	if err != nil {
+		return nil, fmt.Errorf("转换文本轮廓失败 / Failed to convert text to outlines: %v", err)
+	}
+
+	path := elements.NewPath(d)
+	for name, value := range element.GetAttributes() {
+		if !textOnlyAttributes[name] {
+			path.SetAttribute(name, value)
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,217 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hoonfeng/svg/font"
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// glyphPlacement 沿路径放置的单个字形，坐标为设备像素的基线起点 / A glyph placed along a path, positioned at its baseline origin in device pixels
+type glyphPlacement struct {
+	text  string
+	x, y  float64
+	angle float64 // 基线方向（弧度）/ Baseline direction in radians
+}
+
+// lookupTextPathTarget 按href查找textPath引用的路径元素 / Look up the path element a textPath references
+func (r *ImageRenderer) lookupTextPathTarget(href string) types.Element {
+	id := strings.TrimPrefix(strings.TrimSpace(href), "#")
+	if r.doc == nil || id == "" {
+		return nil
+	}
+	element := findDefinition(r.doc.Defs, id)
+	if element == nil {
+		element = r.doc.FindElementByID(id)
+	}
+	if element == nil || element.Tag() != "path" {
+		return nil
+	}
+	return element
+}
+
+// renderTextPath 将textPath的文本沿引用路径逐字形绘制，字形随切线旋转
+// renderTextPath draws a textPath's content glyph by glyph along the referenced path, rotating each glyph to the tangent
+//
+// attrs为继承自text元素并被textPath覆盖后的属性 / attrs are the text element's attributes overridden by the textPath's own
+func (r *ImageRenderer) renderTextPath(img *image.RGBA, textPath types.Element, attrs map[string]string, viewBox []float64, scaleX, scaleY float64) error {
+	content, ok := textPath.(interface{ GetContent() string })
+	if !ok || strings.TrimSpace(content.GetContent()) == "" {
+		return nil
+	}
+	href := attrs["href"]
+	if href == "" {
+		href = attrs["xlink:href"]
+	}
+	target := r.lookupTextPathTarget(href)
+	if target == nil {
+		return nil
+	}
+	guide, err := path.ParsePath(target.GetAttributes()["d"])
+	if err != nil {
+		return err
+	}
+
+	style := r.createTextStyleFromAttributes(attrs, scaleX, scaleY)
+	placements, err := r.placeGlyphs(guide, content.GetContent(), attrs, style, viewBox, scaleX, scaleY)
+	if err != nil {
+		return err
+	}
+
+	// 每个字形先在水平基线上绘制，再旋转合成 / Each glyph is drawn on a horizontal baseline, then rotated into place
+	style.TextAnchor = font.TextAnchorStart
+	size := int(math.Ceil(style.FontSize*3)) + 4
+	pivot := float64(size) / 2
+	for _, g := range placements {
+		glyph := image.NewRGBA(image.Rect(0, 0, size, size))
+		if err := r.text().RenderText(glyph, g.text, pivot, pivot, style); err != nil {
+			return err
+		}
+		r.drawRotated(img, glyph, pivot, pivot, g.x, g.y, g.angle, samplePremultiplied)
+	}
+	return nil
+}
+
+// placeGlyphs 按弧长计算每个字形的位置，字形中点落在路径上，超出路径的字形被丢弃
+// placeGlyphs positions each glyph by arc length with its midpoint on the path, dropping glyphs that fall off the path
+func (r *ImageRenderer) placeGlyphs(guide *path.SVGPath, text string, attrs map[string]string, style *font.TextStyle, viewBox []float64, scaleX, scaleY float64) ([]glyphPlacement, error) {
+	scale := (scaleX + scaleY) / 2
+	total := guide.TotalLength(0)
+
+	// startOffset可为用户单位或路径长度的百分比 / startOffset is in user units or a percentage of the path length
+	offset := 0.0
+	if value := strings.TrimSpace(attrs["startOffset"]); strings.HasSuffix(value, "%") {
+		percent, _ := parseFloat(strings.TrimSuffix(value, "%"), 0)
+		offset = total * percent / 100
+	} else {
+		offset, _ = parseFloat(value, 0)
+	}
+
+	runes := []rune(text)
+	advances := make([]float64, len(runes))
+	width := 0.0
+	for i, ch := range runes {
+		metrics, err := r.text().MeasureText(string(ch), style)
+		if err != nil {
+			return nil, err
+		}
+		advances[i] = metrics.Advance / scale
+		width += advances[i]
+	}
+	switch style.TextAnchor {
+	case font.TextAnchorMiddle:
+		offset -= width / 2
+	case font.TextAnchorEnd:
+		offset -= width
+	}
+
+	placements := make([]glyphPlacement, 0, len(runes))
+	for i, ch := range runes {
+		mid := offset + advances[i]/2
+		offset += advances[i]
+		if mid < 0 || mid > total || strings.TrimSpace(string(ch)) == "" {
+			continue
+		}
+		point, angle := guide.PointAtLength(mid, 0)
+		dx, dy := math.Cos(angle), math.Sin(angle)
+		half := advances[i] / 2
+		// 设备空间中的切线方向 / Tangent direction in device space
+		deviceAngle := math.Atan2(dy*scaleY, dx*scaleX)
+		placements = append(placements, glyphPlacement{
+			text:  string(ch),
+			x:     (point.X - dx*half - viewBox[0]) * scaleX,
+			y:     (point.Y - dy*half - viewBox[1]) * scaleY,
+			angle: deviceAngle,
+		})
+	}
+	return placements, nil
+}
+
+// drawRotated 将src绕(px,py)旋转angle后平移到目标的(x,y)处，用sample双线性采样并按当前混合设置合成
+// drawRotated rotates src by angle about (px,py), moves that pivot to (x,y) in dst, samples bilinearly with sample and composites with the current blending settings
+func (r *ImageRenderer) drawRotated(dst, src *image.RGBA, px, py, x, y, angle float64, sample func(src *image.RGBA, x, y float64) color.RGBA) {
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	sb := src.Bounds()
+
+	// 旋转后源图像四角的包围盒 / Bounding box of the rotated source corners
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range [][2]float64{{0, 0}, {float64(sb.Dx()), 0}, {0, float64(sb.Dy())}, {float64(sb.Dx()), float64(sb.Dy())}} {
+		ux, uy := c[0]-px, c[1]-py
+		cx, cy := x+ux*cos-uy*sin, y+ux*sin+uy*cos
+		minX, maxX = math.Min(minX, cx), math.Max(maxX, cx)
+		minY, maxY = math.Min(minY, cy), math.Max(maxY, cy)
+	}
+	area := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).Intersect(dst.Bounds())
+
+	for dy := area.Min.Y; dy < area.Max.Y; dy++ {
+		for dx := area.Min.X; dx < area.Max.X; dx++ {
+			// 反向映射像素中心到源图像 / Map the pixel center back into the source
+			ux, uy := float64(dx)+0.5-x, float64(dy)+0.5-y
+			sx := ux*cos + uy*sin + px - 0.5
+			sy := -ux*sin + uy*cos + py - 0.5
+			c := sample(src, sx, sy)
+			if c.A == 0 {
+				continue
+			}
+			bg := dst.RGBAAt(dx, dy)
+			if bg.A == 0 {
+				dst.SetRGBA(dx, dy, c)
+				continue
+			}
+			dst.SetRGBA(dx, dy, r.compositeColors(bg, color.RGBA{c.R, c.G, c.B, 255}, float64(c.A)/255))
+		}
+	}
+}
+
+// samplePremultiplied 在字体绘制的预乘图像上双线性采样，返回非预乘颜色
+// samplePremultiplied bilinearly samples a premultiplied image drawn by the font renderer, returning a straight-alpha color
+func samplePremultiplied(src *image.RGBA, x, y float64) color.RGBA {
+	return sampleBilinear(src, x, y, false)
+}
+
+// sampleStraight 在元素渲染得到的非预乘图像上双线性采样，返回非预乘颜色
+// sampleStraight bilinearly samples a straight-alpha image drawn by element rendering, returning a straight-alpha color
+func sampleStraight(src *image.RGBA, x, y float64) color.RGBA {
+	return sampleBilinear(src, x, y, true)
+}
+
+// sampleBilinear 按预乘颜色插值，straight时先将源像素预乘 / Interpolate premultiplied colors, premultiplying the source pixels first when straight
+func sampleBilinear(src *image.RGBA, x, y float64, straight bool) color.RGBA {
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	var r, g, b, a float64
+	for _, s := range [4]struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - fx) * (1 - fy)},
+		{x0 + 1, y0, fx * (1 - fy)},
+		{x0, y0 + 1, (1 - fx) * fy},
+		{x0 + 1, y0 + 1, fx * fy},
+	} {
+		if !(image.Point{X: s.x, Y: s.y}).In(src.Bounds()) || s.w == 0 {
+			continue
+		}
+		c := src.RGBAAt(s.x, s.y)
+		w := s.w
+		if straight {
+			w *= float64(c.A) / 255
+		}
+		r += w * float64(c.R)
+		g += w * float64(c.G)
+		b += w * float64(c.B)
+		a += s.w * float64(c.A)
+	}
+	if a < 0.5 {
+		return color.RGBA{}
+	}
+	unpremultiply := func(v float64) uint8 {
+		return uint8(math.Round(math.Min(255, v*255/a)))
+	}
+	return color.RGBA{R: unpremultiply(r), G: unpremultiply(g), B: unpremultiply(b), A: uint8(math.Round(a))}
+}
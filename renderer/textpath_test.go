@@ -0,0 +1,92 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/path"
+	"github.com/hoonfeng/svg/types"
+)
+
+// 圆心(100,100)、半径60的圆，从左侧顺时针经过顶部 / Circle at (100,100) with radius 60, running clockwise from the left over the top
+const textPathCircle = "M 40 100 A 60 60 0 0 1 160 100 A 60 60 0 0 1 40 100"
+
+// TestTextPathPlacement 测试字形沿圆形路径放置并随切线旋转 / Test glyphs are placed along a circle and rotated to its tangent
+func TestTextPathPlacement(t *testing.T) {
+	guide, err := path.ParsePath(textPathCircle)
+	if err != nil {
+		t.Fatalf("ParsePath failed: %v", err)
+	}
+	r := NewImageRenderer()
+	viewBox := []float64{0, 0, 200, 200}
+	attrs := map[string]string{"font-size": "16"}
+	style := r.createTextStyleFromAttributes(attrs, 1, 1)
+
+	placements, err := r.placeGlyphs(guide, "ABCD", attrs, style, viewBox, 1, 1)
+	if err != nil {
+		t.Fatalf("placeGlyphs failed: %v", err)
+	}
+	if len(placements) != 4 {
+		t.Fatalf("expected 4 glyphs, got %d", len(placements))
+	}
+	for i, g := range placements {
+		if d := math.Hypot(g.x-100, g.y-100); math.Abs(d-60) > 1 {
+			t.Errorf("glyph %d at (%.1f, %.1f) is %.2f from the center, want about 60", i, g.x, g.y, d)
+		}
+		// 基线与半径垂直 / The baseline is perpendicular to the radius
+		radial := math.Atan2(g.y-100, g.x-100)
+		if math.Abs(math.Cos(g.angle-radial)) > 0.2 {
+			t.Errorf("glyph %d angle %.2f is not tangent to the circle", i, g.angle)
+		}
+		if i > 0 && g.x <= placements[i-1].x {
+			t.Errorf("expected glyphs to advance clockwise from the left, got %v", placements)
+		}
+	}
+
+	attrs["startOffset"] = "25%"
+	shifted, err := r.placeGlyphs(guide, "A", attrs, style, viewBox, 1, 1)
+	if err != nil || len(shifted) != 1 {
+		t.Fatalf("placeGlyphs failed: %v", err)
+	}
+	// 四分之一周长处为圆顶 / A quarter of the way round is the top of the circle
+	if math.Abs(shifted[0].y-40) > 1.5 || math.Abs(shifted[0].x-100) > 10 {
+		t.Errorf("expected the glyph near the top with startOffset=25%%, got (%.1f, %.1f)", shifted[0].x, shifted[0].y)
+	}
+}
+
+// TestTextPathRender 测试textPath渲染的墨迹都位于圆附近 / Test that rendered textPath ink stays near the circle
+func TestTextPathRender(t *testing.T) {
+	doc := types.NewDocument(200, 200)
+	doc.SetViewBox(0, 0, 200, 200)
+	curve := elements.NewPath(textPathCircle)
+	curve.SetID("curve")
+	doc.AddDef(curve)
+
+	text := elements.NewText(0, 0, "")
+	text.SetAttribute("fill", "#000000")
+	textPath := elements.NewTextPath("#curve", "HELLO")
+	textPath.SetStartOffset("10")
+	text.AppendChild(textPath)
+	doc.AppendElement(text)
+
+	img, err := NewImageRenderer().Render(doc, 200, 200)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	ink := 0
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			if img.RGBAAt(x, y).A == 0 {
+				continue
+			}
+			ink++
+			if d := math.Hypot(float64(x)+0.5-100, float64(y)+0.5-100); d < 58 || d > 78 {
+				t.Fatalf("ink at (%d, %d) is %.1f from the center, outside the text band", x, y, d)
+			}
+		}
+	}
+	if ink < 50 {
+		t.Errorf("expected glyphs to be drawn along the path, got %d ink pixels", ink)
+	}
+}
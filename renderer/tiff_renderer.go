@@ -0,0 +1,96 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/image/tiff"
+
+	"github.com/hoonfeng/svg/types"
+)
+
+// tiffTypeSizes TIFF字段类型对应的字节数 / Byte size of each TIFF field type
+var tiffTypeSizes = map[uint16]uint32{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+// tiffTagStripOffsets 条带偏移标签 / The StripOffsets tag
+const tiffTagStripOffsets = 273
+
+// RenderAnimationToTIFF 将动画帧渲染为多页TIFF文件，每帧一页，使用无损Deflate压缩
+// RenderAnimationToTIFF renders animation frames to a multi-page TIFF file, one page per frame, using lossless Deflate compression
+func RenderAnimationToTIFF(frames []*types.Document, width, height int, filename string) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("没有动画帧可渲染")
+	}
+
+	renderer := NewImageRenderer()
+	var buf bytes.Buffer
+	pages := make([][]byte, 0, len(frames))
+	for i, frame := range frames {
+		img, err := renderer.Render(frame, width, height)
+		if err != nil {
+			return fmt.Errorf("渲染第%d帧失败: %v", i, err)
+		}
+		buf.Reset()
+		if err := tiff.Encode(&buf, img, &tiff.Options{Compression: tiff.Deflate}); err != nil {
+			return fmt.Errorf("编码第%d帧失败: %v", i, err)
+		}
+		pages = append(pages, append([]byte(nil), buf.Bytes()...))
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer file.Close()
+	return joinTIFFPages(file, pages)
+}
+
+// joinTIFFPages 将多个单页小端TIFF拼接为一个多页TIFF，重定位各页偏移并串联IFD链
+// joinTIFFPages joins single-page little-endian TIFFs into one multi-page TIFF, relocating each page's offsets and chaining the IFDs
+func joinTIFFPages(w io.Writer, pages [][]byte) error {
+	le := binary.LittleEndian
+	out := []byte("II\x2A\x00\x00\x00\x00\x00")
+	// nextLink 为上一页"下一IFD"字段的位置，首个为文件头中的偏移 / nextLink is where the previous "next IFD" field lives; the first is in the header
+	nextLink := 4
+
+	for i, page := range pages {
+		if len(page) < 8 || string(page[:4]) != "II\x2A\x00" {
+			return fmt.Errorf("第%d页不是小端TIFF", i)
+		}
+		if len(out)%2 != 0 {
+			out = append(out, 0) // TIFF偏移需字对齐 / TIFF offsets must be word aligned
+		}
+		shift := uint32(len(out) - 8)
+		body := append([]byte(nil), page[8:]...)
+
+		ifd := le.Uint32(page[4:8]) - 8
+		if int(ifd)+2 > len(body) {
+			return fmt.Errorf("第%d页IFD偏移无效", i)
+		}
+		count := int(le.Uint16(body[ifd:]))
+		end := int(ifd) + 2 + count*12
+		if end+4 > len(body) {
+			return fmt.Errorf("第%d页IFD被截断", i)
+		}
+		for e := int(ifd) + 2; e < end; e += 12 {
+			tag, typ := le.Uint16(body[e:]), le.Uint16(body[e+2:])
+			n := le.Uint32(body[e+4:])
+			// 超过4字节的值存于偏移处，条带偏移本身也需重定位 / Values over 4 bytes live at an offset, and strip offsets themselves need relocating
+			if tiffTypeSizes[typ]*n > 4 || tag == tiffTagStripOffsets {
+				le.PutUint32(body[e+8:], le.Uint32(body[e+8:])+shift)
+			}
+		}
+
+		le.PutUint32(out[nextLink:], ifd+8+shift)
+		nextLink = len(out) + end
+		out = append(out, body...)
+	}
+
+	_, err := w.Write(out)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"image/color"
 	"math"
 
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/types"
 )
 
@@ -18,12 +19,12 @@ const (
 )
 
 // StrokeJoinStyle 线段连接样式 / Stroke join style
-type StrokeJoinStyle int
+type StrokeJoinStyle = path.StrokeJoinStyle
 
 const (
-	JoinMiter StrokeJoinStyle = iota // 尖角连接 / Miter join
-	JoinRound                        // 圆角连接 / Round join
-	JoinBevel                        // 斜角连接 / Bevel join
+	JoinMiter = path.JoinMiter // 尖角连接 / Miter join
+	JoinRound = path.JoinRound // 圆角连接 / Round join
+	JoinBevel = path.JoinBevel // 斜角连接 / Bevel join
 )
 
 // TrueStrokePathGenerator 真正的描边路径生成器 / True stroke path generator
@@ -53,18 +54,9 @@ func (g *TrueStrokePathGenerator) GenerateStrokePath(path []types.Point, strokeW
 
 	// 处理路径闭合 / Handle path closure
 	processedPath := path
-	if closePath && len(path) >= 3 {
-		// 检查是否需要添加闭合线段 / Check if closure segment is needed
-		firstPoint := path[0]
-		lastPoint := path[len(path)-1]
-		dx := firstPoint.X - lastPoint.X
-		dy := firstPoint.Y - lastPoint.Y
-		distance := math.Sqrt(dx*dx + dy*dy)
-		if distance > 0.1 { // 如果起点和终点距离大于0.1像素，添加闭合线段
-			processedPath = make([]types.Point, len(path)+1)
-			copy(processedPath, path)
-			processedPath[len(path)] = firstPoint
-		}
+	if closePath {
+		processedPath = closedStrokeRing(path)
+		closePath = len(processedPath) > len(path)
 	}
 
 	// 生成左侧和右侧偏移路径 / Generate left and right offset paths
@@ -78,7 +70,7 @@ func (g *TrueStrokePathGenerator) GenerateStrokePath(path []types.Point, strokeW
 
 		// 如果不是闭合路径，添加终点线帽 / Add end cap if not closed
 		if !closePath {
-			endCap := g.generateEndCap(processedPath[len(processedPath)-2], processedPath[len(processedPath)-1], halfWidth, false)
+			endCap := g.generateEndCap(processedPath[len(processedPath)-2], processedPath[len(processedPath)-1], halfWidth)
 			strokePath = append(strokePath, endCap...)
 		}
 
@@ -89,7 +81,7 @@ func (g *TrueStrokePathGenerator) GenerateStrokePath(path []types.Point, strokeW
 
 		// 如果不是闭合路径，添加起点线帽 / Add start cap if not closed
 		if !closePath {
-			startCap := g.generateEndCap(processedPath[1], processedPath[0], halfWidth, true)
+			startCap := g.generateEndCap(processedPath[1], processedPath[0], halfWidth)
 			strokePath = append(strokePath, startCap...)
 		}
 	}
@@ -97,256 +89,50 @@ func (g *TrueStrokePathGenerator) GenerateStrokePath(path []types.Point, strokeW
 	return strokePath
 }
 
-// generateOffsetPath 生成偏移路径 / Generate offset path
-func (g *TrueStrokePathGenerator) generateOffsetPath(path []types.Point, offset float64, isLeft bool) []types.Point {
-	if len(path) < 2 {
-		return nil
-	}
-
-	offsetPath := make([]types.Point, 0)
-
-	for i := 0; i < len(path)-1; i++ {
-		current := path[i]
-		next := path[i+1]
-
-		// 计算线段的法向量 / Calculate normal vector of segment
-		dx := next.X - current.X
-		dy := next.Y - current.Y
-		length := math.Sqrt(dx*dx + dy*dy)
-
-		if length < 1e-10 {
-			continue // 跳过长度为0的线段 / Skip zero-length segments
-		}
-
-		// 归一化方向向量 / Normalize direction vector
-		dx /= length
-		dy /= length
-
-		// 计算法向量（垂直向量）/ Calculate normal vector (perpendicular)
-		normalX := -dy
-		normalY := dx
-
-		// 根据左右侧调整法向量方向 / Adjust normal direction for left/right
-		if !isLeft {
-			normalX = -normalX
-			normalY = -normalY
-		}
-
-		// 计算偏移点 / Calculate offset points
-		offsetStart := types.Point{
-			X: current.X + normalX*offset,
-			Y: current.Y + normalY*offset,
-		}
-		offsetEnd := types.Point{
-			X: next.X + normalX*offset,
-			Y: next.Y + normalY*offset,
-		}
-
-		// 处理线段连接 / Handle segment joins
-		if i == 0 {
-			// 第一个线段，直接添加起点 / First segment, add start point directly
-			offsetPath = append(offsetPath, offsetStart)
-		} else {
-			// 处理与前一个线段的连接 / Handle join with previous segment
-			joinPoints := g.generateJoin(path[i-1], current, next, offset, isLeft)
-			offsetPath = append(offsetPath, joinPoints...)
-		}
-
-		// 添加线段终点 / Add segment end point
-		if i == len(path)-2 {
-			// 最后一个线段，添加终点 / Last segment, add end point
-			offsetPath = append(offsetPath, offsetEnd)
-		}
-	}
+// closedStrokeRing 将闭合折线展开为从首边中点出发并回到该点的环，使每个顶点（包括起点）都生成一次连接
+// closedStrokeRing unrolls a closed polyline into a ring that starts and ends mid-way along the first edge, so every vertex, the start included, is joined exactly once
+//
+// 与起点重合的末尾顶点视为闭合边的一部分而被去掉；少于3个不同顶点时按原样返回
+// A trailing vertex that repeats the start is treated as part of the closing edge and dropped; fewer than 3 distinct vertices are returned unchanged
+func closedStrokeRing(points []types.Point) []types.Point {
+	if len(points) >= 3 && points[0].Distance(points[len(points)-1]) <= 0.1 {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 3 {
+		return points
+	}
+	mid := points[0].Lerp(points[1], 0.5)
+	ring := make([]types.Point, 0, len(points)+2)
+	ring = append(ring, mid)
+	ring = append(ring, points[1:]...)
+	return append(ring, points[0], mid)
+}
 
-	return offsetPath
+// generateOffsetPath 生成偏移路径 / Generate offset path
+func (g *TrueStrokePathGenerator) generateOffsetPath(points []types.Point, offset float64, isLeft bool) []types.Point {
+	return path.OffsetPolyline(points, offset, isLeft, g.JoinStyle, g.MiterLimit)
 }
 
 // generateJoin 生成线段连接 / Generate segment join
 func (g *TrueStrokePathGenerator) generateJoin(prev, current, next types.Point, offset float64, isLeft bool) []types.Point {
-	joinPoints := make([]types.Point, 0)
-
-	// 计算前一个线段的方向 / Calculate previous segment direction
-	prevDx := current.X - prev.X
-	prevDy := current.Y - prev.Y
-	prevLength := math.Sqrt(prevDx*prevDx + prevDy*prevDy)
-
-	// 计算下一个线段的方向 / Calculate next segment direction
-	nextDx := next.X - current.X
-	nextDy := next.Y - current.Y
-	nextLength := math.Sqrt(nextDx*nextDx + nextDy*nextDy)
-
-	if prevLength < 1e-10 || nextLength < 1e-10 {
-		return joinPoints // 跳过长度为0的线段 / Skip zero-length segments
-	}
-
-	// 归一化方向向量 / Normalize direction vectors
-	prevDx /= prevLength
-	prevDy /= prevLength
-	nextDx /= nextLength
-	nextDy /= nextLength
-
-	// 计算法向量 / Calculate normal vectors
-	prevNormalX := -prevDy
-	prevNormalY := prevDx
-	nextNormalX := -nextDy
-	nextNormalY := nextDx
-
-	// 根据左右侧调整法向量方向 / Adjust normal direction for left/right
-	if !isLeft {
-		prevNormalX = -prevNormalX
-		prevNormalY = -prevNormalY
-		nextNormalX = -nextNormalX
-		nextNormalY = -nextNormalY
-	}
-
-	// 计算偏移点 / Calculate offset points
-	prevOffset := types.Point{
-		X: current.X + prevNormalX*offset,
-		Y: current.Y + prevNormalY*offset,
-	}
-	nextOffset := types.Point{
-		X: current.X + nextNormalX*offset,
-		Y: current.Y + nextNormalY*offset,
-	}
-
-	// 根据连接样式生成连接点 / Generate join points based on join style
-	switch g.JoinStyle {
-	case JoinMiter:
-		// 尖角连接 / Miter join
-		miterPoint := g.calculateMiterJoin(prevOffset, current, nextOffset, offset)
-		if miterPoint != nil {
-			joinPoints = append(joinPoints, *miterPoint)
-		} else {
-			// 尖角过长，回退到斜角连接 / Miter too long, fallback to bevel
-			joinPoints = append(joinPoints, prevOffset, nextOffset)
-		}
-	case JoinRound:
-		// 圆角连接 / Round join
-		roundPoints := g.generateRoundJoin(prevOffset, current, nextOffset, offset)
-		joinPoints = append(joinPoints, roundPoints...)
-	case JoinBevel:
-		// 斜角连接 / Bevel join
-		joinPoints = append(joinPoints, prevOffset, nextOffset)
-	}
-
-	return joinPoints
+	return path.JoinPoints(prev, current, next, offset, isLeft, g.JoinStyle, g.MiterLimit)
 }
 
-// calculateMiterJoin 计算尖角连接 / Calculate miter join
-func (g *TrueStrokePathGenerator) calculateMiterJoin(prevOffset, center, nextOffset types.Point, offset float64) *types.Point {
-	// 计算两条偏移线的交点 / Calculate intersection of two offset lines
-	// 使用线段交点公式 / Use line intersection formula
-
-	// 前一条线的方向向量 / Previous line direction vector
-	prevDx := center.X - prevOffset.X
-	prevDy := center.Y - prevOffset.Y
-
-	// 下一条线的方向向量 / Next line direction vector
-	nextDx := center.X - nextOffset.X
-	nextDy := center.Y - nextOffset.Y
-
-	// 计算行列式 / Calculate determinant
-	det := prevDx*nextDy - prevDy*nextDx
-
-	if math.Abs(det) < 1e-10 {
-		return nil // 线段平行，无交点 / Lines are parallel, no intersection
-	}
-
-	// 计算参数 / Calculate parameters
-	dx := nextOffset.X - prevOffset.X
-	dy := nextOffset.Y - prevOffset.Y
-	t := (dx*nextDy - dy*nextDx) / det
-
-	// 计算交点 / Calculate intersection point
-	intersection := types.Point{
-		X: prevOffset.X + t*prevDx,
-		Y: prevOffset.Y + t*prevDy,
-	}
-
-	// 检查尖角长度限制 / Check miter length limit
-	distance := math.Sqrt((intersection.X-center.X)*(intersection.X-center.X) + (intersection.Y-center.Y)*(intersection.Y-center.Y))
-	if distance > offset*g.MiterLimit {
-		return nil // 尖角过长 / Miter too long
-	}
-
-	return &intersection
-}
-
-// generateRoundJoin 生成圆角连接 / Generate round join
-func (g *TrueStrokePathGenerator) generateRoundJoin(prevOffset, center, nextOffset types.Point, offset float64) []types.Point {
-	roundPoints := make([]types.Point, 0)
-
-	// 计算起始和结束角度 / Calculate start and end angles
-	startAngle := math.Atan2(prevOffset.Y-center.Y, prevOffset.X-center.X)
-	endAngle := math.Atan2(nextOffset.Y-center.Y, nextOffset.X-center.X)
-
-	// 确保角度差在合理范围内 / Ensure angle difference is reasonable
-	angleDiff := endAngle - startAngle
-	if angleDiff > math.Pi {
-		angleDiff -= 2 * math.Pi
-	} else if angleDiff < -math.Pi {
-		angleDiff += 2 * math.Pi
-	}
-
-	// 计算圆弧分段数 / Calculate arc segments
-	segments := int(math.Ceil(math.Abs(angleDiff) / (math.Pi / 8))) // 每22.5度一个分段
-	if segments < 2 {
-		segments = 2
-	}
-
-	// 生成圆弧点 / Generate arc points
-	roundPoints = append(roundPoints, prevOffset)
-	for i := 1; i < segments; i++ {
-		t := float64(i) / float64(segments)
-		angle := startAngle + t*angleDiff
-		point := types.Point{
-			X: center.X + offset*math.Cos(angle),
-			Y: center.Y + offset*math.Sin(angle),
-		}
-		roundPoints = append(roundPoints, point)
-	}
-	roundPoints = append(roundPoints, nextOffset)
-
-	return roundPoints
-}
-
-// generateEndCap 生成线帽 / Generate end cap
-func (g *TrueStrokePathGenerator) generateEndCap(prev, end types.Point, offset float64, isStart bool) []types.Point {
+// generateEndCap 生成线帽，起点线帽传入反向的线段使方向始终朝外 / Generate an end cap; start caps pass the reversed segment so the direction always points outward
+func (g *TrueStrokePathGenerator) generateEndCap(prev, end types.Point, offset float64) []types.Point {
 	capPoints := make([]types.Point, 0)
 
-	// 计算线段方向 / Calculate segment direction
-	dx := end.X - prev.X
-	dy := end.Y - prev.Y
-	length := math.Sqrt(dx*dx + dy*dy)
-
-	if length < 1e-10 {
+	// 计算线段的单位方向和法向量 / Calculate the segment's unit direction and normal
+	direction := end.Sub(prev)
+	if direction.Length() < 1e-10 {
 		return capPoints // 跳过长度为0的线段 / Skip zero-length segments
 	}
-
-	// 归一化方向向量 / Normalize direction vector
-	dx /= length
-	dy /= length
-
-	// 计算法向量 / Calculate normal vector
-	normalX := -dy
-	normalY := dx
-
-	// 根据起点/终点调整方向 / Adjust direction for start/end
-	if isStart {
-		dx = -dx
-		dy = -dy
-	}
+	direction = direction.Normalize()
+	normal := types.Point{X: -direction.Y, Y: direction.X}
 
 	// 计算线帽的基础点 / Calculate cap base points
-	leftPoint := types.Point{
-		X: end.X + normalX*offset,
-		Y: end.Y + normalY*offset,
-	}
-	rightPoint := types.Point{
-		X: end.X - normalX*offset,
-		Y: end.Y - normalY*offset,
-	}
+	leftPoint := end.Add(normal.Scale(offset))
+	rightPoint := end.Sub(normal.Scale(offset))
 
 	// 根据线帽样式生成线帽 / Generate cap based on cap style
 	switch g.CapStyle {
@@ -355,18 +141,12 @@ func (g *TrueStrokePathGenerator) generateEndCap(prev, end types.Point, offset f
 		capPoints = append(capPoints, leftPoint, rightPoint)
 	case CapSquare:
 		// 方形线帽 / Square cap
-		extendedLeft := types.Point{
-			X: leftPoint.X + dx*offset,
-			Y: leftPoint.Y + dy*offset,
-		}
-		extendedRight := types.Point{
-			X: rightPoint.X + dx*offset,
-			Y: rightPoint.Y + dy*offset,
-		}
+		extendedLeft := leftPoint.Add(direction.Scale(offset))
+		extendedRight := rightPoint.Add(direction.Scale(offset))
 		capPoints = append(capPoints, leftPoint, extendedLeft, extendedRight, rightPoint)
 	case CapRound:
 		// 圆形线帽 / Round cap
-		roundCap := g.generateRoundCap(end, leftPoint, rightPoint, offset, dx, dy)
+		roundCap := g.generateRoundCap(end, leftPoint, rightPoint, offset, direction.X, direction.Y)
 		capPoints = append(capPoints, roundCap...)
 	}
 
@@ -449,24 +229,25 @@ func (r *TrueStrokeRenderer) renderStrokePathDirect(img *image.RGBA, strokePath
 	}
 
 	// 计算路径边界 / Calculate path bounds
-	bounds := r.calculatePathBounds(strokePath)
-	minX := int(math.Floor(bounds.MinX - 2))
-	maxX := int(math.Ceil(bounds.MaxX + 2))
-	minY := int(math.Floor(bounds.MinY - 2))
-	maxY := int(math.Ceil(bounds.MaxY + 2))
+	bounds := types.RectFromPoints(strokePath)
+	minX := int(math.Floor(bounds.X - 2))
+	maxX := int(math.Ceil(bounds.MaxX() + 2))
+	minY := int(math.Floor(bounds.Y - 2))
+	maxY := int(math.Ceil(bounds.MaxY() + 2))
 
 	// 确保边界在图像范围内 / Ensure bounds are within image
-	if minX < 0 {
-		minX = 0
+	imgBounds := img.Bounds()
+	if minX < imgBounds.Min.X {
+		minX = imgBounds.Min.X
 	}
-	if maxX >= img.Bounds().Dx() {
-		maxX = img.Bounds().Dx() - 1
+	if maxX >= imgBounds.Max.X {
+		maxX = imgBounds.Max.X - 1
 	}
-	if minY < 0 {
-		minY = 0
+	if minY < imgBounds.Min.Y {
+		minY = imgBounds.Min.Y
 	}
-	if maxY >= img.Bounds().Dy() {
-		maxY = img.Bounds().Dy() - 1
+	if maxY >= imgBounds.Max.Y {
+		maxY = imgBounds.Max.Y - 1
 	}
 
 	// 遍历边界内的每个像素 / Iterate through each pixel in bounds
@@ -484,8 +265,8 @@ func (r *TrueStrokeRenderer) renderStrokePathDirect(img *image.RGBA, strokePath
 
 // calculateStrokePathCoverage 计算像素对描边路径的覆盖率 / Calculate pixel coverage for stroke path
 func (r *TrueStrokeRenderer) calculateStrokePathCoverage(pixelX, pixelY float64, strokePath []types.Point) float64 {
-	// 使用4x4子像素采样 / Use 4x4 sub-pixel sampling
-	samples := 4
+	// 使用NxN子像素采样 / Use NxN sub-pixel sampling
+	samples := r.sampleCount()
 	insideCount := 0
 	totalSamples := samples * samples
 	step := 1.0 / float64(samples)
@@ -542,45 +323,15 @@ func (r *TrueStrokeRenderer) blendPixel(img *image.RGBA, x, y int, colors color.
 
 	// Alpha混合 / Alpha blending
 	alpha := float64(colors.A) * coverage / 255.0
-	invAlpha := 1.0 - alpha
 
-	newR := uint8(float64(colors.R)*alpha + float64(currentColor.R)*invAlpha)
-	newG := uint8(float64(colors.G)*alpha + float64(currentColor.G)*invAlpha)
-	newB := uint8(float64(colors.B)*alpha + float64(currentColor.B)*invAlpha)
+	newR := blendChannel(currentColor.R, colors.R, alpha, r.LinearBlending)
+	newG := blendChannel(currentColor.G, colors.G, alpha, r.LinearBlending)
+	newB := blendChannel(currentColor.B, colors.B, alpha, r.LinearBlending)
 	newA := uint8(math.Min(255, float64(currentColor.A)+alpha*255))
 
 	img.SetRGBA(x, y, color.RGBA{R: newR, G: newG, B: newB, A: newA})
 }
 
-// PathBounds 路径边界框结构 / Path bounds structure
-
-// calculatePathBounds 计算路径边界框 / Calculate path bounds
-func (r *TrueStrokeRenderer) calculatePathBounds(path []types.Point) PathBounds {
-	if len(path) == 0 {
-		return PathBounds{}
-	}
-
-	bounds := PathBounds{
-		MinX: path[0].X, MaxX: path[0].X,
-		MinY: path[0].Y, MaxY: path[0].Y,
-	}
-
-	for _, point := range path[1:] {
-		if point.X < bounds.MinX {
-			bounds.MinX = point.X
-		} else if point.X > bounds.MaxX {
-			bounds.MaxX = point.X
-		}
-		if point.Y < bounds.MinY {
-			bounds.MinY = point.Y
-		} else if point.Y > bounds.MaxY {
-			bounds.MaxY = point.Y
-		}
-	}
-
-	return bounds
-}
-
 // RenderTrueStrokeComplexPath 渲染复杂路径的真正描边 / Render true stroke for complex path
 func (r *TrueStrokeRenderer) RenderTrueStrokeComplexPath(img *image.RGBA, subPaths [][]types.Point, strokeColor color.RGBA, strokeWidth float64, closeSubPaths []bool) {
 	if len(subPaths) == 0 {
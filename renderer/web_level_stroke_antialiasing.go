@@ -81,7 +81,7 @@ func (r *WebLevelStrokeRenderer) StrokeWebLevelAntiAliased(img *image.RGBA, path
 			minCoverage := 0.05 // 只有覆盖率大于5%才进行描边
 			if coverage > minCoverage {
 				// 混合颜色
-				blendedColor := blendColors(getPixelColor(img, x, y), strokeColor, coverage)
+				blendedColor := r.compositeColors(getPixelColor(img, x, y), strokeColor, coverage)
 				DrawPixel(img, x, y, blendedColor)
 			}
 		}
@@ -95,20 +95,20 @@ func (r *WebLevelStrokeRenderer) calculateWebLevelDistanceToPath(x, y float64, p
 	}
 
 	// 使用边界框快速预筛选
-	bounds := r.calculatePathBounds(path)
+	bounds := types.RectFromPoints(path)
 
 	// 计算到边界框的距离作为下界
 	dx := 0.0
 	dy := 0.0
-	if x < bounds.MinX {
-		dx = bounds.MinX - x
-	} else if x > bounds.MaxX {
-		dx = x - bounds.MaxX
+	if x < bounds.X {
+		dx = bounds.X - x
+	} else if x > bounds.MaxX() {
+		dx = x - bounds.MaxX()
 	}
-	if y < bounds.MinY {
-		dy = bounds.MinY - y
-	} else if y > bounds.MaxY {
-		dy = y - bounds.MaxY
+	if y < bounds.Y {
+		dy = bounds.Y - y
+	} else if y > bounds.MaxY() {
+		dy = y - bounds.MaxY()
 	}
 	boundsDistance := math.Sqrt(dx*dx + dy*dy)
 
@@ -123,25 +123,20 @@ func (r *WebLevelStrokeRenderer) calculateWebLevelDistanceToPath(x, y float64, p
 	// 只检查可能影响结果的线段（Web级别优化）
 	for i := 0; i < len(path)-1; i++ {
 		// 快速跳过距离过远的线段
-		segmentBounds := PathBounds{
-			MinX: math.Min(path[i].X, path[i+1].X),
-			MaxX: math.Max(path[i].X, path[i+1].X),
-			MinY: math.Min(path[i].Y, path[i+1].Y),
-			MaxY: math.Max(path[i].Y, path[i+1].Y),
-		}
+		segmentBounds := types.RectFromPoints([]types.Point{path[i], path[i+1]})
 
 		// 计算到线段边界框的距离
 		dx := 0.0
 		dy := 0.0
-		if x < segmentBounds.MinX {
-			dx = segmentBounds.MinX - x
-		} else if x > segmentBounds.MaxX {
-			dx = x - segmentBounds.MaxX
+		if x < segmentBounds.X {
+			dx = segmentBounds.X - x
+		} else if x > segmentBounds.MaxX() {
+			dx = x - segmentBounds.MaxX()
 		}
-		if y < segmentBounds.MinY {
-			dy = segmentBounds.MinY - y
-		} else if y > segmentBounds.MaxY {
-			dy = y - segmentBounds.MaxY
+		if y < segmentBounds.Y {
+			dy = segmentBounds.Y - y
+		} else if y > segmentBounds.MaxY() {
+			dy = y - segmentBounds.MaxY()
 		}
 		segmentBoundsDistance := math.Sqrt(dx*dx + dy*dy)
 
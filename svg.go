@@ -3,18 +3,26 @@
 package svg
 
 import (
+	"encoding/base64"
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"image/jpeg"
 	"image/png"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/hoonfeng/svg/animation"
 	"github.com/hoonfeng/svg/api"
+	"github.com/hoonfeng/svg/attributes"
+	"github.com/hoonfeng/svg/elements"
+	"github.com/hoonfeng/svg/font"
 	"github.com/hoonfeng/svg/io"
+	"github.com/hoonfeng/svg/path"
 	"github.com/hoonfeng/svg/renderer"
 	. "github.com/hoonfeng/svg/types"
 )
@@ -30,6 +38,13 @@ type SVG struct {
 	gen     *api.SVGGenerator // 生成器 / Generator
 	width   int               // 画布宽度 / Canvas width
 	height  int               // 画布高度 / Canvas height
+	merges  int               // 已合并的文档数，用于生成ID前缀 / Number of merged documents, used for ID prefixes
+	// flatness 渲染时的曲线展平容差（设备像素），0表示自适应 / Curve flattening tolerance in device pixels when rendering, 0 for adaptive
+	flatness float64
+	// textRenderer 渲染文本所用的文本渲染器，nil时使用font.DefaultTextRenderer / Text renderer used for text, font.DefaultTextRenderer when nil
+	textRenderer font.TextRenderer
+	// imageDir image元素可读取本地文件的目录，为空时只接受data URI / Directory image elements may read local files from; only data URIs are accepted when empty
+	imageDir string
 }
 
 // ============================================================================
@@ -109,6 +124,22 @@ func Parse(svgContent string) (*SVG, error) {
 	}, nil
 }
 
+// FromImage 将位图以base64 PNG数据URI的<image>元素嵌入新的SVG，画布与图像同尺寸
+// FromImage embeds a raster as an <image> element with a base64 PNG data URI in a new SVG sized to the image
+//
+// 图像编码失败时返回空白画布 / An empty canvas is returned if the image cannot be encoded
+func FromImage(img image.Image) *SVG {
+	bounds := img.Bounds()
+	s := NewWithViewBox(bounds.Dx(), bounds.Dy(), 0, 0, float64(bounds.Dx()), float64(bounds.Dy()))
+	data, err := ImageToPNGBytes(img)
+	if err != nil {
+		return s
+	}
+	href := "data:image/png;base64," + base64.StdEncoding.EncodeToString(data)
+	s.doc.AppendElement(elements.NewImage(0, 0, float64(bounds.Dx()), float64(bounds.Dy()), href))
+	return s
+}
+
 // ============================================================================
 // 基础绘图方法 / Basic Drawing Methods
 // ============================================================================
@@ -197,6 +228,23 @@ func (s *SVG) Polygon(cx, cy, radius float64, sides int) *ShapeElement {
 	return &ShapeElement{svg: s, shapeType: "polygon", options: options}
 }
 
+// Points 按给定顶点创建真正的<polyline>，closed为true时创建<polygon>；折线默认不填充并以黑色描边
+// Points creates a real <polyline> from the given vertices, or a <polygon> when closed is true; polylines default to no fill and a black stroke
+//
+// 与生成正多边形的Polygon不同，顶点原样写入points属性 / Unlike Polygon, which generates a regular polygon, the vertices are written to the points attribute as given
+func (s *SVG) Points(points []Point, closed bool) *PolyElement {
+	var element Element
+	if closed {
+		element = elements.NewPolygon(points)
+	} else {
+		element = elements.NewPolyline(points)
+		element.SetAttribute("fill", "none")
+		element.SetAttribute("stroke", "#000000")
+	}
+	s.doc.AppendElement(element)
+	return &PolyElement{element: element, svg: s}
+}
+
 // ============================================================================
 // 图表方法 / Chart Methods
 // ============================================================================
@@ -278,6 +326,107 @@ func (s *SVG) Group() *GroupElement {
 	return &GroupElement{builder: groupBuilder, svg: s}
 }
 
+// Merge 将另一个SVG的内容克隆到平移(dx,dy)的组中并追加到当前文档
+// Merge clones another SVG's content into a group translated by (dx,dy) and appends it to this document
+//
+// 被合并内容的ID及其url(#id)和href引用会加上唯一前缀以避免冲突
+// IDs in the merged content, and the url(#id) and href references to them, get a unique prefix to avoid collisions
+func (s *SVG) Merge(other *SVG, dx, dy float64) *SVG {
+	if other == nil || other.doc == nil {
+		return s
+	}
+	s.merges++
+	prefix := fmt.Sprintf("merge%d-", s.merges)
+
+	clones := make([]Element, 0, len(other.doc.Elements))
+	for _, element := range other.doc.Elements {
+		clones = append(clones, element.Clone())
+	}
+	defs := make([]Element, 0, len(other.doc.Defs))
+	for _, def := range other.doc.Defs {
+		defs = append(defs, def.Clone())
+	}
+
+	// 先收集所有ID，再统一改写，使前向引用也能被替换 / Collect every ID first so forward references are rewritten too
+	ids := make(map[string]bool)
+	for _, element := range append(append([]Element{}, defs...), clones...) {
+		collectIDs(element, ids)
+	}
+	for _, element := range append(append([]Element{}, defs...), clones...) {
+		namespaceIDs(element, prefix, ids)
+	}
+
+	group := elements.NewGroup()
+	group.SetAttribute("transform", fmt.Sprintf("translate(%g,%g)", dx, dy))
+	for _, clone := range clones {
+		group.AppendChild(clone)
+	}
+	s.doc.AppendElement(group)
+	for _, def := range defs {
+		s.doc.AddDef(def)
+	}
+	return s
+}
+
+// collectIDs 递归收集元素树中的ID / Recursively collect the IDs in an element tree
+func collectIDs(element Element, ids map[string]bool) {
+	if id := element.ID(); id != "" {
+		ids[id] = true
+	}
+	for _, child := range element.Children() {
+		collectIDs(child, ids)
+	}
+}
+
+// namespaceIDs 为元素树中的ID及其引用加上前缀 / Prefix the IDs in an element tree and the references to them
+func namespaceIDs(element Element, prefix string, ids map[string]bool) {
+	if id := element.ID(); id != "" {
+		element.SetID(prefix + id)
+		if _, ok := element.GetAttribute("id"); ok {
+			element.SetAttribute("id", prefix+id)
+		}
+	}
+	for name, value := range element.GetAttributes() {
+		if name == "id" {
+			continue
+		}
+		if rewritten := namespaceReferences(value, prefix, ids); rewritten != value {
+			element.SetAttribute(name, rewritten)
+		}
+	}
+	for _, child := range element.Children() {
+		namespaceIDs(child, prefix, ids)
+	}
+}
+
+// namespaceReferences 改写属性值中的url(#id)和#id引用 / Rewrite url(#id) and #id references in an attribute value
+func namespaceReferences(value, prefix string, ids map[string]bool) string {
+	if strings.HasPrefix(value, "#") && ids[value[1:]] {
+		return "#" + prefix + value[1:]
+	}
+	var sb strings.Builder
+	rest := value
+	for {
+		start := strings.Index(rest, "url(#")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], ")")
+		if end < 0 {
+			break
+		}
+		id := rest[start+len("url(#") : start+end]
+		sb.WriteString(rest[:start+len("url(#")])
+		if ids[id] {
+			sb.WriteString(prefix)
+		}
+		sb.WriteString(id)
+		rest = rest[start+end:]
+	}
+	sb.WriteString(rest)
+	return sb.String()
+}
+
 // ============================================================================
 // 高级输出方法 / Advanced Output Methods
 // ============================================================================
@@ -300,12 +449,163 @@ func (s *SVG) Render(width, height int) (*image.RGBA, error) {
 	if height <= 0 {
 		height = s.height
 	}
-	return renderer.RenderDocument(s.doc, width, height)
+	return s.newRenderer().Render(s.doc, width, height)
 }
 
 // RenderToSize 渲染到指定尺寸 / Render to specified size
 func (s *SVG) RenderToSize(width, height int) (*image.RGBA, error) {
-	return renderer.RenderDocument(s.doc, width, height)
+	return s.newRenderer().Render(s.doc, width, height)
+}
+
+// SetFlatness 设置渲染时的曲线展平容差（设备像素），越小越平滑但生成的线段越多，0恢复按曲线自适应
+// SetFlatness sets the curve flattening tolerance in device pixels used when rendering; smaller is smoother but generates more segments, and 0 restores per-curve adaptive flattening
+//
+// 容差以设备像素计，放大渲染时在用户空间中相应变细，大文档缩小渲染时则变粗
+// The tolerance is in device pixels, so it becomes finer in user space for zoomed renders and coarser for large documents rendered small
+func (s *SVG) SetFlatness(tolerance float64) *SVG {
+	s.flatness = math.Max(0, tolerance)
+	return s
+}
+
+// SetTextRenderer 设置渲染文本所用的文本渲染器，例如加载了自定义字体的实例；同一实例在各次渲染间复用，其缓存得以保留，nil恢复使用font.DefaultTextRenderer
+// SetTextRenderer sets the text renderer used for text, e.g. one with custom fonts loaded; the same instance is reused across renders so its caches are kept, and nil goes back to font.DefaultTextRenderer
+func (s *SVG) SetTextRenderer(textRenderer font.TextRenderer) *SVG {
+	s.textRenderer = textRenderer
+	return s
+}
+
+// SetImageDir 允许image元素读取dir内的本地文件，相对路径相对于dir；默认不读取任何文件，空字符串恢复默认
+// SetImageDir lets image elements read local files inside dir, relative paths being relative to dir; by default no files are read, and an empty string restores that
+func (s *SVG) SetImageDir(dir string) *SVG {
+	s.imageDir = dir
+	return s
+}
+
+// newRenderer 创建带有文档渲染设置的图像渲染器 / Create an image renderer carrying the document's render settings
+func (s *SVG) newRenderer() *renderer.ImageRenderer {
+	r := renderer.NewImageRenderer()
+	r.SetFlatness(s.flatness)
+	r.SetTextRenderer(s.textRenderer)
+	r.ImageDir = s.imageDir
+	return r
+}
+
+// RenderToRGBA 渲染到调用方提供的图像缓冲区，以其边界作为视口 / Render into a caller-provided buffer using its bounds as the viewport
+// composite: 为true时在原有内容上合成，默认先清除为透明 / When true, composite over the existing content; by default the buffer is cleared to transparent
+func (s *SVG) RenderToRGBA(dst *image.RGBA, composite ...bool) error {
+	if dst == nil {
+		return fmt.Errorf("render target is nil")
+	}
+	if len(composite) == 0 || !composite[0] {
+		draw.Draw(dst, dst.Bounds(), image.Transparent, image.Point{}, draw.Src)
+	}
+	return s.newRenderer().RenderInto(dst, s.doc)
+}
+
+// DrawOn 将文档按r的尺寸渲染，并以源覆盖方式绘制到dst的r.Min处 / Render the document sized to r and draw it onto dst at r.Min with source-over
+func (s *SVG) DrawOn(dst draw.Image, r image.Rectangle) error {
+	if dst == nil {
+		return fmt.Errorf("draw target is nil")
+	}
+	if r.Empty() {
+		return nil
+	}
+	img, err := s.RenderToSize(r.Dx(), r.Dy())
+	if err != nil {
+		return err
+	}
+	// 渲染结果为非预乘颜色，按NRGBA解释后再合成 / The render holds straight-alpha colors, so composite it as NRGBA
+	src := &image.NRGBA{Pix: img.Pix, Stride: img.Stride, Rect: img.Rect}
+	draw.Draw(dst, r, src, src.Rect.Min, draw.Over)
+	return nil
+}
+
+// RenderGray 渲染为8位灰度图，按亮度权重转换，透明区域视为白色 / Render to 8-bit grayscale using luminance weighting, with transparency as white
+func (s *SVG) RenderGray(width, height int) (*image.Gray, error) {
+	img, err := s.Render(width, height)
+	if err != nil {
+		return nil, err
+	}
+	return renderer.ToGray(img), nil
+}
+
+// RenderCMYK 渲染为CMYK图像，透明区域视为白色 / Render to a CMYK image, with transparency as white
+func (s *SVG) RenderCMYK(width, height int) (*image.CMYK, error) {
+	img, err := s.Render(width, height)
+	if err != nil {
+		return nil, err
+	}
+	return renderer.ToCMYK(img), nil
+}
+
+// RenderScaled 按固有尺寸的倍数渲染 / Render at a multiple of the intrinsic size
+// scale: 缩放倍数，例如2表示2倍分辨率 / Scale factor, e.g. 2 for a 2x render
+func (s *SVG) RenderScaled(scale float64) (*image.RGBA, error) {
+	if scale <= 0 {
+		return nil, fmt.Errorf("invalid render scale: %v", scale)
+	}
+	width := int(math.Round(float64(s.width) * scale))
+	height := int(math.Round(float64(s.height) * scale))
+	return s.RenderToSize(width, height)
+}
+
+// Thumbnail 渲染缩略图，较长边等于maxDim，两个方向使用相同缩放比例 / Render a thumbnail whose longer side equals maxDim, scaling both axes uniformly
+func (s *SVG) Thumbnail(maxDim int) (*image.RGBA, error) {
+	if maxDim <= 0 {
+		return nil, fmt.Errorf("invalid thumbnail size: %d", maxDim)
+	}
+	if s.width <= 0 || s.height <= 0 {
+		return nil, fmt.Errorf("invalid document size: %dx%d", s.width, s.height)
+	}
+	scale := float64(maxDim) / math.Max(float64(s.width), float64(s.height))
+	width := int(math.Max(1, math.Round(float64(s.width)*scale)))
+	height := int(math.Max(1, math.Round(float64(s.height)*scale)))
+	return s.RenderToSize(width, height)
+}
+
+// RenderDPI 按指定DPI将物理尺寸映射为像素进行渲染 / Render mapping physical units to pixels at the given DPI
+// 无单位或px尺寸按CSS的96 DPI换算 / Unitless or px sizes are converted at the CSS reference of 96 DPI
+func (s *SVG) RenderDPI(dpi float64) (*image.RGBA, error) {
+	if dpi <= 0 {
+		return nil, fmt.Errorf("invalid render dpi: %v", dpi)
+	}
+	widthInches := lengthToInches(s.doc.Width, float64(s.width))
+	heightInches := lengthToInches(s.doc.Height, float64(s.height))
+	width := int(math.Round(widthInches * dpi))
+	height := int(math.Round(heightInches * dpi))
+	return s.RenderToSize(width, height)
+}
+
+// RenderFrames 逐帧调用update修改文档并渲染，返回按固有尺寸渲染的帧
+// RenderFrames calls update to mutate the document before rendering each frame and returns the frames at the intrinsic size
+//
+// t为归一化时间，首帧为0、末帧为1；fps为帧率，决定帧间隔(1/fps秒)而不影响t。update的修改保留在文档中
+// t is normalized time, 0 on the first frame and 1 on the last; fps sets the frame interval (1/fps seconds) and does not affect t. Changes made by update stay in the document
+func (s *SVG) RenderFrames(n int, fps int, update func(t float64, doc *Document)) ([]*image.RGBA, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid frame count: %d", n)
+	}
+	if fps <= 0 {
+		return nil, fmt.Errorf("invalid frame rate: %d", fps)
+	}
+	if update == nil {
+		return nil, fmt.Errorf("frame update function is nil")
+	}
+
+	frames := make([]*image.RGBA, n)
+	for i := 0; i < n; i++ {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		update(t, s.doc)
+		frame, err := s.Render(0, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render frame %d: %v", i, err)
+		}
+		frames[i] = frame
+	}
+	return frames, nil
 }
 
 // SavePNG 保存为PNG文件 / Save as PNG file
@@ -394,6 +694,176 @@ func (s *SVG) SetSize(width, height int) *SVG {
 	return s
 }
 
+// SetRootAttribute 设置根<svg>元素的属性，如preserveAspectRatio、class或data-*，输出时一并序列化
+// SetRootAttribute sets an attribute on the root <svg> element, such as preserveAspectRatio, class or data-*, which is serialized with the document
+//
+// width、height和viewBox写入文档对应的字段，宽高同时更新画布尺寸 / width, height and viewBox go to the document's dedicated fields, and width and height also update the canvas size
+func (s *SVG) SetRootAttribute(name, value string) *SVG {
+	switch name {
+	case "width", "height":
+		pixels := 0
+		if length, err := ParseLength(value); err == nil && length.IsAbsolute() {
+			pixels = int(math.Round(length.Resolve(0, DefaultDPI)))
+		}
+		if name == "width" {
+			s.doc.Width, s.width = value, pixels
+		} else {
+			s.doc.Height, s.height = value, pixels
+		}
+	case "viewBox":
+		s.doc.ViewBox = value
+	default:
+		s.doc.SetAttribute(name, value)
+	}
+	return s
+}
+
+// GetRootAttribute 获取根<svg>元素的属性 / Get an attribute of the root <svg> element
+func (s *SVG) GetRootAttribute(name string) (string, bool) {
+	switch name {
+	case "width":
+		return s.doc.Width, s.doc.Width != ""
+	case "height":
+		return s.doc.Height, s.doc.Height != ""
+	case "viewBox":
+		return s.doc.ViewBox, s.doc.ViewBox != ""
+	}
+	return s.doc.GetAttribute(name)
+}
+
+// Crop 将文档裁剪到用户空间矩形：视图框设为该矩形，宽高按原有缩放比例缩小
+// Crop trims the document to a user-space rectangle: the viewBox becomes that rectangle and the width and height shrink at the existing scale
+//
+// 之后的渲染只显示该区域并缩放填满画布；w或h不为正时不做修改
+// Subsequent renders show only that region scaled to fill the canvas; a non-positive w or h leaves the document unchanged
+func (s *SVG) Crop(x, y, w, h float64) *SVG {
+	if w <= 0 || h <= 0 {
+		return s
+	}
+	_, _, viewWidth, viewHeight := s.viewBox()
+	width := int(math.Max(1, math.Round(w*float64(s.width)/viewWidth)))
+	height := int(math.Max(1, math.Round(h*float64(s.height)/viewHeight)))
+	s.doc.SetViewBox(x, y, w, h)
+	return s.SetSize(width, height)
+}
+
+// AutoCrop 裁剪到内容的几何边界，去除四周空白；没有可确定边界的内容时不做修改
+// AutoCrop crops to the geometry bounds of the content, trimming surrounding whitespace; without content of known bounds the document is unchanged
+func (s *SVG) AutoCrop() *SVG {
+	return s.FitViewBox(0)
+}
+
+// FitViewBox 将视图框设为内容边界（含变换）向外扩展padding，宽高按原有缩放比例调整
+// FitViewBox sets the viewBox to the transform-aware content bounds grown by padding on every side, resizing the width and height at the existing scale
+//
+// 没有可确定边界的内容时不做修改 / Without content of known bounds the document is unchanged
+func (s *SVG) FitViewBox(padding float64) *SVG {
+	bounds, ok := s.doc.ContentBounds()
+	if !ok {
+		return s
+	}
+	bounds = bounds.Inset(-padding)
+	return s.Crop(bounds.X, bounds.Y, bounds.W, bounds.H)
+}
+
+// Scale 永久缩放文档：宽高和视图框乘以factor，内容移入scale(factor)的组中，使所有几何的有效坐标随之缩放
+// Scale permanently rescales the document: the width, height and viewBox are multiplied by factor and the content moves into a scale(factor) group so every geometry's effective coordinates scale with it
+//
+// 与渲染缩放不同，结果保存在文档中；宽高的单位保持不变。之后添加的元素使用缩放后的坐标，factor不为正时不做修改
+// Unlike render scaling, the result is stored in the document and the width and height keep their units. Elements added afterwards use the scaled coordinates; a non-positive factor leaves the document unchanged
+func (s *SVG) Scale(factor float64) *SVG {
+	if factor <= 0 {
+		return s
+	}
+	x, y, w, h := s.viewBox()
+
+	group := elements.NewGroup()
+	group.SetAttribute("transform", fmt.Sprintf("scale(%g)", factor))
+	for _, element := range s.doc.Elements {
+		group.AppendChild(element)
+	}
+	s.doc.Elements = nil
+	s.doc.AppendElement(group)
+
+	s.doc.SetViewBox(x*factor, y*factor, w*factor, h*factor)
+	s.doc.Width = scaleLength(s.doc.Width, factor)
+	s.doc.Height = scaleLength(s.doc.Height, factor)
+	s.width = int(math.Round(float64(s.width) * factor))
+	s.height = int(math.Round(float64(s.height) * factor))
+	return s
+}
+
+// scaleLength 缩放带单位的长度值，无法解析时原样返回 / Scale a length value with units, returning it unchanged when it cannot be parsed
+func scaleLength(value string, factor float64) string {
+	length, err := ParseLength(value)
+	if err != nil {
+		return value
+	}
+	length.Value *= factor
+	return length.String()
+}
+
+// ConvertTextToOutlines 将所有文本元素替换为由字形轮廓组成的路径，使输出不依赖系统字体
+// ConvertTextToOutlines replaces every text element with a path built from its glyph outlines so the output no longer depends on system fonts
+//
+// 字体按渲染时的规则解析（包括SetTextRenderer设置的文本渲染器），填充、描边和位置保持不变；
+// 无法转换的文本保持原样，其说明作为警告返回
+// Fonts resolve as they do when rendering, including through the text renderer set with SetTextRenderer, and fill, stroke and position are preserved;
+// text that cannot be converted is kept and described in the returned warnings
+func (s *SVG) ConvertTextToOutlines() ([]string, error) {
+	r := s.newRenderer()
+	if err := r.ConvertTextToOutlines(s.doc); err != nil {
+		return nil, err
+	}
+	return r.Warnings(), nil
+}
+
+// EmbedFont 将字体以base64 data URI的@font-face规则嵌入文档，使文本在未安装该字体的查看器中也能正确显示
+// EmbedFont embeds a font as an @font-face rule with a base64 data URI so text displays correctly in viewers without the font installed
+//
+// 字体格式(TrueType、OpenType、WOFF、WOFF2)按文件头识别，字体按原样完整嵌入
+// The font format (TrueType, OpenType, WOFF or WOFF2) is detected from the file header, and the font is embedded whole
+func (s *SVG) EmbedFont(family string, fontData []byte) *SVG {
+	mime, format := embeddedFontFormat(fontData)
+	css := fmt.Sprintf("@font-face { font-family: \"%s\"; src: url(data:%s;base64,%s) format(\"%s\"); }",
+		strings.ReplaceAll(family, `"`, `\"`), mime, base64.StdEncoding.EncodeToString(fontData), format)
+	s.doc.AddDef(elements.NewStyle(css))
+	return s
+}
+
+// embeddedFontFormat 按文件头返回字体的MIME类型和CSS格式名 / Return a font's MIME type and CSS format name from its header
+func embeddedFontFormat(fontData []byte) (mime, format string) {
+	if len(fontData) >= 4 {
+		switch string(fontData[:4]) {
+		case "wOFF":
+			return "font/woff", "woff"
+		case "wOF2":
+			return "font/woff2", "woff2"
+		case "OTTO":
+			return "font/otf", "opentype"
+		}
+	}
+	return "font/ttf", "truetype"
+}
+
+// viewBox 返回文档的视图框，未设置时为(0, 0, 宽, 高) / Return the document's viewBox, defaulting to (0, 0, width, height)
+func (s *SVG) viewBox() (x, y, w, h float64) {
+	fields := strings.FieldsFunc(s.doc.ViewBox, func(c rune) bool { return c == ',' || c == ' ' })
+	if len(fields) == 4 {
+		values := make([]float64, 4)
+		valid := true
+		for i, field := range fields {
+			v, err := strconv.ParseFloat(field, 64)
+			valid = valid && err == nil
+			values[i] = v
+		}
+		if valid && values[2] > 0 && values[3] > 0 {
+			return values[0], values[1], values[2], values[3]
+		}
+	}
+	return 0, 0, float64(s.width), float64(s.height)
+}
+
 // ============================================================================
 // 元素类型绑定方法 / Element Type Binding Methods
 // ============================================================================
@@ -559,6 +1029,29 @@ func (p *PathElement) StrokeWidth(width float64) *PathElement {
 	return p
 }
 
+// FillRule 设置填充规则，"nonzero"或"evenodd" / Set the fill rule, "nonzero" or "evenodd"
+func (p *PathElement) FillRule(rule string) *PathElement {
+	p.builder.FillRule(rule)
+	return p
+}
+
+// Dash 设置描边虚线模式 / Set the stroke dash pattern
+func (p *PathElement) Dash(pattern ...float64) *PathElement {
+	p.builder.Dash(pattern...)
+	return p
+}
+
+// DashOffset 设置虚线模式的起始偏移 / Set the offset into the dash pattern
+func (p *PathElement) DashOffset(offset float64) *PathElement {
+	p.builder.DashOffset(offset)
+	return p
+}
+
+// MarchingAnts 创建循环移动虚线的"行军蚁"动画，speed为每秒移动的用户单位 / Create a looping marching-ants dash animation; speed is in user units per second
+func (p *PathElement) MarchingAnts(speed float64) *animation.PropertyAnimation {
+	return p.builder.MarchingAnts(speed)
+}
+
 func (p *PathElement) End() *SVG {
 	p.builder.End()
 	return p.svg
@@ -593,6 +1086,35 @@ func (s *ShapeElement) End() *SVG {
 	return s.svg
 }
 
+// PolyElement 折线或多边形元素 / Polyline or polygon element
+type PolyElement struct {
+	element Element
+	svg     *SVG
+}
+
+// Fill 设置填充颜色 / Set fill color
+func (p *PolyElement) Fill(color color.Color) *PolyElement {
+	p.element.SetAttribute("fill", attributes.ColorToHex(color))
+	return p
+}
+
+// Stroke 设置描边颜色 / Set stroke color
+func (p *PolyElement) Stroke(color color.Color) *PolyElement {
+	p.element.SetAttribute("stroke", attributes.ColorToHex(color))
+	return p
+}
+
+// StrokeWidth 设置描边宽度 / Set stroke width
+func (p *PolyElement) StrokeWidth(width float64) *PolyElement {
+	p.element.SetAttribute("stroke-width", strconv.FormatFloat(width, 'f', -1, 64))
+	return p
+}
+
+// End 结束构建 / End building
+func (p *PolyElement) End() *SVG {
+	return p.svg
+}
+
 // ChartElement 图表元素 / Chart element
 type ChartElement struct {
 	svg       *SVG
@@ -624,17 +1146,17 @@ type GroupElement struct {
 }
 
 func (g *GroupElement) Translate(x, y float64) *GroupElement {
-	g.builder.Transform(fmt.Sprintf("translate(%.2f,%.2f)", x, y))
+	g.builder.Transform(fmt.Sprintf("translate(%s,%s)", path.FormatNumber(x), path.FormatNumber(y)))
 	return g
 }
 
 func (g *GroupElement) Scale(sx, sy float64) *GroupElement {
-	g.builder.Transform(fmt.Sprintf("scale(%.2f,%.2f)", sx, sy))
+	g.builder.Transform(fmt.Sprintf("scale(%s,%s)", path.FormatNumber(sx), path.FormatNumber(sy)))
 	return g
 }
 
 func (g *GroupElement) Rotate(angle float64) *GroupElement {
-	g.builder.Transform(fmt.Sprintf("rotate(%.2f)", angle))
+	g.builder.Transform("rotate(" + path.FormatNumber(angle) + ")")
 	return g
 }
 
@@ -764,16 +1286,27 @@ func extractDimensions(doc *Document) (width, height float64) {
 	return width, height
 }
 
-// parseFloat 解析浮点数 / Parse float
-func parseFloat(s string, defaultValue float64) (float64, error) {
-	s = strings.TrimSuffix(s, "px")
-	s = strings.TrimSuffix(s, "pt")
-	s = strings.TrimSuffix(s, "em")
-	s = strings.TrimSuffix(s, "rem")
+// lengthToInches 将带单位的长度转换为英寸，按types.ParseLength解析，无单位或px按96 DPI换算
+// lengthToInches converts a length with units to inches, parsed by types.ParseLength with unitless and px lengths at 96 DPI
+//
+// 无法解析或相对的长度（百分比、em等）使用以像素为单位的默认值 / Lengths that cannot be parsed or are relative (percentages, em and so on) fall back to a pixel default
+func lengthToInches(length string, defaultPixels float64) float64 {
+	parsed, err := ParseLength(length)
+	if err != nil || !parsed.IsAbsolute() {
+		return defaultPixels / DefaultDPI
+	}
+	return parsed.Resolve(0, DefaultDPI) / DefaultDPI
+}
 
-	if s == "" {
+// parseFloat 按types.ParseLength解析长度并换算为像素，绝对单位按96 DPI，em和rem按默认字号，百分比相对0的视口
+// parseFloat parses a length with types.ParseLength and converts it to pixels: absolute units at 96 DPI, em and rem at the default font size, and percentages of a zero viewport
+func parseFloat(s string, defaultValue float64) (float64, error) {
+	if strings.TrimSpace(s) == "" {
 		return defaultValue, nil
 	}
-
-	return strconv.ParseFloat(s, 64)
+	length, err := ParseLength(s)
+	if err != nil {
+		return defaultValue, err
+	}
+	return length.Resolve(0, DefaultDPI), nil
 }
@@ -0,0 +1,1197 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/hoonfeng/svg/api"
+	"github.com/hoonfeng/svg/font"
+	"github.com/hoonfeng/svg/renderer"
+	"github.com/hoonfeng/svg/types"
+)
+
+const scaleTestSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="50" height="40" viewBox="0 0 50 40">
+	<circle cx="25" cy="20" r="15" fill="#000000"/>
+</svg>`
+
+// TestRenderScaled 测试按倍数渲染 / Test rendering at a scale factor
+func TestRenderScaled(t *testing.T) {
+	s, err := Parse(scaleTestSVG)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	img, err := s.RenderScaled(2)
+	if err != nil {
+		t.Fatalf("RenderScaled failed: %v", err)
+	}
+	if img.Bounds().Dx() != 100 || img.Bounds().Dy() != 80 {
+		t.Fatalf("expected 100x80 image, got %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+
+	// 边缘应该是抗锯齿的，并且过渡宽度不超过两个像素 / Edges should be anti-aliased with a transition no wider than two pixels
+	partial := 0
+	for x := 0; x < 50; x++ {
+		a := img.RGBAAt(x, 40).A
+		if a == 255 {
+			break
+		}
+		if a > 0 {
+			partial++
+		}
+	}
+	if partial == 0 {
+		t.Error("expected anti-aliased edge pixels at scale 2")
+	}
+	if partial > 2 {
+		t.Errorf("expected a crisp edge, got %d partially covered pixels", partial)
+	}
+
+	if _, err := s.RenderScaled(0); err == nil {
+		t.Error("expected error for non-positive scale")
+	}
+}
+
+// TestRenderDPI 测试按DPI渲染 / Test rendering at a DPI
+func TestRenderDPI(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height string
+		dpi           float64
+		wantW, wantH  int
+	}{
+		{"unitless at css dpi", "50", "40", 96, 50, 40},
+		{"unitless at double dpi", "50", "40", 192, 100, 80},
+		{"inches", "2in", "1in", 150, 300, 150},
+		{"millimeters", "25.4mm", "50.8mm", 100, 100, 200},
+		{"points and picas", "72pt", "3pc", 96, 96, 48},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(scaleTestSVG)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			s.doc.Width = tt.width
+			s.doc.Height = tt.height
+
+			img, err := s.RenderDPI(tt.dpi)
+			if err != nil {
+				t.Fatalf("RenderDPI failed: %v", err)
+			}
+			if img.Bounds().Dx() != tt.wantW || img.Bounds().Dy() != tt.wantH {
+				t.Errorf("expected %dx%d, got %dx%d", tt.wantW, tt.wantH, img.Bounds().Dx(), img.Bounds().Dy())
+			}
+		})
+	}
+}
+
+// TestExtractDimensionsUnits 测试文档尺寸按单位换算为像素 / Test document dimensions convert to pixels by their units
+func TestExtractDimensionsUnits(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="1in" height="12pt"></svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if s.width != 96 || s.height != 16 {
+		t.Errorf("expected 96x16, got %vx%v", s.width, s.height)
+	}
+}
+
+// TestRenderToRGBA 测试复用缓冲区渲染时清除旧内容 / Test rendering into a reused buffer clears the previous content
+func TestRenderToRGBA(t *testing.T) {
+	left, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20" viewBox="0 0 40 20">
+	<rect x="0" y="0" width="20" height="20" fill="#ff0000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	right, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20" viewBox="0 0 40 20">
+	<rect x="20" y="0" width="20" height="20" fill="#0000ff"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	buf := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	if err := left.RenderToRGBA(buf); err != nil {
+		t.Fatalf("first render failed: %v", err)
+	}
+	if got := buf.RGBAAt(10, 10); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Fatalf("expected red after first render, got %v", got)
+	}
+
+	if err := right.RenderToRGBA(buf); err != nil {
+		t.Fatalf("second render failed: %v", err)
+	}
+	if got := buf.RGBAAt(10, 10); got.A != 0 {
+		t.Errorf("expected the first render to be cleared, got %v", got)
+	}
+	if got := buf.RGBAAt(30, 10); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected blue after second render, got %v", got)
+	}
+
+	// 合成模式保留已有内容 / Composite mode keeps the existing content
+	if err := left.RenderToRGBA(buf, true); err != nil {
+		t.Fatalf("composite render failed: %v", err)
+	}
+	if buf.RGBAAt(10, 10).R != 255 || buf.RGBAAt(30, 10).B != 255 {
+		t.Errorf("expected both rects after compositing, got %v and %v", buf.RGBAAt(10, 10), buf.RGBAAt(30, 10))
+	}
+}
+
+// TestRenderGrayAndCMYK 测试灰度与CMYK输出 / Test grayscale and CMYK output
+func TestRenderGrayAndCMYK(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="10" viewBox="0 0 20 10">
+	<rect x="0" y="0" width="10" height="10" fill="#ff0000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	gray, err := s.RenderGray(20, 10)
+	if err != nil {
+		t.Fatalf("RenderGray failed: %v", err)
+	}
+	// 红色亮度为0.299*255 / The luminance of red is 0.299*255
+	if got := gray.GrayAt(5, 5).Y; got != 76 {
+		t.Errorf("expected red to map to gray 76, got %d", got)
+	}
+	if got := gray.GrayAt(15, 5).Y; got != 255 {
+		t.Errorf("expected transparent area to map to white, got %d", got)
+	}
+
+	cmyk, err := s.RenderCMYK(20, 10)
+	if err != nil {
+		t.Fatalf("RenderCMYK failed: %v", err)
+	}
+	if got := cmyk.CMYKAt(5, 5); got != (color.CMYK{C: 0, M: 255, Y: 255, K: 0}) {
+		t.Errorf("expected red as full magenta and yellow, got %v", got)
+	}
+	if got := cmyk.CMYKAt(15, 5); got != (color.CMYK{}) {
+		t.Errorf("expected transparent area to carry no ink, got %v", got)
+	}
+
+	for _, c := range []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}, {0, 0, 0, 255}, {255, 255, 255, 255}} {
+		if got := renderer.CMYKToRGBA(renderer.RGBAToCMYK(c)); got != c {
+			t.Errorf("CMYK round trip of %v gave %v", c, got)
+		}
+	}
+}
+
+// TestMerge 测试合并文档并平移 / Test merging a document at an offset
+func TestMerge(t *testing.T) {
+	base, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<rect id="shape" x="0" y="0" width="10" height="10" fill="#0000ff"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	sprite, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20">
+	<circle id="shape" cx="10" cy="10" r="8" fill="#ff0000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	base.Merge(sprite, 60, 40)
+
+	if base.doc.FindElementByID("shape") == nil || base.doc.FindElementByID("merge1-shape") == nil {
+		t.Error("expected the merged circle id to be namespaced alongside the original")
+	}
+	if sprite.doc.FindElementByID("shape") == nil {
+		t.Error("expected the source document to be left unchanged")
+	}
+
+	img, err := base.Render(100, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := img.RGBAAt(70, 50); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the circle at the offset position, got %v", got)
+	}
+	if got := img.RGBAAt(10, 10); got.A != 0 {
+		t.Errorf("expected no circle at the original position, got %v", got)
+	}
+}
+
+// TestFromImage 测试位图嵌入为数据URI后可解码回原像素 / Test that an embedded raster decodes back to the original pixels
+func TestFromImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			src.SetRGBA(x, y, color.RGBA{uint8(x * 60), uint8(y * 60), 200, 255})
+		}
+	}
+
+	s := FromImage(src)
+	if w, h := s.GetSize(); w != 4 || h != 4 {
+		t.Errorf("expected a 4x4 canvas, got %dx%d", w, h)
+	}
+	out := s.String()
+	if !strings.Contains(out, "<image") {
+		t.Fatalf("expected an <image> element, got %s", out)
+	}
+
+	const prefix = "data:image/png;base64,"
+	elements := s.GetDocument().Elements
+	if len(elements) != 1 {
+		t.Fatalf("expected one element, got %d", len(elements))
+	}
+	href, _ := elements[0].GetAttribute("href")
+	if !strings.HasPrefix(href, prefix) {
+		t.Fatalf("expected a PNG data URI, got %q", href)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(href, prefix))
+	if err != nil {
+		t.Fatalf("decoding base64 failed: %v", err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decoding PNG failed: %v", err)
+	}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if got := color.RGBAModel.Convert(decoded.At(x, y)); got != src.RGBAAt(x, y) {
+				t.Errorf("pixel (%d,%d): expected %v, got %v", x, y, src.RGBAAt(x, y), got)
+			}
+		}
+	}
+}
+
+// TestDrawOn 测试将SVG合成到已有图像上 / Test compositing an SVG onto an existing image
+func TestDrawOn(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20">
+		<circle cx="10" cy="10" r="8" fill="#ff0000"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	background := color.RGBA{0, 0, 255, 255}
+	dst := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	if err := s.DrawOn(dst, image.Rect(50, 30, 90, 70)); err != nil {
+		t.Fatalf("DrawOn failed: %v", err)
+	}
+
+	if got := dst.RGBAAt(70, 50); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the circle center to be red, got %v", got)
+	}
+	// 目标矩形内圆外及矩形外的背景保持不变 / Background inside the rect but outside the circle, and outside the rect, stays untouched
+	for _, p := range []image.Point{{51, 31}, {88, 68}, {10, 10}, {70, 80}} {
+		if got := dst.RGBAAt(p.X, p.Y); got != background {
+			t.Errorf("expected untouched background at %v, got %v", p, got)
+		}
+	}
+	// 抗锯齿边缘与背景混合 / Anti-aliased edges blend with the background
+	edge := dst.RGBAAt(70, 34)
+	if edge.A != 255 {
+		t.Errorf("expected an opaque edge pixel, got %v", edge)
+	}
+}
+
+// TestOptimize 测试移除无效元素和合并共线直线 / Test removing no-op elements and merging collinear lines
+func TestOptimize(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20">
+		<circle cx="10" cy="10" r="0" fill="#ff0000"/>
+		<rect x="0" y="0" width="5" height="5" fill="none"/>
+		<path d="M 0 0 L 5 5 L 10 10 L 10 20" fill="none" stroke="#000000" stroke-width="1" opacity="1"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	elements := s.Optimize().GetDocument().Elements
+	if len(elements) != 1 || elements[0].Tag() != "path" {
+		t.Fatalf("expected only the path to remain, got %d elements", len(elements))
+	}
+	attrs := elements[0].GetAttributes()
+	if got := attrs["d"]; got != "M 0 0 L 10 10 L 10 20" {
+		t.Errorf("expected the collinear middle point to be dropped, got %q", got)
+	}
+	for _, name := range []string{"stroke-width", "opacity"} {
+		if _, ok := attrs[name]; ok {
+			t.Errorf("expected default attribute %s to be removed", name)
+		}
+	}
+	if attrs["stroke"] != "#000000" {
+		t.Errorf("expected stroke to be kept, got %q", attrs["stroke"])
+	}
+}
+
+// TestOptimizePreservesRendering 测试优化前后渲染结果一致 / Test that optimizing does not change the rendered output
+func TestOptimizePreservesRendering(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="40" viewBox="0 0 40 40">
+		<polyline points="2,2 38,2 38,38" fill="none" stroke="none"/>
+		<polygon points="5,10 30,10 30,30" fill="#0000ff" stroke="none"/>
+		<line x1="0" y1="35" x2="40" y2="35" stroke="none" stroke-width="1"/>
+		<rect x="10" y="0" width="5" height="5" fill="none" stroke="#ff0000" stroke-width="1"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	before, err := s.Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// Render可能复用缓冲区，先复制 / Render may reuse its buffer, so copy first
+	want := append([]uint8(nil), before.Pix...)
+	after, err := s.Optimize().Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !bytes.Equal(want, after.Pix) {
+		t.Errorf("expected Optimize to leave the rendering unchanged")
+	}
+}
+
+// TestOptimizeInheritedStroke 测试继承描边的元素不会被当作不可见而移除 / Test that elements stroked through inheritance are not removed as invisible
+func TestOptimizeInheritedStroke(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20">
+		<g stroke="#ff0000"><rect x="2" y="2" width="10" height="10" fill="none"/></g>
+		<g fill="none"><circle cx="10" cy="10" r="5"/></g>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	elements := s.Optimize().GetDocument().Elements
+	if len(elements) != 1 {
+		t.Fatalf("expected only the stroked group to remain, got %d elements", len(elements))
+	}
+	children := elements[0].Children()
+	if len(children) != 1 || children[0].Tag() != "rect" {
+		t.Errorf("expected the rect stroked by its group to be kept")
+	}
+}
+
+// TestThumbnail 测试缩略图保持宽高比 / Test thumbnails preserve the aspect ratio
+func TestThumbnail(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100" viewBox="0 0 200 100">
+		<rect x="0" y="0" width="200" height="100" fill="#ff0000"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	img, err := s.Thumbnail(50)
+	if err != nil {
+		t.Fatalf("Thumbnail failed: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 50 || b.Dy() != 25 {
+		t.Errorf("expected a 50x25 thumbnail, got %dx%d", b.Dx(), b.Dy())
+	}
+	if got := img.RGBAAt(25, 12); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the scaled content, got %v", got)
+	}
+
+	if _, err := s.Thumbnail(0); err == nil {
+		t.Error("expected an error for a non-positive size")
+	}
+}
+
+// TestValidate 测试Validate报告未定义引用和其他结构问题 / Test Validate reports undefined references and other structural problems
+func TestValidate(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="40" height="20">
+		<rect x="0" y="0" width="10" height="10" fill="url(#nope)"/>
+		<circle cx="20" cy="10" r="-3"/>
+		<path d="M 0 0 L 10"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	problems := s.Validate()
+	if len(problems) != 3 {
+		t.Fatalf("expected 3 problems, got %v", problems)
+	}
+	for i, want := range []string{`fill references undefined id "nope"`, "r must not be negative", "malformed path data"} {
+		if !strings.Contains(problems[i].Error(), want) {
+			t.Errorf("expected problem %d to mention %q, got %q", i, want, problems[i])
+		}
+	}
+
+	valid, err := Parse(scaleTestSVG)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if problems := valid.Validate(); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+// TestDashedLineChart 测试折线图的虚线模式写入路径并渲染为虚线 / Test a line chart's dash pattern is written to its path and renders as dashes
+func TestDashedLineChart(t *testing.T) {
+	gen := api.NewSVGGenerator(100, 40)
+	gen.CreateChart("line", []float64{5, 5, 5}, api.ChartOptions{
+		Width:       100,
+		Height:      20,
+		StrokeColor: color.Black,
+		DashPattern: []float64{10, 10},
+	})
+	doc := gen.GetDocument()
+
+	if len(doc.Elements) != 1 {
+		t.Fatalf("expected one chart path, got %d elements", len(doc.Elements))
+	}
+	if got, _ := doc.Elements[0].GetAttribute("stroke-dasharray"); got != "10,10" {
+		t.Errorf("expected stroke-dasharray 10,10, got %q", got)
+	}
+
+	img, err := renderer.NewImageRenderer().Render(doc, 100, 40)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 平直数据的折线位于y=20 / Flat data puts the line at y=20
+	for _, x := range []int{5, 25, 45, 65} {
+		if a := img.RGBAAt(x, 20).A; a == 0 {
+			t.Errorf("expected a dash at x=%d", x)
+		}
+	}
+	for _, x := range []int{15, 35, 55, 75} {
+		if a := img.RGBAAt(x, 20).A; a != 0 {
+			t.Errorf("expected a gap at x=%d, got alpha %d", x, a)
+		}
+	}
+}
+
+// TestPieChartLabels 测试饼图在各扇形质心处标注百分比 / Test a pie chart labels each slice with its percentage at the centroid
+func TestPieChartLabels(t *testing.T) {
+	gen := api.NewSVGGenerator(200, 200)
+	gen.CreateChart("pie", []float64{1, 1, 2}, api.ChartOptions{
+		Width:       200,
+		Height:      200,
+		StrokeColor: color.Black,
+		LabelColor:  color.White,
+		FontSize:    12,
+	})
+
+	var labels []string
+	for _, el := range gen.GetDocument().Elements {
+		if el.Tag() != "text" {
+			continue
+		}
+		labels = append(labels, el.(interface{ GetContent() string }).GetContent())
+		if fill, _ := el.GetAttribute("fill"); fill != "rgb(255,255,255)" {
+			t.Errorf("expected white label fill, got %q", fill)
+		}
+	}
+	want := []string{"25.0%", "25.0%", "50.0%"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("expected labels %q, got %q", want, labels)
+	}
+
+	// 首个扇形覆盖0到90度，质心位于右下方 / The first slice covers 0 to 90 degrees, so its centroid is to the lower right
+	first := gen.GetDocument().Elements[3]
+	x, _ := first.GetAttribute("x")
+	y, _ := first.GetAttribute("y")
+	if fx, _ := strconv.ParseFloat(x, 64); fx <= 100 {
+		t.Errorf("expected the first label right of centre, got x=%s", x)
+	}
+	if fy, _ := strconv.ParseFloat(y, 64); fy <= 100 {
+		t.Errorf("expected the first label below centre, got y=%s", y)
+	}
+}
+
+// TestBarChartLabels 测试柱状图的数值和类别标签 / Test a bar chart's value and category labels
+func TestBarChartLabels(t *testing.T) {
+	gen := api.NewSVGGenerator(100, 120)
+	gen.CreateChart("bar", []float64{3, 6}, api.ChartOptions{
+		Width:       100,
+		Height:      100,
+		FillColor:   color.Black,
+		StrokeColor: color.Black,
+		Labels:      []string{"a", "b"},
+		FontSize:    10,
+	})
+
+	var labels []string
+	for _, el := range gen.GetDocument().Elements {
+		if el.Tag() == "text" {
+			labels = append(labels, el.(interface{ GetContent() string }).GetContent())
+		}
+	}
+	want := []string{"3", "a", "6", "b"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("expected labels %q, got %q", want, labels)
+	}
+
+	// 标签留在图表区域内：数值的基线下方有一行字高，类别标签在底部一行内 / Labels stay inside the chart area: a line of text fits above each value baseline and the categories fit in the bottom line
+	for _, el := range gen.GetDocument().Elements {
+		if el.Tag() != "text" {
+			continue
+		}
+		y, _ := el.GetAttribute("y")
+		fy, _ := strconv.ParseFloat(y, 64)
+		if baseline, _ := el.GetAttribute("dominant-baseline"); baseline == "hanging" {
+			if fy+10 > 100 {
+				t.Errorf("expected category label at y=%s to fit inside the chart", y)
+			}
+		} else if fy-10 < 0 {
+			t.Errorf("expected value label at y=%s to fit inside the chart", y)
+		}
+	}
+
+	// SVG上的图表默认不带标签 / Charts on an SVG have no labels by default
+	s := New(100, 100)
+	s.BarChart([]float64{3, 6}, 0, 0, 100, 100)
+	s.PieChart([]float64{1, 2}, 50, 50, 40)
+	tags := map[string]int{}
+	for _, el := range s.gen.GetDocument().Elements {
+		tags[el.Tag()]++
+	}
+	if tags["rect"] != 2 || tags["text"] != 0 {
+		t.Errorf("expected two bars and no labels by default, got %v", tags)
+	}
+}
+
+// TestPathFillRule 测试通过构建器设置的evenodd使自相交五角星中心镂空 / Test evenodd set through the builder leaves a self-intersecting star's centre hollow
+func TestPathFillRule(t *testing.T) {
+	const star = "M 50 5 L 79 95 L 2 39 L 98 39 L 21 95 Z"
+
+	for _, tc := range []struct {
+		rule   string
+		filled bool
+	}{
+		{"evenodd", false},
+		{"nonzero", true},
+	} {
+		for _, crisp := range []bool{false, true} {
+			builder := api.NewSVGBuilder(100, 100)
+			builder.AddPath(star).Fill(color.Black).FillRule(tc.rule).End()
+
+			r := renderer.NewImageRenderer()
+			r.CrispEdges = crisp
+			img, err := r.Render(builder.GetDocument(), 100, 100)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if got := img.RGBAAt(50, 50).A != 0; got != tc.filled {
+				t.Errorf("%s (crisp=%v): expected centre filled=%v, got %v", tc.rule, crisp, tc.filled, got)
+			}
+			// 星角始终填充 / The star's points are always filled
+			if img.RGBAAt(50, 20).A == 0 {
+				t.Errorf("%s (crisp=%v): expected the top point to be filled", tc.rule, crisp)
+			}
+		}
+	}
+}
+
+// TestRenderFrames 测试逐帧更新文档并渲染移动的圆 / Test updating the document per frame to render a moving circle
+func TestRenderFrames(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="20" viewBox="0 0 100 20">
+	<circle cx="10" cy="10" r="5" fill="#000000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var times []float64
+	frames, err := s.RenderFrames(5, 10, func(ft float64, doc *types.Document) {
+		times = append(times, ft)
+		doc.Elements[0].SetAttribute("cx", strconv.FormatFloat(10+80*ft, 'f', -1, 64))
+	})
+	if err != nil {
+		t.Fatalf("RenderFrames failed: %v", err)
+	}
+	if len(frames) != 5 {
+		t.Fatalf("expected 5 frames, got %d", len(frames))
+	}
+	if times[0] != 0 || times[4] != 1 {
+		t.Errorf("expected normalized time from 0 to 1, got %v", times)
+	}
+
+	// 每帧圆心处应被覆盖，前一帧的位置应为空 / Each frame covers its circle center and leaves the previous position empty
+	for i, frame := range frames {
+		cx := int(10 + 80*times[i])
+		if frame.RGBAAt(cx, 10).A != 255 {
+			t.Errorf("frame %d: expected the circle at x=%d", i, cx)
+		}
+		if i > 0 {
+			prev := int(10 + 80*times[i-1])
+			if frame.RGBAAt(prev, 10).A != 0 {
+				t.Errorf("frame %d: expected x=%d to be empty", i, prev)
+			}
+		}
+	}
+
+	if _, err := s.RenderFrames(0, 10, func(float64, *types.Document) {}); err == nil {
+		t.Error("expected error for zero frames")
+	}
+}
+
+// TestMarchingAnts 测试行军蚁动画推进时虚线相位沿路径移动并循环 / Test the marching-ants animation shifts the dash phase along the path and loops
+func TestMarchingAnts(t *testing.T) {
+	builder := api.NewSVGBuilder(100, 20)
+	anim := builder.AddPath("M 0 10 L 100 10").Stroke(color.Black).StrokeWidth(2).Dash(10, 10).MarchingAnts(20)
+	anim.Start()
+
+	render := func() *image.RGBA {
+		img, err := renderer.NewImageRenderer().Render(builder.GetDocument(), 100, 20)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		return img
+	}
+
+	first := render()
+	// 周期20、速度20，四分之一秒前进5个单位 / A period of 20 at speed 20 advances 5 units in a quarter second
+	anim.Update(0.25)
+	second := render()
+	if first.RGBAAt(2, 10).A == 0 || second.RGBAAt(2, 10).A != 0 {
+		t.Errorf("expected x=2 to move from dash to gap, got alpha %d then %d", first.RGBAAt(2, 10).A, second.RGBAAt(2, 10).A)
+	}
+	if first.RGBAAt(12, 10).A != 0 || second.RGBAAt(12, 10).A == 0 {
+		t.Errorf("expected x=12 to move from gap to dash, got alpha %d then %d", first.RGBAAt(12, 10).A, second.RGBAAt(12, 10).A)
+	}
+
+	// 一个完整周期后回到相同相位 / A full period later the phase repeats
+	anim.Update(1)
+	if !anim.IsRunning() {
+		t.Fatal("expected the animation to keep looping")
+	}
+	if looped := render(); !bytes.Equal(looped.Pix, second.Pix) {
+		t.Error("expected the dash phase to repeat after one period")
+	}
+}
+
+// TestCrop 测试裁剪后只渲染裁剪区域并缩放填满 / Test that after cropping only the cropped region renders, scaled to fill
+func TestCrop(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<rect x="50" y="50" width="40" height="40" fill="#000000"/>
+	<rect x="5" y="5" width="10" height="10" fill="#ff0000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	s.Crop(50, 50, 50, 50)
+	if w, h := s.GetSize(); w != 50 || h != 50 {
+		t.Errorf("expected the size to shrink to 50x50, got %dx%d", w, h)
+	}
+
+	img, err := s.Render(100, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 黑色矩形占裁剪区域的前80%，放大2倍后覆盖到80像素 / The black rect fills the first 80% of the crop and reaches 80 pixels at 2x
+	if got := img.RGBAAt(75, 75); got.A != 255 || got.R != 0 {
+		t.Errorf("expected the black rect scaled up to (75,75), got %v", got)
+	}
+	if got := img.RGBAAt(85, 85); got.A != 0 {
+		t.Errorf("expected the area past the rect to be empty, got %v", got)
+	}
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			if img.RGBAAt(x, y).R != 0 {
+				t.Fatalf("expected the red rect outside the crop to be gone, found it at (%d,%d)", x, y)
+			}
+		}
+	}
+
+	// AutoCrop裁剪到两个矩形的并集 / AutoCrop trims to the union of both rects
+	s, err = Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<rect x="50" y="50" width="40" height="40" fill="#000000"/>
+	<rect x="5" y="5" width="10" height="10" fill="#ff0000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	s.AutoCrop()
+	if w, h := s.GetSize(); w != 85 || h != 85 {
+		t.Errorf("expected AutoCrop to size the document to 85x85, got %dx%d", w, h)
+	}
+	if vb := s.GetDocument().ViewBox; !strings.HasPrefix(vb, "5.000000 5.000000 85.000000 85.000000") {
+		t.Errorf("expected the viewBox to fit the content, got %q", vb)
+	}
+}
+
+// TestFitViewBox 测试视图框适配含变换的内容并留出边距 / Test the viewBox fits transformed content with padding
+func TestFitViewBox(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200" viewBox="0 0 200 200">
+	<rect x="10" y="20" width="30" height="30" fill="#000000"/>
+	<g transform="translate(100, 50)">
+		<circle cx="0" cy="0" r="20" fill="#ff0000"/>
+	</g>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	s.FitViewBox(5)
+	// 内容为(10,20)-(120,70)，每边扩展5 / Content spans (10,20)-(120,70), grown by 5 on each side
+	if vb := s.GetDocument().ViewBox; !strings.HasPrefix(vb, "5.000000 15.000000 120.000000 60.000000") {
+		t.Errorf("expected the viewBox to enclose both shapes with padding, got %q", vb)
+	}
+	if w, h := s.GetSize(); w != 120 || h != 60 {
+		t.Errorf("expected the size to follow the viewBox at 1x, got %dx%d", w, h)
+	}
+}
+
+// TestFitViewBoxHorizontalLine 测试视图框包含零高的水平线 / Test the viewBox includes a zero-height horizontal line
+func TestFitViewBoxHorizontalLine(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<rect x="40" y="40" width="10" height="10" fill="#000000"/>
+	<line x1="0" y1="45" x2="20" y2="45" stroke="#000000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	s.FitViewBox(0)
+	if vb := s.GetDocument().ViewBox; !strings.HasPrefix(vb, "0.000000 40.000000 50.000000 10.000000") {
+		t.Errorf("expected the viewBox to include the line, got %q", vb)
+	}
+}
+
+// TestEmbedFont 测试嵌入字体生成带data URI的@font-face规则 / Test embedding a font produces an @font-face rule with a data URI
+func TestEmbedFont(t *testing.T) {
+	s := New(100, 50)
+	s.EmbedFont("Go Regular", goregular.TTF)
+	s.Text(10, 30, "Go").End()
+
+	out := s.String()
+	for _, want := range []string{
+		"@font-face",
+		`font-family: "Go Regular"`,
+		"data:font/ttf;base64," + base64.StdEncoding.EncodeToString(goregular.TTF[:48]),
+		`format("truetype")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected serialized SVG to contain %q", want)
+		}
+	}
+	if !strings.Contains(out, "<defs>\n<style") {
+		t.Errorf("expected the style sheet inside defs")
+	}
+}
+
+// TestRemoveElement 测试移除元素后其不再出现在文档树和渲染结果中 / Test a removed element disappears from both the tree and the rendered output
+func TestRemoveElement(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="90" height="30" viewBox="0 0 90 30">
+	<rect x="0" y="0" width="30" height="30" fill="#ff0000"/>
+	<rect x="30" y="0" width="30" height="30" fill="#00ff00"/>
+	<rect x="60" y="0" width="30" height="30" fill="#0000ff"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	doc := s.GetDocument()
+	middle := doc.Elements[1]
+
+	if !doc.RemoveElement(middle) {
+		t.Fatal("expected the middle rect to be found")
+	}
+	if len(doc.Elements) != 2 || strings.Contains(s.String(), "#00ff00") {
+		t.Errorf("expected the middle rect to be gone from the tree, got %d elements", len(doc.Elements))
+	}
+
+	img, err := s.Render(90, 30)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := img.RGBAAt(45, 15); got.A != 0 {
+		t.Errorf("expected the removed rect not to be drawn, got %v", got)
+	}
+	if got := img.RGBAAt(15, 15); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected the first rect to remain, got %v", got)
+	}
+	if got := img.RGBAAt(75, 15); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected the last rect to remain, got %v", got)
+	}
+}
+
+// TestBuilderLengths 测试构建器写入带单位的长度并按单位渲染 / Test the builder writes lengths with units and renders them by unit
+func TestBuilderLengths(t *testing.T) {
+	builder := api.NewSVGBuilder(200, 100)
+	builder.AddRectLength(types.Length{Value: 0}, types.Length{Value: 0},
+		types.Length{Value: 1, Unit: types.UnitIn}, types.Length{Value: 10, Unit: types.UnitMm}).
+		Fill(color.Black).
+		StrokeWidthLength(types.Length{Value: 0.5, Unit: types.UnitPt}).
+		End()
+	builder.AddTextLength(types.Length{Value: 1, Unit: types.UnitCm}, types.Length{Value: 80}, "hi").
+		FontSizeLength(types.Length{Value: 2, Unit: types.UnitEm}).
+		End()
+
+	doc := builder.GetDocument()
+	rect, text := doc.Elements[0], doc.Elements[1]
+	for element, want := range map[types.Element]map[string]string{
+		rect: {"width": "1in", "height": "10mm", "stroke-width": "0.5pt"},
+		text: {"x": "1cm", "y": "80", "font-size": "2em"},
+	} {
+		for name, value := range want {
+			if got, _ := element.GetAttribute(name); got != value {
+				t.Errorf("expected %s=%q, got %q", name, value, got)
+			}
+		}
+	}
+
+	img, err := renderer.NewImageRenderer().Render(doc, 200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 1英寸为96像素，10毫米约为37.8像素 / 1 inch is 96 pixels and 10 millimeters about 37.8 pixels
+	if got := img.RGBAAt(94, 36).A; got != 255 {
+		t.Errorf("expected the rect to reach (94,36), got alpha %d", got)
+	}
+	if got := img.RGBAAt(98, 20).A; got != 0 {
+		t.Errorf("expected the rect to end near x=96, got alpha %d", got)
+	}
+	if got := img.RGBAAt(20, 40).A; got != 0 {
+		t.Errorf("expected the rect to end near y=38, got alpha %d", got)
+	}
+}
+
+// TestDiff 测试结构差异报告改变的属性以及新增和删除的元素 / Test the structural diff reports changed attributes and added and removed elements
+func TestDiff(t *testing.T) {
+	const before = `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<rect x="0" y="0" width="10" height="10" fill="#ff0000"/>
+	<rect id="box" x="20" y="0" width="10" height="10" fill="#00ff00"/>
+	<circle cx="50" cy="50" r="5" fill="#0000ff"/>
+</svg>`
+	a, err := Parse(before)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	b, err := Parse(strings.Replace(before, `fill="#00ff00"`, `fill="#ffff00"`, 1))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if changes := a.Diff(a); len(changes) != 0 {
+		t.Errorf("expected no changes against itself, got %v", changes)
+	}
+	changes := a.Diff(b)
+	if len(changes) != 1 {
+		t.Fatalf("expected exactly one change, got %v", changes)
+	}
+	want := Change{
+		Kind: ChangeModified, Path: "rect#box", Tag: "rect", ID: "box",
+		Attributes: []AttributeChange{{Name: "fill", Old: "#00ff00", New: "#ffff00"}},
+	}
+	if !reflect.DeepEqual(changes[0], want) {
+		t.Errorf("expected %+v, got %+v", want, changes[0])
+	}
+
+	// 删除第一个矩形、新增一个椭圆 / Remove the first rect and add an ellipse
+	c, err := Parse(strings.Replace(strings.Replace(before, `<rect x="0" y="0" width="10" height="10" fill="#ff0000"/>`, "", 1),
+		"</svg>", `<ellipse cx="5" cy="5" rx="2" ry="1"/></svg>`, 1))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var kinds []string
+	for _, change := range a.Diff(c) {
+		kinds = append(kinds, change.String())
+	}
+	if want := []string{"removed rect[0]", "added ellipse[0]"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("expected %q, got %q", want, kinds)
+	}
+
+	// 重复的ID不会匹配到同一个元素 / Repeated IDs do not both match the same element
+	d, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg"><rect id="dup" width="1" height="1"/><rect id="dup" width="2" height="2"/></svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	e, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg"><rect id="dup" width="1" height="1"/></svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	kinds = nil
+	for _, change := range d.Diff(e) {
+		kinds = append(kinds, change.String())
+	}
+	if want := []string{"removed rect#dup"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("expected %q, got %q", want, kinds)
+	}
+}
+
+// TestScale 测试缩放文档后几何坐标和固有渲染尺寸加倍 / Test scaling a document doubles the geometry and the intrinsic render size
+func TestScale(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50" viewBox="0 0 100 50">
+	<rect x="10" y="10" width="20" height="10" fill="#000000"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	before, err := s.Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	s.Scale(2)
+	if w, h := s.GetSize(); w != 200 || h != 100 {
+		t.Errorf("expected the size to double to 200x100, got %dx%d", w, h)
+	}
+	if doc := s.GetDocument(); doc.Width != "200" || doc.Height != "100" {
+		t.Errorf("expected width and height attributes 200 and 100, got %q and %q", doc.Width, doc.Height)
+	}
+	if bounds, ok := s.GetDocument().ContentBounds(); !ok || bounds != (types.Rect{X: 20, Y: 20, W: 40, H: 20}) {
+		t.Errorf("expected the rect's effective bounds to double to {20 20 40 20}, got %v", bounds)
+	}
+
+	after, err := s.Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if after.Bounds().Dx() != 2*before.Bounds().Dx() || after.Bounds().Dy() != 2*before.Bounds().Dy() {
+		t.Fatalf("expected the render to double from %v, got %v", before.Bounds(), after.Bounds())
+	}
+	// 缩放后的矩形覆盖(20,20)-(60,40) / The scaled rect covers (20,20)-(60,40)
+	if got := after.RGBAAt(55, 35); got.A != 255 {
+		t.Errorf("expected the scaled rect at (55,35), got %v", got)
+	}
+	if got := after.RGBAAt(15, 15); got.A != 0 {
+		t.Errorf("expected (15,15) outside the scaled rect to be empty, got %v", got)
+	}
+}
+
+// TestRootAttribute 测试根属性的序列化以及preserveAspectRatio对渲染的影响 / Test root attributes are serialized and preserveAspectRatio affects rendering
+func TestRootAttribute(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+	<rect x="0" y="0" width="50" height="100" fill="#ff0000"/>
+	<rect x="50" y="0" width="50" height="100" fill="#0000ff"/>
+	<rect x="0" y="0" width="100" height="20" fill="#00ff00"/>
+</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// 未设置时视图框拉伸填满画布 / Without the attribute the viewBox stretches to fill the canvas
+	stretched, err := s.Render(200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := stretched.RGBAAt(10, 50); got.R != 255 {
+		t.Errorf("expected the stretched red rect at the left edge, got %v", got)
+	}
+	if got := stretched.RGBAAt(100, 5); got.G != 255 {
+		t.Errorf("expected the green band at the top, got %v", got)
+	}
+
+	s.SetRootAttribute("preserveAspectRatio", "xMidYMid slice").SetRootAttribute("data-kind", "test")
+	if value, ok := s.GetRootAttribute("preserveAspectRatio"); !ok || value != "xMidYMid slice" {
+		t.Errorf("expected the attribute to be stored, got %q, %v", value, ok)
+	}
+	out := s.String()
+	for _, want := range []string{`preserveAspectRatio="xMidYMid slice"`, `data-kind="test"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in the output", want)
+		}
+	}
+
+	// slice按2倍等比缩放并居中，只能看到视图框中间的50单位高 / slice scales uniformly by 2 and centers, showing only the middle 50 units vertically
+	sliced, err := s.Render(200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got := sliced.RGBAAt(10, 50); got.R != 255 || got.B != 0 {
+		t.Errorf("expected red at the left, got %v", got)
+	}
+	if got := sliced.RGBAAt(190, 50); got.B != 255 || got.R != 0 {
+		t.Errorf("expected blue at the right, got %v", got)
+	}
+	if got := sliced.RGBAAt(100, 5); got.G != 0 {
+		t.Errorf("expected the green band to be cropped away, got %v", got)
+	}
+
+	s.SetRootAttribute("preserveAspectRatio", "xMidYMid meet")
+	met, err := s.Render(200, 100)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// meet按1倍缩放并水平居中于50..150 / meet keeps a scale of 1 and centers horizontally in 50..150
+	if got := met.RGBAAt(10, 50); got.A != 0 {
+		t.Errorf("expected the letterbox at the left to be empty, got %v", got)
+	}
+	if got := met.RGBAAt(60, 50); got.R != 255 {
+		t.Errorf("expected red just inside the centered viewBox, got %v", got)
+	}
+}
+
+// TestPoints 测试按顶点创建折线和多边形及其渲染 / Test creating polylines and polygons from vertices and rendering them
+func TestPoints(t *testing.T) {
+	s := NewWithViewBox(100, 100, 0, 0, 100, 100)
+	triangle := []types.Point{{X: 10, Y: 90}, {X: 50, Y: 10}, {X: 90, Y: 90}}
+	s.Points(triangle, false).Stroke(color.RGBA{255, 0, 0, 255}).StrokeWidth(4).End()
+
+	out := s.String()
+	if !strings.Contains(out, `<polyline`) || !strings.Contains(out, `points="10,90 50,10 90,90"`) {
+		t.Errorf("expected a polyline with the triangle's points, got %s", out)
+	}
+	if !strings.Contains(out, `stroke-width="4"`) || !strings.Contains(out, `fill="none"`) {
+		t.Errorf("expected an unfilled polyline with a stroke width of 4, got %s", out)
+	}
+
+	img, err := s.Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 左边的中点在描边上，三角形内部不填充 / The midpoint of the left side is on the stroke and the inside is unfilled
+	if got := img.RGBAAt(30, 50); got.R < 200 || got.A < 200 {
+		t.Errorf("expected the red stroke at (30,50), got %v", got)
+	}
+	if got := img.RGBAAt(50, 70); got.A != 0 {
+		t.Errorf("expected the open polyline to be unfilled, got %v", got)
+	}
+
+	s.Points(triangle, true).Fill(color.RGBA{0, 0, 255, 255}).End()
+	if out := s.String(); !strings.Contains(out, `<polygon`) || !strings.Contains(out, `fill="#0000ff"`) {
+		t.Errorf("expected a closed shape to be a blue polygon, got %s", out)
+	}
+	img, err = s.Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// 闭合形状的内部被填充 / The inside of the closed shape is filled
+	if got := img.RGBAAt(50, 70); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected the polygon to be filled blue at (50,70), got %v", got)
+	}
+}
+
+// TestRenderToASCII 测试文本预览中心为暗字符、角落为亮字符 / Test the text preview is dark at the center and light in the corners
+func TestRenderToASCII(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+		<rect x="0" y="0" width="100" height="100" fill="#ffffff"/>
+		<circle cx="50" cy="50" r="35" fill="#000000"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(s.RenderToASCII(20, 10), "\n"), "\n")
+	if len(lines) != 10 || len(lines[0]) != 20 {
+		t.Fatalf("expected 10 lines of 20 characters, got %q", lines)
+	}
+	dark, light := asciiRamp[len(asciiRamp)-3:], asciiRamp[:2]
+	for _, cell := range [][2]int{{4, 9}, {4, 10}, {5, 9}, {5, 10}} {
+		if c := lines[cell[0]][cell[1]]; !strings.ContainsRune(dark, rune(c)) {
+			t.Errorf("expected a dark character at row %d column %d, got %q", cell[0], cell[1], c)
+		}
+	}
+	for _, cell := range [][2]int{{0, 0}, {0, 19}, {9, 0}, {9, 19}} {
+		if c := lines[cell[0]][cell[1]]; !strings.ContainsRune(light, rune(c)) {
+			t.Errorf("expected a light character at row %d column %d, got %q", cell[0], cell[1], c)
+		}
+	}
+
+	ansi := strings.Split(s.RenderToANSI(20, 10), "\n")
+	if !strings.HasPrefix(ansi[0], "\x1b[48;2;255;255;255m ") || !strings.Contains(ansi[5], "\x1b[48;2;0;0;0m ") {
+		t.Errorf("expected white corners and black center cells in the ANSI preview, got %q and %q", ansi[0], ansi[5])
+	}
+	if s.RenderToASCII(0, 10) != "" {
+		t.Error("expected an empty preview for an invalid size")
+	}
+}
+
+// TestSetFlatness 测试展平容差传递到渲染 / Test the flattening tolerance is passed on to rendering
+func TestSetFlatness(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+		<path d="M 10 50 C 10 0 90 0 90 50 S 10 100 10 50 Z" fill="#000000"/>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	smooth, err := s.SetFlatness(0.1).Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	rough, err := s.SetFlatness(20).Render(0, 0)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if equal, _, _ := renderer.CompareImages(smooth, rough, 0); equal {
+		t.Error("expected a coarse tolerance to change the rendered curve")
+	}
+}
+
+// countingTextRenderer 统计绘制次数的文本渲染器 / A text renderer that counts its draws
+type countingTextRenderer struct {
+	font.TextRenderer
+	draws int
+}
+
+// RenderText 计数后委托给内嵌的渲染器 / Count, then delegate to the embedded renderer
+func (c *countingTextRenderer) RenderText(img draw.Image, text string, x, y float64, style *font.TextStyle) error {
+	c.draws++
+	return c.TextRenderer.RenderText(img, text, x, y, style)
+}
+
+// TestSetTextRenderer 测试各次渲染复用设置的文本渲染器，未设置时使用font.DefaultTextRenderer
+// TestSetTextRenderer tests renders reuse the configured text renderer and use font.DefaultTextRenderer when unset
+func TestSetTextRenderer(t *testing.T) {
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="60" height="20" viewBox="0 0 60 20">
+		<text x="2" y="15">Hi</text>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	injected := &countingTextRenderer{TextRenderer: font.NewSVGTextRenderer()}
+	s.SetTextRenderer(injected)
+	for i := 0; i < 2; i++ {
+		if _, err := s.Render(0, 0); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+	}
+	if injected.draws != 2 {
+		t.Errorf("expected both renders to use the injected text renderer, got %d draws", injected.draws)
+	}
+
+	previous := font.DefaultTextRenderer
+	defer func() { font.DefaultTextRenderer = previous }()
+	shared := &countingTextRenderer{TextRenderer: previous}
+	font.DefaultTextRenderer = shared
+	if _, err := s.SetTextRenderer(nil).Render(0, 0); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if shared.draws != 1 || injected.draws != 2 {
+		t.Errorf("expected the default text renderer after clearing, got %d default and %d injected draws", shared.draws, injected.draws)
+	}
+}
+
+// TestConvertTextToOutlinesTextRenderer 测试轮廓转换使用SetTextRenderer设置的字体，无法转换的文本作为警告返回
+// TestConvertTextToOutlinesTextRenderer tests outline conversion uses the fonts of the text renderer set with SetTextRenderer and reports unconvertible text as warnings
+func TestConvertTextToOutlinesTextRenderer(t *testing.T) {
+	fontPath := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(fontPath, goregular.TTF, 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	textRenderer := font.NewSVGTextRenderer()
+	if err := textRenderer.LoadFontFromFile(fontPath, "Injected", 20); err != nil {
+		t.Fatalf("LoadFontFromFile failed: %v", err)
+	}
+
+	s, err := Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="40" viewBox="0 0 100 40">
+		<text x="2" y="15" font-family="Injected" font-size="20">Hi</text>
+		<text x="2" y="35" font-family="NoSuchOutlineFont">kept</text>
+	</svg>`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	warnings, err := s.SetTextRenderer(textRenderer).ConvertTextToOutlines()
+	if err != nil {
+		t.Fatalf("ConvertTextToOutlines failed: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected one warning for the text without outlines, got %v", warnings)
+	}
+	out := s.String()
+	if strings.Count(out, "<path") != 1 || strings.Count(out, "<text") != 1 {
+		t.Errorf("expected one converted path and one kept text, got %s", out)
+	}
+}
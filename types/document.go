@@ -1,8 +1,10 @@
 package types
 
 import (
+	"encoding/xml"
 	"fmt"
 	"io"
+	"math"
 	"strings"
 )
 
@@ -14,6 +16,8 @@ type Document struct {
 	Elements   []Element
 	Attributes map[string]string
 	Defs       []Element // 定义区域中的元素
+	Title      string    // 文档标题，对应根级<title> / Document title, the root-level <title>
+	Desc       string    // 文档描述，对应根级<desc> / Document description, the root-level <desc>
 }
 
 // NewDocument 创建一个新的SVG文档
@@ -27,6 +31,37 @@ func NewDocument(width, height int) *Document {
 	}
 }
 
+// ContentBounds 返回所有顶层元素含变换的几何边界的并集，空边界被忽略
+// ContentBounds returns the union of the transform-aware geometry bounds of all top-level elements, ignoring empty bounds
+//
+// 元素需实现TransformedBounds() (Rect, bool)；没有可确定边界的内容时返回false
+// Elements must implement TransformedBounds() (Rect, bool); returns false when no content has known bounds
+func (d *Document) ContentBounds() (Rect, bool) {
+	// 直接累计最小最大值，零宽或零高的边界（如水平线）也计入 / Accumulate min/max directly so zero-width or zero-height bounds, such as a horizontal line, count too
+	var minX, minY, maxX, maxY float64
+	found := false
+	for _, el := range d.Elements {
+		bounded, ok := el.(interface{ TransformedBounds() (Rect, bool) })
+		if !ok {
+			continue
+		}
+		b, ok := bounded.TransformedBounds()
+		if !ok {
+			continue
+		}
+		if !found {
+			minX, minY, maxX, maxY, found = b.X, b.Y, b.MaxX(), b.MaxY(), true
+			continue
+		}
+		minX, minY = math.Min(minX, b.X), math.Min(minY, b.Y)
+		maxX, maxY = math.Max(maxX, b.MaxX()), math.Max(maxY, b.MaxY())
+	}
+	if !found {
+		return Rect{}, false
+	}
+	return Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}, true
+}
+
 // SetViewBox 设置视图框
 func (d *Document) SetViewBox(minX, minY, width, height float64) {
 	d.ViewBox = fmt.Sprintf("%f %f %f %f", minX, minY, width, height)
@@ -43,6 +78,40 @@ func (d *Document) GetAttribute(name string) (string, bool) {
 	return value, ok
 }
 
+// SetTitle 设置文档标题 / Set the document title
+func (d *Document) SetTitle(title string) {
+	d.Title = title
+}
+
+// GetTitle 获取文档标题 / Get the document title
+func (d *Document) GetTitle() string {
+	return d.Title
+}
+
+// SetDesc 设置文档描述 / Set the document description
+func (d *Document) SetDesc(desc string) {
+	d.Desc = desc
+}
+
+// GetDesc 获取文档描述 / Get the document description
+func (d *Document) GetDesc() string {
+	return d.Desc
+}
+
+// metadataXML 返回标题和描述元素，未设置时为空 / Return the title and description elements, empty when unset
+func (d *Document) metadataXML() string {
+	var sb strings.Builder
+	for _, item := range []struct{ tag, text string }{{"title", d.Title}, {"desc", d.Desc}} {
+		if item.text == "" {
+			continue
+		}
+		sb.WriteString("<" + item.tag + ">")
+		xml.EscapeText(&sb, []byte(item.text))
+		sb.WriteString("</" + item.tag + ">\n")
+	}
+	return sb.String()
+}
+
 // AppendElement 添加元素到文档
 func (d *Document) AppendElement(element Element) {
 	d.Elements = append(d.Elements, element)
@@ -100,6 +169,11 @@ func (d *Document) WriteTo(w io.Writer) error {
 		return err
 	}
 
+	// 写入标题和描述
+	if _, err := io.WriteString(w, d.metadataXML()); err != nil {
+		return err
+	}
+
 	// 写入定义区域
 	if len(d.Defs) > 0 {
 		if _, err := io.WriteString(w, "<defs>\n"); err != nil {
@@ -169,6 +243,9 @@ func (d *Document) ToXML() string {
 	// 结束开始标签
 	sb.WriteString(">\n")
 
+	// 标题和描述
+	sb.WriteString(d.metadataXML())
+
 	// 定义区域
 	if len(d.Defs) > 0 {
 		sb.WriteString("<defs>\n")
@@ -213,3 +290,137 @@ func findElementByID(elements []Element, id string) Element {
 
 	return nil
 }
+
+// Walk 深度优先前序遍历文档元素，回调返回false时跳过该元素的子树
+// Walk traverses the document's elements depth-first in pre-order; returning false from fn prunes that subtree
+//
+// 顶层元素的深度为0 / Top-level elements have depth 0
+func (d *Document) Walk(fn func(el Element, depth int) bool) {
+	walkElements(d.Elements, 0, fn)
+}
+
+// walkElements 递归遍历元素列表 / Recursively walk a list of elements
+func walkElements(elements []Element, depth int, fn func(el Element, depth int) bool) {
+	for _, element := range elements {
+		if !fn(element, depth) {
+			continue
+		}
+		walkElements(element.Children(), depth+1, fn)
+	}
+}
+
+// BringToFront 将元素移到其兄弟元素的最后，使其最后绘制；找不到元素时返回false
+// BringToFront moves an element to the end of its siblings so it paints last; returns false if the element is not found
+func (d *Document) BringToFront(el Element) bool {
+	_, siblings, i := d.siblingsOf(el)
+	if i < 0 {
+		return false
+	}
+	copy(siblings[i:], siblings[i+1:])
+	siblings[len(siblings)-1] = el
+	return true
+}
+
+// SendToBack 将元素移到其兄弟元素的最前，使其最先绘制；找不到元素时返回false
+// SendToBack moves an element to the start of its siblings so it paints first; returns false if the element is not found
+func (d *Document) SendToBack(el Element) bool {
+	_, siblings, i := d.siblingsOf(el)
+	if i < 0 {
+		return false
+	}
+	copy(siblings[1:i+1], siblings[:i])
+	siblings[0] = el
+	return true
+}
+
+// MoveBefore 将元素移到同级元素ref之前；两者不是兄弟元素时返回false
+// MoveBefore moves an element just before its sibling ref; returns false if the two are not siblings
+func (d *Document) MoveBefore(el, ref Element) bool {
+	_, siblings, i := d.siblingsOf(el)
+	if i < 0 {
+		return false
+	}
+	j := indexOfElement(siblings, ref)
+	if j < 0 {
+		return false
+	}
+	switch {
+	case i < j:
+		copy(siblings[i:j-1], siblings[i+1:j])
+		siblings[j-1] = el
+	case i > j:
+		copy(siblings[j+1:i+1], siblings[j:i])
+		siblings[j] = el
+	}
+	return true
+}
+
+// RemoveElement 从文档中移除元素，递归进入组；找不到元素时返回false
+// RemoveElement removes an element from the document, recursing into groups; returns false if the element is not found
+//
+// 组内移除要求父元素提供RemoveChild方法 / Removing from a group requires the parent to provide a RemoveChild method
+func (d *Document) RemoveElement(el Element) bool {
+	parent, siblings, i := d.siblingsOf(el)
+	if i < 0 {
+		return false
+	}
+	if parent == nil {
+		d.Elements = append(siblings[:i], siblings[i+1:]...)
+		el.SetParent(nil)
+		return true
+	}
+	remover, ok := parent.(interface{ RemoveChild(child Element) })
+	if !ok {
+		return false
+	}
+	remover.RemoveChild(el)
+	return true
+}
+
+// ReplaceElement 在原位置用新元素替换旧元素，递归进入组；找不到旧元素时返回false
+// ReplaceElement puts a new element in place of an old one, recursing into groups; returns false if the old element is not found
+func (d *Document) ReplaceElement(old, replacement Element) bool {
+	parent, siblings, i := d.siblingsOf(old)
+	if i < 0 {
+		return false
+	}
+	siblings[i] = replacement
+	old.SetParent(nil)
+	replacement.SetParent(parent)
+	return true
+}
+
+// siblingsOf 查找包含元素的父元素、兄弟列表及其下标，递归进入组；顶层元素的父元素为nil
+// siblingsOf finds the parent, sibling list and index of an element, recursing into groups; top-level elements have a nil parent
+//
+// 重排直接修改Children()返回的切片，要求其与元素内部存储共享 / Reordering mutates the slice returned by Children(), which must share the element's storage
+func (d *Document) siblingsOf(el Element) (Element, []Element, int) {
+	if i := indexOfElement(d.Elements, el); i >= 0 {
+		return nil, d.Elements, i
+	}
+	var parent Element
+	var siblings []Element
+	index := -1
+	d.Walk(func(candidate Element, depth int) bool {
+		if index >= 0 {
+			return false
+		}
+		children := candidate.Children()
+		if i := indexOfElement(children, el); i >= 0 {
+			parent, siblings, index = candidate, children, i
+			return false
+		}
+		return true
+	})
+	return parent, siblings, index
+}
+
+// indexOfElement 返回元素在列表中的下标，不存在时返回-1 / Return the element's index in a list, or -1 if absent
+func indexOfElement(elements []Element, el Element) int {
+	for i, element := range elements {
+		if element == el {
+			return i
+		}
+	}
+	return -1
+}
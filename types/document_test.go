@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -40,6 +41,15 @@ func (m *MockElement) SetParent(parent Element)
 func (m *MockElement) Clone() Element                                           { return NewMockElement(m.tag) }
 func (m *MockElement) Tag() string                                              { return m.tag }
 
+func (m *MockElement) RemoveChild(child Element) {
+	for i, c := range m.children {
+		if c == child {
+			m.children = append(m.children[:i], m.children[i+1:]...)
+			return
+		}
+	}
+}
+
 func TestNewDocument(t *testing.T) {
 	doc := NewDocument(800, 600)
 	if doc == nil {
@@ -93,3 +103,147 @@ func TestToXML(t *testing.T) {
 		t.Error("Generated XML is empty")
 	}
 }
+
+// TestTitleAndDesc 测试标题和描述写为转义后的元素 / Test title and description are written as escaped elements
+func TestTitleAndDesc(t *testing.T) {
+	doc := NewDocument(10, 10)
+	doc.SetTitle("A & B")
+	doc.SetDesc("<chart>")
+
+	xml := doc.ToXML()
+	for _, want := range []string{"<title>A &amp; B</title>", "<desc>&lt;chart&gt;</desc>"} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("expected %q in %s", want, xml)
+		}
+	}
+	if doc.GetTitle() != "A & B" || doc.GetDesc() != "<chart>" {
+		t.Errorf("unexpected title %q and desc %q", doc.GetTitle(), doc.GetDesc())
+	}
+}
+
+func TestWalk(t *testing.T) {
+	doc := NewDocument(800, 600)
+
+	group := NewMockElement("g")
+	inner := NewMockElement("g")
+	inner.AppendChild(NewMockElement("circle"))
+	group.AppendChild(NewMockElement("rect"))
+	group.AppendChild(inner)
+	group.AppendChild(NewMockElement("line"))
+	doc.AppendElement(group)
+	doc.AppendElement(NewMockElement("path"))
+
+	type visit struct {
+		tag   string
+		depth int
+	}
+	var visits []visit
+	doc.Walk(func(el Element, depth int) bool {
+		visits = append(visits, visit{el.Tag(), depth})
+		return true
+	})
+
+	expected := []visit{{"g", 0}, {"rect", 1}, {"g", 1}, {"circle", 2}, {"line", 1}, {"path", 0}}
+	if len(visits) != len(expected) {
+		t.Fatalf("Expected %d visits, got %d: %v", len(expected), len(visits), visits)
+	}
+	for i := range expected {
+		if visits[i] != expected[i] {
+			t.Errorf("Visit %d: expected %v, got %v", i, expected[i], visits[i])
+		}
+	}
+
+	// 返回false跳过子树 / Returning false prunes the subtree
+	count := 0
+	doc.Walk(func(el Element, depth int) bool {
+		count++
+		return el != inner
+	})
+	if count != 5 {
+		t.Errorf("Expected 5 visits with pruning, got %d", count)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	doc := NewDocument(800, 600)
+	a, b, c := NewMockElement("a"), NewMockElement("b"), NewMockElement("c")
+	doc.AppendElement(a)
+	doc.AppendElement(b)
+	doc.AppendElement(c)
+
+	order := func(elements []Element) string {
+		s := ""
+		for _, el := range elements {
+			s += el.Tag()
+		}
+		return s
+	}
+
+	steps := []struct {
+		name string
+		move func() bool
+		want string
+	}{
+		{"BringToFront", func() bool { return doc.BringToFront(a) }, "bca"},
+		{"SendToBack", func() bool { return doc.SendToBack(c) }, "cba"},
+		{"MoveBefore later", func() bool { return doc.MoveBefore(c, a) }, "bca"},
+		{"MoveBefore earlier", func() bool { return doc.MoveBefore(a, b) }, "abc"},
+	}
+	for _, step := range steps {
+		if !step.move() {
+			t.Fatalf("%s: expected the move to succeed", step.name)
+		}
+		if got := order(doc.Elements); got != step.want {
+			t.Errorf("%s: expected order %s, got %s", step.name, step.want, got)
+		}
+	}
+
+	// 组内元素在其兄弟之间重排 / Nested elements are reordered among their own siblings
+	group := NewMockElement("g")
+	x, y := NewMockElement("x"), NewMockElement("y")
+	group.AppendChild(x)
+	group.AppendChild(y)
+	doc.AppendElement(group)
+	if !doc.BringToFront(x) || order(group.Children()) != "yx" {
+		t.Errorf("expected nested reorder to give yx, got %s", order(group.Children()))
+	}
+	if doc.MoveBefore(x, a) {
+		t.Error("expected MoveBefore across different parents to fail")
+	}
+	if doc.SendToBack(NewMockElement("missing")) {
+		t.Error("expected reordering a missing element to fail")
+	}
+}
+
+func TestRemoveAndReplaceElement(t *testing.T) {
+	doc := NewDocument(800, 600)
+	a, b := NewMockElement("a"), NewMockElement("b")
+	group := NewMockElement("g")
+	x, y := NewMockElement("x"), NewMockElement("y")
+	group.AppendChild(x)
+	group.AppendChild(y)
+	doc.AppendElement(a)
+	doc.AppendElement(group)
+	doc.AppendElement(b)
+
+	if !doc.RemoveElement(a) || len(doc.Elements) != 2 || doc.Elements[0] != group {
+		t.Errorf("expected a to be removed from the top level, got %v", doc.Elements)
+	}
+	if !doc.RemoveElement(x) || len(group.Children()) != 1 || group.Children()[0] != y {
+		t.Errorf("expected x to be removed from the group, got %v", group.Children())
+	}
+	if doc.RemoveElement(x) {
+		t.Error("expected removing an element twice to fail")
+	}
+
+	z := NewMockElement("z")
+	if !doc.ReplaceElement(y, z) || group.Children()[0] != z || z.parent != group {
+		t.Errorf("expected y to be replaced by z inside the group")
+	}
+	if !doc.ReplaceElement(b, y) || doc.Elements[1] != y || y.parent != nil {
+		t.Errorf("expected b to be replaced by y at the top level")
+	}
+	if doc.ReplaceElement(b, a) {
+		t.Error("expected replacing a missing element to fail")
+	}
+}
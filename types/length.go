@@ -0,0 +1,103 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LengthUnit 长度单位 / Length unit
+type LengthUnit string
+
+const (
+	UnitNone    LengthUnit = ""    // 用户单位 / User units
+	UnitPx      LengthUnit = "px"  // 像素 / Pixels
+	UnitPt      LengthUnit = "pt"  // 点，1/72英寸 / Points, 1/72 inch
+	UnitPc      LengthUnit = "pc"  // 派卡，1/6英寸 / Picas, 1/6 inch
+	UnitMm      LengthUnit = "mm"  // 毫米 / Millimeters
+	UnitCm      LengthUnit = "cm"  // 厘米 / Centimeters
+	UnitIn      LengthUnit = "in"  // 英寸 / Inches
+	UnitPercent LengthUnit = "%"   // 视口尺寸的百分比 / Percentage of the viewport extent
+	UnitEm      LengthUnit = "em"  // 字号的倍数 / Multiples of the font size
+	UnitEx      LengthUnit = "ex"  // x高度的倍数，按半个字号计 / Multiples of the x-height, taken as half the font size
+	UnitRem     LengthUnit = "rem" // 根字号的倍数 / Multiples of the root font size
+)
+
+const (
+	// DefaultDPI CSS参考分辨率，1英寸等于96像素 / The CSS reference resolution, 96 pixels per inch
+	DefaultDPI = 96.0
+	// DefaultFontSize 未指定字号时em和rem的基准 / The em and rem basis when no font size is given
+	DefaultFontSize = 16.0
+)
+
+// lengthUnits 按解析时匹配的顺序排列，rem需先于em / Units in matching order; rem must come before em
+var lengthUnits = []LengthUnit{UnitRem, UnitPx, UnitPt, UnitPc, UnitMm, UnitCm, UnitIn, UnitPercent, UnitEm, UnitEx}
+
+// unitsPerInch 绝对单位每英寸的数量 / Number of each absolute unit per inch
+var unitsPerInch = map[LengthUnit]float64{UnitPt: 72, UnitPc: 6, UnitMm: 25.4, UnitCm: 2.54, UnitIn: 1}
+
+// Length 带单位的长度 / A length with a unit
+type Length struct {
+	Value float64
+	Unit  LengthUnit
+}
+
+// ParseLength 解析如"10mm"、"50%"的长度，无单位时为用户单位 / Parse a length such as "10mm" or "50%"; a bare number is in user units
+func ParseLength(s string) (Length, error) {
+	s = strings.TrimSpace(s)
+	unit := UnitNone
+	for _, u := range lengthUnits {
+		if strings.HasSuffix(s, string(u)) {
+			unit = u
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, string(unit))), 64)
+	if err != nil {
+		return Length{}, fmt.Errorf("无效的长度: %q", s)
+	}
+	return Length{Value: value, Unit: unit}, nil
+}
+
+// String 返回属性值形式，如"10mm" / Return the attribute value form, such as "10mm"
+func (l Length) String() string {
+	return strconv.FormatFloat(l.Value, 'f', -1, 64) + string(l.Unit)
+}
+
+// IsAbsolute 判断长度是否无需视口或字号即可解析 / Report whether the length resolves without a viewport or font size
+func (l Length) IsAbsolute() bool {
+	switch l.Unit {
+	case UnitPercent, UnitEm, UnitEx, UnitRem:
+		return false
+	}
+	return true
+}
+
+// Resolve 将长度转换为像素；百分比相对viewportExtent，物理单位按dpi换算，em按DefaultFontSize计
+// Resolve converts the length to pixels; percentages are relative to viewportExtent, physical units convert at dpi and em uses DefaultFontSize
+//
+// dpi不大于0时使用DefaultDPI / A dpi of zero or less uses DefaultDPI
+func (l Length) Resolve(viewportExtent, dpi float64) float64 {
+	return l.ResolveWithFontSize(viewportExtent, dpi, DefaultFontSize)
+}
+
+// ResolveWithFontSize 与Resolve相同，但em和ex相对给定字号 / Like Resolve, but em and ex are relative to the given font size
+func (l Length) ResolveWithFontSize(viewportExtent, dpi, fontSize float64) float64 {
+	if dpi <= 0 {
+		dpi = DefaultDPI
+	}
+	switch l.Unit {
+	case UnitPercent:
+		return l.Value / 100 * viewportExtent
+	case UnitEm:
+		return l.Value * fontSize
+	case UnitEx:
+		return l.Value * fontSize / 2
+	case UnitRem:
+		return l.Value * DefaultFontSize
+	}
+	if perInch, ok := unitsPerInch[l.Unit]; ok {
+		return l.Value / perInch * dpi
+	}
+	return l.Value
+}
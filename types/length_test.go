@@ -0,0 +1,45 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+// TestLengthResolve 测试毫米、百分比和em长度在已知上下文中的换算 / Test resolving millimeter, percentage and em lengths against known contexts
+func TestLengthResolve(t *testing.T) {
+	tests := []struct {
+		input    string
+		extent   float64
+		dpi      float64
+		fontSize float64
+		want     float64
+	}{
+		{"10mm", 0, 96, DefaultFontSize, 10 / 25.4 * 96},
+		{"10mm", 0, 300, DefaultFontSize, 10 / 25.4 * 300},
+		{"50%", 200, 96, DefaultFontSize, 100},
+		{"2em", 0, 96, DefaultFontSize, 32},
+		{"2em", 0, 96, 10, 20},
+		{"1rem", 0, 96, 10, DefaultFontSize},
+		{"72pt", 0, 0, DefaultFontSize, 96},
+		{"12", 0, 300, DefaultFontSize, 12},
+	}
+	for _, tt := range tests {
+		length, err := ParseLength(tt.input)
+		if err != nil {
+			t.Fatalf("ParseLength(%q) failed: %v", tt.input, err)
+		}
+		if got := length.ResolveWithFontSize(tt.extent, tt.dpi, tt.fontSize); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s at extent %v, %v dpi, font size %v: expected %v, got %v", tt.input, tt.extent, tt.dpi, tt.fontSize, tt.want, got)
+		}
+		if got := length.String(); got != tt.input {
+			t.Errorf("expected %q to round-trip, got %q", tt.input, got)
+		}
+	}
+
+	if got := (Length{Value: 2, Unit: UnitEm}).Resolve(0, 96); got != 2*DefaultFontSize {
+		t.Errorf("expected Resolve to use the default font size, got %v", got)
+	}
+	if _, err := ParseLength("abc"); err == nil {
+		t.Error("expected an error for an invalid length")
+	}
+}
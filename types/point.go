@@ -0,0 +1,53 @@
+package types
+
+import "math"
+
+// Add 返回两个向量之和 / Return the sum of two vectors
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Sub 返回两个向量之差 / Return the difference of two vectors
+func (p Point) Sub(q Point) Point {
+	return Point{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+// Scale 返回按因子缩放的向量 / Return the vector scaled by a factor
+func (p Point) Scale(factor float64) Point {
+	return Point{X: p.X * factor, Y: p.Y * factor}
+}
+
+// Dot 返回点积 / Return the dot product
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Cross 返回二维叉积（z分量），q在p逆时针方向时为正（y轴向下时为顺时针）
+// Cross returns the 2D cross product (the z component), positive when q is counter-clockwise of p (clockwise with y pointing down)
+func (p Point) Cross(q Point) float64 {
+	return p.X*q.Y - p.Y*q.X
+}
+
+// Length 返回向量长度 / Return the vector's length
+func (p Point) Length() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+// Normalize 返回同方向的单位向量，零向量返回零向量 / Return the unit vector in the same direction, or the zero vector for a zero vector
+func (p Point) Normalize() Point {
+	length := p.Length()
+	if length == 0 {
+		return Point{}
+	}
+	return Point{X: p.X / length, Y: p.Y / length}
+}
+
+// Distance 返回两点间距离 / Return the distance between two points
+func (p Point) Distance(q Point) float64 {
+	return q.Sub(p).Length()
+}
+
+// Lerp 在p和q之间线性插值，t=0返回p，t=1返回q / Linearly interpolate between p and q; t=0 returns p and t=1 returns q
+func (p Point) Lerp(q Point, t float64) Point {
+	return Point{X: p.X + (q.X-p.X)*t, Y: p.Y + (q.Y-p.Y)*t}
+}
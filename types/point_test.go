@@ -0,0 +1,68 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPointArithmetic 测试向量加减、缩放、点积和叉积 / Test vector addition, subtraction, scaling, dot and cross products
+func TestPointArithmetic(t *testing.T) {
+	p, q := Point{X: 3, Y: 4}, Point{X: 1, Y: -2}
+
+	if got := p.Add(q); got != (Point{X: 4, Y: 2}) {
+		t.Errorf("Add: got %v", got)
+	}
+	if got := p.Sub(q); got != (Point{X: 2, Y: 6}) {
+		t.Errorf("Sub: got %v", got)
+	}
+	if got := p.Scale(-0.5); got != (Point{X: -1.5, Y: -2}) {
+		t.Errorf("Scale: got %v", got)
+	}
+	if got := p.Dot(q); got != -5 {
+		t.Errorf("Dot: got %v", got)
+	}
+	if got := p.Cross(q); got != -10 {
+		t.Errorf("Cross: got %v", got)
+	}
+	if got := (Point{X: 1}).Cross(Point{Y: 1}); got != 1 {
+		t.Errorf("Cross of unit axes: got %v", got)
+	}
+}
+
+// TestPointLength 测试长度、距离和归一化，包括零向量 / Test length, distance and normalization, including the zero vector
+func TestPointLength(t *testing.T) {
+	p := Point{X: 3, Y: 4}
+
+	if got := p.Length(); got != 5 {
+		t.Errorf("Length: got %v", got)
+	}
+	if got := p.Distance(Point{X: 6, Y: 8}); got != 5 {
+		t.Errorf("Distance: got %v", got)
+	}
+
+	unit := p.Normalize()
+	if math.Abs(unit.X-0.6) > 1e-12 || math.Abs(unit.Y-0.8) > 1e-12 || math.Abs(unit.Length()-1) > 1e-12 {
+		t.Errorf("Normalize: got %v", unit)
+	}
+	if got := (Point{}).Normalize(); got != (Point{}) {
+		t.Errorf("expected the zero vector to normalize to itself, got %v", got)
+	}
+}
+
+// TestPointLerp 测试线性插值的端点和中点 / Test linear interpolation at the endpoints and midpoint
+func TestPointLerp(t *testing.T) {
+	p, q := Point{X: 0, Y: 10}, Point{X: 10, Y: 20}
+
+	for _, tc := range []struct {
+		t    float64
+		want Point
+	}{
+		{0, p},
+		{1, q},
+		{0.5, Point{X: 5, Y: 15}},
+	} {
+		if got := p.Lerp(q, tc.t); got != tc.want {
+			t.Errorf("Lerp(%v): expected %v, got %v", tc.t, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package types
+
+import "math"
+
+// Rect 表示轴对齐矩形区域 / Rect is an axis-aligned rectangle
+type Rect struct {
+	X float64
+	Y float64
+	W float64
+	H float64
+}
+
+// Intersects 判断两个矩形是否相交，仅接触边缘不算相交 / Report whether two rectangles overlap; touching edges do not count
+func (r Rect) Intersects(other Rect) bool {
+	return r.X < other.X+other.W && other.X < r.X+r.W &&
+		r.Y < other.Y+other.H && other.Y < r.Y+r.H
+}
+
+// Contains 判断矩形是否完全包含另一个矩形 / Report whether the rectangle fully contains another
+func (r Rect) Contains(other Rect) bool {
+	return other.X >= r.X && other.Y >= r.Y &&
+		other.X+other.W <= r.X+r.W && other.Y+other.H <= r.Y+r.H
+}
+
+// Inset 向内收缩矩形，负值向外扩展 / Shrink the rectangle inward; negative amounts grow it
+func (r Rect) Inset(d float64) Rect {
+	return Rect{X: r.X + d, Y: r.Y + d, W: r.W - 2*d, H: r.H - 2*d}
+}
+
+// RectFromPoints 返回包含所有点的最小矩形，无点时返回零值 / Return the smallest rectangle containing all points, or the zero value when there are none
+func RectFromPoints(points []Point) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+	minX, minY, maxX, maxY := points[0].X, points[0].Y, points[0].X, points[0].Y
+	for _, p := range points[1:] {
+		minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+		maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+	}
+	return Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+}
+
+// MaxX 返回右边界 / Return the right edge
+func (r Rect) MaxX() float64 {
+	return r.X + r.W
+}
+
+// MaxY 返回下边界 / Return the bottom edge
+func (r Rect) MaxY() float64 {
+	return r.Y + r.H
+}
+
+// IsEmpty 判断矩形面积是否为零或负 / Report whether the rectangle has zero or negative area
+func (r Rect) IsEmpty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// Union 返回同时包含两个矩形的最小矩形，空矩形被忽略 / Return the smallest rectangle containing both; empty rectangles are ignored
+func (r Rect) Union(other Rect) Rect {
+	if r.IsEmpty() {
+		return other
+	}
+	if other.IsEmpty() {
+		return r
+	}
+	minX, minY := math.Min(r.X, other.X), math.Min(r.Y, other.Y)
+	maxX, maxY := math.Max(r.MaxX(), other.MaxX()), math.Max(r.MaxY(), other.MaxY())
+	return Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+}
+
+// Intersect 返回两个矩形的重叠部分，不重叠时返回零值 / Return the overlap of two rectangles, or the zero value when they do not overlap
+func (r Rect) Intersect(other Rect) Rect {
+	minX, minY := math.Max(r.X, other.X), math.Max(r.Y, other.Y)
+	maxX, maxY := math.Min(r.MaxX(), other.MaxX()), math.Min(r.MaxY(), other.MaxY())
+	if maxX <= minX || maxY <= minY {
+		return Rect{}
+	}
+	return Rect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+}
@@ -0,0 +1,57 @@
+package types
+
+import "testing"
+
+// TestRectUnion 测试矩形并集，空矩形被忽略 / Test rectangle union, ignoring empty rectangles
+func TestRectUnion(t *testing.T) {
+	a := Rect{X: 0, Y: 0, W: 10, H: 10}
+	b := Rect{X: 5, Y: -5, W: 10, H: 10}
+
+	if got := a.Union(b); got != (Rect{X: 0, Y: -5, W: 15, H: 15}) {
+		t.Errorf("unexpected union %+v", got)
+	}
+	if got := a.Union(Rect{}); got != a {
+		t.Errorf("expected union with an empty rect to be %+v, got %+v", a, got)
+	}
+	if got := (Rect{X: 100, Y: 100}).Union(b); got != b {
+		t.Errorf("expected a zero-size rect to be ignored, got %+v", got)
+	}
+	if got := (Rect{}).Union(Rect{}); !got.IsEmpty() {
+		t.Errorf("expected union of empty rects to be empty, got %+v", got)
+	}
+}
+
+// TestRectIntersect 测试矩形交集，包括不相交和空矩形 / Test rectangle intersection, including disjoint and empty rectangles
+func TestRectIntersect(t *testing.T) {
+	a := Rect{X: 0, Y: 0, W: 10, H: 10}
+
+	if got := a.Intersect(Rect{X: 5, Y: 5, W: 10, H: 10}); got != (Rect{X: 5, Y: 5, W: 5, H: 5}) {
+		t.Errorf("unexpected intersection %+v", got)
+	}
+	if got := a.Intersect(Rect{X: 2, Y: 2, W: 3, H: 3}); got != (Rect{X: 2, Y: 2, W: 3, H: 3}) {
+		t.Errorf("expected a contained rect to be its own intersection, got %+v", got)
+	}
+	for _, other := range []Rect{
+		{X: 20, Y: 20, W: 5, H: 5}, // 不相交 / Disjoint
+		{X: 10, Y: 0, W: 5, H: 5},  // 仅接触边缘 / Touching edges only
+		{X: 5, Y: 5},               // 零尺寸 / Zero size
+	} {
+		if got := a.Intersect(other); !got.IsEmpty() || got != (Rect{}) {
+			t.Errorf("expected an empty intersection with %+v, got %+v", other, got)
+		}
+	}
+}
+
+// TestRectFromPoints 测试点集边界 / Test the bounds of a point set
+func TestRectFromPoints(t *testing.T) {
+	got := RectFromPoints([]Point{{X: 3, Y: 1}, {X: -2, Y: 4}, {X: 1, Y: -1}})
+	if got != (Rect{X: -2, Y: -1, W: 5, H: 5}) {
+		t.Errorf("unexpected bounds %+v", got)
+	}
+	if got.MaxX() != 3 || got.MaxY() != 4 {
+		t.Errorf("unexpected max edges %v, %v", got.MaxX(), got.MaxY())
+	}
+	if !RectFromPoints(nil).IsEmpty() {
+		t.Error("expected no points to give an empty rect")
+	}
+}
@@ -0,0 +1,118 @@
+package svg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hoonfeng/svg/path"
+	. "github.com/hoonfeng/svg/types"
+)
+
+// urlReferencePattern 匹配url(#id)引用 / Matches url(#id) references
+var urlReferencePattern = regexp.MustCompile(`url\(\s*['"]?#([^'")\s]+)['"]?\s*\)`)
+
+// referenceAttributes 可以通过url(#id)引用其他元素的属性 / Attributes that may reference other elements via url(#id)
+var referenceAttributes = []string{"fill", "stroke", "filter", "clip-path", "mask", "marker-start", "marker-mid", "marker-end", "style"}
+
+// nonNegativeAttributes 各元素不允许为负的尺寸属性 / Size attributes that must not be negative, per element
+var nonNegativeAttributes = map[string][]string{
+	"rect":    {"width", "height", "rx", "ry"},
+	"image":   {"width", "height"},
+	"svg":     {"width", "height"},
+	"circle":  {"r"},
+	"ellipse": {"rx", "ry"},
+}
+
+// Validate 检查文档的结构问题并返回问题列表，不修改文档
+// Validate checks the document for structural problems and returns them without modifying the document
+//
+// 检查未定义的url(#id)和href引用、重复ID、格式错误的路径数据和负尺寸；未闭合的标签在解析时即被拒绝
+// It reports references to undefined url(#id) and href targets, duplicate IDs, malformed path data and negative sizes; unclosed tags are already rejected while parsing
+func (s *SVG) Validate() []error {
+	var problems []error
+
+	// 收集所有ID，包括定义区域 / Collect every ID, including definitions
+	ids := make(map[string]int)
+	walkAll := func(fn func(el Element)) {
+		var walk func(list []Element)
+		walk = func(list []Element) {
+			for _, el := range list {
+				fn(el)
+				walk(el.Children())
+			}
+		}
+		walk(s.doc.Defs)
+		walk(s.doc.Elements)
+	}
+	walkAll(func(el Element) {
+		if id := el.ID(); id != "" {
+			ids[id]++
+		}
+	})
+
+	duplicates := make([]string, 0)
+	for id, count := range ids {
+		if count > 1 {
+			duplicates = append(duplicates, id)
+		}
+	}
+	sort.Strings(duplicates)
+	for _, id := range duplicates {
+		problems = append(problems, fmt.Errorf("duplicate id %q used by %d elements", id, ids[id]))
+	}
+
+	walkAll(func(el Element) {
+		problems = append(problems, validateElement(el, ids)...)
+	})
+	return problems
+}
+
+// validateElement 检查单个元素的引用、路径数据和尺寸 / Check a single element's references, path data and sizes
+func validateElement(el Element, ids map[string]int) []error {
+	var problems []error
+	attrs := el.GetAttributes()
+	name := describeElement(el)
+
+	for _, attr := range referenceAttributes {
+		for _, match := range urlReferencePattern.FindAllStringSubmatch(attrs[attr], -1) {
+			if ids[match[1]] == 0 {
+				problems = append(problems, fmt.Errorf("%s: %s references undefined id %q", name, attr, match[1]))
+			}
+		}
+	}
+	if target := strings.TrimSpace(attrs["href"]); strings.HasPrefix(target, "#") && ids[target[1:]] == 0 {
+		problems = append(problems, fmt.Errorf("%s: href references undefined id %q", name, target[1:]))
+	}
+
+	if el.Tag() == "path" {
+		if d := strings.TrimSpace(attrs["d"]); d != "" {
+			if d[0] != 'M' && d[0] != 'm' {
+				problems = append(problems, fmt.Errorf("%s: malformed path data: must start with a moveto command", name))
+			} else if _, err := path.ParsePath(d); err != nil {
+				problems = append(problems, fmt.Errorf("%s: malformed path data: %v", name, err))
+			}
+		}
+	}
+
+	for _, attr := range nonNegativeAttributes[el.Tag()] {
+		value, ok := attrs[attr]
+		if !ok {
+			continue
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && v < 0 {
+			problems = append(problems, fmt.Errorf("%s: %s must not be negative, got %s", name, attr, value))
+		}
+	}
+	return problems
+}
+
+// describeElement 返回用于错误信息的元素描述 / Describe an element for error messages
+func describeElement(el Element) string {
+	if id := el.ID(); id != "" {
+		return fmt.Sprintf("<%s id=%q>", el.Tag(), id)
+	}
+	return "<" + el.Tag() + ">"
+}